@@ -262,6 +262,8 @@ func protoToJSON(msg proto.Message) ([]byte, error) {
 }
 
 func mapToProto(tree map[string]interface{}, msg proto.Message) error {
+	resolveEnumNames(tree, msg)
+
 	jsonOut, err := json.Marshal(tree)
 	if err != nil {
 		return err
@@ -270,6 +272,44 @@ func mapToProto(tree map[string]interface{}, msg proto.Message) error {
 	return jsonpb.UnmarshalString(string(jsonOut), msg)
 }
 
+// resolveEnumNames replaces the enum value names of the message's fields with their numbers.
+// The SDK's copies of the protos register their enums with an "sdk." prefix, so jsonpb is unable
+// to resolve the enum names given in the struct tags.
+func resolveEnumNames(tree map[string]interface{}, msg proto.Message) {
+	msgType := reflect.TypeOf(msg)
+	if msgType.Kind() != reflect.Ptr || msgType.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	for _, prop := range proto.GetProperties(msgType.Elem()).Prop {
+		if prop.Enum == "" {
+			continue
+		}
+
+		values := proto.EnumValueMap(prop.Enum)
+		if values == nil {
+			values = proto.EnumValueMap("sdk." + prop.Enum)
+		}
+
+		for _, name := range []string{prop.OrigName, prop.JSONName} {
+			switch value := tree[name].(type) {
+			case string:
+				if n, ok := values[value]; ok {
+					tree[name] = n
+				}
+			case []interface{}:
+				for i, v := range value {
+					if s, ok := v.(string); ok {
+						if n, ok := values[s]; ok {
+							value[i] = n
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
 // jsonToMap allocates a map[string]interface{}, unmarshals a JSON document into it
 // and returns it, or error
 func jsonToMap(marshaled []byte) (map[string]interface{}, error) {
@@ -29,6 +29,22 @@ type requestOptions struct {
 	Timeouts      map[fab.TimeoutType]time.Duration //timeout options for channel client operations
 	ParentContext reqContext.Context                //parent grpc context for channel client operations (query, execute, invokehandler)
 	CCFilter      invoke.CCFilter
+	ConflictRetry retry.Opts //retry options for re-submitting transactions that failed with a read conflict
+}
+
+// invokeOpts returns the options used by the invoke handlers. Conflict retry is handled by the client
+// around the handler chain so it isn't passed on.
+func (o requestOptions) invokeOpts() invoke.Opts {
+	return invoke.Opts{
+		Targets:       o.Targets,
+		TargetFilter:  o.TargetFilter,
+		TargetSorter:  o.TargetSorter,
+		Retry:         o.Retry,
+		BeforeRetry:   o.BeforeRetry,
+		Timeouts:      o.Timeouts,
+		ParentContext: o.ParentContext,
+		CCFilter:      o.CCFilter,
+	}
 }
 
 // RequestOption func for each Opts argument
@@ -60,6 +76,9 @@ type Response struct {
 	TxValidationCode pb.TxValidationCode
 	ChaincodeStatus  int32
 	Payload          []byte
+	// TransactionIDs contains the IDs of all transactions that were submitted for the request,
+	// including those that were re-submitted due to a read conflict
+	TransactionIDs []fab.TransactionID
 }
 
 //WithTargets allows overriding of the target peers for the request
@@ -163,3 +182,16 @@ func WithChaincodeFilter(ccFilter invoke.CCFilter) RequestOption {
 		return nil
 	}
 }
+
+// WithConflictRetry re-runs the entire endorse, order and commit flow of Execute, with a new
+// transaction ID, if the transaction is invalidated due to an MVCC or phantom read conflict.
+// If no retryable codes are provided then retry.ConflictRetryableCodes is used.
+func WithConflictRetry(policy retry.Opts) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if len(policy.RetryableCodes) == 0 {
+			policy.RetryableCodes = retry.ConflictRetryableCodes
+		}
+		o.ConflictRetry = policy
+		return nil
+	}
+}
@@ -0,0 +1,390 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultBatchEndorsers   = 10
+	defaultBatchMaxInFlight = 100
+)
+
+// BatchResult contains the outcome of a single request submitted via a BatchSubmitter
+type BatchResult struct {
+	Request  Request
+	Response Response
+	// Err is set if the request failed to be endorsed, broadcast or committed
+	Err error
+	// Conflict is true if the transaction was invalidated due to an MVCC or phantom read conflict,
+	// in which case the request may be re-submitted
+	Conflict bool
+}
+
+// BatchSubmitter executes a stream of requests. Proposals are endorsed by a bounded pool of workers,
+// the number of transactions that have been sent to the orderer but not yet committed is bounded,
+// and commits are tracked with a single filtered block registration rather than one registration per transaction.
+type BatchSubmitter struct {
+	client      *Client
+	endorsers   int
+	maxInFlight int
+}
+
+// BatchOption describes a functional parameter for the NewBatchSubmitter constructor
+type BatchOption func(*BatchSubmitter) error
+
+// WithEndorsementWorkers sets the number of requests that may be endorsed concurrently
+func WithEndorsementWorkers(n int) BatchOption {
+	return func(b *BatchSubmitter) error {
+		if n <= 0 {
+			return errors.New("number of endorsement workers must be greater than zero")
+		}
+		b.endorsers = n
+		return nil
+	}
+}
+
+// WithMaxInFlight sets the maximum number of transactions that may be sent to the orderer
+// and awaiting commit at any one time
+func WithMaxInFlight(n int) BatchOption {
+	return func(b *BatchSubmitter) error {
+		if n <= 0 {
+			return errors.New("max in-flight transactions must be greater than zero")
+		}
+		b.maxInFlight = n
+		return nil
+	}
+}
+
+// NewBatchSubmitter returns a BatchSubmitter that executes requests using the given channel client
+func NewBatchSubmitter(client *Client, opts ...BatchOption) (*BatchSubmitter, error) {
+	if client == nil {
+		return nil, errors.New("channel client is required")
+	}
+
+	b := &BatchSubmitter{
+		client:      client,
+		endorsers:   defaultBatchEndorsers,
+		maxInFlight: defaultBatchMaxInFlight,
+	}
+
+	for _, opt := range opts {
+		if err := opt(b); err != nil {
+			return nil, errors.WithMessage(err, "option failed")
+		}
+	}
+
+	return b, nil
+}
+
+// Submit endorses and commits the requests read from the given channel.
+//  Parameters:
+//  requests is the stream of requests to execute. The caller closes the channel when there are no more requests.
+//  options holds optional request options which are applied to every request
+//
+//  Returns:
+//  a channel on which one result is published per request. The channel is closed once the
+//  request channel is closed and all outstanding transactions have completed.
+func (b *BatchSubmitter) Submit(requests <-chan Request, options ...RequestOption) (<-chan *BatchResult, error) {
+	cc := b.client
+
+	options = append(options, addDefaultTimeout(fab.Execute))
+	options = append(options, addDefaultTargetFilter(cc.context, filter.EndorsingPeer))
+
+	txnOpts, err := cc.prepareOptsFromOptions(cc.context, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	reg, blockEvents, err := cc.eventService.RegisterFilteredBlockEvent()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error registering for filtered block events")
+	}
+
+	s := &batchSession{
+		BatchSubmitter: b,
+		options:        options,
+		commitTimeout:  txnOpts.Timeouts[fab.Execute],
+		opts:           txnOpts,
+		pending:        make(map[string]*pendingTx),
+		inFlight:       make(chan struct{}, b.maxInFlight),
+		results:        make(chan *BatchResult),
+		queued:         make(chan struct{}, 1),
+		done:           make(chan struct{}),
+	}
+
+	go s.trackCommits(blockEvents)
+	go s.forward()
+
+	var workers sync.WaitGroup
+	workers.Add(b.endorsers)
+	for i := 0; i < b.endorsers; i++ {
+		go func() {
+			defer workers.Done()
+			for request := range requests {
+				s.process(request)
+			}
+		}()
+	}
+
+	go func() {
+		workers.Wait()
+		s.outstanding.Wait()
+		close(s.done)
+		cc.eventService.Unregister(reg)
+		close(s.results)
+	}()
+
+	return s.results, nil
+}
+
+type pendingTx struct {
+	result *BatchResult
+	timer  *time.Timer
+}
+
+type queuedResult struct {
+	result *BatchResult
+	// inFlight is true if the result holds an in-flight slot which is released once the result is delivered
+	inFlight bool
+}
+
+// batchSession holds the state of a single invocation of Submit
+type batchSession struct {
+	*BatchSubmitter
+	options       []RequestOption
+	opts          requestOptions
+	commitTimeout time.Duration
+	inFlight      chan struct{}
+	outstanding   sync.WaitGroup
+	results       chan *BatchResult
+	done          chan struct{}
+
+	queueMutex sync.Mutex
+	queue      []queuedResult
+	queued     chan struct{}
+
+	mutex   sync.Mutex
+	pending map[string]*pendingTx
+	// eventErr is set once the block event channel is closed, after which commits can no longer be tracked
+	eventErr error
+}
+
+// process endorses the request and, if successful, broadcasts the transaction.
+// The call blocks if the maximum number of in-flight transactions has been reached.
+func (s *batchSession) process(request Request) {
+	s.outstanding.Add(1)
+
+	result := &BatchResult{Request: request}
+
+	response, err := s.client.InvokeHandler(newBatchEndorseHandler(), request, s.options...)
+	result.Response = response
+	if err != nil {
+		result.Err = errors.WithMessage(err, "endorsement failed")
+		s.publish(result)
+		return
+	}
+
+	s.inFlight <- struct{}{}
+
+	txnID := string(response.TransactionID)
+
+	// Add the transaction to the pending list before broadcasting it so that
+	// its commit cannot be missed
+	s.mutex.Lock()
+	if s.eventErr != nil {
+		err := s.eventErr
+		s.mutex.Unlock()
+		result.Err = err
+		s.complete(&pendingTx{result: result})
+		return
+	}
+	s.pending[txnID] = &pendingTx{
+		result: result,
+		timer:  time.AfterFunc(s.commitTimeout, func() { s.expire(txnID) }),
+	}
+	s.mutex.Unlock()
+
+	if err := s.broadcast(response); err != nil {
+		if p, ok := s.remove(txnID); ok {
+			p.result.Err = errors.WithMessage(err, "CreateAndSendTransaction failed")
+			s.complete(p)
+		}
+	}
+}
+
+func (s *batchSession) broadcast(response Response) error {
+	cc := s.client
+
+	reqCtx, cancel := contextImpl.NewRequest(cc.context, contextImpl.WithTimeout(s.commitTimeout),
+		contextImpl.WithParent(s.opts.ParentContext))
+	defer cancel()
+
+	transactor, err := cc.context.ChannelService().Transactor(reqCtx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create transactor")
+	}
+
+	tx, err := transactor.CreateTransaction(fab.TransactionRequest{
+		Proposal:          response.Proposal,
+		ProposalResponses: response.Responses,
+	})
+	if err != nil {
+		return errors.WithMessage(err, "CreateTransaction failed")
+	}
+
+	if _, err := transactor.SendTransaction(tx); err != nil {
+		return errors.WithMessage(err, "SendTransaction failed")
+	}
+
+	return nil
+}
+
+// trackCommits scans each filtered block once and completes the pending transactions that it contains
+func (s *batchSession) trackCommits(blockEvents <-chan *fab.FilteredBlockEvent) {
+	for {
+		select {
+		case event, ok := <-blockEvents:
+			if !ok {
+				s.failAll(errors.New("filtered block event channel closed"))
+				return
+			}
+			s.handleBlock(event)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *batchSession) handleBlock(event *fab.FilteredBlockEvent) {
+	if event.FilteredBlock == nil {
+		return
+	}
+
+	for _, tx := range event.FilteredBlock.FilteredTransactions {
+		p, ok := s.remove(tx.Txid)
+		if !ok {
+			continue
+		}
+
+		p.result.Response.TxValidationCode = tx.TxValidationCode
+		if tx.TxValidationCode != pb.TxValidationCode_VALID {
+			p.result.Err = status.New(status.EventServerStatus, int32(tx.TxValidationCode),
+				"received invalid transaction", nil)
+			p.result.Conflict = isConflict(tx.TxValidationCode)
+		}
+		s.complete(p)
+	}
+}
+
+func (s *batchSession) expire(txnID string) {
+	if p, ok := s.remove(txnID); ok {
+		p.result.Err = status.New(status.ClientStatus, status.Timeout.ToInt32(),
+			"didn't receive block event", nil)
+		s.complete(p)
+	}
+}
+
+// failAll fails the pending transactions and any transaction submitted afterwards
+func (s *batchSession) failAll(err error) {
+	s.mutex.Lock()
+	s.eventErr = err
+	var failed []*pendingTx
+	for txnID, p := range s.pending {
+		delete(s.pending, txnID)
+		failed = append(failed, p)
+	}
+	s.mutex.Unlock()
+
+	for _, p := range failed {
+		p.result.Err = err
+		s.complete(p)
+	}
+}
+
+func (s *batchSession) remove(txnID string) (*pendingTx, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	p, ok := s.pending[txnID]
+	if ok {
+		delete(s.pending, txnID)
+		p.timer.Stop()
+	}
+	return p, ok
+}
+
+// complete publishes the result of a transaction which holds an in-flight slot. The slot
+// is released only once the result has been delivered to the consumer.
+func (s *batchSession) complete(p *pendingTx) {
+	s.enqueue(queuedResult{result: p.result, inFlight: true})
+}
+
+func (s *batchSession) publish(result *BatchResult) {
+	s.enqueue(queuedResult{result: result})
+}
+
+// enqueue adds the result to the unbounded delivery queue so that commit tracking
+// never blocks on a slow consumer
+func (s *batchSession) enqueue(r queuedResult) {
+	s.queueMutex.Lock()
+	s.queue = append(s.queue, r)
+	s.queueMutex.Unlock()
+
+	select {
+	case s.queued <- struct{}{}:
+	default:
+	}
+}
+
+// forward delivers the queued results to the consumer in order
+func (s *batchSession) forward() {
+	for {
+		s.queueMutex.Lock()
+		if len(s.queue) == 0 {
+			s.queueMutex.Unlock()
+			select {
+			case <-s.queued:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		r := s.queue[0]
+		s.queue[0] = queuedResult{}
+		s.queue = s.queue[1:]
+		s.queueMutex.Unlock()
+
+		s.results <- r.result
+		if r.inFlight {
+			<-s.inFlight
+		}
+		s.outstanding.Done()
+	}
+}
+
+func newBatchEndorseHandler() invoke.Handler {
+	return invoke.NewSelectAndEndorseHandler(
+		invoke.NewEndorsementValidationHandler(
+			invoke.NewSignatureValidationHandler(),
+		),
+	)
+}
+
+func isConflict(code pb.TxValidationCode) bool {
+	return code == pb.TxValidationCode_MVCC_READ_CONFLICT || code == pb.TxValidationCode_PHANTOM_READ_CONFLICT
+}
@@ -0,0 +1,310 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchSubmitter(t *testing.T) {
+	broadcastCh := make(chan *fab.SignedEnvelope, 100)
+	orderer := fcmocks.NewMockOrderer("", broadcastCh)
+	defer orderer.CloseQueue()
+
+	testPeer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer.Payload = []byte("value")
+
+	chClient := setupChannelClientWithNodes([]fab.Peer{testPeer}, []fab.Orderer{orderer}, t)
+	eventService := newBlockEventService()
+	chClient.eventService = eventService
+
+	// Commit every envelope in its own block. Every third transaction is invalidated with an MVCC conflict.
+	go func() {
+		var blockNum uint64
+		for env := range broadcastCh {
+			code := pb.TxValidationCode_VALID
+			if blockNum%3 == 2 {
+				code = pb.TxValidationCode_MVCC_READ_CONFLICT
+			}
+			eventService.publish(blockNum, txIDFromEnvelope(t, env), code)
+			blockNum++
+		}
+	}()
+
+	submitter, err := NewBatchSubmitter(chClient, WithEndorsementWorkers(2), WithMaxInFlight(2))
+	require.NoError(t, err)
+
+	const numRequests = 9
+
+	requests := make(chan Request)
+	results, err := submitter.Submit(requests, WithTimeout(fab.Execute, 5*time.Second))
+	require.NoError(t, err)
+
+	go func() {
+		for i := 0; i < numRequests; i++ {
+			requests <- Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("a")}}
+		}
+		close(requests)
+	}()
+
+	var valid, conflicts int
+	for result := range results {
+		if result.Conflict {
+			conflicts++
+			assert.Error(t, result.Err)
+			assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, result.Response.TxValidationCode)
+			continue
+		}
+		assert.NoError(t, result.Err)
+		assert.Equal(t, []byte("value"), result.Response.Payload)
+		valid++
+	}
+
+	assert.Equal(t, 6, valid)
+	assert.Equal(t, 3, conflicts)
+}
+
+func TestBatchSubmitterMaxInFlight(t *testing.T) {
+	broadcastCh := make(chan *fab.SignedEnvelope, 100)
+	orderer := fcmocks.NewMockOrderer("", broadcastCh)
+	defer orderer.CloseQueue()
+
+	testPeer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer.Payload = []byte("value")
+
+	chClient := setupChannelClientWithNodes([]fab.Peer{testPeer}, []fab.Orderer{orderer}, t)
+	eventService := newBlockEventService()
+	chClient.eventService = eventService
+
+	submitter, err := NewBatchSubmitter(chClient, WithEndorsementWorkers(4), WithMaxInFlight(2))
+	require.NoError(t, err)
+
+	const numRequests = 5
+
+	requests := make(chan Request, numRequests)
+	for i := 0; i < numRequests; i++ {
+		requests <- Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("a")}}
+	}
+	close(requests)
+
+	results, err := submitter.Submit(requests, WithTimeout(fab.Execute, 5*time.Second))
+	require.NoError(t, err)
+
+	// No commits are published so only the maximum number of in-flight transactions may be broadcast
+	var envelopes []*fab.SignedEnvelope
+	for i := 0; i < 2; i++ {
+		select {
+		case env := <-broadcastCh:
+			envelopes = append(envelopes, env)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for broadcast")
+		}
+	}
+	select {
+	case <-broadcastCh:
+		t.Fatal("more transactions were broadcast than the max in-flight")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	go func() {
+		var blockNum uint64
+		for _, env := range envelopes {
+			eventService.publish(blockNum, txIDFromEnvelope(t, env), pb.TxValidationCode_VALID)
+			blockNum++
+		}
+		for env := range broadcastCh {
+			eventService.publish(blockNum, txIDFromEnvelope(t, env), pb.TxValidationCode_VALID)
+			blockNum++
+		}
+	}()
+
+	var count int
+	for result := range results {
+		assert.NoError(t, result.Err)
+		count++
+	}
+	assert.Equal(t, numRequests, count)
+}
+
+func TestBatchSubmitterSlowConsumer(t *testing.T) {
+	broadcastCh := make(chan *fab.SignedEnvelope, 100)
+	orderer := fcmocks.NewMockOrderer("", broadcastCh)
+	defer orderer.CloseQueue()
+
+	testPeer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer.Payload = []byte("value")
+
+	chClient := setupChannelClientWithNodes([]fab.Peer{testPeer}, []fab.Orderer{orderer}, t)
+	eventService := newBlockEventService()
+	chClient.eventService = eventService
+
+	go func() {
+		var blockNum uint64
+		for env := range broadcastCh {
+			eventService.publish(blockNum, txIDFromEnvelope(t, env), pb.TxValidationCode_VALID)
+			blockNum++
+		}
+	}()
+
+	submitter, err := NewBatchSubmitter(chClient, WithEndorsementWorkers(1), WithMaxInFlight(1))
+	require.NoError(t, err)
+
+	const numRequests = 5
+
+	requests := make(chan Request, numRequests)
+	for i := 0; i < numRequests; i++ {
+		requests <- Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("a")}}
+	}
+	close(requests)
+
+	commitTimeout := 300 * time.Millisecond
+	results, err := submitter.Submit(requests, WithTimeout(fab.Execute, commitTimeout))
+	require.NoError(t, err)
+
+	// Don't consume any results for longer than the commit timeout. No transaction
+	// should time out waiting for its commit while the consumer is stalled.
+	time.Sleep(3 * commitTimeout)
+
+	var count int
+	for result := range results {
+		assert.NoError(t, result.Err)
+		count++
+	}
+	assert.Equal(t, numRequests, count)
+}
+
+func TestBatchSubmitterEventsClosed(t *testing.T) {
+	broadcastCh := make(chan *fab.SignedEnvelope, 100)
+	orderer := fcmocks.NewMockOrderer("", broadcastCh)
+	defer orderer.CloseQueue()
+
+	testPeer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer.Payload = []byte("value")
+
+	chClient := setupChannelClientWithNodes([]fab.Peer{testPeer}, []fab.Orderer{orderer}, t)
+	eventService := newBlockEventService()
+	chClient.eventService = eventService
+	close(eventService.eventCh)
+
+	submitter, err := NewBatchSubmitter(chClient, WithMaxInFlight(2))
+	require.NoError(t, err)
+
+	const numRequests = 3
+
+	requests := make(chan Request, numRequests)
+	for i := 0; i < numRequests; i++ {
+		requests <- Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("a")}}
+	}
+	close(requests)
+
+	start := time.Now()
+	results, err := submitter.Submit(requests, WithTimeout(fab.Execute, 5*time.Second))
+	require.NoError(t, err)
+
+	var count int
+	for result := range results {
+		assert.Error(t, result.Err, "expected error since commits cannot be tracked")
+		count++
+	}
+	assert.Equal(t, numRequests, count)
+	assert.True(t, time.Since(start) < 5*time.Second, "expected transactions to fail before the commit timeout")
+}
+
+func TestBatchSubmitterEndorsementError(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = []byte("test1")
+	testPeer2 := fcmocks.NewMockPeer("Peer2", "http://peer2.com")
+	testPeer2.Payload = []byte("test2")
+
+	chClient := setupChannelClient([]fab.Peer{testPeer1, testPeer2}, t)
+	chClient.eventService = newBlockEventService()
+
+	submitter, err := NewBatchSubmitter(chClient)
+	require.NoError(t, err)
+
+	requests := make(chan Request, 1)
+	requests <- Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("a")}}
+	close(requests)
+
+	results, err := submitter.Submit(requests)
+	require.NoError(t, err)
+
+	result, ok := <-results
+	require.True(t, ok)
+	assert.Error(t, result.Err, "expected endorsement mismatch")
+	assert.False(t, result.Conflict)
+
+	_, ok = <-results
+	assert.False(t, ok, "expected results channel to be closed")
+}
+
+func TestBatchSubmitterOptions(t *testing.T) {
+	_, err := NewBatchSubmitter(nil)
+	assert.Error(t, err)
+
+	chClient := setupChannelClient(nil, t)
+
+	_, err = NewBatchSubmitter(chClient, WithEndorsementWorkers(0))
+	assert.Error(t, err)
+
+	_, err = NewBatchSubmitter(chClient, WithMaxInFlight(-1))
+	assert.Error(t, err)
+}
+
+// blockEventService is a mock event service that publishes filtered block events
+type blockEventService struct {
+	*fcmocks.MockEventService
+	eventCh chan *fab.FilteredBlockEvent
+}
+
+func newBlockEventService() *blockEventService {
+	return &blockEventService{
+		MockEventService: fcmocks.NewMockEventService(),
+		eventCh:          make(chan *fab.FilteredBlockEvent, 100),
+	}
+}
+
+func (s *blockEventService) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	return &dispatcher.FilteredBlockReg{Eventch: s.eventCh}, s.eventCh, nil
+}
+
+func (s *blockEventService) publish(blockNum uint64, txID string, code pb.TxValidationCode) {
+	s.eventCh <- &fab.FilteredBlockEvent{
+		FilteredBlock: &pb.FilteredBlock{
+			Number: blockNum,
+			FilteredTransactions: []*pb.FilteredTransaction{
+				{Txid: txID, TxValidationCode: code},
+			},
+		},
+	}
+}
+
+func txIDFromEnvelope(t *testing.T, env *fab.SignedEnvelope) string {
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(env.Payload, payload); err != nil {
+		t.Errorf("failed to unmarshal payload: %s", err)
+		return ""
+	}
+
+	chdr := &common.ChannelHeader{}
+	if err := proto.Unmarshal(payload.Header.ChannelHeader, chdr); err != nil {
+		t.Errorf("failed to unmarshal channel header: %s", err)
+		return ""
+	}
+
+	return chdr.TxId
+}
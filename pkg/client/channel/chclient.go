@@ -27,6 +27,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/pkg/errors"
@@ -113,6 +114,80 @@ func (cc *Client) Execute(request Request, options ...RequestOption) (Response,
 	return callExecute(cc, request, options...)
 }
 
+// executeWithConflictRetry invokes the execute handler. If a conflict retry policy was provided then
+// the request is re-submitted, with a new transaction ID, for as long as the transaction is invalidated
+// with one of the policy's retryable codes.
+func (cc *Client) executeWithConflictRetry(request Request, options ...RequestOption) (Response, error) {
+	txnOpts, err := cc.prepareOptsFromOptions(cc.context, options...)
+	if err != nil {
+		return Response{}, err
+	}
+
+	retryHandler := retry.NewBackoff(txnOpts.ConflictRetry)
+
+	var txnIDs []fab.TransactionID
+	for {
+		response, err := cc.InvokeHandler(invoke.NewExecuteHandler(), request, options...)
+		if response.TransactionID != fab.EmptyTransactionID {
+			txnIDs = append(txnIDs, response.TransactionID)
+		}
+		response.TransactionIDs = txnIDs
+
+		if err == nil || parentDone(txnOpts.ParentContext) {
+			return response, err
+		}
+
+		backoff, ok := retryHandler.Backoff(err)
+		if !ok || !waitForRetry(txnOpts.ParentContext, backoff) {
+			return response, err
+		}
+	}
+}
+
+// waitForRetry waits for the given backoff period. False is returned if the parent context
+// is cancelled (or times out) before the backoff period has elapsed.
+func waitForRetry(parentCtx reqContext.Context, backoff time.Duration) bool {
+	var done <-chan struct{}
+	if parentCtx != nil {
+		done = parentCtx.Done()
+	}
+
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// parentDone returns true if the given parent context has been cancelled or has timed out
+func parentDone(parentCtx reqContext.Context) bool {
+	if parentCtx == nil {
+		return false
+	}
+	select {
+	case <-parentCtx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// newResponse converts the response of the invoke handlers to a channel client response
+func newResponse(r invoke.Response) Response {
+	return Response{
+		Proposal:         r.Proposal,
+		Responses:        r.Responses,
+		TransactionID:    r.TransactionID,
+		TxValidationCode: r.TxValidationCode,
+		ChaincodeStatus:  r.ChaincodeStatus,
+		Payload:          r.Payload,
+	}
+}
+
 // addDefaultTargetFilter adds default target filter if target filter is not specified
 func addDefaultTargetFilter(chCtx context.Channel, ft filter.EndpointType) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
@@ -151,6 +226,12 @@ func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options
 	reqCtx, cancel := cc.createReqContext(&txnOpts)
 	defer cancel()
 
+	span, reqCtx := tracing.StartSpan(reqCtx, tracing.SpanInvoke)
+	span.SetTag(tracing.TagChannelID, cc.context.ChannelID())
+	span.SetTag(tracing.TagChaincodeID, request.ChaincodeID)
+	span.SetTag(tracing.TagFcn, request.Fcn)
+	defer span.Finish()
+
 	//Prepare context objects for handler
 	requestContext, clientContext, err := cc.prepareHandlerContexts(reqCtx, request, txnOpts)
 	if err != nil {
@@ -186,10 +267,16 @@ func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options
 	}()
 	select {
 	case <-complete:
-		return Response(requestContext.Response), requestContext.Error
+		span.SetTag(tracing.TagTxnID, string(requestContext.Response.TransactionID))
+		if requestContext.Error != nil {
+			span.SetError(requestContext.Error)
+		}
+		return newResponse(requestContext.Response), requestContext.Error
 	case <-reqCtx.Done():
-		return Response{}, status.New(status.ClientStatus, status.Timeout.ToInt32(),
+		err := status.New(status.ClientStatus, status.Timeout.ToInt32(),
 			"request timed out or been cancelled", nil)
+		span.SetError(err)
+		return Response{}, err
 	}
 }
 
@@ -262,7 +349,7 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 
 	requestContext := &invoke.RequestContext{
 		Request:         invoke.Request(request),
-		Opts:            invoke.Opts(o),
+		Opts:            o.invokeOpts(),
 		Response:        invoke.Response{},
 		RetryHandler:    retry.New(o.Retry),
 		Ctx:             reqCtx,
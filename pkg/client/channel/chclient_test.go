@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	reqContext "context"
 	"fmt"
 	"strings"
 	"testing"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
 	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
@@ -22,7 +24,9 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
@@ -383,6 +387,115 @@ func TestTransactionValidationError(t *testing.T) {
 	assert.EqualValues(t, validationCode, status.ToTransactionValidationCode(statusError.Code))
 }
 
+func TestExecuteTxWithConflictRetry(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = []byte("test")
+
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	eventService := &conflictEventService{MockEventService: fcmocks.NewMockEventService(), conflicts: 2}
+	chClient.eventService = eventService
+
+	retryOpts := retry.Opts{Attempts: 3, InitialBackoff: 10 * time.Millisecond, MaxBackoff: time.Second, BackoffFactor: 2}
+
+	response, err := chClient.Execute(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("a")}},
+		WithConflictRetry(retryOpts))
+	assert.NoError(t, err)
+	assert.Equal(t, pb.TxValidationCode_VALID, response.TxValidationCode)
+	assert.Len(t, response.TransactionIDs, 3, "expected three transactions to be submitted")
+	assert.Equal(t, response.TransactionID, response.TransactionIDs[2])
+	assert.NotEqual(t, response.TransactionIDs[0], response.TransactionIDs[1], "expected a new transaction ID for each attempt")
+
+	// Without the conflict retry option the conflict is returned
+	eventService.conflicts = 1
+	response, err = chClient.Execute(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("a")}})
+	assert.Error(t, err)
+	statusError, ok := status.FromError(err)
+	assert.True(t, ok, "Expected status error got %+v", err)
+	assert.EqualValues(t, pb.TxValidationCode_MVCC_READ_CONFLICT, status.ToTransactionValidationCode(statusError.Code))
+	assert.Len(t, response.TransactionIDs, 1)
+
+	// Retries are exhausted
+	eventService.conflicts = 3
+	response, err = chClient.Execute(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("a")}},
+		WithConflictRetry(retry.Opts{Attempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Second, BackoffFactor: 1}))
+	assert.Error(t, err)
+	assert.Len(t, response.TransactionIDs, 3)
+
+	// Retries stop once the parent context is cancelled (during the backoff after the first conflict)
+	parentCtx, cancel := reqContext.WithCancel(reqContext.Background())
+	defer cancel()
+	eventService.conflicts = 3
+	eventService.onConflict = func() {
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+		}()
+	}
+	start := time.Now()
+	response, err = chClient.Execute(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("a")}},
+		WithConflictRetry(retry.Opts{Attempts: 3, InitialBackoff: 10 * time.Second, MaxBackoff: time.Minute, BackoffFactor: 1}),
+		WithParentContext(parentCtx))
+	assert.True(t, time.Since(start) < 5*time.Second, "expected the backoff to be interrupted by the cancelled parent context")
+	assert.Error(t, err)
+	statusError, ok = status.FromError(err)
+	assert.True(t, ok, "Expected status error got %+v", err)
+	assert.EqualValues(t, pb.TxValidationCode_MVCC_READ_CONFLICT, status.ToTransactionValidationCode(statusError.Code))
+	assert.Len(t, response.TransactionIDs, 1, "expected no retries after the parent context was cancelled")
+}
+
+func TestExecuteTxTracing(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = []byte("test")
+
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+
+	tracer := tracing.NewMockTracer()
+	parentSpan, parentCtx := tracer.StartSpan(tracing.WithTracer(reqContext.Background(), tracer), "parent")
+
+	_, err := chClient.Execute(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("a")}},
+		WithParentContext(parentCtx))
+	require.NoError(t, err)
+	parentSpan.Finish()
+
+	invokeSpans := tracer.SpansByName(tracing.SpanInvoke)
+	require.Len(t, invokeSpans, 1)
+	invokeSpan := invokeSpans[0]
+	assert.Equal(t, parentSpan.(*tracing.MockSpan).ID, invokeSpan.ParentID, "expected invoke span to be a child of the parent span")
+	assert.Equal(t, "testCC", invokeSpan.Tags[tracing.TagChaincodeID])
+	assert.True(t, invokeSpan.IsFinished())
+
+	for _, name := range []string{tracing.SpanSelection, tracing.SpanValidation, tracing.SpanCommit} {
+		spans := tracer.SpansByName(name)
+		require.Len(t, spans, 1, "expected one span for %s", name)
+		assert.Equal(t, invokeSpan.ID, spans[0].ParentID)
+		assert.True(t, spans[0].IsFinished())
+		assert.NoError(t, spans[0].Err)
+	}
+}
+
+// conflictEventService invalidates the given number of transactions with an MVCC read conflict
+// and then validates all subsequent transactions
+type conflictEventService struct {
+	*fcmocks.MockEventService
+	conflicts  int
+	onConflict func()
+}
+
+func (m *conflictEventService) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	code := pb.TxValidationCode_VALID
+	if m.conflicts > 0 {
+		code = pb.TxValidationCode_MVCC_READ_CONFLICT
+		m.conflicts--
+		if m.onConflict != nil {
+			m.onConflict()
+		}
+	}
+
+	eventCh := make(chan *fab.TxStatusEvent, 1)
+	eventCh <- &fab.TxStatusEvent{TxID: txID, TxValidationCode: code}
+	return &dispatcher.TxStatusReg{Eventch: eventCh, TxID: txID}, eventCh, nil
+}
+
 func TestTransactionTimeout(t *testing.T) {
 	mockEventService := fcmocks.NewMockEventService()
 	mockEventService.Timeout = true
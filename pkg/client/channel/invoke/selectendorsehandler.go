@@ -11,6 +11,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/pkg/errors"
 
@@ -115,8 +116,15 @@ func getEndorsers(requestContext *RequestContext, clientContext *ClientContext,
 		selectionOpts = append(selectionOpts, selectopts.WithPeerSorter(requestContext.PeerSorter))
 	}
 
+	span, _ := tracing.StartSpan(requestContext.Ctx, tracing.SpanSelection)
+	defer span.Finish()
+
 	ccCalls := newInvocationChain(requestContext)
 	peers, err := clientContext.Selection.GetEndorsersForChaincode(newInvocationChain(requestContext), selectionOpts...)
+	span.SetTag(tracing.TagNumTargets, len(peers))
+	if err != nil {
+		span.SetError(err)
+	}
 	return ccCalls, peers, err
 }
 
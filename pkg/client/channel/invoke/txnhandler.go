@@ -11,6 +11,7 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/pkg/errors"
 
 	selectopts "github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
@@ -89,7 +90,10 @@ func (h *ProposalProcessorHandler) Handle(requestContext *RequestContext, client
 			selectionOpts = append(selectionOpts, selectopts.WithPeerSorter(requestContext.PeerSorter))
 		}
 
+		span, _ := tracing.StartSpan(requestContext.Ctx, tracing.SpanSelection)
 		endorsers, err := clientContext.Selection.GetEndorsersForChaincode(newInvocationChain(requestContext), selectionOpts...)
+		span.SetTag(tracing.TagNumTargets, len(endorsers))
+		tracing.Finish(span, err)
 		if err != nil {
 			requestContext.Error = errors.WithMessage(err, "Failed to get endorsing peers")
 			return
@@ -124,7 +128,9 @@ type EndorsementValidationHandler struct {
 func (f *EndorsementValidationHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
 
 	//Filter tx proposal responses
+	span, _ := tracing.StartSpan(requestContext.Ctx, tracing.SpanValidation)
 	err := f.validate(requestContext.Response.Responses)
+	tracing.Finish(span, err)
 	if err != nil {
 		requestContext.Error = errors.WithMessage(err, "endorsement validation failed")
 		return
@@ -181,18 +187,25 @@ func (c *CommitTxHandler) Handle(requestContext *RequestContext, clientContext *
 		return
 	}
 
+	span, _ := tracing.StartSpan(requestContext.Ctx, tracing.SpanCommit)
+	span.SetTag(tracing.TagTxnID, string(txnID))
+	defer span.Finish()
+
 	select {
 	case txStatus := <-statusNotifier:
 		requestContext.Response.TxValidationCode = txStatus.TxValidationCode
+		span.SetTag(tracing.TagTxnStatus, txStatus.TxValidationCode.String())
 
 		if txStatus.TxValidationCode != pb.TxValidationCode_VALID {
 			requestContext.Error = status.New(status.EventServerStatus, int32(txStatus.TxValidationCode),
 				"received invalid transaction", nil)
+			span.SetError(requestContext.Error)
 			return
 		}
 	case <-requestContext.Ctx.Done():
 		requestContext.Error = status.New(status.ClientStatus, status.Timeout.ToInt32(),
 			"Execute didn't receive block event", nil)
+		span.SetError(requestContext.Error)
 		return
 	}
 
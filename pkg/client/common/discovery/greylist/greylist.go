@@ -60,6 +60,12 @@ func (b *Filter) Greylist(err error) {
 	}
 }
 
+// GreylistURL greylists the given peer URL
+func (b *Filter) GreylistURL(peerURL string) {
+	logger.Infof("Greylisting peer %s", peerURL)
+	b.greylistURLs.Store(endpoint.ToAddress(peerURL), time.Now())
+}
+
 // required decides whether the given status error warrants a greylist
 // on the peer causing the error
 func required(s *status.Status) (bool, string) {
@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
@@ -79,6 +80,7 @@ type Service struct {
 	chResponseCache *lazycache.Cache
 	retryOpts       retry.Opts
 	errHandler      fab.ErrorHandler
+	cachedKeys      sync.Map
 }
 
 // New creates a new dynamic selection service using Fabric's Discovery Service
@@ -132,6 +134,9 @@ func New(ctx contextAPI.Client, channelID string, discovery fab.DiscoveryService
 			}
 
 			endorsers, err := s.queryEndorsers(invocationChain, ropts)
+			if err != nil {
+				s.ctx.GetMetrics().SelectionRefreshErrors.With("channel", s.channelID).Add(1)
+			}
 			if err != nil && s.errHandler != nil {
 				logger.Debugf("[%s] Got error from discovery query: %s. Invoking error handler", s.channelID, err)
 				s.errHandler(s.ctx, s.channelID, err)
@@ -200,10 +205,20 @@ func (s *Service) getEndorsers(chaincodes []*fab.ChaincodeCall, chResponse discc
 
 func (s *Service) getChannelResponse(chaincodes []*fab.ChaincodeCall, retryOpts retry.Opts) (discclient.ChannelResponse, error) {
 	key := newCacheKey(chaincodes)
+
+	if _, ok := s.cachedKeys.Load(key.String()); ok {
+		s.ctx.GetMetrics().SelectionCacheHits.With("channel", s.channelID).Add(1)
+	} else {
+		s.ctx.GetMetrics().SelectionCacheMisses.With("channel", s.channelID).Add(1)
+	}
+
 	chResp, err := s.chResponseCache.Get(key, retryOpts)
 	if err != nil {
+		s.cachedKeys.Delete(key.String())
 		return nil, err
 	}
+	s.cachedKeys.Store(key.String(), true)
+
 	return chResp.(discclient.ChannelResponse), nil
 }
 
@@ -7,28 +7,109 @@ SPDX-License-Identifier: Apache-2.0
 package staticselection
 
 import (
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection/pgresolver"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	copts "github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
 )
 
 const loggerModule = "fabsdk/client"
 
 var logger = logging.NewLogger(loggerModule)
 
-// SelectionService implements static selection service
+// Opt applies a selection service option
+type Opt func(*SelectionService)
+
+// WithChaincodePolicies sets the chaincode endorsement policies (typically from the channel's
+// endpoint config). The policies are used to choose a minimal set of peers which satisfy the
+// endorsement policies of the invoked chaincodes. Chaincode names are matched case-insensitively
+// since the config keys are lower-cased when the config is loaded.
+func WithChaincodePolicies(chaincodes map[string]fab.ChaincodeConfig) Opt {
+	return func(s *SelectionService) {
+		s.chaincodes = chaincodes
+	}
+}
+
+// WithFallback sets the selection service which is used for chaincodes that have no configured
+// endorsement policy (typically the discovery-based selection service for the channel). If no
+// fallback is set then all of the peers are returned for such chaincodes.
+func WithFallback(fallback fab.SelectionService) Opt {
+	return func(s *SelectionService) {
+		s.fallback = fallback
+	}
+}
+
+// WithLoadBalancePolicy sets the load-balance policy which chooses between the peer groups
+// that satisfy the endorsement policies (default: random)
+func WithLoadBalancePolicy(lbp pgresolver.LoadBalancePolicy) Opt {
+	return func(s *SelectionService) {
+		s.pgLBP = lbp
+	}
+}
+
+// SelectionService implements static selection service. If endorsement policies are configured for
+// the invoked chaincodes then a minimal set of peers which satisfies the policies is returned;
+// otherwise the fallback selection service is used or, if none is set, all of the peers are returned.
 type SelectionService struct {
 	discoveryService fab.DiscoveryService
+	chaincodes       map[string]fab.ChaincodeConfig
+	fallback         fab.SelectionService
+	pgLBP            pgresolver.LoadBalancePolicy
+	policyGroups     map[string]pgresolver.GroupRetriever
 }
 
 // NewService creates a static selection service
-func NewService(discovery fab.DiscoveryService) (fab.SelectionService, error) {
-	return &SelectionService{discoveryService: discovery}, nil
+func NewService(discovery fab.DiscoveryService, opts ...Opt) (fab.SelectionService, error) {
+	s := &SelectionService{
+		discoveryService: discovery,
+		policyGroups:     make(map[string]pgresolver.GroupRetriever),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.pgLBP == nil {
+		s.pgLBP = pgresolver.NewRandomLBP()
+	}
+
+	for ccID, ccConfig := range s.chaincodes {
+		if ccConfig.Policy == "" {
+			continue
+		}
+
+		sigPolicyEnv, err := cauthdsl.FromString(ccConfig.Policy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid endorsement policy for chaincode [%s]", ccID)
+		}
+
+		policyGroup, err := pgresolver.CompileSignaturePolicy(sigPolicyEnv)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "error compiling endorsement policy for chaincode [%s]", ccID)
+		}
+
+		s.policyGroups[strings.ToLower(ccID)] = policyGroup
+	}
+
+	return s, nil
 }
 
 // GetEndorsersForChaincode returns a set of endorsing peers
 func (s *SelectionService) GetEndorsersForChaincode(chaincodes []*fab.ChaincodeCall, opts ...copts.Opt) ([]fab.Peer, error) {
+	resolver, ok, err := s.getPeerGroupResolver(chaincodes)
+	if err != nil {
+		return nil, err
+	}
+	if !ok && s.fallback != nil {
+		logger.Debugf("Using fallback selection service for chaincodes %s", chaincodeIDs(chaincodes))
+		return s.fallback.GetEndorsersForChaincode(chaincodes, opts...)
+	}
+
 	params := options.NewParams(opts)
 
 	channelPeers, err := s.discoveryService.GetPeers()
@@ -65,5 +146,93 @@ func (s *SelectionService) GetEndorsersForChaincode(chaincodes []*fab.ChaincodeC
 		logger.Debugf("Available peers: [%s]", str)
 	}
 
-	return channelPeers, nil
+	if !ok {
+		return channelPeers, nil
+	}
+
+	peerGroup, err := resolver.Resolve(channelPeers)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error resolving peers for endorsement policy")
+	}
+
+	if len(peerGroup.Peers()) == 0 {
+		return nil, errors.Errorf("no set of peers satisfies the endorsement policies of chaincodes %s", chaincodeIDs(chaincodes))
+	}
+
+	return peerGroup.Peers(), nil
+}
+
+// Close closes the fallback selection service, if any
+func (s *SelectionService) Close() {
+	if c, ok := s.fallback.(closable); ok {
+		c.Close()
+	}
+}
+
+type closable interface {
+	Close()
+}
+
+// getPeerGroupResolver returns a resolver for the endorsement policies of all of the given chaincodes.
+// False is returned if an endorsement policy is not configured for any one of the chaincodes.
+func (s *SelectionService) getPeerGroupResolver(chaincodes []*fab.ChaincodeCall) (pgresolver.PeerGroupResolver, bool, error) {
+	if len(chaincodes) == 0 {
+		return nil, false, nil
+	}
+
+	var policyGroups []pgresolver.GroupRetriever
+	for _, cc := range chaincodes {
+		policyGroup, ok := s.policyGroups[strings.ToLower(cc.ID)]
+		if !ok {
+			logger.Debugf("No endorsement policy configured for chaincode [%s]", cc.ID)
+			return nil, false, nil
+		}
+		policyGroups = append(policyGroups, policyGroup)
+	}
+
+	// Perform an 'and' operation on all of the peer groups
+	aggregatePolicyGroupRetriever := func(peerRetriever pgresolver.MSPPeerRetriever) (pgresolver.GroupOfGroups, error) {
+		var groups []pgresolver.Group
+		for _, f := range policyGroups {
+			grps, err := f(peerRetriever)
+			if err != nil {
+				return nil, err
+			}
+			groups = append(groups, grps)
+		}
+		return pgresolver.NewGroupOfGroups(groups).Nof(int32(len(policyGroups)))
+	}
+
+	resolver, err := pgresolver.NewPeerGroupResolver(aggregatePolicyGroupRetriever, &minimalLBP{lbp: s.pgLBP})
+	if err != nil {
+		return nil, false, errors.WithMessagef(err, "error creating peer group resolver for chaincodes %s", chaincodeIDs(chaincodes))
+	}
+
+	return resolver, true, nil
+}
+
+// minimalLBP is a load-balance policy which only chooses from the peer groups with the fewest peers
+type minimalLBP struct {
+	lbp pgresolver.LoadBalancePolicy
+}
+
+func (p *minimalLBP) Choose(peerGroups []pgresolver.PeerGroup) pgresolver.PeerGroup {
+	var minimal []pgresolver.PeerGroup
+	for _, pg := range peerGroups {
+		switch {
+		case len(minimal) == 0 || len(pg.Peers()) == len(minimal[0].Peers()):
+			minimal = append(minimal, pg)
+		case len(pg.Peers()) < len(minimal[0].Peers()):
+			minimal = []pgresolver.PeerGroup{pg}
+		}
+	}
+	return p.lbp.Choose(minimal)
+}
+
+func chaincodeIDs(chaincodes []*fab.ChaincodeCall) []string {
+	var ids []string
+	for _, cc := range chaincodes {
+		ids = append(ids, cc.ID)
+	}
+	return ids
 }
@@ -11,6 +11,8 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	fabmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 )
@@ -53,3 +55,112 @@ func TestStaticSelection(t *testing.T) {
 		t.Fatalf("Expecting peer %s but got %s", peer2.URL(), peers[0].URL())
 	}
 }
+
+func TestPolicySelection(t *testing.T) {
+	peer1 := newMockPeer("p1", "peer1.org1.com:7051", "Org1MSP")
+	peer2 := newMockPeer("p2", "peer2.org1.com:7051", "Org1MSP")
+	peer3 := newMockPeer("p3", "peer3.org2.com:7051", "Org2MSP")
+	peer4 := newMockPeer("p4", "peer4.org3.com:7051", "Org3MSP")
+
+	selectionService, err := NewService(
+		fabmocks.NewMockDiscoveryService(nil, peer1, peer2, peer3, peer4),
+		WithChaincodePolicies(map[string]fab.ChaincodeConfig{
+			"cc1": {Policy: "AND('Org1MSP.peer','Org2MSP.peer')"},
+			"cc2": {Policy: "OR('Org2MSP.peer',AND('Org1MSP.peer','Org3MSP.peer'))"},
+			"cc3": {Policy: "OutOf(2,'Org1MSP.peer','Org2MSP.peer','Org3MSP.peer')"},
+			"cc4": {Policy: "OR('Org3MSP.member')"},
+		}),
+	)
+	require.NoError(t, err)
+
+	t.Run("AND", func(t *testing.T) {
+		peers, err := selectionService.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: "cc1"}})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"Org1MSP", "Org2MSP"}, mspIDs(peers))
+	})
+
+	t.Run("Minimal", func(t *testing.T) {
+		// Only the single peer from Org2 should be chosen since it's the smallest set of peers
+		peers, err := selectionService.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: "cc2"}})
+		require.NoError(t, err)
+		require.Len(t, peers, 1)
+		assert.Equal(t, peer3.URL(), peers[0].URL())
+	})
+
+	t.Run("OutOf", func(t *testing.T) {
+		peers, err := selectionService.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: "cc3"}})
+		require.NoError(t, err)
+		assert.Len(t, peers, 2)
+		assert.NotEqual(t, peers[0].MSPID(), peers[1].MSPID())
+	})
+
+	t.Run("Multiple chaincodes", func(t *testing.T) {
+		peers, err := selectionService.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: "cc1"}, {ID: "cc4"}})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"Org1MSP", "Org2MSP", "Org3MSP"}, mspIDs(peers))
+	})
+
+	t.Run("Case-insensitive chaincode name", func(t *testing.T) {
+		peers, err := selectionService.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: "CC1"}})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"Org1MSP", "Org2MSP"}, mspIDs(peers))
+	})
+
+	t.Run("No policy", func(t *testing.T) {
+		peers, err := selectionService.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: "cc1"}, {ID: "other"}})
+		require.NoError(t, err)
+		assert.Len(t, peers, 4)
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		fallback := fabmocks.NewMockSelectionService(nil, peer4)
+		selectionService, err := NewService(
+			fabmocks.NewMockDiscoveryService(nil, peer1, peer2, peer3, peer4),
+			WithChaincodePolicies(map[string]fab.ChaincodeConfig{
+				"cc1": {Policy: "AND('Org1MSP.peer','Org2MSP.peer')"},
+			}),
+			WithFallback(fallback),
+		)
+		require.NoError(t, err)
+
+		peers, err := selectionService.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: "cc1"}})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"Org1MSP", "Org2MSP"}, mspIDs(peers))
+
+		peers, err = selectionService.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: "cc1"}, {ID: "other"}})
+		require.NoError(t, err)
+		require.Len(t, peers, 1)
+		assert.Equal(t, peer4.URL(), peers[0].URL())
+	})
+
+	t.Run("Policy not satisfied", func(t *testing.T) {
+		_, err := selectionService.GetEndorsersForChaincode([]*fab.ChaincodeCall{{ID: "cc1"}},
+			options.WithPeerFilter(func(peer fab.Peer) bool { return peer.MSPID() != "Org2MSP" }),
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no set of peers satisfies the endorsement policies of chaincodes [cc1]")
+	})
+
+	t.Run("Invalid policy", func(t *testing.T) {
+		_, err := NewService(
+			fabmocks.NewMockDiscoveryService(nil, peer1),
+			WithChaincodePolicies(map[string]fab.ChaincodeConfig{"cc1": {Policy: "AND('Org1MSP.peer'"}}),
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid endorsement policy for chaincode [cc1]")
+	})
+}
+
+func newMockPeer(name, url, mspID string) fab.Peer {
+	peer := fabmocks.NewMockPeer(name, url)
+	peer.SetMSPID(mspID)
+	return peer
+}
+
+func mspIDs(peers []fab.Peer) []string {
+	var ids []string
+	for _, peer := range peers {
+		ids = append(ids, peer.MSPID())
+	}
+	return ids
+}
@@ -0,0 +1,216 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifier
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// BlockError is returned when a block fails verification
+type BlockError struct {
+	BlockNumber uint64
+	Reason      string
+}
+
+// Error returns the error message
+func (e *BlockError) Error() string {
+	return fmt.Sprintf("verification of block [%d] failed: %s", e.BlockNumber, e.Reason)
+}
+
+// MembershipProvider returns the channel membership (MSPs) defined by the given channel config
+type MembershipProvider func(cfg fab.ChannelCfg) (fab.ChannelMembership, error)
+
+// ConfigProvider returns the channel config contained in the given config block
+type ConfigProvider func(block *common.Block) (fab.ChannelCfg, error)
+
+// Block verifies the integrity of blocks. The data hash of each block is recomputed, the previous hash
+// is checked against the header of the previously verified block and the orderer signatures are
+// evaluated against the given block validation policy. The genesis block (block 0) is not signed by
+// the orderer and is trusted as the root of the chain, so only its data hash is checked.
+// Blocks must be verified in order. Block is not safe for concurrent use.
+type Block struct {
+	Policy policies.Policy
+	// Hasher computes the block hashes. SHA-256 is used if not set.
+	Hasher   *BlockHasher
+	previous *common.BlockHeader
+
+	// Set by NewConfigBlock in order to reload the policy from config blocks
+	cryptoSuite        core.CryptoSuite
+	configProvider     ConfigProvider
+	membershipProvider MembershipProvider
+	// trusted is set if NewConfigBlock was given a channel config, in which case the (unsigned)
+	// genesis block may not replace it
+	trusted bool
+}
+
+// NewBlock returns a block verifier which evaluates the orderer signatures against the given policy
+func NewBlock(policy policies.Policy) *Block {
+	return &Block{Policy: policy}
+}
+
+// NewConfigBlock returns a block verifier which evaluates the orderer signatures against the BlockValidation
+// policy of the given channel config. The config may be nil if verification starts at the genesis block.
+// The policy and hashing algorithm are reloaded from every config block that is verified, so each block
+// is verified against the config in effect when it was created (a config block itself is verified
+// against the config that precedes it). If a config is given then the config of the genesis block is
+// ignored since the genesis block is not signed and could otherwise replace the trusted policy.
+func NewConfigBlock(cryptoSuite core.CryptoSuite, cfg fab.ChannelCfg, configProvider ConfigProvider, membershipProvider MembershipProvider) (*Block, error) {
+	if configProvider == nil || membershipProvider == nil {
+		return nil, errors.New("config provider and membership provider are required")
+	}
+
+	v := &Block{cryptoSuite: cryptoSuite, configProvider: configProvider, membershipProvider: membershipProvider}
+	if cfg != nil {
+		if err := v.loadConfig(cfg); err != nil {
+			return nil, err
+		}
+		v.trusted = true
+	}
+	return v, nil
+}
+
+// Verify verifies the given block. A *BlockError is returned if the block fails verification.
+func (v *Block) Verify(block *common.Block) error {
+	if block == nil || block.Header == nil {
+		return &BlockError{Reason: "block header is missing"}
+	}
+
+	header := block.Header
+
+	if block.Data == nil {
+		return v.error(header, "block data is missing")
+	}
+
+	dataHash, err := v.Hasher.DataHash(block.Data)
+	if err != nil {
+		return v.error(header, err.Error())
+	}
+	if !bytes.Equal(dataHash, header.DataHash) {
+		return v.error(header, "data hash does not match block data")
+	}
+
+	if v.previous != nil {
+		if header.Number != v.previous.Number+1 {
+			return v.error(header, fmt.Sprintf("expected block number %d", v.previous.Number+1))
+		}
+		previousHash, err := v.Hasher.HeaderHash(v.previous)
+		if err != nil {
+			return v.error(header, err.Error())
+		}
+		if !bytes.Equal(previousHash, header.PreviousHash) {
+			return v.error(header, fmt.Sprintf("previous hash does not match the hash of block [%d]", v.previous.Number))
+		}
+	}
+
+	if header.Number > 0 {
+		if err := v.verifySignatures(block); err != nil {
+			return v.error(header, err.Error())
+		}
+	}
+
+	if v.configProvider != nil && protoutil.IsConfigBlock(block) && (header.Number > 0 || !v.trusted) {
+		cfg, err := v.configProvider(block)
+		if err != nil {
+			return v.error(header, fmt.Sprintf("invalid config block: %s", err))
+		}
+		if err := v.loadConfig(cfg); err != nil {
+			return v.error(header, err.Error())
+		}
+	}
+
+	v.previous = header
+
+	return nil
+}
+
+// Previous returns the header of the last verified block (or nil if no block was verified)
+func (v *Block) Previous() *common.BlockHeader {
+	return v.previous
+}
+
+// SetPrevious sets the header of the block against which the next block is chained
+func (v *Block) SetPrevious(header *common.BlockHeader) {
+	v.previous = header
+}
+
+// loadConfig loads the block validation policy and the hashing algorithm from the given channel config
+func (v *Block) loadConfig(cfg fab.ChannelCfg) error {
+	membership, err := v.membershipProvider(cfg)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create channel membership")
+	}
+
+	policy, err := NewBlockValidationPolicy(membership, cfg)
+	if err != nil {
+		return errors.WithMessage(err, "failed to load block validation policy")
+	}
+
+	v.Policy = policy
+	v.Hasher = NewBlockHasher(v.cryptoSuite, cfg.HashingAlgorithm())
+	return nil
+}
+
+func (v *Block) verifySignatures(block *common.Block) error {
+	if v.Policy == nil {
+		return errors.New("block validation policy is not set")
+	}
+
+	signatureSet, err := BlockSignatures(block)
+	if err != nil {
+		return err
+	}
+
+	if err := v.Policy.Evaluate(signatureSet); err != nil {
+		return errors.WithMessage(err, "orderer signatures do not satisfy the block validation policy")
+	}
+
+	return nil
+}
+
+func (v *Block) error(header *common.BlockHeader, reason string) error {
+	return &BlockError{BlockNumber: header.Number, Reason: reason}
+}
+
+// BlockSignatures returns the orderer signatures of the given block as a signature set
+func BlockSignatures(block *common.Block) ([]*protoutil.SignedData, error) {
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_SIGNATURES) {
+		return nil, errors.New("block signatures metadata is missing")
+	}
+
+	metadata := &common.Metadata{}
+	if err := proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES], metadata); err != nil {
+		return nil, errors.Wrap(err, "unmarshal block signatures metadata failed")
+	}
+
+	headerBytes := protoutil.BlockHeaderBytes(block.Header)
+
+	var signatureSet []*protoutil.SignedData
+	for _, signature := range metadata.Signatures {
+		signatureHeader := &common.SignatureHeader{}
+		if err := proto.Unmarshal(signature.SignatureHeader, signatureHeader); err != nil {
+			return nil, errors.Wrap(err, "unmarshal signature header failed")
+		}
+
+		signatureSet = append(signatureSet, &protoutil.SignedData{
+			Identity:  signatureHeader.Creator,
+			Data:      util.ConcatenateBytes(metadata.Value, signature.SignatureHeader, headerBytes),
+			Signature: signature.Signature,
+		})
+	}
+
+	return signatureSet, nil
+}
@@ -0,0 +1,364 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifier
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/gm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tjfoc/gmsm/sm3"
+)
+
+const (
+	ordererMSP1 = "OrdererMSP1"
+	ordererMSP2 = "OrdererMSP2"
+)
+
+var validSignature = []byte("valid")
+
+func TestBlockVerifier(t *testing.T) {
+	policy, err := NewBlockValidationPolicy(&testMembership{}, newTestChannelCfg(common.ImplicitMetaPolicy_ANY))
+	require.NoError(t, err)
+
+	v := NewBlock(policy)
+
+	block0 := newTestBlock(0, nil, ordererMSP1)
+	block1 := newTestBlock(1, block0.Header, ordererMSP2)
+	block2 := newTestBlock(2, block1.Header, ordererMSP1)
+
+	require.NoError(t, v.Verify(block0))
+	require.NoError(t, v.Verify(block1))
+	require.NoError(t, v.Verify(block2))
+	assert.Equal(t, block2.Header, v.Previous())
+
+	t.Run("Data hash", func(t *testing.T) {
+		v := NewBlock(policy)
+		block := newTestBlock(0, nil, ordererMSP1)
+		block.Data.Data = append(block.Data.Data, []byte("tx"))
+		assertBlockError(t, v.Verify(block), 0, "data hash does not match block data")
+	})
+
+	t.Run("Previous hash", func(t *testing.T) {
+		v := NewBlock(policy)
+		v.SetPrevious(block0.Header)
+		block := newTestBlock(1, block1.Header, ordererMSP1)
+		assertBlockError(t, v.Verify(block), 1, "previous hash does not match the hash of block [0]")
+	})
+
+	t.Run("Block number", func(t *testing.T) {
+		v := NewBlock(policy)
+		v.SetPrevious(block0.Header)
+		assertBlockError(t, v.Verify(block2), 2, "expected block number 1")
+	})
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		v := NewBlock(policy)
+		block := newTestBlock(1, nil, ordererMSP1)
+		setSignature(block, ordererMSP1, []byte("invalid"))
+		assertBlockError(t, v.Verify(block), 1, "orderer signatures do not satisfy the block validation policy")
+	})
+
+	t.Run("Non-orderer signature", func(t *testing.T) {
+		v := NewBlock(policy)
+		block := newTestBlock(1, nil, "PeerMSP")
+		assertBlockError(t, v.Verify(block), 1, "orderer signatures do not satisfy the block validation policy")
+	})
+
+	t.Run("Missing signatures", func(t *testing.T) {
+		v := NewBlock(policy)
+		block := newTestBlock(1, nil, ordererMSP1)
+		block.Metadata = nil
+		assertBlockError(t, v.Verify(block), 1, "block signatures metadata is missing")
+	})
+
+	t.Run("Unsigned genesis block", func(t *testing.T) {
+		v := NewBlock(policy)
+		block := newTestBlock(0, nil, ordererMSP1)
+		block.Metadata = nil
+		assert.NoError(t, v.Verify(block))
+	})
+
+	t.Run("Membership without principal validation", func(t *testing.T) {
+		policy, err := NewBlockValidationPolicy(&mocks.MockMembership{}, newTestChannelCfg(common.ImplicitMetaPolicy_ANY))
+		require.NoError(t, err)
+		block := newTestBlock(1, nil, ordererMSP1)
+		assertBlockError(t, NewBlock(policy).Verify(block), 1, "orderer signatures do not satisfy the block validation policy")
+	})
+}
+
+func TestConfigBlockVerifier(t *testing.T) {
+	membershipProvider := func(cfg fab.ChannelCfg) (fab.ChannelMembership, error) {
+		return &testMembership{}, nil
+	}
+
+	configProvider := func(block *common.Block) (fab.ChannelCfg, error) {
+		return chconfig.FromBlock("mychannel", block)
+	}
+
+	suite, err := gm.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	_, err = NewConfigBlock(suite, nil, nil, membershipProvider)
+	assert.Error(t, err, "expecting error for missing config provider")
+
+	// The orderer org changes from OrdererMSP1 to OrdererMSP2 in block 2. The config block is
+	// signed under the previous config.
+	block0 := newTestConfigBlock(0, nil, "", ordererMSP1)
+	block1 := newTestBlock(1, block0.Header, ordererMSP1)
+	block2 := newTestConfigBlock(2, block1.Header, ordererMSP1, ordererMSP2)
+	block3 := newTestBlock(3, block2.Header, ordererMSP2)
+
+	v, err := NewConfigBlock(suite, nil, configProvider, membershipProvider)
+	require.NoError(t, err)
+	for _, block := range []*common.Block{block0, block1, block2, block3} {
+		require.NoError(t, v.Verify(block))
+	}
+
+	t.Run("Signed by previous orderer org", func(t *testing.T) {
+		v, err := NewConfigBlock(suite, nil, configProvider, membershipProvider)
+		require.NoError(t, err)
+		for _, block := range []*common.Block{block0, block1, block2} {
+			require.NoError(t, v.Verify(block))
+		}
+		assertBlockError(t, v.Verify(newTestBlock(3, block2.Header, ordererMSP1)), 3, "orderer signatures do not satisfy the block validation policy")
+	})
+
+	t.Run("Signed by next orderer org", func(t *testing.T) {
+		v, err := NewConfigBlock(suite, nil, configProvider, membershipProvider)
+		require.NoError(t, err)
+		require.NoError(t, v.Verify(block0))
+		require.NoError(t, v.Verify(block1))
+		assertBlockError(t, v.Verify(newTestConfigBlock(2, block1.Header, ordererMSP2, ordererMSP2)), 2, "orderer signatures do not satisfy the block validation policy")
+	})
+
+	t.Run("Fake genesis block", func(t *testing.T) {
+		cfg, err := configProvider(block0)
+		require.NoError(t, err)
+
+		// The genesis block is not signed so its config must not replace the trusted config
+		const foreignMSP = "ForeignOrdererMSP"
+		fakeBlock0 := newTestConfigBlock(0, nil, "", foreignMSP)
+		fakeBlock1 := newTestBlock(1, fakeBlock0.Header, foreignMSP)
+
+		v, err := NewConfigBlock(suite, cfg, configProvider, membershipProvider)
+		require.NoError(t, err)
+		require.NoError(t, v.Verify(fakeBlock0))
+		assertBlockError(t, v.Verify(fakeBlock1), 1, "orderer signatures do not satisfy the block validation policy")
+
+		v, err = NewConfigBlock(suite, cfg, configProvider, membershipProvider)
+		require.NoError(t, err)
+		require.NoError(t, v.Verify(block0))
+		require.NoError(t, v.Verify(block1))
+	})
+
+	t.Run("Policy not loaded", func(t *testing.T) {
+		v, err := NewConfigBlock(suite, nil, configProvider, membershipProvider)
+		require.NoError(t, err)
+		assertBlockError(t, v.Verify(block1), 1, "block validation policy is not set")
+	})
+}
+
+func TestBlockVerifierHashingAlgorithm(t *testing.T) {
+	policy, err := NewBlockValidationPolicy(&testMembership{}, newTestChannelCfg(common.ImplicitMetaPolicy_ANY))
+	require.NoError(t, err)
+
+	suite, err := gm.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	block0 := newTestBlock(0, nil, ordererMSP1)
+	block0.Header.DataHash = sm3.Sm3Sum(bytes.Join(block0.Data.Data, nil))
+	block1 := newTestBlock(1, nil, ordererMSP1)
+	block1.Header.DataHash = sm3.Sm3Sum(bytes.Join(block1.Data.Data, nil))
+	block1.Header.PreviousHash = sm3.Sm3Sum(protoutil.BlockHeaderBytes(block0.Header))
+
+	v := NewBlock(policy)
+	v.Hasher = NewBlockHasher(suite, "SM3")
+	require.NoError(t, v.Verify(block0))
+	require.NoError(t, v.Verify(block1))
+
+	v = NewBlock(policy)
+	assertBlockError(t, v.Verify(block0), 0, "data hash does not match block data")
+
+	v = NewBlock(policy)
+	v.Hasher = NewBlockHasher(suite, "MD5")
+	assertBlockError(t, v.Verify(block0), 0, "unsupported hashing algorithm")
+}
+
+func TestBlockValidationPolicy(t *testing.T) {
+	policy, err := NewBlockValidationPolicy(&testMembership{}, newTestChannelCfg(common.ImplicitMetaPolicy_ALL))
+	require.NoError(t, err)
+
+	block := newTestBlock(1, nil, ordererMSP1)
+	assert.Error(t, NewBlock(policy).Verify(block), "expected ALL policy to require signatures from both orderer orgs")
+
+	addSignature(block, ordererMSP2, validSignature)
+	assert.NoError(t, NewBlock(policy).Verify(block))
+
+	_, err = NewBlockValidationPolicy(&testMembership{}, mocks.NewMockChannelCfg("mychannel"))
+	assert.Error(t, err)
+}
+
+func assertBlockError(t *testing.T, err error, blockNumber uint64, reason string) {
+	require.Error(t, err)
+	blockErr, ok := err.(*BlockError)
+	require.True(t, ok, "expected BlockError but got %T", err)
+	assert.Equal(t, blockNumber, blockErr.BlockNumber)
+	assert.Contains(t, blockErr.Reason, reason)
+}
+
+// testMembership accepts signatures which match validSignature
+type testMembership struct {
+	mocks.MockMembership
+}
+
+func (m *testMembership) Verify(serializedID []byte, msg []byte, sig []byte) error {
+	if !bytes.Equal(sig, validSignature) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// SatisfiesPrincipal accepts identities of the MSP of a member role principal
+func (m *testMembership) SatisfiesPrincipal(serializedID []byte, principal *mb.MSPPrincipal) error {
+	role := &mb.MSPRole{}
+	if err := proto.Unmarshal(principal.Principal, role); err != nil {
+		return err
+	}
+	sID := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, sID); err != nil {
+		return err
+	}
+	if principal.PrincipalClassification != mb.MSPPrincipal_ROLE || role.Role != mb.MSPRole_MEMBER || sID.Mspid != role.MspIdentifier {
+		return errors.New("principal not satisfied")
+	}
+	return nil
+}
+
+func newTestChannelCfg(rule common.ImplicitMetaPolicy_Rule) fab.ChannelCfg {
+	cfg := mocks.NewMockChannelCfg("mychannel")
+	cfg.MockVersions = &fab.Versions{
+		Channel: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{
+				"Orderer": {
+					Groups: map[string]*common.ConfigGroup{
+						ordererMSP1: newTestOrgGroup(ordererMSP1),
+						ordererMSP2: newTestOrgGroup(ordererMSP2),
+					},
+					Policies: map[string]*common.ConfigPolicy{
+						"BlockValidation": {
+							Policy: &common.Policy{
+								Type:  int32(common.Policy_IMPLICIT_META),
+								Value: protoutil.MarshalOrPanic(&common.ImplicitMetaPolicy{SubPolicy: "Writers", Rule: rule}),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return cfg
+}
+
+// newTestOrgGroup returns an org group whose Writers policy is satisfied by members of the given MSP
+func newTestOrgGroup(mspID string) *common.ConfigGroup {
+	return &common.ConfigGroup{
+		Policies: map[string]*common.ConfigPolicy{
+			"Writers": {
+				Policy: &common.Policy{
+					Type:  int32(common.Policy_SIGNATURE),
+					Value: protoutil.MarshalOrPanic(cauthdsl.SignedByMspMember(mspID)),
+				},
+			},
+		},
+	}
+}
+
+// newTestConfigBlock returns a config block whose orderer group contains the given orderer org. The block
+// is signed by signerMSP (unless empty).
+func newTestConfigBlock(number uint64, previous *common.BlockHeader, signerMSP string, ordererMSP string) *common.Block {
+	var previousHash []byte
+	if previous != nil {
+		previousHash = protoutil.BlockHeaderHash(previous)
+	}
+
+	ordererGroup := newTestChannelCfg(common.ImplicitMetaPolicy_ANY).Versions().Channel.Groups["Orderer"]
+	ordererGroup.Groups = map[string]*common.ConfigGroup{ordererMSP: newTestOrgGroup(ordererMSP)}
+
+	config := &common.ConfigEnvelope{
+		Config: &common.Config{
+			ChannelGroup: &common.ConfigGroup{
+				Groups: map[string]*common.ConfigGroup{"Orderer": ordererGroup},
+				Values: map[string]*common.ConfigValue{
+					"HashingAlgorithm": {Value: protoutil.MarshalOrPanic(&common.HashingAlgorithm{Name: "SHA256"})},
+				},
+			},
+		},
+	}
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader: protoutil.MarshalOrPanic(&common.ChannelHeader{Type: int32(common.HeaderType_CONFIG), ChannelId: "mychannel"}),
+		},
+		Data: protoutil.MarshalOrPanic(config),
+	}
+
+	block := protoutil.NewBlock(number, previousHash)
+	block.Data.Data = [][]byte{protoutil.MarshalOrPanic(&common.Envelope{Payload: protoutil.MarshalOrPanic(payload)})}
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+
+	if signerMSP != "" {
+		setSignature(block, signerMSP, validSignature)
+	}
+
+	return block
+}
+
+func newTestBlock(number uint64, previous *common.BlockHeader, mspID string) *common.Block {
+	var previousHash []byte
+	if previous != nil {
+		previousHash = protoutil.BlockHeaderHash(previous)
+	}
+
+	block := protoutil.NewBlock(number, previousHash)
+	block.Data.Data = [][]byte{[]byte("tx1"), []byte("tx2")}
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+
+	setSignature(block, mspID, validSignature)
+
+	return block
+}
+
+func setSignature(block *common.Block, mspID string, signature []byte) {
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = nil
+	addSignature(block, mspID, signature)
+}
+
+func addSignature(block *common.Block, mspID string, signature []byte) {
+	metadata := &common.Metadata{}
+	if err := proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES], metadata); err != nil {
+		panic(err)
+	}
+
+	creator := protoutil.MarshalOrPanic(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(mspID)})
+	metadata.Signatures = append(metadata.Signatures, &common.MetadataSignature{
+		SignatureHeader: protoutil.MarshalOrPanic(&common.SignatureHeader{Creator: creator}),
+		Signature:       signature,
+	})
+
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(metadata)
+}
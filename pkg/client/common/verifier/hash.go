@@ -0,0 +1,58 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifier
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// BlockHasher computes block data and header hashes with the hashing algorithm of the channel
+// (e.g. SM3 on GM networks). A nil BlockHasher computes SHA-256 hashes.
+type BlockHasher struct {
+	cryptoSuite core.CryptoSuite
+	algorithm   string
+}
+
+// NewBlockHasher returns a BlockHasher which computes hashes with the given channel hashing
+// algorithm using the given crypto suite
+func NewBlockHasher(cryptoSuite core.CryptoSuite, algorithm string) *BlockHasher {
+	return &BlockHasher{cryptoSuite: cryptoSuite, algorithm: algorithm}
+}
+
+// DataHash returns the hash of the given block data
+func (h *BlockHasher) DataHash(data *common.BlockData) ([]byte, error) {
+	if h == nil {
+		return protoutil.BlockDataHash(data), nil
+	}
+	return h.hash(util.ConcatenateBytes(data.Data...))
+}
+
+// HeaderHash returns the hash of the given block header
+func (h *BlockHasher) HeaderHash(header *common.BlockHeader) ([]byte, error) {
+	if h == nil {
+		return protoutil.BlockHeaderHash(header), nil
+	}
+	return h.hash(protoutil.BlockHeaderBytes(header))
+}
+
+func (h *BlockHasher) hash(msg []byte) ([]byte, error) {
+	opts, err := cryptosuite.GetHashOpts(h.algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := h.cryptoSuite.Hash(msg, opts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "hash computation failed")
+	}
+	return digest, nil
+}
@@ -0,0 +1,238 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifier
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+// PrincipalValidator is implemented by a channel membership which is able to check
+// whether an identity satisfies an MSP principal
+type PrincipalValidator interface {
+	SatisfiesPrincipal(serializedID []byte, principal *mb.MSPPrincipal) error
+}
+
+// NewBlockValidationPolicy returns the orderer BlockValidation policy of the given channel config.
+// Signatures are verified against the given channel membership.
+func NewBlockValidationPolicy(membership fab.ChannelMembership, cfg fab.ChannelCfg) (policies.Policy, error) {
+	return NewConfigPolicy(membership, cfg, policies.BlockValidation)
+}
+
+// NewConfigPolicy returns the policy at the given path (e.g. /Channel/Application/Admins) of the given
+// channel config. Signatures are verified against the given channel membership.
+func NewConfigPolicy(membership fab.ChannelMembership, cfg fab.ChannelCfg, policyPath string) (policies.Policy, error) {
+	versions := cfg.Versions()
+	if versions == nil || versions.Channel == nil {
+		return nil, errors.New("channel config group not found in channel config")
+	}
+
+	elements := strings.Split(strings.TrimPrefix(policyPath, policies.PathSeparator), policies.PathSeparator)
+	if len(elements) < 2 || elements[0] != policies.ChannelPrefix {
+		return nil, errors.Errorf("invalid policy path [%s]", policyPath)
+	}
+
+	group := versions.Channel
+	for _, name := range elements[1 : len(elements)-1] {
+		subGroup, ok := group.Groups[name]
+		if !ok {
+			return nil, errors.Errorf("config group [%s] of policy [%s] not found in channel config", name, policyPath)
+		}
+		group = subGroup
+	}
+
+	configPolicy, ok := group.Policies[elements[len(elements)-1]]
+	if !ok || configPolicy.Policy == nil {
+		return nil, errors.Errorf("policy [%s] not found in channel config", policyPath)
+	}
+
+	return newPolicy(membership, group, configPolicy.Policy)
+}
+
+func newPolicy(membership fab.ChannelMembership, group *common.ConfigGroup, policy *common.Policy) (policies.Policy, error) {
+	switch common.Policy_PolicyType(policy.Type) {
+	case common.Policy_SIGNATURE:
+		envelope := &common.SignaturePolicyEnvelope{}
+		if err := proto.Unmarshal(policy.Value, envelope); err != nil {
+			return nil, errors.Wrap(err, "unmarshal signature policy envelope failed")
+		}
+		return &signaturePolicy{membership: membership, envelope: envelope}, nil
+
+	case common.Policy_IMPLICIT_META:
+		implicitMeta := &common.ImplicitMetaPolicy{}
+		if err := proto.Unmarshal(policy.Value, implicitMeta); err != nil {
+			return nil, errors.Wrap(err, "unmarshal implicit meta policy failed")
+		}
+		return newImplicitMetaPolicy(membership, group, implicitMeta)
+
+	default:
+		return nil, errors.Errorf("unsupported policy type: %s", common.Policy_PolicyType(policy.Type))
+	}
+}
+
+// implicitMetaPolicy is satisfied when the required number of sub-policies
+// (one per sub-group, e.g. one per orderer org) are satisfied
+type implicitMetaPolicy struct {
+	subPolicyName string
+	subPolicies   []policies.Policy
+	threshold     int
+}
+
+func newImplicitMetaPolicy(membership fab.ChannelMembership, group *common.ConfigGroup, implicitMeta *common.ImplicitMetaPolicy) (*implicitMetaPolicy, error) {
+	var subPolicies []policies.Policy
+	for name, subGroup := range group.Groups {
+		configPolicy, ok := subGroup.Policies[implicitMeta.SubPolicy]
+		if !ok || configPolicy.Policy == nil {
+			logger.Debugf("sub-policy [%s] not found in group [%s]", implicitMeta.SubPolicy, name)
+			continue
+		}
+
+		subPolicy, err := newPolicy(membership, subGroup, configPolicy.Policy)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to create sub-policy [%s] for group [%s]", implicitMeta.SubPolicy, name)
+		}
+		subPolicies = append(subPolicies, subPolicy)
+	}
+
+	var threshold int
+	switch implicitMeta.Rule {
+	case common.ImplicitMetaPolicy_ANY:
+		threshold = 1
+	case common.ImplicitMetaPolicy_ALL:
+		threshold = len(subPolicies)
+	case common.ImplicitMetaPolicy_MAJORITY:
+		threshold = len(subPolicies)/2 + 1
+	default:
+		return nil, errors.Errorf("unsupported implicit meta policy rule: %s", implicitMeta.Rule)
+	}
+
+	return &implicitMetaPolicy{
+		subPolicyName: implicitMeta.SubPolicy,
+		subPolicies:   subPolicies,
+		threshold:     threshold,
+	}, nil
+}
+
+// Evaluate evaluates the given signature set against the sub-policies
+func (p *implicitMetaPolicy) Evaluate(signatureSet []*protoutil.SignedData) error {
+	satisfied := 0
+	for _, subPolicy := range p.subPolicies {
+		if err := subPolicy.Evaluate(signatureSet); err != nil {
+			logger.Debugf("sub-policy [%s] not satisfied: %s", p.subPolicyName, err)
+			continue
+		}
+		satisfied++
+		if satisfied >= p.threshold {
+			return nil
+		}
+	}
+
+	if satisfied >= p.threshold {
+		return nil
+	}
+	return errors.Errorf("implicit policy evaluation failed - %d sub-policies were satisfied, but this policy requires %d of the '%s' sub-policies to be satisfied", satisfied, p.threshold, p.subPolicyName)
+}
+
+// signaturePolicy evaluates a signature policy envelope (as in cauthdsl)
+type signaturePolicy struct {
+	membership fab.ChannelMembership
+	envelope   *common.SignaturePolicyEnvelope
+}
+
+// Evaluate evaluates the given signature set against the signature policy
+func (p *signaturePolicy) Evaluate(signatureSet []*protoutil.SignedData) error {
+	identities := p.verifiedIdentities(signatureSet)
+	used := make([]bool, len(identities))
+	if !p.evaluate(p.envelope.Rule, identities, used) {
+		return errors.New("signature set did not satisfy policy")
+	}
+	return nil
+}
+
+// verifiedIdentities returns the distinct identities of the signature set whose signatures are valid
+func (p *signaturePolicy) verifiedIdentities(signatureSet []*protoutil.SignedData) [][]byte {
+	var identities [][]byte
+	for _, sd := range signatureSet {
+		if containsIdentity(identities, sd.Identity) {
+			logger.Debugf("de-duplicating identity in signature set")
+			continue
+		}
+		if err := p.membership.Verify(sd.Identity, sd.Data, sd.Signature); err != nil {
+			logger.Debugf("signature for identity is invalid: %s", err)
+			continue
+		}
+		identities = append(identities, sd.Identity)
+	}
+	return identities
+}
+
+func (p *signaturePolicy) evaluate(rule *common.SignaturePolicy, identities [][]byte, used []bool) bool {
+	switch t := rule.Type.(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(p.envelope.Identities) {
+			logger.Debugf("identity index out of range: %d", t.SignedBy)
+			return false
+		}
+		principal := p.envelope.Identities[t.SignedBy]
+		for i, identity := range identities {
+			if used[i] {
+				continue
+			}
+			if err := p.satisfiesPrincipal(identity, principal); err == nil {
+				used[i] = true
+				return true
+			}
+		}
+		return false
+
+	case *common.SignaturePolicy_NOutOf_:
+		verified := int32(0)
+		tentativelyUsed := make([]bool, len(used))
+		copy(tentativelyUsed, used)
+		for _, subRule := range t.NOutOf.Rules {
+			if p.evaluate(subRule, identities, tentativelyUsed) {
+				verified++
+			}
+		}
+		if verified < t.NOutOf.N {
+			return false
+		}
+		copy(used, tentativelyUsed)
+		return true
+
+	default:
+		logger.Debugf("unknown signature policy type: %T", rule.Type)
+		return false
+	}
+}
+
+// satisfiesPrincipal uses the membership to check the principal. The principal is not satisfied if the
+// membership isn't able to check principals.
+func (p *signaturePolicy) satisfiesPrincipal(identity []byte, principal *mb.MSPPrincipal) error {
+	validator, ok := p.membership.(PrincipalValidator)
+	if !ok {
+		return errors.New("channel membership does not support principal validation")
+	}
+	return validator.SatisfiesPrincipal(identity, principal)
+}
+
+func containsIdentity(identities [][]byte, identity []byte) bool {
+	for _, id := range identities {
+		if bytes.Equal(id, identity) {
+			return true
+		}
+	}
+	return false
+}
@@ -12,11 +12,13 @@ import (
 	//"crypto/x509"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
 	"github.com/pkg/errors"
 )
 
@@ -65,6 +67,33 @@ func (v *Signature) Match(response []*fab.TransactionProposalResponse) error {
 	return nil
 }
 
+// VerifySignature verifies that the signature over the message was produced by the given serialized
+// identity and that the identity was issued by one of the MSPs of the channel
+func VerifySignature(membership fab.ChannelMembership, serializedID []byte, msg []byte, sig []byte) error {
+	if membership == nil {
+		return errors.New("channel membership is required")
+	}
+
+	sID := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, sID); err != nil {
+		return errors.Wrap(err, "unmarshal serialized identity failed")
+	}
+
+	if !membership.ContainsMSP(sID.Mspid) {
+		return errors.Errorf("MSP [%s] is not a member of the channel", sID.Mspid)
+	}
+
+	if err := membership.Validate(serializedID); err != nil {
+		return errors.WithMessagef(err, "identity of MSP [%s] is not valid", sID.Mspid)
+	}
+
+	if err := membership.Verify(serializedID, msg, sig); err != nil {
+		return errors.WithMessagef(err, "signature verification failed for identity of MSP [%s]", sID.Mspid)
+	}
+
+	return nil
+}
+
 //ValidateCertificateDates used to verify if certificate was expired or not valid until later date
 func ValidateCertificateDates(cert *sm2.Certificate) error {
 	if cert == nil {
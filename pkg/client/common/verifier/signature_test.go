@@ -0,0 +1,36 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifier
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySignature(t *testing.T) {
+	serializedID := protoutil.MarshalOrPanic(&mb.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("cert")})
+	msg := []byte("message")
+
+	assert.NoError(t, VerifySignature(&testMembership{}, serializedID, msg, validSignature))
+
+	err := VerifySignature(&testMembership{}, serializedID, msg, []byte("invalid"))
+	assert.EqualError(t, err, "signature verification failed for identity of MSP [Org1MSP]: invalid signature")
+
+	err = VerifySignature(&testMembership{MockMembership: mocks.MockMembership{ValidateErr: errors.New("expired")}}, serializedID, msg, validSignature)
+	assert.EqualError(t, err, "identity of MSP [Org1MSP] is not valid: expired")
+
+	err = VerifySignature(mocks.NewMockMembershipWithMSPFilter([]string{"Org1MSP"}), serializedID, msg, validSignature)
+	assert.EqualError(t, err, "MSP [Org1MSP] is not a member of the channel")
+
+	assert.Error(t, VerifySignature(&testMembership{}, []byte("invalid"), msg, validSignature))
+	assert.EqualError(t, VerifySignature(nil, serializedID, msg, validSignature), "channel membership is required")
+}
@@ -0,0 +1,416 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package discovery enables queries against Fabric's discovery service. Discovery client supports the following
+// queries: Peers (the peers joined to a channel along with their ledger height and installed chaincodes),
+// LocalPeers (the peers known to the local MSP), Config (the MSPs and orderer endpoints of a channel) and
+// EndorsersFor (the endorsement layouts for a set of chaincodes on a channel).
+//
+// Each query is sent to a set of target peers (by default, the configured peers of the channel) and the first
+// successful response is used. The targets may be overridden per request with the WithTargetEndpoints option.
+//
+//  Basic Flow:
+//  1) Prepare client context
+//  2) Create discovery client
+//  3) Query discovery service
+package discovery
+
+import (
+	reqContext "context"
+	"fmt"
+	"strings"
+	"time"
+
+	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/random"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+	grpcCodes "google.golang.org/grpc/codes"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+var retryableCodes = map[status.Group][]status.Code{
+	status.GRPCTransportStatus: {
+		status.Code(grpcCodes.Unavailable),
+	},
+	status.DiscoveryServerStatus: {
+		status.QueryEndorsers,
+	},
+}
+
+var defaultRetryOpts = retry.Opts{
+	Attempts:       6,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	BackoffFactor:  1.75,
+	RetryableCodes: retryableCodes,
+}
+
+// discoveryClient is the client to the discovery service
+type discoveryClient interface {
+	Send(ctx reqContext.Context, req *discclient.Request, targets ...fab.PeerConfig) ([]fabdiscovery.Response, error)
+}
+
+// clientProvider is overridden by unit tests
+var clientProvider = func(ctx context.Client) (discoveryClient, error) {
+	return fabdiscovery.New(ctx)
+}
+
+// layoutsProvider is implemented by channel responses which expose the endorsement layouts
+type layoutsProvider interface {
+	Layouts(invocationChain discclient.InvocationChain) ([]map[string]int, map[string][]*discclient.Peer, error)
+}
+
+// Client enables queries against Fabric's discovery service.
+type Client struct {
+	ctx        context.Client
+	discClient discoveryClient
+}
+
+// Peer contains the information returned by the discovery service about a peer
+type Peer struct {
+	MSPID    string
+	Endpoint string
+	Identity []byte
+	// LedgerHeight is the height of the peer's ledger on the channel (not provided by LocalPeers)
+	LedgerHeight uint64
+	// Chaincodes are the chaincodes installed on the peer for the channel (not provided by LocalPeers)
+	Chaincodes []*Chaincode
+}
+
+// Chaincode contains the name and version of a chaincode installed on a peer
+type Chaincode struct {
+	Name    string
+	Version string
+}
+
+// ChannelConfig contains the MSP configuration and orderer endpoints of a channel
+type ChannelConfig struct {
+	// MSPs contains the MSP configuration by MSP ID
+	MSPs map[string]*msp.FabricMSPConfig
+	// Orderers contains the orderer endpoints by MSP ID
+	Orderers map[string][]*Endpoint
+}
+
+// Endpoint is a host and port
+type Endpoint struct {
+	Host string
+	Port uint32
+}
+
+// EndorsementLayouts contains the endorsement layouts for an invocation chain. Each layout maps
+// a group to the number of endorsers required from the group. The endorsement policy is satisfied
+// by any one of the layouts.
+type EndorsementLayouts struct {
+	Layouts          []map[string]int
+	EndorsersByGroup map[string][]*Peer
+}
+
+// New returns a discovery client instance.
+func New(ctxProvider context.ClientProvider) (*Client, error) {
+	ctx, err := ctxProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create client context")
+	}
+
+	discClient, err := clientProvider(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create discovery client")
+	}
+
+	return &Client{
+		ctx:        ctx,
+		discClient: discClient,
+	}, nil
+}
+
+// Peers queries the discovery service for the peers joined to the given channel.
+//  Parameters:
+//  channelID is the channel
+//  options are optional request options
+//
+//  Returns:
+//  the peers of the channel along with their ledger height and installed chaincodes
+func (c *Client) Peers(channelID string, options ...RequestOption) ([]*Peer, error) {
+	req := discclient.NewRequest().OfChannel(channelID).AddPeersQuery()
+
+	resp, err := c.query(req, c.channelTargets(channelID), options,
+		func(response fabdiscovery.Response) (interface{}, error) {
+			return response.ForChannel(channelID).Peers()
+		},
+	)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Peers failed for channel [%s]", channelID)
+	}
+
+	return asPeers(resp.([]*discclient.Peer)), nil
+}
+
+// LocalPeers queries the discovery service for the peers in the local MSP. (Note that the discovery service
+// only responds to local peer queries from administrators.)
+//  Parameters:
+//  options are optional request options
+//
+//  Returns:
+//  the peers of the local MSP
+func (c *Client) LocalPeers(options ...RequestOption) ([]*Peer, error) {
+	req := discclient.NewRequest().AddLocalPeersQuery()
+
+	resp, err := c.query(req, c.localTargets, options,
+		func(response fabdiscovery.Response) (interface{}, error) {
+			return response.ForLocal().Peers()
+		},
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "LocalPeers failed")
+	}
+
+	return asPeers(resp.([]*discclient.Peer)), nil
+}
+
+// Config queries the discovery service for the configuration of the given channel.
+//  Parameters:
+//  channelID is the channel
+//  options are optional request options
+//
+//  Returns:
+//  the MSPs and orderer endpoints of the channel
+func (c *Client) Config(channelID string, options ...RequestOption) (*ChannelConfig, error) {
+	req := discclient.NewRequest().OfChannel(channelID).AddConfigQuery()
+
+	resp, err := c.query(req, c.channelTargets(channelID), options,
+		func(response fabdiscovery.Response) (interface{}, error) {
+			return response.ForChannel(channelID).Config()
+		},
+	)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "Config failed for channel [%s]", channelID)
+	}
+
+	return asChannelConfig(resp.(*discovery.ConfigResult)), nil
+}
+
+// EndorsersFor queries the discovery service for the endorsement layouts of the given chaincode invocation chain
+// (for example, a chaincode which invokes another chaincode) on the given channel.
+//  Parameters:
+//  channelID is the channel
+//  chaincodes is the invocation chain
+//  options are optional request options
+//
+//  Returns:
+//  the endorsement layouts and the endorsers of each group
+func (c *Client) EndorsersFor(channelID string, chaincodes []*fab.ChaincodeCall, options ...RequestOption) (*EndorsementLayouts, error) {
+	if len(chaincodes) == 0 {
+		return nil, errors.New("no chaincodes provided")
+	}
+
+	invocationChain := asInvocationChain(chaincodes)
+
+	req, err := discclient.NewRequest().OfChannel(channelID).AddEndorsersQuery(&discovery.ChaincodeInterest{Chaincodes: invocationChain})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating endorser query request")
+	}
+
+	resp, err := c.query(req, c.channelTargets(channelID), options,
+		func(response fabdiscovery.Response) (interface{}, error) {
+			provider, ok := response.ForChannel(channelID).(layoutsProvider)
+			if !ok {
+				return nil, errors.New("discovery response does not provide endorsement layouts")
+			}
+
+			layouts, endorsersByGroup, err := provider.Layouts(invocationChain)
+			if err != nil {
+				return nil, err
+			}
+
+			return asEndorsementLayouts(layouts, endorsersByGroup), nil
+		},
+	)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "EndorsersFor failed for channel [%s]", channelID)
+	}
+
+	return resp.(*EndorsementLayouts), nil
+}
+
+type evaluator func(response fabdiscovery.Response) (interface{}, error)
+
+// query sends the request to the targets and returns the result of the first response which is successfully
+// evaluated. The query is retried if all of the responses contain transient errors.
+func (c *Client) query(req *discclient.Request, defaultTargets func() ([]fab.PeerConfig, error), options []RequestOption, evaluate evaluator) (interface{}, error) {
+	opts, err := c.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := opts.Targets
+	if len(targets) == 0 {
+		targets, err = defaultTargets()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return retry.NewInvoker(retry.New(opts.Retry)).Invoke(
+		func() (interface{}, error) {
+			return c.send(req, targets, opts, evaluate)
+		},
+	)
+}
+
+func (c *Client) send(req *discclient.Request, targets []fab.PeerConfig, opts *requestOptions, evaluate evaluator) (interface{}, error) {
+	reqCtx, cancel := contextImpl.NewRequest(c.ctx, contextImpl.WithTimeout(opts.Timeout), contextImpl.WithParent(opts.ParentContext))
+	defer cancel()
+
+	responses, err := c.discClient.Send(reqCtx, req, targets...)
+	if err != nil {
+		if len(responses) == 0 {
+			return nil, errors.Wrapf(err, "error calling discover service send")
+		}
+		logger.Warnf("Received %d response(s) and one or more errors from discovery client: %s", len(responses), err)
+	}
+
+	if len(responses) == 0 {
+		return nil, errors.New("no successful response received from any peer")
+	}
+
+	// TODO: validate the signatures in the responses.
+	// For now just pick the first successful response
+
+	var errs []error
+	transient := false
+	for _, response := range responses {
+		logger.Debugf("Checking response from [%s]...", response.Target())
+		result, err := evaluate(response)
+		if err != nil {
+			logger.Debugf("... got error response from [%s]: %s", response.Target(), err)
+			transient = transient || isTransient(err)
+			errs = append(errs, errors.WithMessage(err, "From target: "+response.Target()))
+			continue
+		}
+		logger.Debugf("... got success response from [%s]", response.Target())
+		return result, nil
+	}
+
+	if transient {
+		errMsg := fmt.Sprintf("error received from Discovery Server: %s", multi.New(errs...))
+		return nil, status.New(status.DiscoveryServerStatus, int32(status.QueryEndorsers), errMsg, []interface{}{})
+	}
+
+	return nil, multi.New(errs...)
+}
+
+func (c *Client) channelTargets(channelID string) func() ([]fab.PeerConfig, error) {
+	return func() ([]fab.PeerConfig, error) {
+		chPeers := c.ctx.EndpointConfig().ChannelPeers(channelID)
+		if len(chPeers) == 0 {
+			return nil, errors.Errorf("no channel peers configured for channel [%s]", channelID)
+		}
+
+		chConfig := c.ctx.EndpointConfig().ChannelConfig(channelID)
+
+		//pick number of peers given in channel policy
+		return random.PickRandomNPeerConfigs(chPeers, chConfig.Policies.Discovery.MaxTargets), nil
+	}
+}
+
+func (c *Client) localTargets() ([]fab.PeerConfig, error) {
+	mspID := c.ctx.Identifier().MSPID
+
+	// Need to go to a peer with the local MSPID, otherwise the request will be rejected
+	var targets []fab.PeerConfig
+	for _, p := range c.ctx.EndpointConfig().NetworkPeers() {
+		if p.MSPID == mspID {
+			targets = append(targets, p.PeerConfig)
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, errors.Errorf("no peers configured for MSP [%s]", mspID)
+	}
+	return targets, nil
+}
+
+func isTransient(err error) bool {
+	return strings.Contains(err.Error(), "failed constructing descriptor for chaincodes") ||
+		strings.Contains(err.Error(), "no endorsement combination can be satisfied")
+}
+
+func asInvocationChain(chaincodes []*fab.ChaincodeCall) discclient.InvocationChain {
+	var invocChain discclient.InvocationChain
+	for _, cc := range chaincodes {
+		invocChain = append(invocChain, &discovery.ChaincodeCall{
+			Name:            cc.ID,
+			CollectionNames: cc.Collections,
+		})
+	}
+	return invocChain
+}
+
+func asPeers(endpoints []*discclient.Peer) []*Peer {
+	var peers []*Peer
+	for _, endpoint := range endpoints {
+		peers = append(peers, asPeer(endpoint))
+	}
+	return peers
+}
+
+func asPeer(endpoint *discclient.Peer) *Peer {
+	peer := &Peer{
+		MSPID:    endpoint.MSPID,
+		Identity: endpoint.Identity,
+	}
+
+	if endpoint.AliveMessage != nil {
+		peer.Endpoint = endpoint.AliveMessage.GetAliveMsg().GetMembership().GetEndpoint()
+	}
+
+	if endpoint.StateInfoMessage != nil {
+		properties := endpoint.StateInfoMessage.GetStateInfo().GetProperties()
+		peer.LedgerHeight = properties.GetLedgerHeight()
+		for _, cc := range properties.GetChaincodes() {
+			peer.Chaincodes = append(peer.Chaincodes, &Chaincode{Name: cc.Name, Version: cc.Version})
+		}
+	}
+
+	return peer
+}
+
+func asChannelConfig(config *discovery.ConfigResult) *ChannelConfig {
+	orderers := make(map[string][]*Endpoint)
+	for mspID, endpoints := range config.Orderers {
+		for _, endpoint := range endpoints.Endpoint {
+			orderers[mspID] = append(orderers[mspID], &Endpoint{Host: endpoint.Host, Port: endpoint.Port})
+		}
+	}
+
+	return &ChannelConfig{
+		MSPs:     config.Msps,
+		Orderers: orderers,
+	}
+}
+
+func asEndorsementLayouts(layouts []map[string]int, endorsersByGroup map[string][]*discclient.Peer) *EndorsementLayouts {
+	peersByGroup := make(map[string][]*Peer)
+	for grp, endorsers := range endorsersByGroup {
+		peersByGroup[grp] = asPeers(endorsers)
+	}
+
+	return &EndorsementLayouts{
+		Layouts:          layouts,
+		EndorsersByGroup: peersByGroup,
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	discmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+const (
+	channelID        = "mychannel"
+	peerAddress      = "localhost:9983"
+	emptyPeerAddress = "localhost:9984"
+	peer2Address     = "peer2.org2.com:7051"
+	ccID             = "mycc"
+)
+
+var (
+	peer1 = &discmocks.MockDiscoveryPeerEndpoint{
+		MSPID:        "Org1MSP",
+		Endpoint:     peerAddress,
+		LedgerHeight: 26,
+		Chaincodes:   []*gossip.Chaincode{{Name: ccID, Version: "v1"}},
+	}
+	peer2 = &discmocks.MockDiscoveryPeerEndpoint{
+		MSPID:        "Org2MSP",
+		Endpoint:     peer2Address,
+		LedgerHeight: 25,
+		Chaincodes:   []*gossip.Chaincode{{Name: ccID, Version: "v1"}},
+	}
+)
+
+func TestPeers(t *testing.T) {
+	client := newClient(t, peerAddress)
+
+	peers, err := client.Peers(channelID, WithTargetEndpoints(peerAddress))
+	require.NoError(t, err)
+	require.Len(t, peers, 2)
+
+	peersByMSP := make(map[string]*Peer)
+	for _, p := range peers {
+		peersByMSP[p.MSPID] = p
+	}
+
+	p := peersByMSP["Org2MSP"]
+	require.NotNil(t, p)
+	assert.Equal(t, peer2Address, p.Endpoint)
+	assert.Equal(t, uint64(25), p.LedgerHeight)
+	require.Len(t, p.Chaincodes, 1)
+	assert.Equal(t, &Chaincode{Name: ccID, Version: "v1"}, p.Chaincodes[0])
+
+	_, err = client.Peers("noChannelPeers")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no channel peers configured for channel [noChannelPeers]")
+}
+
+func TestLocalPeers(t *testing.T) {
+	// The local peers are queried from the configured peers of the local MSP
+	client := newClient(t, peerAddress)
+
+	peers, err := client.LocalPeers()
+	require.NoError(t, err)
+	require.Len(t, peers, 1)
+	assert.Equal(t, "Org1MSP", peers[0].MSPID)
+	assert.Equal(t, peerAddress, peers[0].Endpoint)
+}
+
+func TestConfig(t *testing.T) {
+	client := newClient(t, peerAddress)
+
+	config, err := client.Config(channelID, WithTargetEndpoints(peerAddress))
+	require.NoError(t, err)
+	require.Contains(t, config.MSPs, "Org1MSP")
+	assert.Equal(t, "Org1MSP", config.MSPs["Org1MSP"].Name)
+	assert.Equal(t, []*Endpoint{{Host: "orderer.example.com", Port: 7050}}, config.Orderers["OrdererMSP"])
+}
+
+func TestEndorsersFor(t *testing.T) {
+	client := newClient(t, peerAddress)
+
+	_, err := client.EndorsersFor(channelID, nil)
+	assert.EqualError(t, err, "no chaincodes provided")
+
+	layouts, err := client.EndorsersFor(channelID, []*fab.ChaincodeCall{{ID: ccID}}, WithTargetEndpoints(peerAddress))
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]int{{"G1": 1, "G2": 1}, {"G1": 2}}, layouts.Layouts)
+	require.Len(t, layouts.EndorsersByGroup["G1"], 1)
+	assert.Equal(t, peerAddress, layouts.EndorsersByGroup["G1"][0].Endpoint)
+	require.Len(t, layouts.EndorsersByGroup["G2"], 1)
+	assert.Equal(t, "Org2MSP", layouts.EndorsersByGroup["G2"][0].MSPID)
+}
+
+func TestErrorResponse(t *testing.T) {
+	client := newClient(t, emptyPeerAddress)
+
+	_, err := client.Config(channelID, WithTargetEndpoints(emptyPeerAddress))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not implemented")
+
+	_, err = client.EndorsersFor(channelID, []*fab.ChaincodeCall{{ID: ccID}}, WithTargetEndpoints(emptyPeerAddress), WithRetry(retry.Opts{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not implemented")
+
+	_, err = client.Peers(channelID, WithTargetEndpoints("invalid"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "peer [invalid] not found")
+}
+
+func TestMain(m *testing.M) {
+	startServer(peerAddress, discmocks.NewServer(
+		discmocks.WithLocalPeers(peer1),
+		discmocks.WithPeers(peer1, peer2),
+		discmocks.WithConfig(&discovery.ConfigResult{
+			Msps: map[string]*msp.FabricMSPConfig{
+				"Org1MSP": {Name: "Org1MSP"},
+				"Org2MSP": {Name: "Org2MSP"},
+			},
+			Orderers: map[string]*discovery.Endpoints{
+				"OrdererMSP": {Endpoint: []*discovery.Endpoint{{Host: "orderer.example.com", Port: 7050}}},
+			},
+		}),
+		discmocks.WithEndorsers(
+			map[string][]*discmocks.MockDiscoveryPeerEndpoint{
+				"G1": {peer1},
+				"G2": {peer2},
+			},
+			map[string]uint32{"G1": 1, "G2": 1},
+			map[string]uint32{"G1": 2},
+		),
+	))
+	startServer(emptyPeerAddress, discmocks.NewServer())
+
+	time.Sleep(time.Second)
+	os.Exit(m.Run())
+}
+
+func startServer(address string, server discovery.DiscoveryServer) {
+	grpcServer := grpc.NewServer()
+
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		panic(fmt.Sprintf("Error starting discovery listener %s", err))
+	}
+
+	discovery.RegisterDiscoveryServer(grpcServer, server)
+
+	go grpcServer.Serve(lis)
+}
+
+func newClient(t *testing.T, address string) *Client {
+	peerConfig := fab.PeerConfig{
+		URL:         address,
+		GRPCOptions: map[string]interface{}{"allow-insecure": true},
+	}
+
+	config := &mocks.MockConfig{}
+	config.SetCustomPeerCfg(&peerConfig)
+	config.SetCustomNetworkPeerCfg([]fab.NetworkPeer{{PeerConfig: peerConfig, MSPID: "Org1MSP"}})
+
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP"))
+	ctx.SetCustomInfraProvider(comm.NewMockInfraProvider())
+	ctx.SetEndpointConfig(config)
+
+	client, err := New(func() (context.Client, error) { return ctx, nil })
+	require.NoError(t, err)
+	return client
+}
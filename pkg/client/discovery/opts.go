@@ -0,0 +1,89 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	reqContext "context"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/pkg/errors"
+)
+
+// RequestOption func for each requestOptions argument
+type RequestOption func(ctx context.Client, opts *requestOptions) error
+
+// requestOptions contains options for operations performed by the discovery client
+type requestOptions struct {
+	Targets       []fab.PeerConfig   // target peers
+	Timeout       time.Duration      // response timeout for discovery queries
+	Retry         retry.Opts         // retry options for discovery queries
+	ParentContext reqContext.Context // parent grpc context for discovery queries
+}
+
+// WithTargetEndpoints allows overriding of the peers to which discovery queries are sent.
+// Targets are specified by name or URL. The discovery service of each of the targets is queried
+// and the first successful response is used.
+func WithTargetEndpoints(keys ...string) RequestOption {
+	return func(ctx context.Client, opts *requestOptions) error {
+		var targets []fab.PeerConfig
+		for _, key := range keys {
+			peerCfg, err := comm.NetworkPeerConfig(ctx.EndpointConfig(), key)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, peerCfg.PeerConfig)
+		}
+
+		opts.Targets = targets
+		return nil
+	}
+}
+
+// WithTimeout sets the response timeout for discovery queries.
+// The default is the discovery response timeout in the endpoint config.
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.Timeout = timeout
+		return nil
+	}
+}
+
+// WithRetry sets the retry options for discovery queries. Queries are retried on transient errors,
+// for example if the endorsement policy of a chaincode can't (yet) be satisfied by the peers.
+func WithRetry(opts retry.Opts) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.Retry = opts
+		return nil
+	}
+}
+
+// WithParentContext encapsulates grpc parent context
+func WithParentContext(parentContext reqContext.Context) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.ParentContext = parentContext
+		return nil
+	}
+}
+
+func (c *Client) prepareRequestOpts(options ...RequestOption) (*requestOptions, error) {
+	opts := &requestOptions{Retry: defaultRetryOpts}
+	for _, option := range options {
+		if err := option(c.ctx, opts); err != nil {
+			return nil, errors.WithMessage(err, "failed to read opts")
+		}
+	}
+
+	if opts.Timeout == 0 {
+		opts.Timeout = c.ctx.EndpointConfig().Timeout(fab.DiscoveryResponse)
+	}
+
+	return opts, nil
+}
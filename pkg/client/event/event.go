@@ -28,6 +28,7 @@ import (
 type Client struct {
 	eventService      fab.EventService
 	permitBlockEvents bool
+	verifyBlocks      bool
 	fromBlock         uint64
 	seekType          seek.Type
 }
@@ -64,6 +65,9 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 				opts = append(opts, deliverclient.WithBlockNum(eventClient.fromBlock))
 			}
 		}
+		if eventClient.verifyBlocks {
+			opts = append(opts, deliverclient.WithBlockVerification())
+		}
 		es, err = channelContext.ChannelService().EventService(opts...)
 	} else {
 		es, err = channelContext.ChannelService().EventService()
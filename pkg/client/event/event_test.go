@@ -16,9 +16,12 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabtest"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service"
@@ -448,3 +451,32 @@ func newServiceWithMockProducer(opts []options.Opt, pOpts ...producerOpt) (*serv
 
 	return serv, eventProducer, nil
 }
+
+func TestBlockVerificationFromOldest(t *testing.T) {
+	// Block 0 is the genesis block, block 2 is a config block
+	net, err := fabtest.NewNetworkWithConfigBlock()
+	require.NoError(t, err)
+	defer net.Stop()
+
+	sdk, err := fabsdk.New(net.ConfigProvider())
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	channelContext := sdk.ChannelContext("mychannel", fabsdk.WithUser(fabtest.UserName), fabsdk.WithOrg("Org1"))
+
+	client, err := New(channelContext, WithBlockVerification(), WithSeekType(seek.Oldest))
+	require.NoError(t, err)
+
+	reg, eventch, err := client.RegisterBlockEvent()
+	require.NoError(t, err)
+	defer client.Unregister(reg)
+
+	for expected := uint64(0); expected < 4; expected++ {
+		select {
+		case event := <-eventch:
+			assert.Equal(t, expected, event.Block.Header.Number)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for block %d", expected)
+		}
+	}
+}
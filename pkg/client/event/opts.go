@@ -20,6 +20,17 @@ func WithBlockEvents() ClientOption {
 	}
 }
 
+// WithBlockVerification indicates that the blocks received from the peer are to be verified (hash chain
+// and orderer signatures) before events are published. Block events are implicitly enabled by this option.
+// Only deliverclient supports this
+func WithBlockVerification() ClientOption {
+	return func(c *Client) error {
+		c.permitBlockEvents = true
+		c.verifyBlocks = true
+		return nil
+	}
+}
+
 // WithBlockNum indicates the block number from which events are to be received.
 // Only deliverclient supports this
 func WithBlockNum(from uint64) ClientOption {
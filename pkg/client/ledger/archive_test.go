@@ -0,0 +1,149 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive(t *testing.T) {
+	blocks := []*common.Block{newArchiveBlock(0), newArchiveBlock(1), newArchiveBlock(2)}
+	archive := newArchive(t, blocks...)
+
+	importer, err := NewImporter(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{0, 1, 2}, importer.BlockNumbers())
+
+	block, err := importer.Block(1)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(blocks[1], block))
+
+	_, err = importer.Block(3)
+	assert.EqualError(t, err, "block [3] not found in archive")
+
+	t.Run("Replay", func(t *testing.T) {
+		reg, eventch, err := importer.RegisterBlockEvent()
+		require.NoError(t, err)
+		defer importer.Unregister(reg)
+
+		var numbers []uint64
+		for event := range eventch {
+			numbers = append(numbers, event.Block.Header.Number)
+		}
+		assert.Equal(t, []uint64{0, 1, 2}, numbers)
+	})
+
+	t.Run("Replay with filter", func(t *testing.T) {
+		reg, eventch, err := importer.RegisterBlockEvent(func(block *common.Block) bool { return block.Header.Number != 1 })
+		require.NoError(t, err)
+		defer importer.Unregister(reg)
+
+		var numbers []uint64
+		for event := range eventch {
+			numbers = append(numbers, event.Block.Header.Number)
+		}
+		assert.Equal(t, []uint64{0, 2}, numbers)
+	})
+
+	t.Run("Export from events", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		exporter, err := NewExporter(buf)
+		require.NoError(t, err)
+
+		require.NoError(t, exporter.ExportFromEvents(importer, 1, time.Second))
+		last, ok := exporter.LastBlockNum()
+		assert.True(t, ok)
+		assert.Equal(t, uint64(1), last)
+
+		err = exporter.ExportFromEvents(importer, 5, time.Second)
+		assert.EqualError(t, err, "event channel closed before block [5] was received")
+		require.NoError(t, exporter.Close())
+
+		replayed, err := NewImporter(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{0, 1, 2}, replayed.BlockNumbers())
+	})
+}
+
+func TestExporter(t *testing.T) {
+	exporter, err := NewExporter(&bytes.Buffer{})
+	require.NoError(t, err)
+
+	_, ok := exporter.LastBlockNum()
+	assert.False(t, ok)
+
+	require.NoError(t, exporter.Write(newArchiveBlock(5)))
+	assert.EqualError(t, exporter.Write(newArchiveBlock(5)), "block [5] must be greater than the last exported block [5]")
+	assert.EqualError(t, exporter.Write(&common.Block{}), "block header is missing")
+
+	require.NoError(t, exporter.Close())
+	assert.EqualError(t, exporter.Write(newArchiveBlock(6)), "exporter is closed")
+}
+
+func TestExportFromLedger(t *testing.T) {
+	peer := &mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Status: 200, MockMSP: "test", Payload: protoutil.MarshalOrPanic(newArchiveBlock(3))}
+	lc := setupLedgerClient([]fab.Peer{peer}, t)
+
+	buf := &bytes.Buffer{}
+	exporter, err := NewExporter(buf)
+	require.NoError(t, err)
+
+	assert.EqualError(t, exporter.ExportFromLedger(lc, 4, 3), "invalid block range [4, 3]")
+	require.NoError(t, exporter.ExportFromLedger(lc, 3, 3))
+	require.NoError(t, exporter.Close())
+
+	importer, err := NewImporter(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{3}, importer.BlockNumbers())
+}
+
+func TestInvalidArchive(t *testing.T) {
+	_, err := NewImporter(bytes.NewReader([]byte("invalid")), 7)
+	assert.EqualError(t, err, "invalid archive: archive is too small")
+
+	// The index and footer are only written on Close
+	buf := &bytes.Buffer{}
+	exporter, err := NewExporter(buf)
+	require.NoError(t, err)
+	require.NoError(t, exporter.Write(newArchiveBlock(0)))
+	_, err = NewImporter(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.EqualError(t, err, "invalid archive: unexpected footer (the archive may not have been closed)")
+
+	archive := newArchive(t, newArchiveBlock(0))
+	archive[0] = 'X'
+	_, err = NewImporter(bytes.NewReader(archive), int64(len(archive)))
+	assert.EqualError(t, err, "invalid archive: unexpected header")
+}
+
+func newArchive(t *testing.T, blocks ...*common.Block) []byte {
+	buf := &bytes.Buffer{}
+	exporter, err := NewExporter(buf)
+	require.NoError(t, err)
+	for _, block := range blocks {
+		require.NoError(t, exporter.Write(block))
+	}
+	require.NoError(t, exporter.Close())
+	return buf.Bytes()
+}
+
+func newArchiveBlock(number uint64) *common.Block {
+	block := protoutil.NewBlock(number, []byte("previous"))
+	block.Data.Data = [][]byte{[]byte(fmt.Sprintf("tx%d", number))}
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+	return block
+}
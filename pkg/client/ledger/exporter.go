@@ -0,0 +1,188 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// The archive consists of a header (archiveMagic) followed by the length-delimited (uvarint length prefix)
+// protobuf encoded blocks. The blocks are followed by the index, which is the number of blocks followed
+// by a (block number, offset) pair for each block. All of these values are uvarint encoded. The archive
+// ends with a footer which contains the offset of the index (8 bytes, big endian) followed by archiveMagic.
+const (
+	archiveMagic = "FSDKBLK1"
+	footerSize   = 8 + len(archiveMagic)
+)
+
+// BlockEventSource is a source of block events, for example the event client or an archive Importer
+type BlockEventSource interface {
+	RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error)
+	Unregister(reg fab.Registration)
+}
+
+type indexEntry struct {
+	number uint64
+	offset uint64
+}
+
+// Exporter writes blocks to an archive. Blocks must be written in ascending order of block number.
+// Close must be called once all blocks have been written in order to write the index.
+// Exporter is not safe for concurrent use.
+type Exporter struct {
+	w      io.Writer
+	offset uint64
+	index  []indexEntry
+	closed bool
+}
+
+// NewExporter returns an exporter which writes the archive to the given writer (typically an os.File).
+func NewExporter(w io.Writer) (*Exporter, error) {
+	e := &Exporter{w: w}
+	if err := e.write([]byte(archiveMagic)); err != nil {
+		return nil, errors.WithMessage(err, "failed to write archive header")
+	}
+	return e, nil
+}
+
+// Write appends the given block to the archive
+func (e *Exporter) Write(block *common.Block) error {
+	if e.closed {
+		return errors.New("exporter is closed")
+	}
+	if block == nil || block.Header == nil {
+		return errors.New("block header is missing")
+	}
+
+	if last, ok := e.LastBlockNum(); ok && block.Header.Number <= last {
+		return errors.Errorf("block [%d] must be greater than the last exported block [%d]", block.Header.Number, last)
+	}
+
+	blockBytes, err := proto.Marshal(block)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal block [%d]", block.Header.Number)
+	}
+
+	offset := e.offset
+	if err := e.write(proto.EncodeVarint(uint64(len(blockBytes)))); err != nil {
+		return errors.WithMessagef(err, "failed to write block [%d]", block.Header.Number)
+	}
+	if err := e.write(blockBytes); err != nil {
+		return errors.WithMessagef(err, "failed to write block [%d]", block.Header.Number)
+	}
+
+	e.index = append(e.index, indexEntry{number: block.Header.Number, offset: offset})
+
+	return nil
+}
+
+// LastBlockNum returns the number of the last block written to the archive. False is returned if no block was written.
+func (e *Exporter) LastBlockNum() (uint64, bool) {
+	if len(e.index) == 0 {
+		return 0, false
+	}
+	return e.index[len(e.index)-1].number, true
+}
+
+// ExportFromLedger queries the blocks in the given range (inclusive) using the given ledger client
+// and writes them to the archive
+func (e *Exporter) ExportFromLedger(client *Client, from, to uint64, options ...RequestOption) error {
+	if from > to {
+		return errors.Errorf("invalid block range [%d, %d]", from, to)
+	}
+
+	for blockNumber := from; ; blockNumber++ {
+		block, err := client.QueryBlock(blockNumber, options...)
+		if err != nil {
+			return errors.WithMessagef(err, "ExportFromLedger failed to query block %d", blockNumber)
+		}
+
+		if err := e.Write(block); err != nil {
+			return err
+		}
+
+		if blockNumber == to {
+			return nil
+		}
+	}
+}
+
+// ExportFromEvents registers for block events with the given event source (e.g. an event client
+// created with the WithBlockEvents and WithSeekType options) and writes the received blocks to the archive
+// until block number 'to' has been written. Blocks which have already been written are ignored.
+// An error is returned if no block is received within the given timeout.
+func (e *Exporter) ExportFromEvents(source BlockEventSource, to uint64, timeout time.Duration) error {
+	reg, eventch, err := source.RegisterBlockEvent()
+	if err != nil {
+		return errors.WithMessage(err, "ExportFromEvents failed to register for block events")
+	}
+	defer source.Unregister(reg)
+
+	for {
+		select {
+		case event, ok := <-eventch:
+			if !ok {
+				return errors.Errorf("event channel closed before block [%d] was received", to)
+			}
+
+			if last, ok := e.LastBlockNum(); ok && event.Block.Header.Number <= last {
+				continue
+			}
+
+			if err := e.Write(event.Block); err != nil {
+				return err
+			}
+
+			if event.Block.Header.Number >= to {
+				return nil
+			}
+		case <-time.After(timeout):
+			return errors.Errorf("timed out waiting for block events (waiting for block [%d])", to)
+		}
+	}
+}
+
+// Close writes the index and footer to the archive. The underlying writer is not closed.
+func (e *Exporter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+
+	indexOffset := e.offset
+
+	index := proto.EncodeVarint(uint64(len(e.index)))
+	for _, entry := range e.index {
+		index = append(index, proto.EncodeVarint(entry.number)...)
+		index = append(index, proto.EncodeVarint(entry.offset)...)
+	}
+	if err := e.write(index); err != nil {
+		return errors.WithMessage(err, "failed to write archive index")
+	}
+
+	footer := make([]byte, 8, footerSize)
+	binary.BigEndian.PutUint64(footer, indexOffset)
+	footer = append(footer, archiveMagic...)
+	if err := e.write(footer); err != nil {
+		return errors.WithMessage(err, "failed to write archive footer")
+	}
+
+	return nil
+}
+
+func (e *Exporter) write(b []byte) error {
+	n, err := e.w.Write(b)
+	e.offset += uint64(n)
+	return err
+}
@@ -0,0 +1,218 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const importerEventBufferSize = 100
+
+// Importer reads the blocks of an archive written by the Exporter. The archived blocks may be replayed
+// (without a network) through the same block event channel interface as the event client.
+// Importer is safe for concurrent use.
+type Importer struct {
+	r           io.ReaderAt
+	indexOffset uint64
+	index       []indexEntry
+}
+
+// NewImporter returns an importer which reads the archive from the given reader (typically an os.File)
+// of the given size
+func NewImporter(r io.ReaderAt, size int64) (*Importer, error) {
+	if size < int64(len(archiveMagic)+footerSize) {
+		return nil, errors.New("invalid archive: archive is too small")
+	}
+
+	header := make([]byte, len(archiveMagic))
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, errors.Wrap(err, "failed to read archive header")
+	}
+	if string(header) != archiveMagic {
+		return nil, errors.New("invalid archive: unexpected header")
+	}
+
+	footer := make([]byte, footerSize)
+	if _, err := r.ReadAt(footer, size-int64(footerSize)); err != nil {
+		return nil, errors.Wrap(err, "failed to read archive footer")
+	}
+	if string(footer[8:]) != archiveMagic {
+		return nil, errors.New("invalid archive: unexpected footer (the archive may not have been closed)")
+	}
+
+	indexOffset := binary.BigEndian.Uint64(footer)
+	if indexOffset < uint64(len(archiveMagic)) || indexOffset > uint64(size-int64(footerSize)) {
+		return nil, errors.Errorf("invalid archive: index offset [%d] is out of range", indexOffset)
+	}
+
+	indexBytes := make([]byte, uint64(size-int64(footerSize))-indexOffset)
+	if _, err := r.ReadAt(indexBytes, int64(indexOffset)); err != nil {
+		return nil, errors.Wrap(err, "failed to read archive index")
+	}
+
+	index, err := decodeIndex(indexBytes, indexOffset)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid archive index")
+	}
+
+	return &Importer{
+		r:           r,
+		indexOffset: indexOffset,
+		index:       index,
+	}, nil
+}
+
+// BlockNumbers returns the numbers of the archived blocks in ascending order
+func (i *Importer) BlockNumbers() []uint64 {
+	numbers := make([]uint64, len(i.index))
+	for j, entry := range i.index {
+		numbers[j] = entry.number
+	}
+	return numbers
+}
+
+// Block returns the archived block with the given number
+func (i *Importer) Block(number uint64) (*common.Block, error) {
+	j := sort.Search(len(i.index), func(j int) bool { return i.index[j].number >= number })
+	if j == len(i.index) || i.index[j].number != number {
+		return nil, errors.Errorf("block [%d] not found in archive", number)
+	}
+	return i.readBlock(i.index[j])
+}
+
+// RegisterBlockEvent replays the archived blocks (in ascending order) over the returned channel.
+// Unlike the event client, the channel is closed once all archived blocks have been sent (or when
+// Unregister is called).
+// - filter is an optional filter that filters out unwanted events. (Note: Only one filter may be specified.)
+func (i *Importer) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	var blockFilter fab.BlockFilter
+	if len(filter) > 1 {
+		return nil, nil, errors.New("only one block filter may be specified")
+	}
+	if len(filter) == 1 {
+		blockFilter = filter[0]
+	}
+
+	reg := &importerRegistration{done: make(chan struct{})}
+	eventch := make(chan *fab.BlockEvent, importerEventBufferSize)
+
+	go i.replay(reg, blockFilter, eventch)
+
+	return reg, eventch, nil
+}
+
+// Unregister stops replaying blocks for the given registration
+func (i *Importer) Unregister(reg fab.Registration) {
+	if r, ok := reg.(*importerRegistration); ok {
+		r.close()
+	}
+}
+
+func (i *Importer) replay(reg *importerRegistration, filter fab.BlockFilter, eventch chan<- *fab.BlockEvent) {
+	defer close(eventch)
+
+	for _, entry := range i.index {
+		block, err := i.readBlock(entry)
+		if err != nil {
+			logger.Errorf("Error reading block from archive - stopping replay: %s", err)
+			return
+		}
+
+		if filter != nil && !filter(block) {
+			continue
+		}
+
+		select {
+		case eventch <- &fab.BlockEvent{Block: block}:
+		case <-reg.done:
+			return
+		}
+	}
+}
+
+func (i *Importer) readBlock(entry indexEntry) (*common.Block, error) {
+	reader := bufio.NewReader(io.NewSectionReader(i.r, int64(entry.offset), int64(i.indexOffset-entry.offset)))
+
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read length of block [%d]", entry.number)
+	}
+	if length > i.indexOffset-entry.offset {
+		return nil, errors.Errorf("invalid length of block [%d]: %d", entry.number, length)
+	}
+
+	blockBytes := make([]byte, length)
+	if _, err := io.ReadFull(reader, blockBytes); err != nil {
+		return nil, errors.Wrapf(err, "failed to read block [%d]", entry.number)
+	}
+
+	block := &common.Block{}
+	if err := proto.Unmarshal(blockBytes, block); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal block [%d]", entry.number)
+	}
+
+	if block.Header == nil || block.Header.Number != entry.number {
+		return nil, errors.Errorf("archived block at offset [%d] is not block [%d]", entry.offset, entry.number)
+	}
+
+	return block, nil
+}
+
+func decodeIndex(indexBytes []byte, indexOffset uint64) ([]indexEntry, error) {
+	buf := proto.NewBuffer(indexBytes)
+
+	count, err := buf.DecodeVarint()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode number of blocks")
+	}
+
+	var index []indexEntry
+	for j := uint64(0); j < count; j++ {
+		number, err := buf.DecodeVarint()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode block number of entry %d", j)
+		}
+		offset, err := buf.DecodeVarint()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode offset of entry %d", j)
+		}
+
+		if offset < uint64(len(archiveMagic)) || offset >= indexOffset {
+			return nil, errors.Errorf("offset [%d] of block [%d] is out of range", offset, number)
+		}
+		if len(index) > 0 && number <= index[len(index)-1].number {
+			return nil, errors.Errorf("block [%d] is out of order", number)
+		}
+
+		index = append(index, indexEntry{number: number, offset: offset})
+	}
+
+	return index, nil
+}
+
+type importerRegistration struct {
+	done chan struct{}
+	once sync.Once
+}
+
+func (r *importerRegistration) close() {
+	r.once.Do(func() {
+		close(r.done)
+	})
+}
@@ -8,6 +8,8 @@ SPDX-License-Identifier: Apache-2.0
 // An application that requires ledger queries from multiple channels should create a separate
 // instance of the ledger client for each channel. Ledger client supports the following queries:
 // QueryInfo, QueryBlock, QueryBlockByHash,  QueryBlockByTxID, QueryTransaction and QueryConfig.
+// Ledger client also supports verifying the integrity of a range of blocks (VerifyBlocks).
+// Blocks may be exported to a portable archive (Exporter) and replayed offline from the archive (Importer).
 //
 //  Basic Flow:
 //  1) Prepare channel context
@@ -17,6 +19,8 @@ package ledger
 
 import (
 	reqContext "context"
+	"fmt"
+	"math"
 	"math/rand"
 	"time"
 
@@ -35,6 +39,7 @@ import (
 
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel/membership"
 	"github.com/pkg/errors"
 )
 
@@ -76,7 +81,12 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 		return nil, errors.WithMessage(err, "membership creation failed")
 	}
 
-	ledger, err := channel.NewLedger(channelContext.ChannelID())
+	chConfig, err := channelContext.ChannelService().ChannelConfig()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get channel config")
+	}
+
+	ledger, err := channel.NewLedger(channelContext.ChannelID(), fab.WithHashingAlgorithm(chConfig.HashingAlgorithm()))
 	if err != nil {
 		return nil, err
 	}
@@ -232,6 +242,76 @@ func (c *Client) QueryBlock(blockNumber uint64, options ...RequestOption) (*comm
 	return matchBlockData(responses, opts.MinTargets)
 }
 
+// VerifyBlocks verifies the integrity of the blocks in the given range (inclusive). The data hash of
+// each block is recomputed, the previous hash is checked against the preceding block and the orderer
+// signatures are evaluated against the BlockValidation policy of the current channel configuration.
+// The policy is reloaded from each config block in the range. The genesis block is not signed by the
+// orderer so only its data hash is checked.
+//  Parameters:
+//  from is the number of the first block to verify
+//  to is the number of the last block to verify
+//  options hold optional request options
+//
+//  Returns:
+//  an error describing the first inconsistency found (*verifier.BlockError) or nil if all blocks are valid
+func (c *Client) VerifyBlocks(from, to uint64, options ...RequestOption) error {
+	if from > to {
+		return errors.Errorf("invalid block range [%d, %d]", from, to)
+	}
+
+	// The verifier is seeded with the current channel config of the channel service so that the policy is
+	// never taken from a block (such as the genesis block) which has not been verified
+	channelCfg, err := c.ctx.ChannelService().ChannelConfig()
+	if err != nil {
+		return errors.WithMessage(err, "VerifyBlocks failed to query channel config")
+	}
+
+	blockVerifier, err := verifier.NewConfigBlock(c.ctx.CryptoSuite(), channelCfg, c.configFromBlock, c.channelMembership)
+	if err != nil {
+		return errors.WithMessage(err, "VerifyBlocks failed to load block validation policy")
+	}
+
+	if from > 0 {
+		// Chain the first block against its predecessor
+		previous, err := c.QueryBlock(from-1, options...)
+		if err != nil {
+			return errors.WithMessagef(err, "VerifyBlocks failed to query block %d", from-1)
+		}
+		blockVerifier.SetPrevious(previous.Header)
+	}
+
+	for blockNumber := from; blockNumber <= to; blockNumber++ {
+		block, err := c.QueryBlock(blockNumber, options...)
+		if err != nil {
+			return errors.WithMessagef(err, "VerifyBlocks failed to query block %d", blockNumber)
+		}
+
+		if block.Header != nil && block.Header.Number != blockNumber {
+			return &verifier.BlockError{BlockNumber: blockNumber, Reason: fmt.Sprintf("peer returned block %d", block.Header.Number)}
+		}
+
+		if err := blockVerifier.Verify(block); err != nil {
+			return err
+		}
+
+		if blockNumber == math.MaxUint64 {
+			break
+		}
+	}
+
+	return nil
+}
+
+// configFromBlock returns the channel config contained in the given config block
+func (c *Client) configFromBlock(block *common.Block) (fab.ChannelCfg, error) {
+	return chconfig.FromBlock(c.ctx.ChannelID(), block)
+}
+
+// channelMembership returns the channel membership (MSPs) of the given channel config
+func (c *Client) channelMembership(cfg fab.ChannelCfg) (fab.ChannelMembership, error) {
+	return membership.New(membership.Context{Providers: c.ctx, EndpointConfig: c.ctx.EndpointConfig()}, cfg)
+}
+
 func (c *Client) prepareRequestParams(options ...RequestOption) ([]fab.Peer, *requestOptions, error) {
 	opts, err := c.prepareRequestOpts(options...)
 	if err != nil {
@@ -15,9 +15,12 @@ import (
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabtest"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -276,6 +279,48 @@ func TestQueryConfig(t *testing.T) {
 
 }
 
+func TestVerifyBlocks(t *testing.T) {
+	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Status: 200, MockMSP: "test"}
+	lc := setupLedgerClient([]fab.Peer{&peer}, t)
+
+	err := lc.VerifyBlocks(2, 1)
+	expected := "invalid block range [2, 1]"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Fatalf("Test ledger verify blocks should have failed with '%s'", expected)
+	}
+
+	// The mock channel config has no orderer group
+	err = lc.VerifyBlocks(1, 2)
+	expected = "VerifyBlocks failed to load block validation policy"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Fatalf("Test ledger verify blocks should have failed with '%s'", expected)
+	}
+}
+
+func TestVerifyBlocksChain(t *testing.T) {
+	// Block 0 is the genesis block, block 2 is a config block
+	net, err := fabtest.NewNetworkWithConfigBlock()
+	require.NoError(t, err)
+	defer net.Stop()
+
+	sdk, err := fabsdk.New(net.ConfigProvider())
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	channelContext := sdk.ChannelContext("mychannel", fabsdk.WithUser(fabtest.UserName), fabsdk.WithOrg("Org1"))
+
+	lc, err := New(channelContext)
+	require.NoError(t, err)
+
+	info, err := lc.QueryInfo()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), info.BCI.Height)
+
+	assert.NoError(t, lc.VerifyBlocks(0, 3))
+	assert.NoError(t, lc.VerifyBlocks(0, 0))
+	assert.NoError(t, lc.VerifyBlocks(3, 3))
+}
+
 func setupTestChannelService(ctx context.Client, orderers []fab.Orderer) (fab.ChannelService, error) {
 	chProvider, err := fcmocks.NewMockChannelProvider(ctx)
 	if err != nil {
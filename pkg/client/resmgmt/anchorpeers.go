@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	reqContext "context"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// anchorPeersPollInterval is the interval at which the channel config is re-queried when
+// verifying an anchor peers update
+const anchorPeersPollInterval = 200 * time.Millisecond
+
+// AnchorPeer is the host and port of an anchor peer
+type AnchorPeer struct {
+	Host string
+	Port int32
+}
+
+// UpdateAnchorPeers replaces the anchor peers of an organization on a channel. The config update is computed
+// from the current config block of the channel, signed by the client (who must be an admin of the organization)
+// and submitted to the orderer. The update is then verified by re-querying the channel config from the orderer
+// until the organization's anchor peers match (or the orderer response timeout expires).
+//  Parameters:
+//  channelID is mandatory channel ID
+//  org is the name of the organization's application config group (as in configtx.yaml)
+//  peers holds the new anchor peers of the organization (empty to remove all anchor peers)
+//  options holds optional request options
+//
+//  Returns:
+//  save channel response with transaction ID of the config update
+func (rc *Client) UpdateAnchorPeers(channelID string, org string, peers []AnchorPeer, options ...RequestOption) (SaveChannelResponse, error) {
+	if channelID == "" || org == "" {
+		return SaveChannelResponse{}, errors.New("must provide channel ID and organization")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return SaveChannelResponse{}, err
+	}
+
+	orderer, err := rc.requestOrderer(&opts, channelID)
+	if err != nil {
+		return SaveChannelResponse{}, errors.WithMessage(err, "failed to find orderer for request")
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.OrdererResponse)
+	defer cancel()
+
+	var anchorPeers []*pb.AnchorPeer
+	for _, p := range peers {
+		anchorPeers = append(anchorPeers, &pb.AnchorPeer{Host: p.Host, Port: p.Port})
+	}
+	txID, err := rc.updateConfig(reqCtx, channelID, orderer, opts, func(config *common.Config) ([]byte, error) {
+		return resource.CreateAnchorPeersConfigUpdate(config, channelID, org, anchorPeers)
+	})
+	if err != nil {
+		return SaveChannelResponse{}, errors.WithMessage(err, "update anchor peers failed")
+	}
+
+	if err := verifyAnchorPeers(reqCtx, channelID, org, peers, orderer); err != nil {
+		return SaveChannelResponse{TransactionID: txID}, err
+	}
+
+	return SaveChannelResponse{TransactionID: txID}, nil
+}
+
+// verifyAnchorPeers queries the channel config from the orderer until the anchor peers of the organization
+// match the given peers
+func verifyAnchorPeers(reqCtx reqContext.Context, channelID string, org string, peers []AnchorPeer, orderer fab.Orderer) error {
+	channelConfig, err := chconfig.New(channelID, chconfig.WithOrderer(orderer))
+	if err != nil {
+		return errors.WithMessage(err, "QueryConfig failed")
+	}
+
+	for {
+		cfg, err := channelConfig.Query(reqCtx)
+		if err != nil {
+			return errors.WithMessage(err, "verifying anchor peers update failed")
+		}
+		if sameAnchorPeers(cfg.AnchorPeers(), org, peers) {
+			return nil
+		}
+
+		select {
+		case <-time.After(anchorPeersPollInterval):
+		case <-reqCtx.Done():
+			return errors.Errorf("anchor peers of org [%s] were not updated in the config of channel [%s]", org, channelID)
+		}
+	}
+}
+
+func sameAnchorPeers(anchorPeers []*fab.OrgAnchorPeer, org string, peers []AnchorPeer) bool {
+	var orgPeers []AnchorPeer
+	for _, p := range anchorPeers {
+		if p.Org == org {
+			orgPeers = append(orgPeers, AnchorPeer{Host: p.Host, Port: p.Port})
+		}
+	}
+
+	if len(orgPeers) != len(peers) {
+		return false
+	}
+	for i, p := range peers {
+		if orgPeers[i] != p {
+			return false
+		}
+	}
+	return true
+}
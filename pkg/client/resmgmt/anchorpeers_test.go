@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateAnchorPeers(t *testing.T) {
+	net, err := fabtest.NewNetwork(fabtest.WithPeersPerOrg(2))
+	require.NoError(t, err)
+	defer net.Stop()
+
+	sdk, err := fabsdk.New(net.ConfigProvider())
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	rc, err := New(sdk.Context(fabsdk.WithUser(fabtest.AdminName), fabsdk.WithOrg("Org1")))
+	require.NoError(t, err)
+
+	anchorPeer := toAnchorPeer(t, net.Peers()[1].Address())
+	resp, err := rc.UpdateAnchorPeers("mychannel", "Org1", []AnchorPeer{anchorPeer})
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.TransactionID)
+
+	cfg, err := rc.QueryConfigFromOrderer("mychannel")
+	require.NoError(t, err)
+	require.Len(t, cfg.AnchorPeers(), 1)
+	assert.Equal(t, "Org1", cfg.AnchorPeers()[0].Org)
+	assert.Equal(t, anchorPeer.Host, cfg.AnchorPeers()[0].Host)
+	assert.Equal(t, anchorPeer.Port, cfg.AnchorPeers()[0].Port)
+	assert.Equal(t, uint64(1), cfg.BlockNumber())
+
+	// The anchor peers are unchanged
+	_, err = rc.UpdateAnchorPeers("mychannel", "Org1", []AnchorPeer{anchorPeer})
+	assert.Error(t, err)
+
+	_, err = rc.UpdateAnchorPeers("mychannel", "Org2", []AnchorPeer{anchorPeer})
+	assert.Error(t, err)
+	_, err = rc.UpdateAnchorPeers("", "Org1", nil)
+	assert.Error(t, err)
+
+	// Remove all anchor peers
+	_, err = rc.UpdateAnchorPeers("mychannel", "Org1", nil)
+	require.NoError(t, err)
+	cfg, err = rc.QueryConfigFromOrderer("mychannel")
+	require.NoError(t, err)
+	assert.Empty(t, cfg.AnchorPeers())
+}
+
+func toAnchorPeer(t *testing.T, address string) AnchorPeer {
+	host, port, err := net.SplitHostPort(address)
+	require.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	return AnchorPeer{Host: host, Port: int32(p)}
+}
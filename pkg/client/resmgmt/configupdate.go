@@ -0,0 +1,338 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"bytes"
+	reqContext "context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel/membership"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// ChannelAdminsPolicy is the path of the channel's application admins policy. It is the policy that the
+// signatures of a config update proposal must satisfy unless another policy is given.
+const ChannelAdminsPolicy = policies.ChannelApplicationAdmins
+
+// ConfigUpdateProposal holds a channel config update, the signatures collected so far and the path of the channel
+// config policy that the signatures must satisfy. A proposal is passed between organizations in its serialized
+// form (see Marshal and UnmarshalConfigUpdateProposal): each organization adds its admin's signature (Sign)
+// or merges the signatures of another copy of the proposal (Merge) until the policy is satisfied (IsSatisfied),
+// at which point the update can be submitted (Submit).
+type ConfigUpdateProposal struct {
+	client       *Client
+	channelID    string
+	configUpdate []byte
+	policy       string
+	signatures   []*common.ConfigSignature
+}
+
+// marshaledConfigUpdateProposal is the serialized form of a proposal. The update and the signatures
+// are carried in a standard ConfigUpdateEnvelope.
+type marshaledConfigUpdateProposal struct {
+	Policy               string `json:"policy"`
+	ConfigUpdateEnvelope []byte `json:"configUpdateEnvelope"`
+}
+
+// NewConfigUpdateProposal creates a proposal, without signatures, for the given config update.
+//  Parameters:
+//  configUpdate is the marshaled ConfigUpdate (e.g. extracted from a channel config tx file with resource.ExtractChannelConfig)
+//  policy is the path of the channel config policy that must be satisfied (ChannelAdminsPolicy if empty)
+//
+//  Returns:
+//  config update proposal bound to this client
+func (rc *Client) NewConfigUpdateProposal(configUpdate []byte, policy string) (*ConfigUpdateProposal, error) {
+	update := &common.ConfigUpdate{}
+	if err := proto.Unmarshal(configUpdate, update); err != nil {
+		return nil, errors.Wrap(err, "unmarshal config update failed")
+	}
+	if update.ChannelId == "" {
+		return nil, errors.New("config update has no channel ID")
+	}
+
+	if policy == "" {
+		policy = ChannelAdminsPolicy
+	}
+
+	return &ConfigUpdateProposal{
+		client:       rc,
+		channelID:    update.ChannelId,
+		configUpdate: configUpdate,
+		policy:       policy,
+	}, nil
+}
+
+// UnmarshalConfigUpdateProposal reads a proposal serialized with ConfigUpdateProposal.Marshal from reader
+//  Returns:
+//  config update proposal bound to this client
+func (rc *Client) UnmarshalConfigUpdateProposal(reader io.Reader) (*ConfigUpdateProposal, error) {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading config update proposal failed")
+	}
+
+	marshaled := &marshaledConfigUpdateProposal{}
+	if err := json.Unmarshal(data, marshaled); err != nil {
+		return nil, errors.Wrap(err, "unmarshal config update proposal failed")
+	}
+	envelope := &common.ConfigUpdateEnvelope{}
+	if err := proto.Unmarshal(marshaled.ConfigUpdateEnvelope, envelope); err != nil {
+		return nil, errors.Wrap(err, "unmarshal config update envelope failed")
+	}
+
+	p, err := rc.NewConfigUpdateProposal(envelope.ConfigUpdate, marshaled.Policy)
+	if err != nil {
+		return nil, err
+	}
+	for _, signature := range envelope.Signatures {
+		if err := p.addSignature(signature); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Marshal serializes the proposal so that it can be passed to another organization
+func (p *ConfigUpdateProposal) Marshal() ([]byte, error) {
+	envelope, err := proto.Marshal(p.envelope())
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal config update envelope failed")
+	}
+	return json.Marshal(&marshaledConfigUpdateProposal{Policy: p.policy, ConfigUpdateEnvelope: envelope})
+}
+
+// ChannelID returns the ID of the channel being updated
+func (p *ConfigUpdateProposal) ChannelID() string {
+	return p.channelID
+}
+
+// ConfigUpdate returns the marshaled ConfigUpdate
+func (p *ConfigUpdateProposal) ConfigUpdate() []byte {
+	return p.configUpdate
+}
+
+// Policy returns the path of the channel config policy that the signatures must satisfy
+func (p *ConfigUpdateProposal) Policy() string {
+	return p.policy
+}
+
+// Signatures returns the signatures collected so far
+func (p *ConfigUpdateProposal) Signatures() []*common.ConfigSignature {
+	return p.signatures
+}
+
+// Sign adds the signature of the given identity (typically an organization admin) to the proposal.
+// Signing again with the same identity has no effect.
+func (p *ConfigUpdateProposal) Sign(signer msp.SigningIdentity) error {
+	data, err := resource.GetConfigSignatureData(signer, p.configUpdate)
+	if err != nil {
+		return err
+	}
+	if p.hasSignatureFrom(data.SignatureHeader.Creator) {
+		return nil
+	}
+
+	signature, err := signer.Sign(data.SigningBytes)
+	if err != nil {
+		return errors.WithMessage(err, "signing of config update failed")
+	}
+
+	p.signatures = append(p.signatures, &common.ConfigSignature{
+		SignatureHeader: data.SignatureHeaderBytes,
+		Signature:       signature,
+	})
+	return nil
+}
+
+// Merge adds the signatures of another copy of the proposal. The other proposal must have the same
+// config update and policy. Signatures of identities that have already signed are ignored.
+func (p *ConfigUpdateProposal) Merge(other *ConfigUpdateProposal) error {
+	if !bytes.Equal(p.configUpdate, other.configUpdate) {
+		return errors.New("cannot merge proposals for different config updates")
+	}
+	if p.policy != other.policy {
+		return errors.Errorf("cannot merge proposals for different policies [%s] and [%s]", p.policy, other.policy)
+	}
+
+	for _, signature := range other.signatures {
+		if err := p.addSignature(signature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsSatisfied evaluates the signatures collected so far against the policy of the current config
+// of the channel, which is retrieved from the orderer. Signatures are verified against the MSPs of the channel.
+//  Parameters:
+//  options holds optional request options (e.g. WithOrderer)
+//
+//  Returns:
+//  true if the signatures satisfy the policy
+func (p *ConfigUpdateProposal) IsSatisfied(options ...RequestOption) (bool, error) {
+	cfg, err := p.client.QueryConfigFromOrderer(p.channelID, options...)
+	if err != nil {
+		return false, err
+	}
+
+	channelMembership, err := membership.New(membership.Context{Providers: p.client.ctx, EndpointConfig: p.client.ctx.EndpointConfig()}, cfg)
+	if err != nil {
+		return false, errors.WithMessage(err, "creating channel membership failed")
+	}
+
+	policy, err := verifier.NewConfigPolicy(channelMembership, cfg, p.policy)
+	if err != nil {
+		return false, err
+	}
+
+	signatureSet, err := protoutil.ConfigUpdateEnvelopeAsSignedData(p.envelope())
+	if err != nil {
+		return false, err
+	}
+
+	if err := policy.Evaluate(signatureSet); err != nil {
+		logger.Debugf("signatures of config update for channel [%s] do not satisfy policy [%s]: %s", p.channelID, p.policy, err)
+		return false, nil
+	}
+	return true, nil
+}
+
+// Submit submits the config update with the signatures collected so far using SaveChannel. The orderer
+// rejects the update if the signatures do not satisfy the modification policies of the updated elements.
+//  Parameters:
+//  options holds optional request options
+//
+//  Returns:
+//  save channel response with transaction ID
+func (p *ConfigUpdateProposal) Submit(options ...RequestOption) (SaveChannelResponse, error) {
+	if len(p.signatures) == 0 {
+		return SaveChannelResponse{}, errors.New("config update proposal has no signatures")
+	}
+
+	// SaveChannel reads the config update from a channel config tx
+	configTx, err := proto.Marshal(&common.Envelope{
+		Payload: protoutil.MarshalOrPanic(&common.Payload{
+			Header: protoutil.MakePayloadHeader(protoutil.MakeChannelHeader(common.HeaderType_CONFIG_UPDATE, 0, p.channelID, 0), &common.SignatureHeader{}),
+			Data:   protoutil.MarshalOrPanic(&common.ConfigUpdateEnvelope{ConfigUpdate: p.configUpdate}),
+		}),
+	})
+	if err != nil {
+		return SaveChannelResponse{}, errors.Wrap(err, "marshal config tx failed")
+	}
+
+	req := SaveChannelRequest{ChannelID: p.channelID, ChannelConfig: bytes.NewReader(configTx)}
+	return p.client.SaveChannel(req, append(options, WithConfigSignatures(p.signatures...))...)
+}
+
+func (p *ConfigUpdateProposal) envelope() *common.ConfigUpdateEnvelope {
+	return &common.ConfigUpdateEnvelope{ConfigUpdate: p.configUpdate, Signatures: p.signatures}
+}
+
+func (p *ConfigUpdateProposal) addSignature(signature *common.ConfigSignature) error {
+	header, err := protoutil.GetSignatureHeader(signature.SignatureHeader)
+	if err != nil {
+		return errors.WithMessage(err, "invalid config signature")
+	}
+	if !p.hasSignatureFrom(header.Creator) {
+		p.signatures = append(p.signatures, signature)
+	}
+	return nil
+}
+
+func (p *ConfigUpdateProposal) hasSignatureFrom(creator []byte) bool {
+	for _, signature := range p.signatures {
+		header, err := protoutil.GetSignatureHeader(signature.SignatureHeader)
+		if err == nil && bytes.Equal(header.Creator, creator) {
+			return true
+		}
+	}
+	return false
+}
+
+// submitConfigUpdate computes a config update from the current config of the channel, signs it with the
+// client's identity (unless signatures are given with WithConfigSignatures) and submits it to the orderer
+func (rc *Client) submitConfigUpdate(channelID string, options []RequestOption, createUpdate func(config *common.Config) ([]byte, error)) (SaveChannelResponse, error) {
+	if channelID == "" {
+		return SaveChannelResponse{}, errors.New("must provide channel ID")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return SaveChannelResponse{}, err
+	}
+
+	orderer, err := rc.requestOrderer(&opts, channelID)
+	if err != nil {
+		return SaveChannelResponse{}, errors.WithMessage(err, "failed to find orderer for request")
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.OrdererResponse)
+	defer cancel()
+
+	txID, err := rc.updateConfig(reqCtx, channelID, orderer, opts, createUpdate)
+	if err != nil {
+		return SaveChannelResponse{}, err
+	}
+	return SaveChannelResponse{TransactionID: txID}, nil
+}
+
+func (rc *Client) updateConfig(reqCtx reqContext.Context, channelID string, orderer fab.Orderer, opts requestOptions, createUpdate func(config *common.Config) ([]byte, error)) (fab.TransactionID, error) {
+	config, err := currentConfig(reqCtx, channelID, orderer, opts)
+	if err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	configUpdate, err := createUpdate(config)
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "creating config update failed")
+	}
+
+	// as in SaveChannel, signatures given with WithConfigSignatures take precedence over the client's signature
+	configSignatures := opts.Signatures
+	if configSignatures == nil {
+		configSignatures, err = rc.createCfgSigFromIDs(configUpdate, rc.ctx)
+		if err != nil {
+			return fab.EmptyTransactionID, err
+		}
+	}
+
+	request := resource.CreateChannelRequest{
+		Name:       channelID,
+		Orderer:    orderer,
+		Config:     configUpdate,
+		Signatures: configSignatures,
+	}
+	txID, err := resource.CreateChannel(reqCtx, request, resource.WithRetry(opts.Retry))
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "config update failed")
+	}
+	return txID, nil
+}
+
+// currentConfig retrieves the current config of the channel from the orderer
+func currentConfig(reqCtx reqContext.Context, channelID string, orderer fab.Orderer, opts requestOptions) (*common.Config, error) {
+	configBlock, err := resource.LastConfigFromOrderer(reqCtx, channelID, orderer, resource.WithRetry(opts.Retry))
+	if err != nil {
+		return nil, errors.WithMessage(err, "retrieving config block failed")
+	}
+	configEnvelope, err := resource.CreateConfigEnvelope(configBlock.Data.Data[0])
+	if err != nil {
+		return nil, errors.WithMessage(err, "extracting config from config block failed")
+	}
+	return configEnvelope.Config, nil
+}
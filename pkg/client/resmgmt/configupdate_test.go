@@ -0,0 +1,115 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabtest"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigUpdateProposal(t *testing.T) {
+	net, err := fabtest.NewNetwork(fabtest.WithOrgs("Org1", "Org2"), fabtest.WithPeersPerOrg(2))
+	require.NoError(t, err)
+	defer net.Stop()
+
+	sdk, err := fabsdk.New(net.ConfigProvider())
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	org1Ctx, err := sdk.Context(fabsdk.WithUser(fabtest.AdminName), fabsdk.WithOrg("Org1"))()
+	require.NoError(t, err)
+	org2Ctx, err := sdk.Context(fabsdk.WithUser(fabtest.AdminName), fabsdk.WithOrg("Org2"))()
+	require.NoError(t, err)
+	org1Client, err := New(sdk.Context(fabsdk.WithUser(fabtest.AdminName), fabsdk.WithOrg("Org1")))
+	require.NoError(t, err)
+	org2Client, err := New(sdk.Context(fabsdk.WithUser(fabtest.AdminName), fabsdk.WithOrg("Org2")))
+	require.NoError(t, err)
+
+	anchorPeer := toAnchorPeer(t, net.Peers()[1].Address())
+	configUpdate := anchorPeersConfigUpdate(t, org1Client, "Org1", anchorPeer)
+
+	_, err = org1Client.NewConfigUpdateProposal([]byte("invalid"), "")
+	assert.Error(t, err)
+
+	proposal, err := org1Client.NewConfigUpdateProposal(configUpdate, "")
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", proposal.ChannelID())
+	assert.Equal(t, ChannelAdminsPolicy, proposal.Policy())
+
+	_, err = proposal.Submit()
+	assert.Error(t, err, "expecting error for proposal without signatures")
+
+	require.NoError(t, proposal.Sign(org1Ctx))
+	require.NoError(t, proposal.Sign(org1Ctx))
+	assert.Len(t, proposal.Signatures(), 1)
+
+	// The admins policy requires a majority of the two orgs
+	satisfied, err := proposal.IsSatisfied()
+	require.NoError(t, err)
+	assert.False(t, satisfied)
+
+	data, err := proposal.Marshal()
+	require.NoError(t, err)
+	org2Proposal, err := org2Client.UnmarshalConfigUpdateProposal(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, configUpdate, org2Proposal.ConfigUpdate())
+	assert.Len(t, org2Proposal.Signatures(), 1)
+
+	require.NoError(t, org2Proposal.Sign(org2Ctx))
+	satisfied, err = org2Proposal.IsSatisfied()
+	require.NoError(t, err)
+	assert.True(t, satisfied)
+
+	require.NoError(t, proposal.Merge(org2Proposal))
+	assert.Len(t, proposal.Signatures(), 2)
+	satisfied, err = proposal.IsSatisfied()
+	require.NoError(t, err)
+	assert.True(t, satisfied)
+
+	other, err := org2Client.NewConfigUpdateProposal(anchorPeersConfigUpdate(t, org2Client, "Org2", anchorPeer), "")
+	require.NoError(t, err)
+	assert.Error(t, proposal.Merge(other))
+	other, err = org2Client.NewConfigUpdateProposal(configUpdate, "/Channel/Application/Writers")
+	require.NoError(t, err)
+	assert.Error(t, proposal.Merge(other))
+
+	resp, err := proposal.Submit()
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.TransactionID)
+
+	cfg, err := org1Client.QueryConfigFromOrderer("mychannel")
+	require.NoError(t, err)
+	assert.Contains(t, cfg.AnchorPeers(), &fab.OrgAnchorPeer{Org: "Org1", Host: anchorPeer.Host, Port: anchorPeer.Port})
+}
+
+// anchorPeersConfigUpdate computes a config update which sets the anchor peer of the given org
+func anchorPeersConfigUpdate(t *testing.T, rc *Client, org string, anchorPeer AnchorPeer) []byte {
+	opts, err := rc.prepareRequestOpts()
+	require.NoError(t, err)
+	orderer, err := rc.requestOrderer(&opts, "mychannel")
+	require.NoError(t, err)
+	reqCtx, cancel := rc.createRequestContext(opts, fab.OrdererResponse)
+	defer cancel()
+
+	block, err := resource.LastConfigFromOrderer(reqCtx, "mychannel", orderer)
+	require.NoError(t, err)
+	configEnvelope, err := resource.CreateConfigEnvelope(block.Data.Data[0])
+	require.NoError(t, err)
+
+	configUpdate, err := resource.CreateAnchorPeersConfigUpdate(configEnvelope.Config, "mychannel", org,
+		[]*pb.AnchorPeer{{Host: anchorPeer.Host, Port: anchorPeer.Port}})
+	require.NoError(t, err)
+	return configUpdate
+}
@@ -111,7 +111,10 @@ func WithOrderer(orderer fab.Orderer) RequestOption {
 	}
 }
 
-// WithConfigSignatures allows to provide pre defined signatures for resmgmt client's SaveChannel call
+// WithConfigSignatures allows to provide pre defined signatures for resmgmt client's SaveChannel call.
+// It also applies to the config updates computed by the client (e.g. AddConsenter, UpdateAnchorPeers), in which
+// case the signatures must be created over the update computed by the corresponding function of the resource
+// package (e.g. resource.CreateConsentersConfigUpdate) from the current channel config.
 func WithConfigSignatures(signatures ...*common.ConfigSignature) RequestOption {
 	return func(ctx context.Client, opts *requestOptions) error {
 		opts.Signatures = signatures
@@ -0,0 +1,199 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	"github.com/pkg/errors"
+)
+
+// Consenter is an etcdraft consenter (orderer node) of a channel
+type Consenter struct {
+	Host          string
+	Port          uint32
+	ClientTLSCert []byte // PEM encoded
+	ServerTLSCert []byte // PEM encoded
+}
+
+// QuerySystemChannelConfig returns the current config of the orderer system channel. The client must be
+// able to read the system channel (typically an orderer organization admin).
+//  Parameters:
+//  systemChannelID is the ID of the orderer system channel
+//  options holds optional request options
+//
+//  Returns:
+//  current config of the system channel
+func (rc *Client) QuerySystemChannelConfig(systemChannelID string, options ...RequestOption) (*common.Config, error) {
+	if systemChannelID == "" {
+		return nil, errors.New("must provide system channel ID")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	orderer, err := rc.requestOrderer(&opts, systemChannelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to find orderer for request")
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.OrdererResponse)
+	defer cancel()
+
+	return currentConfig(reqCtx, systemChannelID, orderer, opts)
+}
+
+// AddConsortiumMember adds an organization to a consortium of the orderer system channel. The update is signed
+// by the client, who must be an orderer organization admin, unless signatures are given with WithConfigSignatures.
+//  Parameters:
+//  systemChannelID is the ID of the orderer system channel
+//  consortium is the name of the consortium
+//  org is the name of the organization's config group
+//  orgGroup is the config group of the organization (e.g. as printed by configtxgen -printOrg)
+//  options holds optional request options
+//
+//  Returns:
+//  save channel response with transaction ID of the config update
+func (rc *Client) AddConsortiumMember(systemChannelID string, consortium string, org string, orgGroup *common.ConfigGroup, options ...RequestOption) (SaveChannelResponse, error) {
+	return rc.submitConfigUpdate(systemChannelID, options, func(config *common.Config) ([]byte, error) {
+		return resource.CreateAddConsortiumMemberConfigUpdate(config, systemChannelID, consortium, org, orgGroup)
+	})
+}
+
+// RemoveConsortiumMember removes an organization from a consortium of the orderer system channel. The update
+// is signed by the client, who must be an orderer organization admin, unless signatures are given with
+// WithConfigSignatures.
+//  Parameters:
+//  systemChannelID is the ID of the orderer system channel
+//  consortium is the name of the consortium
+//  org is the name of the organization's config group
+//  options holds optional request options
+//
+//  Returns:
+//  save channel response with transaction ID of the config update
+func (rc *Client) RemoveConsortiumMember(systemChannelID string, consortium string, org string, options ...RequestOption) (SaveChannelResponse, error) {
+	return rc.submitConfigUpdate(systemChannelID, options, func(config *common.Config) ([]byte, error) {
+		return resource.CreateRemoveConsortiumMemberConfigUpdate(config, systemChannelID, consortium, org)
+	})
+}
+
+// UpdateOrdererAddresses replaces the orderer addresses of a channel (the system channel or an application
+// channel). The update is signed by the client, who must be an orderer organization admin, unless signatures
+// are given with WithConfigSignatures.
+//  Parameters:
+//  channelID is mandatory channel ID
+//  addresses holds the new orderer addresses (host:port)
+//  options holds optional request options
+//
+//  Returns:
+//  save channel response with transaction ID of the config update
+func (rc *Client) UpdateOrdererAddresses(channelID string, addresses []string, options ...RequestOption) (SaveChannelResponse, error) {
+	return rc.submitConfigUpdate(channelID, options, func(config *common.Config) ([]byte, error) {
+		return resource.CreateOrdererAddressesConfigUpdate(config, channelID, addresses)
+	})
+}
+
+// AddConsenter adds an etcdraft consenter to a channel. The update is signed by the client, who must be
+// an orderer organization admin, unless signatures are given with WithConfigSignatures.
+//  Parameters:
+//  channelID is mandatory channel ID
+//  consenter is the new consenter
+//  options holds optional request options
+//
+//  Returns:
+//  save channel response with transaction ID of the config update
+func (rc *Client) AddConsenter(channelID string, consenter Consenter, options ...RequestOption) (SaveChannelResponse, error) {
+	return rc.updateConsenters(channelID, options, func(consenters []*etcdraft.Consenter) ([]*etcdraft.Consenter, error) {
+		if findConsenter(consenters, consenter.Host, consenter.Port) >= 0 {
+			return nil, errors.Errorf("consenter %s:%d already exists", consenter.Host, consenter.Port)
+		}
+		return append(consenters, &etcdraft.Consenter{
+			Host:          consenter.Host,
+			Port:          consenter.Port,
+			ClientTlsCert: consenter.ClientTLSCert,
+			ServerTlsCert: consenter.ServerTLSCert,
+		}), nil
+	})
+}
+
+// RemoveConsenter removes an etcdraft consenter from a channel. The update is signed by the client, who must
+// be an orderer organization admin, unless signatures are given with WithConfigSignatures.
+//  Parameters:
+//  channelID is mandatory channel ID
+//  host and port identify the consenter
+//  options holds optional request options
+//
+//  Returns:
+//  save channel response with transaction ID of the config update
+func (rc *Client) RemoveConsenter(channelID string, host string, port uint32, options ...RequestOption) (SaveChannelResponse, error) {
+	return rc.updateConsenters(channelID, options, func(consenters []*etcdraft.Consenter) ([]*etcdraft.Consenter, error) {
+		i := findConsenter(consenters, host, port)
+		if i < 0 {
+			return nil, errors.Errorf("consenter %s:%d not found", host, port)
+		}
+		return append(consenters[:i], consenters[i+1:]...), nil
+	})
+}
+
+// RotateConsenterTLSCert replaces the TLS certificates of an etcdraft consenter of a channel. The update is
+// signed by the client, who must be an orderer organization admin, unless signatures are given with
+// WithConfigSignatures.
+//  Parameters:
+//  channelID is mandatory channel ID
+//  host and port identify the consenter
+//  clientTLSCert and serverTLSCert are the new PEM encoded certificates (nil to keep the current certificate)
+//  options holds optional request options
+//
+//  Returns:
+//  save channel response with transaction ID of the config update
+func (rc *Client) RotateConsenterTLSCert(channelID string, host string, port uint32, clientTLSCert, serverTLSCert []byte, options ...RequestOption) (SaveChannelResponse, error) {
+	if clientTLSCert == nil && serverTLSCert == nil {
+		return SaveChannelResponse{}, errors.New("must provide a client or server TLS certificate")
+	}
+
+	return rc.updateConsenters(channelID, options, func(consenters []*etcdraft.Consenter) ([]*etcdraft.Consenter, error) {
+		i := findConsenter(consenters, host, port)
+		if i < 0 {
+			return nil, errors.Errorf("consenter %s:%d not found", host, port)
+		}
+		if clientTLSCert != nil {
+			consenters[i].ClientTlsCert = clientTLSCert
+		}
+		if serverTLSCert != nil {
+			consenters[i].ServerTlsCert = serverTLSCert
+		}
+		return consenters, nil
+	})
+}
+
+// updateConsenters submits the config update resulting from the modification of the channel's current consenters
+func (rc *Client) updateConsenters(channelID string, options []RequestOption, modify func(consenters []*etcdraft.Consenter) ([]*etcdraft.Consenter, error)) (SaveChannelResponse, error) {
+	return rc.submitConfigUpdate(channelID, options, func(config *common.Config) ([]byte, error) {
+		consenters, err := resource.Consenters(config)
+		if err != nil {
+			return nil, err
+		}
+		consenters, err = modify(consenters)
+		if err != nil {
+			return nil, err
+		}
+		return resource.CreateConsentersConfigUpdate(config, channelID, consenters)
+	})
+}
+
+func findConsenter(consenters []*etcdraft.Consenter, host string, port uint32) int {
+	for i, c := range consenters {
+		if c.Host == host && c.Port == port {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,208 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabtest"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrdererAdmin(t *testing.T) {
+	net, err := fabtest.NewNetwork()
+	require.NoError(t, err)
+	defer net.Stop()
+
+	sdk, err := fabsdk.New(net.ConfigProvider())
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	rc, err := New(sdk.Context(fabsdk.WithUser(fabtest.AdminName), fabsdk.WithOrg("OrdererOrg")))
+	require.NoError(t, err)
+
+	// The test network has no system channel so the application channel is used
+	config, err := rc.QuerySystemChannelConfig("mychannel")
+	require.NoError(t, err)
+	require.Contains(t, config.ChannelGroup.Groups, channelconfig.OrdererGroupKey)
+	_, err = rc.QuerySystemChannelConfig("")
+	assert.Error(t, err)
+
+	addresses := []string{net.Orderer().Address(), "orderer2.example.com:7050"}
+	resp, err := rc.UpdateOrdererAddresses("mychannel", addresses)
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.TransactionID)
+
+	cfg, err := rc.QueryConfigFromOrderer("mychannel")
+	require.NoError(t, err)
+	assert.Equal(t, addresses, cfg.Orderers())
+
+	_, err = rc.UpdateOrdererAddresses("mychannel", addresses)
+	assert.Error(t, err, "expecting error for unchanged addresses")
+	_, err = rc.UpdateOrdererAddresses("", addresses)
+	assert.Error(t, err)
+
+	// The test network uses solo consensus and has no consortiums
+	_, err = rc.AddConsenter("mychannel", Consenter{Host: "orderer2.example.com", Port: 7050})
+	assert.Error(t, err)
+	_, err = rc.RotateConsenterTLSCert("mychannel", "orderer.example.com", 7050, []byte("cert"), nil)
+	assert.Error(t, err)
+	_, err = rc.RotateConsenterTLSCert("mychannel", "orderer.example.com", 7050, nil, nil)
+	assert.Error(t, err)
+	_, err = rc.AddConsortiumMember("mychannel", "SampleConsortium", "Org2", &common.ConfigGroup{})
+	assert.Error(t, err)
+	_, err = rc.RemoveConsortiumMember("mychannel", "SampleConsortium", "Org1")
+	assert.Error(t, err)
+}
+
+func TestOrdererAdminConsenters(t *testing.T) {
+	net, err := fabtest.NewNetwork(fabtest.WithEtcdRaft())
+	require.NoError(t, err)
+	defer net.Stop()
+
+	sdk, err := fabsdk.New(net.ConfigProvider())
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	rc, err := New(sdk.Context(fabsdk.WithUser(fabtest.AdminName), fabsdk.WithOrg("OrdererOrg")))
+	require.NoError(t, err)
+
+	consenters := queryConsenters(t, rc, "mychannel")
+	require.Len(t, consenters, 1)
+	host, port := consenters[0].Host, consenters[0].Port
+
+	newConsenter := Consenter{Host: "orderer2.example.com", Port: 7050, ClientTLSCert: []byte("client cert"), ServerTLSCert: []byte("server cert")}
+	resp, err := rc.AddConsenter("mychannel", newConsenter)
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.TransactionID)
+
+	consenters = queryConsenters(t, rc, "mychannel")
+	require.Len(t, consenters, 2)
+	assert.Equal(t, "orderer2.example.com", consenters[1].Host)
+	assert.Equal(t, []byte("client cert"), consenters[1].ClientTlsCert)
+
+	_, err = rc.AddConsenter("mychannel", newConsenter)
+	assert.Error(t, err, "expecting error for existing consenter")
+
+	_, err = rc.RotateConsenterTLSCert("mychannel", host, port, nil, []byte("new server cert"))
+	require.NoError(t, err)
+
+	consenters = queryConsenters(t, rc, "mychannel")
+	require.Len(t, consenters, 2)
+	assert.NotEmpty(t, consenters[0].ClientTlsCert, "client certificate should be kept")
+	assert.Equal(t, []byte("new server cert"), consenters[0].ServerTlsCert)
+
+	_, err = rc.RotateConsenterTLSCert("mychannel", "orderer3.example.com", 7050, []byte("cert"), nil)
+	assert.Error(t, err, "expecting error for unknown consenter")
+
+	_, err = rc.RemoveConsenter("mychannel", "orderer2.example.com", 7050)
+	require.NoError(t, err)
+	assert.Len(t, queryConsenters(t, rc, "mychannel"), 1)
+}
+
+func TestOrdererAdminConsortiumMembers(t *testing.T) {
+	net, err := fabtest.NewNetwork(fabtest.WithOrgs("Org1", "Org2"), fabtest.WithEtcdRaft(), fabtest.WithSystemChannel("testchainid"))
+	require.NoError(t, err)
+	defer net.Stop()
+
+	sdk, err := fabsdk.New(net.ConfigProvider())
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	rc, err := New(sdk.Context(fabsdk.WithUser(fabtest.AdminName), fabsdk.WithOrg("OrdererOrg")))
+	require.NoError(t, err)
+
+	config, err := rc.QuerySystemChannelConfig("testchainid")
+	require.NoError(t, err)
+	org2Group := consortiumGroup(t, config).Groups["Org2"]
+	require.NotNil(t, org2Group)
+
+	resp, err := rc.RemoveConsortiumMember("testchainid", "SampleConsortium", "Org2")
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.TransactionID)
+
+	config, err = rc.QuerySystemChannelConfig("testchainid")
+	require.NoError(t, err)
+	assert.NotContains(t, consortiumGroup(t, config).Groups, "Org2")
+	assert.Contains(t, consortiumGroup(t, config).Groups, "Org1")
+
+	_, err = rc.RemoveConsortiumMember("testchainid", "SampleConsortium", "Org2")
+	assert.Error(t, err, "expecting error for organization that isn't a member")
+
+	_, err = rc.AddConsortiumMember("testchainid", "SampleConsortium", "Org2", org2Group)
+	require.NoError(t, err)
+
+	config, err = rc.QuerySystemChannelConfig("testchainid")
+	require.NoError(t, err)
+	assert.Contains(t, consortiumGroup(t, config).Groups, "Org2")
+
+	_, err = rc.AddConsortiumMember("testchainid", "SampleConsortium", "Org2", org2Group)
+	assert.Error(t, err, "expecting error for existing member")
+	_, err = rc.AddConsortiumMember("testchainid", "OtherConsortium", "Org2", org2Group)
+	assert.Error(t, err, "expecting error for unknown consortium")
+}
+
+func TestOrdererAdminConfigSignatures(t *testing.T) {
+	net, err := fabtest.NewNetwork()
+	require.NoError(t, err)
+	defer net.Stop()
+
+	sdk, err := fabsdk.New(net.ConfigProvider())
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	ctx := sdk.Context(fabsdk.WithUser(fabtest.AdminName), fabsdk.WithOrg("OrdererOrg"))
+	rc, err := New(ctx)
+	require.NoError(t, err)
+	signer, err := ctx()
+	require.NoError(t, err)
+
+	addresses := []string{net.Orderer().Address(), "orderer2.example.com:7050"}
+	config, err := rc.QuerySystemChannelConfig("mychannel")
+	require.NoError(t, err)
+	configUpdate, err := resource.CreateOrdererAddressesConfigUpdate(config, "mychannel", addresses)
+	require.NoError(t, err)
+	signature, err := resource.CreateConfigSignature(signer, configUpdate)
+	require.NoError(t, err)
+
+	// A signature over another update is used instead of the client's signature so the orderer rejects the update
+	otherUpdate, err := resource.CreateOrdererAddressesConfigUpdate(config, "mychannel", addresses[1:])
+	require.NoError(t, err)
+	otherSignature, err := resource.CreateConfigSignature(signer, otherUpdate)
+	require.NoError(t, err)
+	_, err = rc.UpdateOrdererAddresses("mychannel", addresses, WithConfigSignatures(otherSignature))
+	assert.Error(t, err)
+
+	_, err = rc.UpdateOrdererAddresses("mychannel", addresses, WithConfigSignatures(signature))
+	require.NoError(t, err)
+
+	cfg, err := rc.QueryConfigFromOrderer("mychannel")
+	require.NoError(t, err)
+	assert.Equal(t, addresses, cfg.Orderers())
+}
+
+func queryConsenters(t *testing.T, rc *Client, channelID string) []*etcdraft.Consenter {
+	config, err := rc.QuerySystemChannelConfig(channelID)
+	require.NoError(t, err)
+	consenters, err := resource.Consenters(config)
+	require.NoError(t, err)
+	return consenters
+}
+
+func consortiumGroup(t *testing.T, config *common.Config) *common.ConfigGroup {
+	consortiums := config.ChannelGroup.Groups[channelconfig.ConsortiumsGroupKey]
+	require.NotNil(t, consortiums)
+	group := consortiums.Groups["SampleConsortium"]
+	require.NotNil(t, group)
+	return group
+}
@@ -0,0 +1,415 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	tls "github.com/tjfoc/gmtls"
+)
+
+const (
+	healthPath  = "/healthz"
+	versionPath = "/version"
+
+	healthStatusOK = "OK"
+)
+
+// ChaincodeData holds the data kept by the peer (LSCC) for a chaincode instantiated on a channel
+type ChaincodeData struct {
+	Name                string
+	Version             string
+	Escc                string
+	Vscc                string
+	Policy              *common.SignaturePolicyEnvelope // endorsement policy
+	InstantiationPolicy *common.SignaturePolicyEnvelope
+	ID                  []byte // fingerprint of the chaincode package
+}
+
+// PeerHealth is the status reported by the health check endpoint (/healthz) of a peer's operations service
+type PeerHealth struct {
+	Status       string            `json:"status"`
+	Time         time.Time         `json:"time"`
+	FailedChecks []PeerHealthCheck `json:"failed_checks,omitempty"`
+}
+
+// Healthy returns true if all health checks of the peer passed
+func (h *PeerHealth) Healthy() bool {
+	return h.Status == healthStatusOK
+}
+
+// PeerHealthCheck is a failed health check of a peer component
+type PeerHealthCheck struct {
+	Component string `json:"component"`
+	Reason    string `json:"reason"`
+}
+
+// PeerVersion is the version reported by the version endpoint (/version) of a peer's operations service
+type PeerVersion struct {
+	Version   string `json:"Version"`
+	CommitSHA string `json:"CommitSHA"`
+}
+
+// QueryInstalledChaincodePackage queries the package of a chaincode installed on a peer.
+//  Parameters:
+//  name is the name of the installed chaincode
+//  version is the version of the installed chaincode
+//  options holds optional request options (one target is required)
+//
+//  Returns:
+//  bytes of the chaincode install package
+func (rc *Client) QueryInstalledChaincodePackage(name, version string, options ...RequestOption) ([]byte, error) {
+	if name == "" || version == "" {
+		return nil, errors.New("chaincode name and version are required")
+	}
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(opts.Targets) != 1 {
+		return nil, errors.New("only one target is supported")
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	return resource.QueryInstalledChaincodePackage(reqCtx, opts.Targets[0], name, version, resource.WithRetry(opts.Retry))
+}
+
+// QueryChaincodeData queries the data of a chaincode instantiated on a channel. If peer is not specified in options
+// it will query random peer on this channel.
+//  Parameters:
+//  channelID is mandatory channel name
+//  chaincodeName is mandatory chaincode name
+//  options hold optional request options
+//
+//  Returns:
+//  chaincode data (version, endorsement and instantiation policies, etc.)
+func (rc *Client) QueryChaincodeData(channelID string, chaincodeName string, options ...RequestOption) (*ChaincodeData, error) {
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	l, target, responseVerifier, err := rc.prepareLedgerQuery(channelID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	responses, err := l.QueryChaincodeData(reqCtx, chaincodeName, []fab.ProposalProcessor{target}, responseVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	cd := responses[0]
+	data := &ChaincodeData{Name: cd.Name, Version: cd.Version, Escc: cd.Escc, Vscc: cd.Vscc, ID: cd.Id}
+	if data.Policy, err = unmarshalSignaturePolicy(cd.Policy); err != nil {
+		return nil, errors.WithMessage(err, "invalid endorsement policy")
+	}
+	if data.InstantiationPolicy, err = unmarshalSignaturePolicy(cd.InstantiationPolicy); err != nil {
+		return nil, errors.WithMessage(err, "invalid instantiation policy")
+	}
+	return data, nil
+}
+
+// QueryChaincodeDeploymentSpec queries the deployment spec of a chaincode instantiated on a channel. If peer is not
+// specified in options it will query random peer on this channel.
+//  Parameters:
+//  channelID is mandatory channel name
+//  chaincodeName is mandatory chaincode name
+//  options hold optional request options
+//
+//  Returns:
+//  chaincode deployment spec
+func (rc *Client) QueryChaincodeDeploymentSpec(channelID string, chaincodeName string, options ...RequestOption) (*pb.ChaincodeDeploymentSpec, error) {
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	l, target, responseVerifier, err := rc.prepareLedgerQuery(channelID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	responses, err := l.QueryChaincodeDeploymentSpec(reqCtx, chaincodeName, []fab.ProposalProcessor{target}, responseVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return responses[0], nil
+}
+
+// QueryChaincodePolicy queries the endorsement policy of a chaincode instantiated on a channel. If peer is not
+// specified in options it will query random peer on this channel.
+//  Parameters:
+//  channelID is mandatory channel name
+//  chaincodeName is mandatory chaincode name
+//  options hold optional request options
+//
+//  Returns:
+//  endorsement policy in the syntax of the policy parser, e.g. OR('Org1MSP.member','Org2MSP.member')
+func (rc *Client) QueryChaincodePolicy(channelID string, chaincodeName string, options ...RequestOption) (string, error) {
+	data, err := rc.QueryChaincodeData(channelID, chaincodeName, options...)
+	if err != nil {
+		return "", err
+	}
+
+	if data.Policy == nil {
+		return "", errors.Errorf("chaincode [%s] has no endorsement policy", chaincodeName)
+	}
+
+	return signaturePolicyString(data.Policy)
+}
+
+// QueryPeerHealth queries the health check endpoint of a peer's operations service. The operations URL
+// of the peer must be configured (operationsURL). A peer failing its health checks is not an error:
+// the failed checks are returned.
+//  Parameters:
+//  options holds optional request options (one target is required)
+//
+//  Returns:
+//  health status of the peer
+func (rc *Client) QueryPeerHealth(options ...RequestOption) (*PeerHealth, error) {
+	health := &PeerHealth{}
+	code, err := rc.queryOperations(healthPath, health, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if code != http.StatusOK && code != http.StatusServiceUnavailable {
+		return nil, errors.Errorf("health check failed with status code %d", code)
+	}
+	return health, nil
+}
+
+// QueryPeerVersion queries the version endpoint of a peer's operations service. The operations URL
+// of the peer must be configured (operationsURL).
+//  Parameters:
+//  options holds optional request options (one target is required)
+//
+//  Returns:
+//  version of the peer
+func (rc *Client) QueryPeerVersion(options ...RequestOption) (*PeerVersion, error) {
+	version := &PeerVersion{}
+	code, err := rc.queryOperations(versionPath, version, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if code != http.StatusOK {
+		return nil, errors.Errorf("version query failed with status code %d", code)
+	}
+	return version, nil
+}
+
+// prepareLedgerQuery returns the ledger of the channel, the target of the query (the first target of the request
+// or a random channel peer) and the verifier of the responses
+func (rc *Client) prepareLedgerQuery(channelID string, opts requestOptions) (*channel.Ledger, fab.ProposalProcessor, channel.ResponseVerifier, error) {
+	chCtx, err := contextImpl.NewChannel(
+		func() (context.Client, error) {
+			return rc.ctx, nil
+		},
+		channelID,
+	)
+	if err != nil {
+		return nil, nil, nil, errors.WithMessage(err, "failed to create channel context")
+	}
+
+	var target fab.ProposalProcessor
+	if len(opts.Targets) >= 1 {
+		target = opts.Targets[0]
+	} else {
+		target, err = rc.selectRandomChannelPeer(chCtx)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	chConfig, err := chCtx.ChannelService().ChannelConfig()
+	if err != nil {
+		return nil, nil, nil, errors.WithMessage(err, "failed to get channel config")
+	}
+
+	l, err := channel.NewLedger(channelID, fab.WithHashingAlgorithm(chConfig.HashingAlgorithm()))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Channel service membership is required to verify signature
+	membership, err := chCtx.ChannelService().Membership()
+	if err != nil {
+		return nil, nil, nil, errors.WithMessage(err, "membership creation failed")
+	}
+
+	return l, target, &verifier.Signature{Membership: membership}, nil
+}
+
+// queryOperations sends a GET request to the operations service of the target peer and decodes the JSON
+// response into v. The status code of the response is returned.
+func (rc *Client) queryOperations(path string, v interface{}, options []RequestOption) (int, error) {
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(opts.Targets) != 1 {
+		return 0, errors.New("only one target is supported")
+	}
+
+	target := opts.Targets[0]
+	peerCfg, ok := rc.ctx.EndpointConfig().PeerConfig(target.URL())
+	if !ok || peerCfg.OperationsURL == "" {
+		return 0, errors.Errorf("operations URL is not configured for peer [%s]", target.URL())
+	}
+
+	client, err := operationsClient(peerCfg, rc.ctx.EndpointConfig())
+	if err != nil {
+		return 0, err
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	url := operationsURL(peerCfg.OperationsURL) + path
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create operations request")
+	}
+
+	resp, err := client.Do(req.WithContext(reqCtx))
+	if err != nil {
+		return 0, errors.Wrapf(err, "operations request to [%s] failed", url)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			logger.Debugf("Failed to close response body: %s", err)
+		}
+	}()
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return resp.StatusCode, errors.Wrapf(err, "failed to decode response from [%s] (status code %d)", url, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// operationsClient returns the HTTP client for the operations service of the peer. For https, the connection
+// uses the GM TLS stack with the same settings as the peer's gRPC connections: the server certificate is verified
+// against the TLS CA cert pool of the endpoint config (including the peer's TLS CA and, if enabled, the system
+// cert pool) and the client TLS certificates are presented for mutual TLS.
+func operationsClient(peerCfg *fab.PeerConfig, config fab.EndpointConfig) (*http.Client, error) {
+	if !strings.HasPrefix(peerCfg.OperationsURL, "https://") {
+		return &http.Client{}, nil
+	}
+
+	serverName, _ := peerCfg.GRPCOptions["ssl-target-name-override"].(string)
+	tlsConfig, err := comm.TLSConfig(peerCfg.TLSCACert, serverName, config)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create TLS config for operations service")
+	}
+
+	dialer := &net.Dialer{}
+	transport := &http.Transport{
+		DialTLS: func(network, addr string) (net.Conn, error) {
+			return tls.DialWithDialer(dialer, network, addr, tlsConfig)
+		},
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// operationsURL returns the URL without trailing slash, defaulting to the http scheme
+func operationsURL(url string) string {
+	if !strings.Contains(url, "://") {
+		url = "http://" + url
+	}
+	return strings.TrimSuffix(url, "/")
+}
+
+func unmarshalSignaturePolicy(data []byte) (*common.SignaturePolicyEnvelope, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	envelope := &common.SignaturePolicyEnvelope{}
+	if err := proto.Unmarshal(data, envelope); err != nil {
+		return nil, errors.Wrap(err, "unmarshal signature policy envelope failed")
+	}
+	return envelope, nil
+}
+
+// signaturePolicyString returns the signature policy in the syntax of the policy parser
+func signaturePolicyString(envelope *common.SignaturePolicyEnvelope) (string, error) {
+	return signaturePolicyRuleString(envelope.Rule, envelope.Identities)
+}
+
+func signaturePolicyRuleString(rule *common.SignaturePolicy, identities []*mb.MSPPrincipal) (string, error) {
+	switch t := rule.GetType().(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(identities) {
+			return "", errors.Errorf("identity index %d out of range", t.SignedBy)
+		}
+		return principalString(identities[t.SignedBy])
+	case *common.SignaturePolicy_NOutOf_:
+		var rules []string
+		for _, r := range t.NOutOf.Rules {
+			s, err := signaturePolicyRuleString(r, identities)
+			if err != nil {
+				return "", err
+			}
+			rules = append(rules, s)
+		}
+
+		n := int(t.NOutOf.N)
+		switch {
+		case n == 1:
+			return fmt.Sprintf("OR(%s)", strings.Join(rules, ", ")), nil
+		case n == len(rules):
+			return fmt.Sprintf("AND(%s)", strings.Join(rules, ", ")), nil
+		default:
+			return fmt.Sprintf("OutOf(%d, %s)", n, strings.Join(rules, ", ")), nil
+		}
+	default:
+		return "", errors.Errorf("unsupported signature policy type %T", t)
+	}
+}
+
+func principalString(principal *mb.MSPPrincipal) (string, error) {
+	if principal.PrincipalClassification != mb.MSPPrincipal_ROLE {
+		return "", errors.Errorf("unsupported principal classification %s", principal.PrincipalClassification)
+	}
+
+	role := &mb.MSPRole{}
+	if err := proto.Unmarshal(principal.Principal, role); err != nil {
+		return "", errors.Wrap(err, "unmarshal MSP role failed")
+	}
+	return fmt.Sprintf("'%s.%s'", role.MspIdentifier, strings.ToLower(role.Role.String())), nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resmgmt
+
+import (
+	stdtls "crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockfab"
+	commtls "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm/tls"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tjfoc/gmsm/sm2"
+	tls "github.com/tjfoc/gmtls"
+)
+
+func TestQueryInstalledChaincodePackage(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	peer := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockMSP: "Org1MSP", Status: http.StatusOK, Payload: []byte("package")}
+	pkg, err := rc.QueryInstalledChaincodePackage("mycc", "v1", WithTargets(peer))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("package"), pkg)
+
+	_, err = rc.QueryInstalledChaincodePackage("mycc", "v1")
+	assert.Error(t, err, "expecting error for missing target")
+	_, err = rc.QueryInstalledChaincodePackage("", "v1", WithTargets(peer))
+	assert.Error(t, err, "expecting error for missing name")
+}
+
+func TestQueryChaincodeData(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	policy, err := cauthdsl.FromString("OR('Org1MSP.member', 'Org2MSP.member')")
+	require.NoError(t, err)
+	payload, err := proto.Marshal(&ccprovider.ChaincodeData{
+		Name:    "mycc",
+		Version: "v1",
+		Escc:    "escc",
+		Vscc:    "vscc",
+		Policy:  protoMarshal(t, policy),
+		Id:      []byte("id"),
+	})
+	require.NoError(t, err)
+
+	peer := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockMSP: "Org1MSP", Status: http.StatusOK, Payload: payload}
+	data, err := rc.QueryChaincodeData("mychannel", "mycc", WithTargets(peer))
+	require.NoError(t, err)
+	assert.Equal(t, "mycc", data.Name)
+	assert.Equal(t, "v1", data.Version)
+	assert.Equal(t, "vscc", data.Vscc)
+	assert.Equal(t, []byte("id"), data.ID)
+	assert.True(t, proto.Equal(policy, data.Policy))
+	assert.Nil(t, data.InstantiationPolicy)
+
+	policyString, err := rc.QueryChaincodePolicy("mychannel", "mycc", WithTargets(peer))
+	require.NoError(t, err)
+	assert.Equal(t, "OR('Org1MSP.member', 'Org2MSP.member')", policyString)
+}
+
+func TestSignaturePolicyString(t *testing.T) {
+	policies := []string{
+		"OR('Org1MSP.member', 'Org2MSP.member')",
+		"AND('Org1MSP.admin', 'Org2MSP.peer')",
+		"OR('Org1MSP.client', AND('Org2MSP.admin', 'Org3MSP.peer'))",
+		"OutOf(2, 'Org1MSP.member', 'Org2MSP.member', 'Org3MSP.member')",
+	}
+	for _, p := range policies {
+		envelope, err := cauthdsl.FromString(p)
+		require.NoError(t, err)
+
+		s, err := signaturePolicyString(envelope)
+		require.NoError(t, err)
+		assert.Equal(t, p, s)
+
+		roundTrip, err := cauthdsl.FromString(s)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(envelope, roundTrip))
+	}
+
+	envelope, err := cauthdsl.FromString("OR('Org1MSP.member')")
+	require.NoError(t, err)
+	envelope.Identities = nil
+	_, err = signaturePolicyString(envelope)
+	assert.Error(t, err, "expecting error for identity out of range")
+}
+
+func TestQueryPeerOperations(t *testing.T) {
+	net, err := fabtest.NewNetwork()
+	require.NoError(t, err)
+	defer net.Stop()
+
+	sdk, err := fabsdk.New(net.ConfigProvider())
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	rc, err := New(sdk.Context(fabsdk.WithUser(fabtest.AdminName), fabsdk.WithOrg("Org1")))
+	require.NoError(t, err)
+
+	peer := net.Peers()[0]
+	target := WithTargetEndpoints(peer.Name())
+
+	health, err := rc.QueryPeerHealth(target)
+	require.NoError(t, err)
+	assert.True(t, health.Healthy())
+	assert.Empty(t, health.FailedChecks)
+
+	peer.SetUnhealthy("couchdb", "not reachable")
+	health, err = rc.QueryPeerHealth(target)
+	require.NoError(t, err)
+	assert.False(t, health.Healthy())
+	assert.Equal(t, []PeerHealthCheck{{Component: "couchdb", Reason: "not reachable"}}, health.FailedChecks)
+
+	version, err := rc.QueryPeerVersion(target)
+	require.NoError(t, err)
+	assert.Equal(t, fabtest.PeerVersion, version.Version)
+
+	_, err = rc.QueryPeerHealth()
+	assert.Error(t, err, "expecting error for missing target")
+
+	mockPeer := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockMSP: "Org1MSP", Status: http.StatusOK}
+	_, err = rc.QueryPeerVersion(WithTargets(mockPeer))
+	assert.Error(t, err, "expecting error for peer without operations URL")
+}
+
+func TestOperationsClientTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, healthStatusOK)
+	}))
+	server.TLS = &stdtls.Config{ClientAuth: stdtls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	caCert, err := sm2.ParseCertificate(server.Certificate().Raw)
+	require.NoError(t, err)
+	// The server's key pair doubles as the client's TLS key pair
+	clientCert := tls.Certificate{Certificate: server.TLS.Certificates[0].Certificate, PrivateKey: server.TLS.Certificates[0].PrivateKey}
+
+	// The peer's TLS CA is added to the cert pool of the endpoint config
+	client, err := operationsClient(&fab.PeerConfig{OperationsURL: server.URL, TLSCACert: caCert}, newOperationsTLSConfig(t, clientCert))
+	require.NoError(t, err)
+
+	resp, err := client.Get(server.URL + healthPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, healthStatusOK, string(body))
+
+	client, err = operationsClient(&fab.PeerConfig{OperationsURL: server.URL, TLSCACert: caCert}, newOperationsTLSConfig(t))
+	require.NoError(t, err)
+	_, err = client.Get(server.URL + healthPath)
+	assert.Error(t, err, "expecting error without client certificate")
+
+	client, err = operationsClient(&fab.PeerConfig{OperationsURL: server.URL}, newOperationsTLSConfig(t, clientCert))
+	require.NoError(t, err)
+	_, err = client.Get(server.URL + healthPath)
+	assert.Error(t, err, "expecting error without the peer's TLS CA")
+
+	config := &fcmocks.MockConfig{CustomTLSCACertPool: &mockfab.MockCertPool{Err: fmt.Errorf("cert pool error")}}
+	_, err = operationsClient(&fab.PeerConfig{OperationsURL: server.URL, TLSCACert: caCert}, config)
+	assert.Error(t, err)
+}
+
+// operationsTLSConfig is an endpoint config with an empty TLS CA cert pool and the given client certificates
+type operationsTLSConfig struct {
+	*fcmocks.MockConfig
+	clientCerts []tls.Certificate
+}
+
+func newOperationsTLSConfig(t *testing.T, clientCerts ...tls.Certificate) *operationsTLSConfig {
+	certPool, err := commtls.NewCertPool(false)
+	require.NoError(t, err)
+	return &operationsTLSConfig{MockConfig: &fcmocks.MockConfig{CustomTLSCACertPool: certPool}, clientCerts: clientCerts}
+}
+
+func (c *operationsTLSConfig) TLSClientCerts() []tls.Certificate {
+	return c.clientCerts
+}
+
+func protoMarshal(t *testing.T, msg proto.Message) []byte {
+	data, err := proto.Marshal(msg)
+	require.NoError(t, err)
+	return data
+}
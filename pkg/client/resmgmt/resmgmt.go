@@ -582,7 +582,15 @@ func (rc *Client) QueryInstantiatedChaincodes(channelID string, options ...Reque
 		}
 	}
 
-	l, err := channel.NewLedger(channelID)
+	// Channel service membership is required to verify signature
+	channelService := chCtx.ChannelService()
+
+	chConfig, err := channelService.ChannelConfig()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get channel config")
+	}
+
+	l, err := channel.NewLedger(channelID, fab.WithHashingAlgorithm(chConfig.HashingAlgorithm()))
 	if err != nil {
 		return nil, err
 	}
@@ -590,9 +598,6 @@ func (rc *Client) QueryInstantiatedChaincodes(channelID string, options ...Reque
 	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
 	defer cancel()
 
-	// Channel service membership is required to verify signature
-	channelService := chCtx.ChannelService()
-
 	membership, err := channelService.Membership()
 	if err != nil {
 		return nil, errors.WithMessage(err, "membership creation failed")
@@ -642,7 +647,15 @@ func (rc *Client) QueryCollectionsConfig(channelID string, chaincodeName string,
 		}
 	}
 
-	l, err := channel.NewLedger(channelID)
+	// Channel service membership is required to verify signature
+	channelService := chCtx.ChannelService()
+
+	chConfig, err := channelService.ChannelConfig()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get channel config")
+	}
+
+	l, err := channel.NewLedger(channelID, fab.WithHashingAlgorithm(chConfig.HashingAlgorithm()))
 	if err != nil {
 		return nil, err
 	}
@@ -650,9 +663,6 @@ func (rc *Client) QueryCollectionsConfig(channelID string, chaincodeName string,
 	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
 	defer cancel()
 
-	// Channel service membership is required to verify signature
-	channelService := chCtx.ChannelService()
-
 	membership, err := channelService.Membership()
 	if err != nil {
 		return nil, errors.WithMessage(err, "membership creation failed")
@@ -754,10 +764,11 @@ func (rc *Client) getCCProposalTargets(channelID string, opts requestOptions) ([
 }
 
 // createTP
-func (rc *Client) createTP(req InstantiateCCRequest, channelID string, ccProposalType chaincodeProposalType) (*fab.TransactionProposal, fab.TransactionID, error) {
+func (rc *Client) createTP(req InstantiateCCRequest, chConfig fab.ChannelCfg, ccProposalType chaincodeProposalType) (*fab.TransactionProposal, fab.TransactionID, error) {
 	deployProposal := chaincodeDeployRequest(req)
+	channelID := chConfig.ID()
 
-	txID, err := txn.NewHeader(rc.ctx, channelID)
+	txID, err := txn.NewHeader(rc.ctx, channelID, fab.WithHashingAlgorithm(chConfig.HashingAlgorithm()))
 	if err != nil {
 		return nil, fab.EmptyTransactionID, errors.WithMessage(err, "create transaction ID failed")
 	}
@@ -806,8 +817,13 @@ func (rc *Client) sendCCProposal(reqCtx reqContext.Context, ccProposalType chain
 		return fab.EmptyTransactionID, errors.WithMessage(err, "get channel transactor failed")
 	}
 
+	chConfig, err := channelService.ChannelConfig()
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "Unable to get channel config")
+	}
+
 	// create a transaction proposal for chaincode deployment
-	tp, txnID, err := rc.createTP(req, channelID, ccProposalType)
+	tp, txnID, err := rc.createTP(req, chConfig, ccProposalType)
 	if err != nil {
 		return txnID, err
 	}
@@ -161,6 +161,15 @@ var ChannelClientRetryableCodes = map[status.Group][]status.Code{
 	},
 }
 
+// ConflictRetryableCodes are the transaction validation codes that warrant re-submitting
+// a transaction with a new transaction ID
+var ConflictRetryableCodes = map[status.Group][]status.Code{
+	status.EventServerStatus: {
+		status.Code(pb.TxValidationCode_MVCC_READ_CONFLICT),
+		status.Code(pb.TxValidationCode_PHANTOM_READ_CONFLICT),
+	},
+}
+
 // ChannelConfigRetryableCodes error codes to be taken into account for query channel config retry
 var ChannelConfigRetryableCodes = map[status.Group][]status.Code{
 	status.EndorserClientStatus: {status.EndorsementMismatch},
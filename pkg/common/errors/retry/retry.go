@@ -43,6 +43,13 @@ type Handler interface {
 	Required(err error) bool
 }
 
+// BackoffHandler decides whether a retry is required for the given error and returns the
+// backoff period to wait before the retry. Unlike Handler it does not sleep, so the caller
+// may abandon the wait (e.g. if a context is cancelled).
+type BackoffHandler interface {
+	Backoff(err error) (time.Duration, bool)
+}
+
 // impl retry Handler implementation
 type impl struct {
 	opts    Opts
@@ -57,6 +64,11 @@ func New(opts Opts) Handler {
 	return &impl{opts: opts}
 }
 
+// NewBackoff new retry BackoffHandler with the given opts
+func NewBackoff(opts Opts) BackoffHandler {
+	return New(opts).(*impl)
+}
+
 // WithDefaults new retry Handler with default opts
 func WithDefaults() Handler {
 	return &impl{opts: DefaultOpts}
@@ -72,18 +84,28 @@ func WithAttempts(attempts int) Handler {
 // Required determines if retry is required for the given error
 // Note: backoffs are implemented behind this interface
 func (i *impl) Required(err error) bool {
+	backoff, ok := i.Backoff(err)
+	if ok {
+		time.Sleep(backoff)
+	}
+	return ok
+}
+
+// Backoff determines if retry is required for the given error and returns the backoff
+// period to wait before retrying
+func (i *impl) Backoff(err error) (time.Duration, bool) {
 	if i.retries == i.opts.Attempts {
-		return false
+		return 0, false
 	}
 
 	s, ok := status.FromError(err)
 	if ok && i.isRetryable(s.Group, s.Code) {
-		time.Sleep(i.backoffPeriod())
+		backoff := i.backoffPeriod()
 		i.retries++
-		return true
+		return backoff, true
 	}
 
-	return false
+	return 0, false
 }
 
 // backoffPeriod calculates the backoff duration based on the provided opts
@@ -40,6 +40,32 @@ func TestRetryRequired(t *testing.T) {
 	assert.False(t, r.Required(unknownErr), "Expected retry to not be required on unknown error")
 }
 
+func TestRetryBackoff(t *testing.T) {
+	transientErr := status.New(status.EndorserClientStatus,
+		status.EndorsementMismatch.ToInt32(), "", nil)
+
+	r := NewBackoff(Opts{
+		Attempts:       2,
+		BackoffFactor:  2,
+		InitialBackoff: 1 * time.Second,
+		MaxBackoff:     10 * time.Second,
+	})
+
+	start := time.Now()
+	backoff, ok := r.Backoff(transientErr)
+	assert.True(t, ok, "Expected retry to be required on transient error")
+	assert.Equal(t, 1*time.Second, backoff)
+	backoff, ok = r.Backoff(transientErr)
+	assert.True(t, ok, "Expected retry to be required on transient error")
+	assert.Equal(t, 2*time.Second, backoff)
+	assert.True(t, time.Since(start) < time.Second, "Expected Backoff not to sleep")
+
+	_, ok = r.Backoff(transientErr)
+	assert.False(t, ok, "Expected retry to not be required after exhausting attempts")
+	_, ok = NewBackoff(DefaultOpts).Backoff(fmt.Errorf("Unknown"))
+	assert.False(t, ok, "Expected retry to not be required on unknown error")
+}
+
 func TestBackoffPeriod(t *testing.T) {
 	testAttempts := 10
 	testBackoffFactor := 3.34
@@ -8,6 +8,7 @@ package fab
 
 import (
 	reqContext "context"
+	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	mspCfg "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
@@ -55,6 +56,45 @@ type ChannelCfg interface {
 	Orderers() []string
 	Versions() *Versions
 	HasCapability(group ConfigGroupKey, capability string) bool
+	// HashingAlgorithm returns the algorithm used by the channel to compute transaction IDs
+	// and block hashes (e.g. SHA256 or SM3)
+	HashingAlgorithm() string
+	// Policies returns the policies defined in the channel config keyed by their
+	// fully qualified path (e.g. /Channel/Application/Writers)
+	Policies() map[string]*ChannelPolicy
+	// OrdererConfig returns the orderer settings of the channel (or nil if the config has no orderer group)
+	OrdererConfig() *ChannelOrdererConfig
+	// ACLs returns the application ACLs which map a resource to a policy reference
+	ACLs() map[string]string
+	// Consortium returns the name of the consortium which created the channel
+	Consortium() string
+}
+
+// ChannelPolicy is a policy defined in the channel config
+type ChannelPolicy struct {
+	Type      common.Policy_PolicyType
+	ModPolicy string
+	// Signature is set for SIGNATURE policies
+	Signature *common.SignaturePolicyEnvelope
+	// ImplicitMeta is set for IMPLICIT_META policies
+	ImplicitMeta *common.ImplicitMetaPolicy
+}
+
+// ChannelOrdererConfig contains the orderer settings of a channel
+type ChannelOrdererConfig struct {
+	ConsensusType     string
+	ConsensusMetadata []byte
+	BatchSize         BatchSize
+	BatchTimeout      time.Duration
+	// MaxChannels is the maximum number of channels that the orderer allows (0 means unlimited)
+	MaxChannels uint64
+}
+
+// BatchSize contains the limits on the number of messages and bytes in a block
+type BatchSize struct {
+	MaxMessageCount   uint32
+	AbsoluteMaxBytes  uint32
+	PreferredMaxBytes uint32
 }
 
 // ChannelMembership helps identify a channel's members
@@ -31,6 +31,15 @@ type ChannelEndpointConfig struct {
 	Peers map[string]PeerChannelConfig
 	//Policies list of policies for channel
 	Policies ChannelPolicies
+	// Chaincodes contains the configuration of the chaincodes on the channel, by chaincode name (in lower case)
+	Chaincodes map[string]ChaincodeConfig
+}
+
+// ChaincodeConfig contains the configuration of a chaincode on a channel
+type ChaincodeConfig struct {
+	// Policy is the endorsement policy of the chaincode (for example, "OR('Org1MSP.peer','Org2MSP.peer')")
+	// which is used by static selection to choose a minimal set of endorsers
+	Policy string
 }
 
 //ChannelPolicies defines list of policies defined for a channel
@@ -133,9 +142,10 @@ type OrdererConfig struct {
 
 // PeerConfig defines a peer configuration
 type PeerConfig struct {
-	URL         string
-	GRPCOptions map[string]interface{}
-	TLSCACert   *sm2.Certificate
+	URL           string
+	GRPCOptions   map[string]interface{}
+	TLSCACert     *sm2.Certificate
+	OperationsURL string // URL of the peer's operations service (/healthz, /version), if configured
 }
 
 // CertKeyPair contains the private key and certificate
@@ -162,6 +172,18 @@ const (
 	PreferOrgStrategy ResolverStrategy = "PreferOrg"
 )
 
+// EventServiceType specifies the type of event service
+type EventServiceType string
+
+const (
+	// DeliverEventServiceType connects to a single peer (chosen by the peer resolver) and receives events from that peer
+	DeliverEventServiceType EventServiceType = "Deliver"
+
+	// ConsensusEventServiceType connects to multiple peers (across different orgs where possible) and publishes events
+	// for a block only once a quorum of the peers has delivered a byte-identical block header.
+	ConsensusEventServiceType EventServiceType = "Consensus"
+)
+
 // MinBlockHeightResolverMode specifies the behaviour of the MinBlockHeight resolver strategy.
 type MinBlockHeightResolverMode string
 
@@ -186,6 +208,19 @@ const (
 
 // EventServicePolicy specifies the policy for the event service
 type EventServicePolicy struct {
+	// Type is the type of event service (Deliver or Consensus)
+	// Default: Deliver
+	Type EventServiceType
+
+	// ConsensusPeers is the number of peers to which the Consensus event service connects
+	// Default: 3
+	ConsensusPeers int
+
+	// ConsensusQuorum is the number of peers which must deliver an identical block before the
+	// Consensus event service publishes events for the block
+	// Default: a majority of ConsensusPeers
+	ConsensusQuorum int
+
 	// ResolverStrategy returns the peer resolver strategy to use when connecting to a peer
 	// Default: MinBlockHeightPeerResolver
 	ResolverStrategy ResolverStrategy
@@ -19,8 +19,9 @@ type ProposalProcessor interface {
 
 // TxnHeaderOptions contains options for creating a Transaction Header
 type TxnHeaderOptions struct {
-	Nonce   []byte
-	Creator []byte
+	Nonce            []byte
+	Creator          []byte
+	HashingAlgorithm string
 }
 
 // TxnHeaderOpt is a Transaction Header option
@@ -33,6 +34,14 @@ func WithNonce(nonce []byte) TxnHeaderOpt {
 	}
 }
 
+// WithHashingAlgorithm specifies the channel hashing algorithm (e.g. SHA256 or SM3) used to compute
+// the transaction ID. SHA-256 is used if not specified.
+func WithHashingAlgorithm(algorithm string) TxnHeaderOpt {
+	return func(options *TxnHeaderOptions) {
+		options.HashingAlgorithm = algorithm
+	}
+}
+
 // WithCreator specifies the creator to use when creating the Transaction Header
 func WithCreator(creator []byte) TxnHeaderOpt {
 	return func(options *TxnHeaderOptions) {
@@ -15,6 +15,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/tjfoc/gmsm/sm2"
 	tls "github.com/tjfoc/gmtls"
@@ -157,6 +158,7 @@ type Providers interface {
 	InfraProvider() InfraProvider
 	EndpointConfig() EndpointConfig
 	MetricsProvider
+	TracerProvider
 }
 
 // CertPool is a thread safe wrapper around the x509 standard library
@@ -173,3 +175,8 @@ type CertPool interface {
 type MetricsProvider interface {
 	GetMetrics() *metrics.ClientMetrics
 }
+
+// TracerProvider represents a provider of a tracer.
+type TracerProvider interface {
+	Tracer() tracing.Tracer
+}
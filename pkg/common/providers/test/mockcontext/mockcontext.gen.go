@@ -11,6 +11,7 @@ import (
 	core "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	fab "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	msp "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	tracing "github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	metrics "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 )
 
@@ -93,6 +94,20 @@ func (mr *MockProvidersMockRecorder) GetMetrics() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetrics", reflect.TypeOf((*MockProviders)(nil).GetMetrics))
 }
 
+// Tracer mocks base method
+func (m *MockProviders) Tracer() tracing.Tracer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tracer")
+	ret0, _ := ret[0].(tracing.Tracer)
+	return ret0
+}
+
+// Tracer indicates an expected call of Tracer
+func (mr *MockProvidersMockRecorder) Tracer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tracer", reflect.TypeOf((*MockProviders)(nil).Tracer))
+}
+
 // IdentityConfig mocks base method
 func (m *MockProviders) IdentityConfig() msp.IdentityConfig {
 	m.ctrl.T.Helper()
@@ -271,6 +286,20 @@ func (mr *MockClientMockRecorder) GetMetrics() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetrics", reflect.TypeOf((*MockClient)(nil).GetMetrics))
 }
 
+// Tracer mocks base method
+func (m *MockClient) Tracer() tracing.Tracer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tracer")
+	ret0, _ := ret[0].(tracing.Tracer)
+	return ret0
+}
+
+// Tracer indicates an expected call of Tracer
+func (mr *MockClientMockRecorder) Tracer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tracer", reflect.TypeOf((*MockClient)(nil).Tracer))
+}
+
 // Identifier mocks base method
 func (m *MockClient) Identifier() *msp.IdentityIdentifier {
 	m.ctrl.T.Helper()
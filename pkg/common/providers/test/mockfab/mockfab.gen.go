@@ -13,6 +13,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	fab "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	tracing "github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	metrics "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 )
 
@@ -327,6 +328,20 @@ func (mr *MockProvidersMockRecorder) GetMetrics() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetrics", reflect.TypeOf((*MockProviders)(nil).GetMetrics))
 }
 
+// Tracer mocks base method
+func (m *MockProviders) Tracer() tracing.Tracer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tracer")
+	ret0, _ := ret[0].(tracing.Tracer)
+	return ret0
+}
+
+// Tracer indicates an expected call of Tracer
+func (mr *MockProvidersMockRecorder) Tracer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tracer", reflect.TypeOf((*MockProviders)(nil).Tracer))
+}
+
 // InfraProvider mocks base method
 func (m *MockProviders) InfraProvider() fab.InfraProvider {
 	m.ctrl.T.Helper()
@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MockTracer is a Tracer that records the spans that were started. It is intended for unit tests.
+type MockTracer struct {
+	mutex  sync.RWMutex
+	spans  []*MockSpan
+	nextID int
+}
+
+// MockSpan is a span recorded by the MockTracer
+type MockSpan struct {
+	mutex         sync.RWMutex
+	ID            int
+	ParentID      int
+	OperationName string
+	Tags          map[string]interface{}
+	Err           error
+	Finished      bool
+}
+
+type mockSpanKey struct{}
+
+// NewMockTracer returns a new MockTracer
+func NewMockTracer() *MockTracer {
+	return &MockTracer{}
+}
+
+// StartSpan records a new span as a child of the span in the given context (if any)
+func (t *MockTracer) StartSpan(ctx context.Context, operationName string) (Span, context.Context) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.nextID++
+	span := &MockSpan{
+		ID:            t.nextID,
+		OperationName: operationName,
+		Tags:          make(map[string]interface{}),
+	}
+	if parent, ok := ctx.Value(mockSpanKey{}).(*MockSpan); ok {
+		span.ParentID = parent.ID
+	}
+	t.spans = append(t.spans, span)
+
+	return span, context.WithValue(ctx, mockSpanKey{}, span)
+}
+
+// Inject writes the ID of the current span into the carrier
+func (t *MockTracer) Inject(ctx context.Context, carrier map[string]string) {
+	if span, ok := ctx.Value(mockSpanKey{}).(*MockSpan); ok {
+		carrier[MockSpanIDKey] = fmt.Sprintf("%d", span.ID)
+	}
+}
+
+// MockSpanIDKey is the carrier key used by the MockTracer to propagate the span ID
+const MockSpanIDKey = "mock-span-id"
+
+// Spans returns the recorded spans
+func (t *MockTracer) Spans() []*MockSpan {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	spans := make([]*MockSpan, len(t.spans))
+	copy(spans, t.spans)
+	return spans
+}
+
+// SpansByName returns the recorded spans with the given operation name
+func (t *MockTracer) SpansByName(operationName string) []*MockSpan {
+	var spans []*MockSpan
+	for _, span := range t.Spans() {
+		if span.OperationName == operationName {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// SetTag sets a tag on the span
+func (s *MockSpan) SetTag(key string, value interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Tags[key] = value
+}
+
+// SetError sets the error on the span
+func (s *MockSpan) SetError(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Err = err
+}
+
+// Finish marks the span as finished
+func (s *MockSpan) Finish() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.Finished = true
+}
+
+// IsFinished returns true if the span was finished
+func (s *MockSpan) IsFinished() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.Finished
+}
@@ -0,0 +1,31 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"context"
+)
+
+var noopTracer = &NoopTracer{}
+
+// NoopTracer is a Tracer that does nothing. It is used when no tracer is configured.
+type NoopTracer struct{}
+
+// StartSpan returns a no-op span and the given context
+func (t *NoopTracer) StartSpan(ctx context.Context, operationName string) (Span, context.Context) {
+	return noopSpan{}, ctx
+}
+
+// Inject does nothing
+func (t *NoopTracer) Inject(ctx context.Context, carrier map[string]string) {
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetTag(key string, value interface{}) {}
+func (noopSpan) SetError(err error)                   {}
+func (noopSpan) Finish()                              {}
@@ -0,0 +1,131 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package tracing provides a pluggable, OpenTelemetry-style tracing abstraction.
+// Spans are created for the individual steps of a request (selection, endorsement,
+// validation, broadcast and commit) and the trace context is propagated to peers and
+// orderers as gRPC metadata.
+package tracing
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Span names used by the SDK
+const (
+	SpanInvoke      = "channel.Invoke"
+	SpanSelection   = "selection"
+	SpanEndorsement = "endorsement"
+	SpanValidation  = "endorsement.validation"
+	SpanBroadcast   = "broadcast"
+	SpanCommit      = "commit"
+)
+
+// Tag keys used by the SDK
+const (
+	TagChannelID   = "channel"
+	TagChaincodeID = "chaincode"
+	TagFcn         = "fcn"
+	TagTxnID       = "txn_id"
+	TagEndpoint    = "endpoint"
+	TagNumTargets  = "num_targets"
+	TagTxnStatus   = "txn_status"
+)
+
+// Span is a single, timed operation within a trace
+type Span interface {
+	// SetTag sets a key/value tag on the span
+	SetTag(key string, value interface{})
+	// SetError marks the span as failed with the given error
+	SetError(err error)
+	// Finish completes the span
+	Finish()
+}
+
+// Tracer creates spans and propagates trace context across process boundaries
+type Tracer interface {
+	// StartSpan starts a new span. If the given context contains a span then the
+	// new span is created as its child. The returned context contains the new span.
+	StartSpan(ctx context.Context, operationName string) (Span, context.Context)
+
+	// Inject writes the trace context of the span contained in the given context
+	// into the carrier, which is sent to the remote endpoint as gRPC metadata.
+	Inject(ctx context.Context, carrier map[string]string)
+}
+
+type tracerKey struct{}
+
+// WithTracer returns a copy of the given context which contains the tracer
+func WithTracer(ctx context.Context, tracer Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, tracer)
+}
+
+// FromContext returns the tracer contained in the given context. A no-op tracer
+// is returned if the context does not contain a tracer.
+func FromContext(ctx context.Context) Tracer {
+	if ctx != nil {
+		if tracer, ok := ctx.Value(tracerKey{}).(Tracer); ok && tracer != nil {
+			return tracer
+		}
+	}
+	return noopTracer
+}
+
+// HasTracer returns true if the given context contains a tracer
+func HasTracer(ctx context.Context) bool {
+	_, ok := ctx.Value(tracerKey{}).(Tracer)
+	return ok
+}
+
+// StartSpan starts a new span using the tracer contained in the given context
+func StartSpan(ctx context.Context, operationName string) (Span, context.Context) {
+	return FromContext(ctx).StartSpan(ctx, operationName)
+}
+
+// Finish sets the error (if any) on the span and finishes it
+func Finish(span Span, err error) {
+	if err != nil {
+		span.SetError(err)
+	}
+	span.Finish()
+}
+
+// UnaryClientInterceptor returns a gRPC interceptor that injects the trace context
+// of the current span into the outgoing metadata of unary calls
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(inject(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor returns a gRPC interceptor that injects the trace context
+// of the current span into the outgoing metadata of streams
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(inject(ctx), desc, cc, method, opts...)
+	}
+}
+
+func inject(ctx context.Context) context.Context {
+	if !HasTracer(ctx) {
+		return ctx
+	}
+
+	carrier := make(map[string]string)
+	FromContext(ctx).Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return ctx
+	}
+
+	kv := make([]string, 0, 2*len(carrier))
+	for k, v := range carrier {
+		kv = append(kv, k, v)
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
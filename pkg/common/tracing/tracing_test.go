@@ -0,0 +1,96 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestNoopTracer(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, HasTracer(ctx))
+	assert.IsType(t, &NoopTracer{}, FromContext(ctx))
+
+	span, spanCtx := StartSpan(ctx, "test")
+	assert.Equal(t, ctx, spanCtx)
+	span.SetTag("key", "value")
+	Finish(span, errors.New("test"))
+}
+
+func TestStartSpan(t *testing.T) {
+	tracer := NewMockTracer()
+	ctx := WithTracer(context.Background(), tracer)
+	assert.True(t, HasTracer(ctx))
+
+	parent, ctx := StartSpan(ctx, "parent")
+	child, _ := StartSpan(ctx, "child")
+	child.SetTag(TagEndpoint, "peer1")
+	Finish(child, errors.New("child failed"))
+	Finish(parent, nil)
+
+	spans := tracer.Spans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "parent", spans[0].OperationName)
+	assert.Equal(t, 0, spans[0].ParentID)
+	assert.NoError(t, spans[0].Err)
+	assert.True(t, spans[0].IsFinished())
+
+	assert.Equal(t, "child", spans[1].OperationName)
+	assert.Equal(t, spans[0].ID, spans[1].ParentID)
+	assert.Equal(t, "peer1", spans[1].Tags[TagEndpoint])
+	assert.EqualError(t, spans[1].Err, "child failed")
+	assert.True(t, spans[1].IsFinished())
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+
+	var md metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	// No tracer
+	err := interceptor(context.Background(), "method", nil, nil, nil, invoker)
+	require.NoError(t, err)
+	assert.Empty(t, md.Get(MockSpanIDKey))
+
+	tracer := NewMockTracer()
+	span, ctx := StartSpan(WithTracer(context.Background(), tracer), "test")
+	defer span.Finish()
+
+	err = interceptor(ctx, "method", nil, nil, nil, invoker)
+	require.NoError(t, err)
+	assert.Equal(t, []string{fmt.Sprintf("%d", span.(*MockSpan).ID)}, md.Get(MockSpanIDKey))
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	interceptor := StreamClientInterceptor()
+
+	var md metadata.MD
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		md, _ = metadata.FromOutgoingContext(ctx)
+		return nil, nil
+	}
+
+	tracer := NewMockTracer()
+	span, ctx := StartSpan(WithTracer(context.Background(), tracer), "test")
+	defer span.Finish()
+
+	_, err := interceptor(ctx, &grpc.StreamDesc{}, nil, "method", streamer)
+	require.NoError(t, err)
+	assert.Equal(t, []string{fmt.Sprintf("%d", span.(*MockSpan).ID)}, md.Get(MockSpanIDKey))
+}
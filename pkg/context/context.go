@@ -17,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 )
 
@@ -75,6 +76,7 @@ type Provider struct {
 	infraProvider          fab.InfraProvider
 	channelProvider        fab.ChannelProvider
 	clientMetrics          *metrics.ClientMetrics
+	tracer                 tracing.Tracer
 }
 
 // CryptoSuite returns the BCCSP provider of sdk.
@@ -124,9 +126,20 @@ func (c *Provider) EndpointConfig() fab.EndpointConfig {
 
 // GetMetrics will return the SDK's metrics instance
 func (c *Provider) GetMetrics() *metrics.ClientMetrics {
+	if c.clientMetrics == nil {
+		return metrics.NewDisabledClientMetrics()
+	}
 	return c.clientMetrics
 }
 
+// Tracer returns the tracer used to trace requests
+func (c *Provider) Tracer() tracing.Tracer {
+	if c.tracer == nil {
+		return &tracing.NoopTracer{}
+	}
+	return c.tracer
+}
+
 //SDKContextParams parameter for creating FabContext
 type SDKContextParams func(opts *Provider)
 
@@ -207,6 +220,13 @@ func WithClientMetrics(cm *metrics.ClientMetrics) SDKContextParams {
 	}
 }
 
+//WithTracer sets the tracer to Context Provider
+func WithTracer(tracer tracing.Tracer) SDKContextParams {
+	return func(ctx *Provider) {
+		ctx.tracer = tracer
+	}
+}
+
 //NewProvider creates new context client provider
 // Not be used by end developers, fabsdk package use only
 func NewProvider(params ...SDKContextParams) *Provider {
@@ -346,6 +366,9 @@ func NewRequest(client context.Client, options ...ReqContextOptions) (reqContext
 
 	ctx := reqContext.WithValue(parentContext, reqContextCommManager, client.InfraProvider().CommManager())
 	ctx = reqContext.WithValue(ctx, reqContextClient, client)
+	if !tracing.HasTracer(ctx) {
+		ctx = tracing.WithTracer(ctx, client.Tracer())
+	}
 	ctx, cancel := reqContext.WithTimeout(ctx, timeout)
 
 	return ctx, cancel
@@ -0,0 +1,343 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"time"
+)
+
+// Profile is a typed representation of the SDK connection profile. A Profile may be built
+// programmatically and passed to FromProfile, or loaded from (and persisted to) JSON or YAML
+// using UnmarshalProfile and MarshalProfile.
+//
+// Fields which are left at their zero value are omitted from the resulting configuration so that
+// the SDK defaults apply. Pointers are used for boolean options whose default is true.
+type Profile struct {
+	Version                string                         `json:"version,omitempty" yaml:"version,omitempty"`
+	Name                   string                         `json:"name,omitempty" yaml:"name,omitempty"`
+	Description            string                         `json:"description,omitempty" yaml:"description,omitempty"`
+	Client                 ClientProfile                  `json:"client,omitempty" yaml:"client,omitempty"`
+	Channels               map[string]ChannelProfile      `json:"channels,omitempty" yaml:"channels,omitempty"`
+	Organizations          map[string]OrganizationProfile `json:"organizations,omitempty" yaml:"organizations,omitempty"`
+	Orderers               map[string]EndpointProfile     `json:"orderers,omitempty" yaml:"orderers,omitempty"`
+	Peers                  map[string]EndpointProfile     `json:"peers,omitempty" yaml:"peers,omitempty"`
+	CertificateAuthorities map[string]CAProfile           `json:"certificateAuthorities,omitempty" yaml:"certificateAuthorities,omitempty"`
+	EntityMatchers         EntityMatchersProfile          `json:"entityMatchers,omitempty" yaml:"entityMatchers,omitempty"`
+	Operations             OperationsProfile              `json:"operations,omitempty" yaml:"operations,omitempty"`
+	Metrics                MetricsProfile                 `json:"metrics,omitempty" yaml:"metrics,omitempty"`
+}
+
+// ClientProfile contains the client section of the connection profile
+type ClientProfile struct {
+	Organization    string                 `json:"organization,omitempty" yaml:"organization,omitempty"`
+	Logging         LoggingProfile         `json:"logging,omitempty" yaml:"logging,omitempty"`
+	CryptoConfig    PathProfile            `json:"cryptoconfig,omitempty" yaml:"cryptoconfig,omitempty"`
+	CredentialStore CredentialStoreProfile `json:"credentialStore,omitempty" yaml:"credentialStore,omitempty"`
+	BCCSP           BCCSPProfile           `json:"BCCSP,omitempty" yaml:"BCCSP,omitempty"`
+	TLSCerts        ClientTLSProfile       `json:"tlsCerts,omitempty" yaml:"tlsCerts,omitempty"`
+	Peer            PeerTimeoutsProfile    `json:"peer,omitempty" yaml:"peer,omitempty"`
+	Orderer         OrdererTimeoutsProfile `json:"orderer,omitempty" yaml:"orderer,omitempty"`
+	EventService    EventServiceProfile    `json:"eventService,omitempty" yaml:"eventService,omitempty"`
+	Discovery       DiscoveryProfile       `json:"discovery,omitempty" yaml:"discovery,omitempty"`
+	Global          GlobalProfile          `json:"global,omitempty" yaml:"global,omitempty"`
+	Cache           CacheProfile           `json:"cache,omitempty" yaml:"cache,omitempty"`
+}
+
+// LoggingProfile contains the client logging configuration
+type LoggingProfile struct {
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+}
+
+// PathProfile contains a single path
+type PathProfile struct {
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// CredentialStoreProfile contains the user and crypto store locations
+type CredentialStoreProfile struct {
+	Path        string      `json:"path,omitempty" yaml:"path,omitempty"`
+	CryptoStore PathProfile `json:"cryptoStore,omitempty" yaml:"cryptoStore,omitempty"`
+}
+
+// BCCSPProfile contains the crypto suite configuration
+type BCCSPProfile struct {
+	Security SecurityProfile `json:"security,omitempty" yaml:"security,omitempty"`
+}
+
+// SecurityProfile contains the crypto suite security options
+type SecurityProfile struct {
+	Enabled       *bool           `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Default       ProviderProfile `json:"default,omitempty" yaml:"default,omitempty"`
+	HashAlgorithm string          `json:"hashAlgorithm,omitempty" yaml:"hashAlgorithm,omitempty"`
+	SoftVerify    *bool           `json:"softVerify,omitempty" yaml:"softVerify,omitempty"`
+	Level         int             `json:"level,omitempty" yaml:"level,omitempty"`
+	Ephemeral     *bool           `json:"ephemeral,omitempty" yaml:"ephemeral,omitempty"`
+	Pin           string          `json:"pin,omitempty" yaml:"pin,omitempty"`
+	Label         string          `json:"label,omitempty" yaml:"label,omitempty"`
+	Library       string          `json:"library,omitempty" yaml:"library,omitempty"`
+}
+
+// ProviderProfile names the default crypto suite provider (SW or PKCS11)
+type ProviderProfile struct {
+	Provider string `json:"provider,omitempty" yaml:"provider,omitempty"`
+}
+
+// ClientTLSProfile contains the client TLS configuration
+type ClientTLSProfile struct {
+	SystemCertPool bool              `json:"systemCertPool,omitempty" yaml:"systemCertPool,omitempty"`
+	Client         TLSKeyPairProfile `json:"client,omitempty" yaml:"client,omitempty"`
+}
+
+// TLSKeyPairProfile contains a private key and certificate
+type TLSKeyPairProfile struct {
+	Key  TLSProfile `json:"key,omitempty" yaml:"key,omitempty"`
+	Cert TLSProfile `json:"cert,omitempty" yaml:"cert,omitempty"`
+}
+
+// TLSProfile references a certificate or key either by path or by its PEM content.
+// If both are set then Pem takes precedence.
+type TLSProfile struct {
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	Pem  string `json:"pem,omitempty" yaml:"pem,omitempty"`
+}
+
+// PeerTimeoutsProfile contains the peer timeouts
+type PeerTimeoutsProfile struct {
+	Timeout PeerTimeoutProfile `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// PeerTimeoutProfile contains the peer timeout values
+type PeerTimeoutProfile struct {
+	Connection time.Duration           `json:"connection,omitempty" yaml:"connection,omitempty"`
+	Response   time.Duration           `json:"response,omitempty" yaml:"response,omitempty"`
+	Discovery  DiscoveryTimeoutProfile `json:"discovery,omitempty" yaml:"discovery,omitempty"`
+}
+
+// DiscoveryTimeoutProfile contains the peer discovery timeouts
+type DiscoveryTimeoutProfile struct {
+	GreylistExpiry time.Duration `json:"greylistExpiry,omitempty" yaml:"greylistExpiry,omitempty"`
+}
+
+// OrdererTimeoutsProfile contains the orderer timeouts
+type OrdererTimeoutsProfile struct {
+	Timeout ConnectionTimeoutProfile `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// ConnectionTimeoutProfile contains connection and response timeouts
+type ConnectionTimeoutProfile struct {
+	Connection time.Duration `json:"connection,omitempty" yaml:"connection,omitempty"`
+	Response   time.Duration `json:"response,omitempty" yaml:"response,omitempty"`
+}
+
+// EventServiceProfile contains the event service timeouts
+type EventServiceProfile struct {
+	Timeout EventServiceTimeoutProfile `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// EventServiceTimeoutProfile contains the event service timeout values
+type EventServiceTimeoutProfile struct {
+	RegistrationResponse time.Duration `json:"registrationResponse,omitempty" yaml:"registrationResponse,omitempty"`
+}
+
+// DiscoveryProfile contains the discovery service timeouts
+type DiscoveryProfile struct {
+	Timeout ConnectionTimeoutProfile `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// GlobalProfile contains the global timeouts and cache expiry settings
+type GlobalProfile struct {
+	Timeout GlobalTimeoutProfile `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Cache   GlobalCacheProfile   `json:"cache,omitempty" yaml:"cache,omitempty"`
+}
+
+// GlobalTimeoutProfile contains the global timeout values
+type GlobalTimeoutProfile struct {
+	Query   time.Duration `json:"query,omitempty" yaml:"query,omitempty"`
+	Execute time.Duration `json:"execute,omitempty" yaml:"execute,omitempty"`
+	Resmgmt time.Duration `json:"resmgmt,omitempty" yaml:"resmgmt,omitempty"`
+}
+
+// GlobalCacheProfile contains the cache expiry values
+type GlobalCacheProfile struct {
+	ConnectionIdle    time.Duration `json:"connectionIdle,omitempty" yaml:"connectionIdle,omitempty"`
+	EventServiceIdle  time.Duration `json:"eventServiceIdle,omitempty" yaml:"eventServiceIdle,omitempty"`
+	ChannelConfig     time.Duration `json:"channelConfig,omitempty" yaml:"channelConfig,omitempty"`
+	ChannelMembership time.Duration `json:"channelMembership,omitempty" yaml:"channelMembership,omitempty"`
+	Discovery         time.Duration `json:"discovery,omitempty" yaml:"discovery,omitempty"`
+	Selection         time.Duration `json:"selection,omitempty" yaml:"selection,omitempty"`
+}
+
+// CacheProfile contains the cache sweep interval
+type CacheProfile struct {
+	Interval CacheIntervalProfile `json:"interval,omitempty" yaml:"interval,omitempty"`
+}
+
+// CacheIntervalProfile contains the cache interval values
+type CacheIntervalProfile struct {
+	Sweep time.Duration `json:"sweep,omitempty" yaml:"sweep,omitempty"`
+}
+
+// ChannelProfile contains the configuration of a channel
+type ChannelProfile struct {
+	Orderers   []string                      `json:"orderers,omitempty" yaml:"orderers,omitempty"`
+	Peers      map[string]PeerChannelProfile `json:"peers,omitempty" yaml:"peers,omitempty"`
+	Policies   ChannelPoliciesProfile        `json:"policies,omitempty" yaml:"policies,omitempty"`
+	Chaincodes map[string]ChaincodeProfile   `json:"chaincodes,omitempty" yaml:"chaincodes,omitempty"`
+}
+
+// PeerChannelProfile contains the roles of a peer on a channel. Roles which are not set default to true.
+type PeerChannelProfile struct {
+	EndorsingPeer  *bool `json:"endorsingPeer,omitempty" yaml:"endorsingPeer,omitempty"`
+	ChaincodeQuery *bool `json:"chaincodeQuery,omitempty" yaml:"chaincodeQuery,omitempty"`
+	LedgerQuery    *bool `json:"ledgerQuery,omitempty" yaml:"ledgerQuery,omitempty"`
+	EventSource    *bool `json:"eventSource,omitempty" yaml:"eventSource,omitempty"`
+}
+
+// ChaincodeProfile contains the configuration of a chaincode on a channel
+type ChaincodeProfile struct {
+	Policy string `json:"policy,omitempty" yaml:"policy,omitempty"`
+}
+
+// ChannelPoliciesProfile contains the channel policies
+type ChannelPoliciesProfile struct {
+	QueryChannelConfig QueryPolicyProfile        `json:"queryChannelConfig,omitempty" yaml:"queryChannelConfig,omitempty"`
+	Discovery          QueryPolicyProfile        `json:"discovery,omitempty" yaml:"discovery,omitempty"`
+	Selection          SelectionPolicyProfile    `json:"selection,omitempty" yaml:"selection,omitempty"`
+	EventService       EventServicePolicyProfile `json:"eventService,omitempty" yaml:"eventService,omitempty"`
+}
+
+// QueryPolicyProfile contains the policy for querying channel config or discovery
+type QueryPolicyProfile struct {
+	MinResponses int              `json:"minResponses,omitempty" yaml:"minResponses,omitempty"`
+	MaxTargets   int              `json:"maxTargets,omitempty" yaml:"maxTargets,omitempty"`
+	RetryOpts    RetryOptsProfile `json:"retryOpts,omitempty" yaml:"retryOpts,omitempty"`
+}
+
+// RetryOptsProfile contains retry options
+type RetryOptsProfile struct {
+	Attempts       int           `json:"attempts,omitempty" yaml:"attempts,omitempty"`
+	InitialBackoff time.Duration `json:"initialBackoff,omitempty" yaml:"initialBackoff,omitempty"`
+	MaxBackoff     time.Duration `json:"maxBackoff,omitempty" yaml:"maxBackoff,omitempty"`
+	BackoffFactor  float64       `json:"backoffFactor,omitempty" yaml:"backoffFactor,omitempty"`
+}
+
+// SelectionPolicyProfile contains the endorser selection policy
+type SelectionPolicyProfile struct {
+	SortingStrategy         string `json:"sortingStrategy,omitempty" yaml:"sortingStrategy,omitempty"`
+	Balancer                string `json:"balancer,omitempty" yaml:"balancer,omitempty"`
+	BlockHeightLagThreshold int    `json:"blockHeightLagThreshold,omitempty" yaml:"blockHeightLagThreshold,omitempty"`
+}
+
+// EventServicePolicyProfile contains the event service policy
+type EventServicePolicyProfile struct {
+	Type                             string        `json:"type,omitempty" yaml:"type,omitempty"`
+	ConsensusPeers                   int           `json:"consensusPeers,omitempty" yaml:"consensusPeers,omitempty"`
+	ConsensusQuorum                  int           `json:"consensusQuorum,omitempty" yaml:"consensusQuorum,omitempty"`
+	ResolverStrategy                 string        `json:"resolverStrategy,omitempty" yaml:"resolverStrategy,omitempty"`
+	MinBlockHeightResolverMode       string        `json:"minBlockHeightResolverMode,omitempty" yaml:"minBlockHeightResolverMode,omitempty"`
+	Balancer                         string        `json:"balancer,omitempty" yaml:"balancer,omitempty"`
+	BlockHeightLagThreshold          int           `json:"blockHeightLagThreshold,omitempty" yaml:"blockHeightLagThreshold,omitempty"`
+	PeerMonitor                      string        `json:"peerMonitor,omitempty" yaml:"peerMonitor,omitempty"`
+	ReconnectBlockHeightLagThreshold int           `json:"reconnectBlockHeightLagThreshold,omitempty" yaml:"reconnectBlockHeightLagThreshold,omitempty"`
+	PeerMonitorPeriod                time.Duration `json:"peerMonitorPeriod,omitempty" yaml:"peerMonitorPeriod,omitempty"`
+}
+
+// OrganizationProfile contains the configuration of an organization
+type OrganizationProfile struct {
+	MSPID                  string                       `json:"mspid,omitempty" yaml:"mspid,omitempty"`
+	CryptoPath             string                       `json:"cryptoPath,omitempty" yaml:"cryptoPath,omitempty"`
+	Users                  map[string]TLSKeyPairProfile `json:"users,omitempty" yaml:"users,omitempty"`
+	Peers                  []string                     `json:"peers,omitempty" yaml:"peers,omitempty"`
+	CertificateAuthorities []string                     `json:"certificateAuthorities,omitempty" yaml:"certificateAuthorities,omitempty"`
+}
+
+// EndpointProfile contains the configuration of a peer or orderer
+type EndpointProfile struct {
+	URL           string                 `json:"url,omitempty" yaml:"url,omitempty"`
+	GRPCOptions   map[string]interface{} `json:"grpcOptions,omitempty" yaml:"grpcOptions,omitempty"`
+	TLSCACerts    TLSProfile             `json:"tlsCACerts,omitempty" yaml:"tlsCACerts,omitempty"`
+	OperationsURL string                 `json:"operationsURL,omitempty" yaml:"operationsURL,omitempty"` // peers only
+}
+
+// CAProfile contains the configuration of a certificate authority
+type CAProfile struct {
+	URL         string                 `json:"url,omitempty" yaml:"url,omitempty"`
+	CAName      string                 `json:"caName,omitempty" yaml:"caName,omitempty"`
+	GRPCOptions map[string]interface{} `json:"grpcOptions,omitempty" yaml:"grpcOptions,omitempty"`
+	TLSCACerts  MutualTLSProfile       `json:"tlsCACerts,omitempty" yaml:"tlsCACerts,omitempty"`
+	Registrar   RegistrarProfile       `json:"registrar,omitempty" yaml:"registrar,omitempty"`
+}
+
+// MutualTLSProfile contains the CA root certificates and the client key pair
+type MutualTLSProfile struct {
+	Pem    []string          `json:"pem,omitempty" yaml:"pem,omitempty"`
+	Path   string            `json:"path,omitempty" yaml:"path,omitempty"`
+	Client TLSKeyPairProfile `json:"client,omitempty" yaml:"client,omitempty"`
+}
+
+// RegistrarProfile contains the CA registrar credentials
+type RegistrarProfile struct {
+	EnrollID     string `json:"enrollId,omitempty" yaml:"enrollId,omitempty"`
+	EnrollSecret string `json:"enrollSecret,omitempty" yaml:"enrollSecret,omitempty"`
+}
+
+// EntityMatchersProfile contains the entity matchers for each type of entity
+type EntityMatchersProfile struct {
+	Peer                 []MatcherProfile `json:"peer,omitempty" yaml:"peer,omitempty"`
+	Orderer              []MatcherProfile `json:"orderer,omitempty" yaml:"orderer,omitempty"`
+	CertificateAuthority []MatcherProfile `json:"certificateAuthority,omitempty" yaml:"certificateAuthority,omitempty"`
+	Channel              []MatcherProfile `json:"channel,omitempty" yaml:"channel,omitempty"`
+}
+
+// MatcherProfile maps entities whose name matches Pattern to a configured entity
+type MatcherProfile struct {
+	Pattern                             string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	URLSubstitutionExp                  string `json:"urlSubstitutionExp,omitempty" yaml:"urlSubstitutionExp,omitempty"`
+	SSLTargetOverrideURLSubstitutionExp string `json:"sslTargetOverrideUrlSubstitutionExp,omitempty" yaml:"sslTargetOverrideUrlSubstitutionExp,omitempty"`
+	MappedHost                          string `json:"mappedHost,omitempty" yaml:"mappedHost,omitempty"`
+	MappedName                          string `json:"mappedName,omitempty" yaml:"mappedName,omitempty"`
+	IgnoreEndpoint                      bool   `json:"ignoreEndpoint,omitempty" yaml:"ignoreEndpoint,omitempty"`
+}
+
+// OperationsProfile contains the operations server configuration
+type OperationsProfile struct {
+	ListenAddress string               `json:"listenAddress,omitempty" yaml:"listenAddress,omitempty"`
+	TLS           OperationsTLSProfile `json:"tls,omitempty" yaml:"tls,omitempty"`
+}
+
+// OperationsTLSProfile contains the operations server TLS configuration
+type OperationsTLSProfile struct {
+	Enabled            bool         `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Cert               FileProfile  `json:"cert,omitempty" yaml:"cert,omitempty"`
+	Key                FileProfile  `json:"key,omitempty" yaml:"key,omitempty"`
+	ClientAuthRequired bool         `json:"clientAuthRequired,omitempty" yaml:"clientAuthRequired,omitempty"`
+	ClientRootCAs      FilesProfile `json:"clientRootCAs,omitempty" yaml:"clientRootCAs,omitempty"`
+}
+
+// FileProfile references a single file
+type FileProfile struct {
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+}
+
+// FilesProfile references a list of files
+type FilesProfile struct {
+	Files []string `json:"files,omitempty" yaml:"files,omitempty"`
+}
+
+// MetricsProfile contains the metrics provider configuration
+type MetricsProfile struct {
+	Provider string        `json:"provider,omitempty" yaml:"provider,omitempty"`
+	Statsd   StatsdProfile `json:"statsd,omitempty" yaml:"statsd,omitempty"`
+}
+
+// StatsdProfile contains the statsd metrics provider configuration
+type StatsdProfile struct {
+	Network       string        `json:"network,omitempty" yaml:"network,omitempty"`
+	Address       string        `json:"address,omitempty" yaml:"address,omitempty"`
+	WriteInterval time.Duration `json:"writeInterval,omitempty" yaml:"writeInterval,omitempty"`
+	Prefix        string        `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+}
@@ -0,0 +1,198 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProfile() *Profile {
+	disabled := false
+
+	return &Profile{
+		Version: "1.0.0",
+		Client: ClientProfile{
+			Organization:    "Org1",
+			Logging:         LoggingProfile{Level: "info"},
+			CredentialStore: CredentialStoreProfile{Path: "/tmp/state-store", CryptoStore: PathProfile{Path: "/tmp/msp"}},
+			BCCSP: BCCSPProfile{Security: SecurityProfile{
+				Enabled:       &disabled,
+				HashAlgorithm: "SHA2",
+				Level:         256,
+			}},
+			Peer: PeerTimeoutsProfile{Timeout: PeerTimeoutProfile{Connection: 5 * time.Second}},
+		},
+		Channels: map[string]ChannelProfile{
+			"mychannel": {
+				Orderers: []string{"orderer.example.com"},
+				Peers: map[string]PeerChannelProfile{
+					"peer0.org1.example.com": {},
+					"peer1.org1.example.com": {EndorsingPeer: &disabled},
+				},
+				Policies: ChannelPoliciesProfile{
+					QueryChannelConfig: QueryPolicyProfile{
+						MinResponses: 1,
+						RetryOpts:    RetryOptsProfile{Attempts: 3, InitialBackoff: 500 * time.Millisecond, BackoffFactor: 2.0},
+					},
+				},
+			},
+		},
+		Organizations: map[string]OrganizationProfile{
+			"Org1": {
+				MSPID:      "Org1MSP",
+				CryptoPath: "/tmp/msp/org1",
+				Peers:      []string{"peer0.org1.example.com", "peer1.org1.example.com"},
+			},
+		},
+		Orderers: map[string]EndpointProfile{
+			"orderer.example.com": {
+				URL:         "grpc://orderer.example.com:7050",
+				GRPCOptions: map[string]interface{}{"keep-alive-time": time.Duration(0), "fail-fast": false},
+			},
+		},
+		Peers: map[string]EndpointProfile{
+			"peer0.org1.example.com": {
+				URL:         "grpc://peer0.org1.example.com:7051",
+				GRPCOptions: map[string]interface{}{"ssl-target-name-override": "peer0.org1.example.com"},
+			},
+			"peer1.org1.example.com": {URL: "grpc://peer1.org1.example.com:7151"},
+		},
+	}
+}
+
+func TestFromProfile(t *testing.T) {
+	_, err := FromProfile(nil)()
+	require.EqualError(t, err, "profile is nil")
+
+	backends, err := FromProfile(newTestProfile())()
+	require.NoError(t, err)
+	require.Len(t, backends, 1)
+	backend := backends[0]
+
+	value, ok := backend.Lookup("client.organization")
+	require.True(t, ok)
+	assert.Equal(t, "Org1", value)
+
+	value, ok = backend.Lookup("client.BCCSP.security.enabled")
+	require.True(t, ok)
+	assert.Equal(t, false, value)
+
+	_, ok = backend.Lookup("client.BCCSP.security.softVerify")
+	assert.False(t, ok, "unset values should not be found")
+
+	value, ok = backend.Lookup("Peers.peer0.org1.example.com.URL")
+	require.True(t, ok)
+	assert.Equal(t, "grpc://peer0.org1.example.com:7051", value)
+
+	value, ok = backend.Lookup("client.peer.timeout.connection")
+	require.True(t, ok)
+	assert.Equal(t, "5s", value)
+
+	endpointConfig, err := fabImpl.ConfigFromBackend(backends...)
+	require.NoError(t, err)
+
+	assert.Equal(t, 5*time.Second, endpointConfig.Timeout(fab.PeerConnection))
+
+	peers, ok := endpointConfig.PeersConfig("org1")
+	require.True(t, ok)
+	assert.Len(t, peers, 2)
+
+	peer, ok := endpointConfig.PeerConfig("peer0.org1.example.com")
+	require.True(t, ok)
+	assert.Equal(t, "grpc://peer0.org1.example.com:7051", peer.URL)
+	assert.Equal(t, "peer0.org1.example.com", peer.GRPCOptions["ssl-target-name-override"])
+
+	channelPeers := endpointConfig.ChannelPeers("mychannel")
+	require.Len(t, channelPeers, 2)
+	for _, p := range channelPeers {
+		assert.True(t, p.LedgerQuery)
+		assert.Equal(t, p.URL == "grpc://peer0.org1.example.com:7051", p.EndorsingPeer)
+	}
+
+	channelConfig := endpointConfig.ChannelConfig("mychannel")
+	require.NotNil(t, channelConfig)
+	assert.Equal(t, 3, channelConfig.Policies.QueryChannelConfig.RetryOpts.Attempts)
+	assert.Equal(t, 500*time.Millisecond, channelConfig.Policies.QueryChannelConfig.RetryOpts.InitialBackoff)
+
+	identityConfig, err := mspImpl.ConfigFromBackend(backends...)
+	require.NoError(t, err)
+	assert.Equal(t, "org1", identityConfig.Client().Organization)
+	assert.Equal(t, "/tmp/state-store", identityConfig.CredentialStorePath())
+}
+
+func TestMarshalProfile(t *testing.T) {
+	profile := newTestProfile()
+	// zero values are omitted so they are not expected to survive the round trip
+	profile.Orderers["orderer.example.com"].GRPCOptions["keep-alive-time"] = "0s"
+
+	for _, configType := range []string{"json", "yaml"} {
+		configBytes, err := MarshalProfile(profile, configType)
+		require.NoError(t, err)
+		assert.Contains(t, string(configBytes), "5s")
+
+		loaded, err := UnmarshalProfile(configBytes, configType)
+		require.NoError(t, err)
+		assert.Equal(t, profile, loaded, "unexpected profile after %s round trip", configType)
+	}
+
+	configBytes, err := MarshalProfile(profile, "json")
+	require.NoError(t, err)
+
+	backends, err := FromJSON(configBytes)()
+	require.NoError(t, err)
+	value, ok := backends[0].Lookup("client.peer.timeout.connection")
+	require.True(t, ok)
+	assert.Equal(t, "5s", value)
+
+	_, err = MarshalProfile(profile, "xml")
+	assert.EqualError(t, err, "unsupported config type: xml")
+
+	_, err = MarshalProfile(nil, "json")
+	assert.EqualError(t, err, "profile is nil")
+
+	_, err = UnmarshalProfile([]byte("{"), "json")
+	assert.Error(t, err)
+
+	_, err = FromJSON([]byte(`{"client": {"peer": {"timeout": {"connection": "never"}}}}`))()
+	assert.Error(t, err)
+}
+
+func TestProfileMatchesViperBackend(t *testing.T) {
+	configBytes, err := ioutil.ReadFile(filepath.Join("testdata", "config_test_embedded_pems.yaml"))
+	require.NoError(t, err)
+
+	profile, err := UnmarshalProfile(configBytes, "yaml")
+	require.NoError(t, err)
+
+	viperConfig := loadEndpointConfig(t, FromRaw(configBytes, "yaml"))
+	profileConfig := loadEndpointConfig(t, FromProfile(profile))
+
+	assert.Equal(t, viperConfig.NetworkConfig(), profileConfig.NetworkConfig())
+	assert.ElementsMatch(t, viperConfig.OrderersConfig(), profileConfig.OrderersConfig())
+	assert.ElementsMatch(t, viperConfig.ChannelPeers("mychannel"), profileConfig.ChannelPeers("mychannel"))
+	assert.Equal(t, viperConfig.Timeout(fab.PeerConnection), profileConfig.Timeout(fab.PeerConnection))
+	assert.Equal(t, viperConfig.Timeout(fab.Query), profileConfig.Timeout(fab.Query))
+}
+
+func loadEndpointConfig(t *testing.T, configProvider core.ConfigProvider) fab.EndpointConfig {
+	backends, err := configProvider()
+	require.NoError(t, err)
+
+	endpointConfig, err := fabImpl.ConfigFromBackend(backends...)
+	require.NoError(t, err)
+	return endpointConfig
+}
@@ -0,0 +1,299 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// FromProfile initializes the configs from the given connection profile. Unlike FromFile and
+// FromRaw, viper is not used and so environment variable overrides are not applied.
+func FromProfile(profile *Profile) core.ConfigProvider {
+	return func() ([]core.ConfigBackend, error) {
+		if profile == nil {
+			return nil, errors.New("profile is nil")
+		}
+
+		backend := &profileBackend{
+			values: lowerKeys(profileToMap(profile)).(map[string]interface{}),
+		}
+		setLogLevel(backend)
+
+		return []core.ConfigBackend{backend}, nil
+	}
+}
+
+// FromJSON initializes the configs from a JSON connection profile. The profile is
+// loaded into a Profile and so has the same semantics as FromProfile.
+func FromJSON(configBytes []byte) core.ConfigProvider {
+	return func() ([]core.ConfigBackend, error) {
+		profile, err := UnmarshalProfile(configBytes, "json")
+		if err != nil {
+			return nil, err
+		}
+		return FromProfile(profile)()
+	}
+}
+
+// UnmarshalProfile loads a connection profile from a byte array.
+// configType can be "json" or "yaml".
+func UnmarshalProfile(configBytes []byte, configType string) (*Profile, error) {
+	var raw interface{}
+	switch strings.ToLower(configType) {
+	case "json":
+		if err := json.Unmarshal(configBytes, &raw); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal JSON connection profile")
+		}
+	case "yaml", "yml":
+		if err := yaml.Unmarshal(configBytes, &raw); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal YAML connection profile")
+		}
+		raw = stringKeys(raw)
+	default:
+		return nil, errors.Errorf("unsupported config type: %s", configType)
+	}
+
+	profile := &Profile{}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		TagName:    "json",
+		Result:     profile,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := decoder.Decode(raw); err != nil {
+		return nil, errors.Wrap(err, "failed to decode connection profile")
+	}
+
+	return profile, nil
+}
+
+// MarshalProfile marshals the connection profile so that it may be persisted and later loaded
+// with FromFile, FromRaw or UnmarshalProfile. Durations are written in their string form (e.g. "10s").
+// configType can be "json" or "yaml".
+func MarshalProfile(profile *Profile, configType string) ([]byte, error) {
+	if profile == nil {
+		return nil, errors.New("profile is nil")
+	}
+
+	values := profileToMap(profile)
+
+	switch strings.ToLower(configType) {
+	case "json":
+		return json.MarshalIndent(values, "", "  ")
+	case "yaml", "yml":
+		return yaml.Marshal(values)
+	default:
+		return nil, errors.Errorf("unsupported config type: %s", configType)
+	}
+}
+
+// profileBackend is a config backend which serves values from a connection profile
+type profileBackend struct {
+	values map[string]interface{}
+}
+
+// Lookup gets the config item value by Key. Keys are case insensitive and
+// may contain dots within a single element (e.g. "peers.peer0.org1.example.com.url").
+func (c *profileBackend) Lookup(key string) (interface{}, bool) {
+	return searchMap(c.values, strings.Split(strings.ToLower(key), "."))
+}
+
+// searchMap returns the value at the given path, trying the longest matching key
+// at each level so that keys which contain dots may be resolved
+func searchMap(m map[string]interface{}, path []string) (interface{}, bool) {
+	if len(path) == 0 {
+		return m, true
+	}
+
+	for i := len(path); i > 0; i-- {
+		value, ok := m[strings.Join(path[:i], ".")]
+		if !ok {
+			continue
+		}
+
+		if i == len(path) {
+			return value, value != nil
+		}
+
+		if child, ok := value.(map[string]interface{}); ok {
+			if v, ok := searchMap(child, path[i:]); ok {
+				return v, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func profileToMap(profile *Profile) map[string]interface{} {
+	value, ok := toValue(reflect.ValueOf(profile).Elem())
+	if !ok {
+		return make(map[string]interface{})
+	}
+	return value.(map[string]interface{})
+}
+
+// toValue converts the given value into the generic form produced by the YAML and JSON decoders
+// (maps, []interface{} and scalars). False is returned if the value is empty and should be omitted.
+func toValue(v reflect.Value) (interface{}, bool) {
+	if v.Type() == durationType {
+		if v.Int() == 0 {
+			return nil, false
+		}
+		return time.Duration(v.Int()).String(), true
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, false
+		}
+		if v.Kind() == reflect.Ptr && v.Elem().Kind() == reflect.Bool {
+			// a pointer is used to distinguish an explicit false from an unset value
+			return v.Elem().Bool(), true
+		}
+		return toValue(v.Elem())
+	case reflect.Struct:
+		return structToMap(v)
+	case reflect.Map:
+		return mapToMap(v)
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			return nil, false
+		}
+		values := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values[i] = toEntry(v.Index(i))
+		}
+		return values, true
+	default:
+		if isZero(v) {
+			return nil, false
+		}
+		return v.Interface(), true
+	}
+}
+
+// toEntry converts a map or slice entry. Unlike fields, entries are never omitted.
+func toEntry(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+
+	if value, ok := toValue(v); ok {
+		return value
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map:
+		return make(map[string]interface{})
+	case reflect.Slice, reflect.Array:
+		return []interface{}{}
+	case reflect.Ptr:
+		return nil
+	default:
+		return v.Interface()
+	}
+}
+
+func structToMap(v reflect.Value) (interface{}, bool) {
+	values := make(map[string]interface{})
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+
+		if value, ok := toValue(v.Field(i)); ok {
+			values[name] = value
+		}
+	}
+	return values, len(values) > 0
+}
+
+func mapToMap(v reflect.Value) (interface{}, bool) {
+	if v.Len() == 0 {
+		return nil, false
+	}
+
+	values := make(map[string]interface{})
+	for _, key := range v.MapKeys() {
+		values[fmt.Sprint(key.Interface())] = toEntry(v.MapIndex(key))
+	}
+	return values, true
+}
+
+func isZero(v reflect.Value) bool {
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+// lowerKeys lowercases all map keys so that lookups are case insensitive (as with viper)
+func lowerKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		values := make(map[string]interface{})
+		for key, val := range v {
+			values[strings.ToLower(key)] = lowerKeys(val)
+		}
+		return values
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, val := range v {
+			values[i] = lowerKeys(val)
+		}
+		return values
+	default:
+		return value
+	}
+}
+
+// stringKeys converts the map[interface{}]interface{} values produced by the YAML decoder
+// into map[string]interface{}
+func stringKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		values := make(map[string]interface{})
+		for key, val := range v {
+			values[fmt.Sprint(key)] = stringKeys(val)
+		}
+		return values
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, val := range v {
+			values[i] = stringKeys(val)
+		}
+		return values
+	default:
+		return value
+	}
+}
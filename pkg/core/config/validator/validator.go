@@ -0,0 +1,502 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package validator validates SDK connection profiles. The profile is loaded through the same
+// lookup and endpoint/identity config loaders used by the SDK and each problem that is found is
+// reported as a Finding along with the YAML path of the offending item.
+package validator
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
+	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultExpiryWarning = 30 * 24 * time.Hour
+	defaultEntity        = "_default"
+)
+
+// Severity is the severity of a finding
+type Severity int
+
+const (
+	// Error indicates that the SDK will fail (or behave incorrectly) with the profile
+	Error Severity = iota
+	// Warning indicates a likely mistake in the profile
+	Warning
+)
+
+// String returns the string representation of the severity
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	default:
+		return "unknown"
+	}
+}
+
+// Finding is a problem found in the connection profile
+type Finding struct {
+	Severity Severity
+	// Path is the YAML path of the item, for example "channels.mychannel.peers.peer0.org1.example.com".
+	// Note that keys are lower case since the config lookup is case insensitive.
+	Path    string
+	Message string
+}
+
+// String returns the string representation of the finding
+func (f Finding) String() string {
+	if f.Path == "" {
+		return fmt.Sprintf("%s: %s", f.Severity, f.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.Path, f.Message)
+}
+
+// Findings is a list of findings
+type Findings []Finding
+
+// HasErrors returns true if any of the findings has severity Error
+func (f Findings) HasErrors() bool {
+	for _, finding := range f {
+		if finding.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+type options struct {
+	expiryWarning time.Duration
+	now           func() time.Time
+}
+
+// Option configures the validator
+type Option func(opts *options)
+
+// WithExpiryWarning sets the period before expiry of a certificate in which a warning is reported.
+// The default is 30 days.
+func WithExpiryWarning(period time.Duration) Option {
+	return func(opts *options) {
+		opts.expiryWarning = period
+	}
+}
+
+// WithTime sets the function which provides the current time when checking certificate expiry.
+func WithTime(now func() time.Time) Option {
+	return func(opts *options) {
+		opts.now = now
+	}
+}
+
+// Validate loads the connection profile from the given config provider and checks it for problems:
+//   - cross-references between the client, organizations, peers, orderers, certificate authorities and channels
+//   - the existence of the files referenced by the profile
+//   - expired (or soon to expire) certificates
+//   - invalid regular expressions in entityMatchers
+//   - peers and orderers which share the same URL
+//
+// The profile is also loaded using the endpoint and identity config loaders; if they fail with a
+// problem that is not otherwise reported then their error is returned as a finding without a path.
+// An error is returned only if the config backend cannot be loaded.
+func Validate(configProvider core.ConfigProvider, opts ...Option) (Findings, error) {
+	if configProvider == nil {
+		return nil, errors.New("config provider is nil")
+	}
+
+	backends, err := configProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to load config backend")
+	}
+
+	v := &validator{
+		backend: lookup.New(backends...),
+		options: options{
+			expiryWarning: defaultExpiryWarning,
+			now:           time.Now,
+		},
+	}
+	for _, opt := range opts {
+		opt(&v.options)
+	}
+
+	v.validate()
+
+	if !v.findings.HasErrors() {
+		if _, err := fabImpl.ConfigFromBackend(backends...); err != nil {
+			v.errorf("", "endpoint config: %s", err)
+		}
+		if _, err := mspImpl.ConfigFromBackend(backends...); err != nil {
+			v.errorf("", "identity config: %s", err)
+		}
+	}
+
+	sort.SliceStable(v.findings, func(i, j int) bool {
+		return v.findings[i].Path < v.findings[j].Path
+	})
+
+	return v.findings, nil
+}
+
+// profile contains the sections of the connection profile which are validated
+type profile struct {
+	Client                 mspImpl.ClientConfig
+	Organizations          map[string]fabImpl.OrganizationConfig
+	Peers                  map[string]fabImpl.PeerConfig
+	Orderers               map[string]fabImpl.OrdererConfig
+	CertificateAuthorities map[string]mspImpl.CAConfig
+	Channels               map[string]channelConfig
+	EntityMatchers         map[string][]fabImpl.MatchConfig
+}
+
+type channelConfig struct {
+	Orderers []string
+	Peers    map[string]interface{}
+}
+
+type validator struct {
+	options
+	backend  *lookup.ConfigLookup
+	profile  profile
+	matchers map[string][]*regexp.Regexp
+	// mapped hosts of the peer matchers
+	mappedPeers map[string]bool
+	findings    Findings
+}
+
+func (v *validator) errorf(path, format string, args ...interface{}) {
+	v.findings = append(v.findings, Finding{Severity: Error, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) warnf(path, format string, args ...interface{}) {
+	v.findings = append(v.findings, Finding{Severity: Warning, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) validate() {
+	if !v.load() {
+		return
+	}
+
+	v.validateEntityMatchers()
+	v.validateClient()
+	v.validateOrganizations()
+	v.validatePeers()
+	v.validateOrderers()
+	v.validateCertificateAuthorities()
+	v.validateChannels()
+	v.validateDuplicateURLs()
+}
+
+func (v *validator) load() bool {
+	sections := []struct {
+		key   string
+		value interface{}
+	}{
+		{"client", &v.profile.Client},
+		{"organizations", &v.profile.Organizations},
+		{"peers", &v.profile.Peers},
+		{"orderers", &v.profile.Orderers},
+		{"certificateAuthorities", &v.profile.CertificateAuthorities},
+		{"channels", &v.profile.Channels},
+		{"entityMatchers", &v.profile.EntityMatchers},
+	}
+
+	ok := true
+	for _, s := range sections {
+		if err := v.backend.UnmarshalKey(s.key, s.value); err != nil {
+			v.errorf(strings.ToLower(s.key), "failed to parse section: %s", err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+func (v *validator) validateEntityMatchers() {
+	v.matchers = make(map[string][]*regexp.Regexp)
+	v.mappedPeers = make(map[string]bool)
+	for entityType, matchers := range v.profile.EntityMatchers {
+		for i, m := range matchers {
+			path := fmt.Sprintf("entitymatchers.%s[%d].pattern", entityType, i)
+			if m.Pattern == "" {
+				v.errorf(path, "pattern is empty")
+				continue
+			}
+			regex, err := regexp.Compile(m.Pattern)
+			if err != nil {
+				v.errorf(path, "invalid regular expression: %s", err)
+				continue
+			}
+			v.matchers[entityType] = append(v.matchers[entityType], regex)
+			v.checkMappedHost(fmt.Sprintf("entitymatchers.%s[%d].mappedhost", entityType, i), entityType, m)
+		}
+	}
+}
+
+// checkMappedHost checks that the mapped host of the matcher is defined. Mapped hosts which contain
+// regular expression substitutions are not checked.
+func (v *validator) checkMappedHost(path, entityType string, m fabImpl.MatchConfig) {
+	if m.IgnoreEndpoint || m.MappedHost == "" || strings.Contains(m.MappedHost, "$") {
+		return
+	}
+
+	host := strings.ToLower(m.MappedHost)
+	switch entityType {
+	case "peer":
+		v.mappedPeers[host] = true
+		if !v.peerDefined(host) {
+			v.errorf(path, "mapped host [%s] is not defined under peers", m.MappedHost)
+		}
+	case "orderer":
+		if !v.ordererDefined(host) {
+			v.errorf(path, "mapped host [%s] is not defined under orderers", m.MappedHost)
+		}
+	case "certificateauthority":
+		if !v.caDefined(host) {
+			v.errorf(path, "mapped host [%s] is not defined under certificateAuthorities", m.MappedHost)
+		}
+	}
+}
+
+// resolves returns true if the named entity of the given type is defined in the given section
+// or if it is matched by one of the entity matchers for the type
+func (v *validator) resolves(entityType, name string, defined func(string) bool) bool {
+	if defined(strings.ToLower(name)) {
+		return true
+	}
+	for _, regex := range v.matchers[entityType] {
+		if regex.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *validator) peerDefined(name string) bool {
+	_, ok := v.profile.Peers[name]
+	return ok
+}
+
+func (v *validator) ordererDefined(name string) bool {
+	_, ok := v.profile.Orderers[name]
+	return ok
+}
+
+func (v *validator) caDefined(name string) bool {
+	_, ok := v.profile.CertificateAuthorities[name]
+	return ok
+}
+
+func (v *validator) validateClient() {
+	client := v.profile.Client
+	if client.Organization == "" {
+		v.errorf("client.organization", "client organization is not specified")
+	} else if _, ok := v.profile.Organizations[strings.ToLower(client.Organization)]; !ok {
+		v.errorf("client.organization", "organization [%s] is not defined under organizations", client.Organization)
+	}
+
+	if path := v.backend.GetString("client.cryptoconfig.path"); path != "" {
+		v.checkPathExists("client.cryptoconfig.path", pathvar.Subst(path))
+	}
+
+	v.checkKeyPair("client.tlscerts.client", client.TLSCerts.Client)
+}
+
+func (v *validator) validateOrganizations() {
+	for name, org := range v.profile.Organizations {
+		path := "organizations." + name
+		if org.MSPID == "" {
+			v.errorf(path+".mspid", "mspid is not specified")
+		}
+		for i, peer := range org.Peers {
+			if !v.resolves("peer", peer, v.peerDefined) {
+				v.errorf(fmt.Sprintf("%s.peers[%d]", path, i), "peer [%s] is not defined under peers", peer)
+			}
+		}
+		for i, ca := range org.CertificateAuthorities {
+			if !v.resolves("certificateauthority", ca, v.caDefined) {
+				v.errorf(fmt.Sprintf("%s.certificateauthorities[%d]", path, i), "certificate authority [%s] is not defined under certificateAuthorities", ca)
+			}
+		}
+		for user, keyPair := range org.Users {
+			v.checkKeyPair(path+".users."+user, keyPair)
+		}
+	}
+}
+
+func (v *validator) validatePeers() {
+	orgPeers := make(map[string]bool)
+	for _, org := range v.profile.Organizations {
+		for _, peer := range org.Peers {
+			orgPeers[strings.ToLower(peer)] = true
+		}
+	}
+
+	for name, peer := range v.profile.Peers {
+		path := "peers." + name
+		if name != defaultEntity && !orgPeers[name] && !v.mappedPeers[name] {
+			v.warnf(path, "peer is not listed under the peers of any organization")
+		}
+		v.checkTLSCACertsSpecified(path, peer.URL, peer.TLSCACerts, v.profile.Peers[defaultEntity].TLSCACerts)
+		v.checkTLSConfig(path+".tlscacerts", peer.TLSCACerts)
+	}
+}
+
+func (v *validator) validateOrderers() {
+	for name, orderer := range v.profile.Orderers {
+		path := "orderers." + name
+		v.checkTLSCACertsSpecified(path, orderer.URL, orderer.TLSCACerts, v.profile.Orderers[defaultEntity].TLSCACerts)
+		v.checkTLSConfig(path+".tlscacerts", orderer.TLSCACerts)
+	}
+}
+
+func (v *validator) validateCertificateAuthorities() {
+	for name, ca := range v.profile.CertificateAuthorities {
+		path := "certificateauthorities." + name
+
+		// the PEMs take precedence over the paths when the CA config is loaded
+		switch {
+		case len(ca.TLSCACerts.Pem) > 0:
+			for _, p := range ca.TLSCACerts.Pem {
+				v.checkTLSConfig(path+".tlscacerts", endpoint.TLSConfig{Pem: p})
+			}
+		case ca.TLSCACerts.Path != "":
+			for _, p := range strings.Split(ca.TLSCACerts.Path, ",") {
+				v.checkTLSConfig(path+".tlscacerts", endpoint.TLSConfig{Path: p})
+			}
+		default:
+			v.errorf(path+".tlscacerts", "neither a path nor a PEM is specified for the TLS CA certificates")
+		}
+		v.checkKeyPair(path+".tlscacerts.client", ca.TLSCACerts.Client)
+	}
+}
+
+func (v *validator) validateChannels() {
+	for name, channel := range v.profile.Channels {
+		path := "channels." + name
+		for i, orderer := range channel.Orderers {
+			if !v.resolves("orderer", orderer, v.ordererDefined) {
+				v.errorf(fmt.Sprintf("%s.orderers[%d]", path, i), "orderer [%s] is not defined under orderers", orderer)
+			}
+		}
+		for peer := range channel.Peers {
+			if !v.resolves("peer", peer, v.peerDefined) {
+				v.errorf(path+".peers."+peer, "peer [%s] is not defined under peers", peer)
+			}
+		}
+	}
+}
+
+func (v *validator) validateDuplicateURLs() {
+	paths := make(map[string][]string)
+	for name, peer := range v.profile.Peers {
+		if name != defaultEntity && peer.URL != "" {
+			address := strings.ToLower(endpoint.ToAddress(peer.URL))
+			paths[address] = append(paths[address], "peers."+name+".url")
+		}
+	}
+	for name, orderer := range v.profile.Orderers {
+		if name != defaultEntity && orderer.URL != "" {
+			address := strings.ToLower(endpoint.ToAddress(orderer.URL))
+			paths[address] = append(paths[address], "orderers."+name+".url")
+		}
+	}
+
+	for address, p := range paths {
+		if len(p) < 2 {
+			continue
+		}
+		sort.Strings(p)
+		for i, path := range p {
+			others := append(append([]string{}, p[:i]...), p[i+1:]...)
+			v.warnf(path, "URL [%s] is also used by %s", address, strings.Join(others, ", "))
+		}
+	}
+}
+
+// checkTLSCACertsSpecified checks that a TLS CA certificate is available for an endpoint with a TLS URL
+func (v *validator) checkTLSCACertsSpecified(path, url string, cfg, defaultCfg endpoint.TLSConfig) {
+	if !endpoint.IsTLSEnabled(url) || v.backend.GetBool("client.tlsCerts.systemCertPool") {
+		return
+	}
+	if cfg.Pem == "" && cfg.Path == "" && defaultCfg.Pem == "" && defaultCfg.Path == "" {
+		v.errorf(path+".tlscacerts", "TLS is enabled for URL [%s] but no TLS CA certificate is specified", url)
+	}
+}
+
+func (v *validator) checkKeyPair(path string, keyPair endpoint.TLSKeyPair) {
+	if keyPair.Key.Pem == "" && keyPair.Key.Path != "" {
+		v.checkPathExists(path+".key.path", pathvar.Subst(keyPair.Key.Path))
+	}
+	v.checkTLSConfig(path+".cert", keyPair.Cert)
+}
+
+// checkTLSConfig checks that the certificate referenced by the config exists and is valid
+func (v *validator) checkTLSConfig(path string, cfg endpoint.TLSConfig) {
+	if cfg.Pem == "" && cfg.Path == "" {
+		return
+	}
+
+	if cfg.Pem != "" {
+		path += ".pem"
+	} else {
+		path += ".path"
+		cfg.Path = pathvar.Subst(cfg.Path)
+		if !v.checkPathExists(path, cfg.Path) {
+			return
+		}
+	}
+
+	if err := cfg.LoadBytes(); err != nil {
+		v.errorf(path, "%s", err)
+		return
+	}
+
+	cert, ok, err := cfg.TLSCert()
+	if err != nil {
+		v.errorf(path, "invalid certificate: %s", err)
+		return
+	}
+	if !ok {
+		v.errorf(path, "no PEM encoded certificate found")
+		return
+	}
+
+	now := v.now()
+	switch {
+	case now.After(cert.NotAfter):
+		v.errorf(path, "certificate [%s] expired on %s", cert.Subject.CommonName, cert.NotAfter.Format(time.RFC3339))
+	case now.Before(cert.NotBefore):
+		v.errorf(path, "certificate [%s] is not valid before %s", cert.Subject.CommonName, cert.NotBefore.Format(time.RFC3339))
+	case now.Add(v.expiryWarning).After(cert.NotAfter):
+		v.warnf(path, "certificate [%s] expires on %s", cert.Subject.CommonName, cert.NotAfter.Format(time.RFC3339))
+	}
+}
+
+func (v *validator) checkPathExists(path, file string) bool {
+	if _, err := os.Stat(file); err != nil {
+		if os.IsNotExist(err) {
+			v.errorf(path, "file [%s] does not exist", file)
+		} else {
+			v.errorf(path, "unable to access file [%s]: %s", file, err)
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,238 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validator
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validProfile = `
+client:
+  organization: org1
+organizations:
+  org1:
+    mspid: Org1MSP
+    cryptoPath: /tmp/msp
+    peers:
+      - peer0.org1.example.com
+    certificateAuthorities:
+      - ca.org1.example.com
+orderers:
+  orderer.example.com:
+    url: grpc://orderer.example.com:7050
+peers:
+  peer0.org1.example.com:
+    url: grpc://peer0.org1.example.com:7051
+    tlsCACerts:
+      path: %s
+certificateAuthorities:
+  ca.org1.example.com:
+    url: http://ca.org1.example.com:7054
+    tlsCACerts:
+      path: %s
+channels:
+  mychannel:
+    orderers:
+      - orderer.example.com
+    peers:
+      peer0.org1.example.com:
+        endorsingPeer: true
+`
+
+const invalidProfile = `
+client:
+  organization: org1
+organizations:
+  org1:
+    mspid: Org1MSP
+    cryptoPath: /tmp/msp
+    peers:
+      - peer0.org1.example.com
+      - peer9.org1.example.com
+      - peer2.org1.example.com
+    certificateAuthorities:
+      - ca.org1.example.com
+  org2:
+    cryptoPath: /tmp/msp
+orderers:
+  orderer.example.com:
+    url: grpc://orderer.example.com:7050
+    tlsCACerts:
+      path: %s
+peers:
+  peer0.org1.example.com:
+    url: grpc://peer0.org1.example.com:7051
+    tlsCACerts:
+      path: %s
+  peer1.org1.example.com:
+    url: grpcs://peer0.org1.example.com:7051
+  peer2.org1.example.com:
+    url: grpcs://peer2.org1.example.com:7051
+certificateAuthorities:
+  ca.org1.example.com:
+    url: http://ca.org1.example.com:7054
+    tlsCACerts:
+      path: %s
+channels:
+  mychannel:
+    orderers:
+      - orderer.example.com
+      - orderer2.example.com
+    peers:
+      peer0.org1.example.com:
+      peer0.org3.example.com:
+      peer0.org4.example.com:
+entityMatchers:
+  peer:
+    - pattern: (\w+).org3.example.(\w+)
+      mappedHost: peer0.org1.example.com
+    - pattern: (\w+.org5.example.(\w+)
+      mappedHost: peer0.org1.example.com
+  orderer:
+    - pattern: (\w+).example5.(\w+)
+      mappedHost: orderer5.example.com
+`
+
+func TestValidateValid(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	certPath := writeCert(t, dir, "tlsca.pem", time.Now().Add(365*24*time.Hour))
+
+	findings, err := Validate(config.FromRaw([]byte(fmt.Sprintf(validProfile, certPath, certPath)), "yaml"))
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+	assert.False(t, findings.HasErrors())
+}
+
+func TestValidateInvalid(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	expiredPath := writeCert(t, dir, "expired.pem", time.Now().Add(-time.Hour))
+	expiringPath := writeCert(t, dir, "expiring.pem", time.Now().Add(24*time.Hour))
+	missingPath := filepath.Join(dir, "missing.pem")
+
+	profile := fmt.Sprintf(invalidProfile, missingPath, expiredPath, expiringPath)
+	findings, err := Validate(config.FromRaw([]byte(profile), "yaml"))
+	require.NoError(t, err)
+	require.True(t, findings.HasErrors())
+
+	expected := map[string]Severity{
+		"certificateauthorities.ca.org1.example.com.tlscacerts.path": Warning,
+		"channels.mychannel.orderers[1]":                             Error,
+		"channels.mychannel.peers.peer0.org4.example.com":            Error,
+		"entitymatchers.orderer[0].mappedhost":                       Error,
+		"entitymatchers.peer[1].pattern":                             Error,
+		"orderers.orderer.example.com.tlscacerts.path":               Error,
+		"organizations.org1.peers[1]":                                Error,
+		"organizations.org2.mspid":                                   Error,
+		"peers.peer0.org1.example.com.tlscacerts.path":               Error,
+		"peers.peer0.org1.example.com.url":                           Warning,
+		"peers.peer1.org1.example.com":                               Warning,
+		"peers.peer1.org1.example.com.url":                           Warning,
+		"peers.peer1.org1.example.com.tlscacerts":                    Error,
+		"peers.peer2.org1.example.com.tlscacerts":                    Error,
+	}
+
+	actual := make(map[string]Severity)
+	for _, f := range findings {
+		actual[f.Path] = f.Severity
+	}
+	assert.Equal(t, expected, actual, "unexpected findings: %v", findings)
+
+	for _, f := range findings {
+		switch f.Path {
+		case "peers.peer0.org1.example.com.tlscacerts.path":
+			assert.Contains(t, f.Message, "expired")
+		case "orderers.orderer.example.com.tlscacerts.path":
+			assert.Contains(t, f.Message, "does not exist")
+		case "certificateauthorities.ca.org1.example.com.tlscacerts.path":
+			assert.Contains(t, f.Message, "expires")
+		}
+	}
+}
+
+func TestValidateLoaderError(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	// The client certificate is valid but its private key is not configured
+	profile := fmt.Sprintf(`
+client:
+  organization: org1
+  tlsCerts:
+    client:
+      cert:
+        path: %s
+organizations:
+  org1:
+    mspid: Org1MSP
+    cryptoPath: /tmp/msp
+`, writeCert(t, dir, "client.pem", time.Now().Add(365*24*time.Hour)))
+
+	findings, err := Validate(config.FromRaw([]byte(profile), "yaml"))
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, Error, findings[0].Severity)
+	assert.Empty(t, findings[0].Path)
+	assert.Contains(t, findings[0].Message, "endpoint config")
+}
+
+func TestValidateBackendError(t *testing.T) {
+	_, err := Validate(nil)
+	require.EqualError(t, err, "config provider is nil")
+
+	_, err = Validate(func() ([]core.ConfigBackend, error) {
+		return nil, errors.New("backend error")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "backend error")
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "validator")
+	require.NoError(t, err)
+	return dir
+}
+
+func writeCert(t *testing.T, dir, name string, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-48 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, name)
+	err = ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	require.NoError(t, err)
+	return path
+}
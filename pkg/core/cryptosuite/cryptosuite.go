@@ -13,6 +13,8 @@ import (
 
 	"sync"
 
+	pkgerrors "github.com/pkg/errors"
+
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
@@ -21,6 +23,9 @@ import (
 
 var logger = logging.NewLogger("fabsdk/core")
 
+// sm3Algorithm is the name of the SM3 hashing algorithm as it appears in the channel config
+const sm3Algorithm = "SM3"
+
 var initOnce sync.Once
 var defaultCryptoSuite core.CryptoSuite
 var initialized int32
@@ -78,6 +83,23 @@ func GetSHA256Opts() core.HashOpts {
 	return &bccsp.SHA256Opts{}
 }
 
+//GetHashOpts returns options for computing hashes with the given channel hashing algorithm
+//(e.g. SHA256, SHA3_256 or SM3). SHA-256 is used if no algorithm is specified.
+func GetHashOpts(algorithm string) (core.HashOpts, error) {
+	switch algorithm {
+	case "":
+		return GetSHA256Opts(), nil
+	case sm3Algorithm:
+		return &bccsp.GMSM3Opts{}, nil
+	}
+
+	opts, err := bccsp.GetHashOpt(algorithm)
+	if err != nil {
+		return nil, pkgerrors.WithMessage(err, "unsupported hashing algorithm")
+	}
+	return opts, nil
+}
+
 //GetSHAOpts returns options for computing SHA.
 func GetSHAOpts() core.HashOpts {
 	return &bccsp.SHAOpts{}
@@ -84,6 +84,15 @@ func TestHashOpts(t *testing.T) {
 	assert.NotZero(t, hashOpts, "Not supposed to be empty sha256HashOpts")
 	assert.True(t, hashOpts.Algorithm() == sha256HashOptsAlgorithm, "Unexpected SHA hash opts, expected [%v], got [%v]", sha256HashOptsAlgorithm, hashOpts.Algorithm())
 
+	//Get CryptoSuite Opts for channel hashing algorithms
+	for algorithm, expected := range map[string]string{"": "SHA256", "SHA256": "SHA256", "SHA3_256": "SHA3_256", "SM3": "GMSM3", "GMSM3": "GMSM3"} {
+		hashOpts, err := GetHashOpts(algorithm)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, hashOpts.Algorithm())
+	}
+
+	_, err := GetHashOpts("MD5")
+	assert.Error(t, err, "expected error for unsupported hashing algorithm")
 }
 
 func TestKeyGenOpts(t *testing.T) {
@@ -34,9 +34,10 @@ type OrdererConfig struct {
 
 // PeerConfig defines a peer configuration
 type PeerConfig struct {
-	URL         string
-	GRPCOptions map[string]interface{}
-	TLSCACerts  endpoint.TLSConfig
+	URL           string
+	GRPCOptions   map[string]interface{}
+	TLSCACerts    endpoint.TLSConfig
+	OperationsURL string
 }
 
 // OrganizationConfig provides the definition of an organization in the network
@@ -57,6 +58,14 @@ type ChannelEndpointConfig struct {
 	Peers map[string]PeerChannelConfig
 	//Policies list of policies for channel
 	Policies ChannelPolicies
+	//Chaincodes the configuration of the chaincodes on the channel
+	Chaincodes map[string]ChaincodeConfig
+}
+
+//ChaincodeConfig defines the configuration of a chaincode on a channel
+type ChaincodeConfig struct {
+	//Policy the endorsement policy of the chaincode
+	Policy string
 }
 
 //ChannelPolicies defines list of policies defined for a channel
@@ -126,6 +135,9 @@ type SelectionPolicy struct {
 
 // EventServicePolicy specifies the policy for the event service
 type EventServicePolicy struct {
+	Type                             string
+	ConsensusPeers                   int
+	ConsensusQuorum                  int
 	ResolverStrategy                 string
 	MinBlockHeightResolverMode       string
 	Balancer                         BalancerType
@@ -13,6 +13,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -28,11 +29,14 @@ const (
 	lscc                  = "lscc"
 	lsccChaincodes        = "getchaincodes"
 	lsccCollectionsConfig = "getcollectionsconfig"
+	lsccChaincodeData     = "getccdata"
+	lsccDeploymentSpec    = "getdepspec"
 )
 
 // Ledger is a client that provides access to the underlying ledger of a channel.
 type Ledger struct {
-	chName string
+	chName     string
+	headerOpts []fab.TxnHeaderOpt
 }
 
 // ResponseVerifier checks transaction proposal response(s)
@@ -41,10 +45,12 @@ type ResponseVerifier interface {
 	Match(response []*fab.TransactionProposalResponse) error
 }
 
-// NewLedger constructs a Ledger client for the current context and named channel.
-func NewLedger(chName string) (*Ledger, error) {
+// NewLedger constructs a Ledger client for the current context and named channel. The given header
+// options (such as fab.WithHashingAlgorithm) are applied to the transaction header of each query.
+func NewLedger(chName string, opts ...fab.TxnHeaderOpt) (*Ledger, error) {
 	l := Ledger{
-		chName: chName,
+		chName:     chName,
+		headerOpts: opts,
 	}
 	return &l, nil
 }
@@ -55,7 +61,7 @@ func (c *Ledger) QueryInfo(reqCtx reqContext.Context, targets []fab.ProposalProc
 	logger.Debug("queryInfo - start")
 
 	cir := createChannelInfoInvokeRequest(c.chName)
-	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier)
+	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier, c.headerOpts...)
 
 	responses := []*fab.BlockchainInfoResponse{}
 	for _, tpr := range tprs {
@@ -88,7 +94,7 @@ func (c *Ledger) QueryBlockByHash(reqCtx reqContext.Context, blockHash []byte, t
 	}
 
 	cir := createBlockByHashInvokeRequest(c.chName, blockHash)
-	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier)
+	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier, c.headerOpts...)
 
 	responses, errors := getConfigBlocks(tprs)
 	errs = multi.Append(errs, errors)
@@ -106,7 +112,7 @@ func (c *Ledger) QueryBlockByTxID(reqCtx reqContext.Context, txID fab.Transactio
 	}
 
 	cir := createBlockByTxIDInvokeRequest(c.chName, txID)
-	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier)
+	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier, c.headerOpts...)
 
 	responses, errors := getConfigBlocks(tprs)
 	errs = multi.Append(errs, errors)
@@ -135,7 +141,7 @@ func getConfigBlocks(tprs []*fab.TransactionProposalResponse) ([]*common.Block,
 func (c *Ledger) QueryBlock(reqCtx reqContext.Context, blockNumber uint64, targets []fab.ProposalProcessor, verifier ResponseVerifier) ([]*common.Block, error) {
 
 	cir := createBlockByNumberInvokeRequest(c.chName, blockNumber)
-	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier)
+	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier, c.headerOpts...)
 
 	responses, errors := getConfigBlocks(tprs)
 	errs = multi.Append(errs, errors)
@@ -157,7 +163,7 @@ func createCommonBlock(tpr *fab.TransactionProposalResponse) (*common.Block, err
 func (c *Ledger) QueryTransaction(reqCtx reqContext.Context, transactionID fab.TransactionID, targets []fab.ProposalProcessor, verifier ResponseVerifier) ([]*pb.ProcessedTransaction, error) {
 
 	cir := createTransactionByIDInvokeRequest(c.chName, transactionID)
-	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier)
+	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier, c.headerOpts...)
 
 	responses := []*pb.ProcessedTransaction{}
 	for _, tpr := range tprs {
@@ -185,7 +191,7 @@ func createProcessedTransaction(tpr *fab.TransactionProposalResponse) (*pb.Proce
 // This query will be made to specified targets.
 func (c *Ledger) QueryInstantiatedChaincodes(reqCtx reqContext.Context, targets []fab.ProposalProcessor, verifier ResponseVerifier) ([]*pb.ChaincodeQueryResponse, error) {
 	cir := createChaincodeInvokeRequest()
-	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier)
+	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier, c.headerOpts...)
 
 	responses := []*pb.ChaincodeQueryResponse{}
 	for _, tpr := range tprs {
@@ -211,7 +217,7 @@ func createChaincodeQueryResponse(tpr *fab.TransactionProposalResponse) (*pb.Cha
 // QueryCollectionsConfig queries the collections config for a chaincode on this channel.
 func (c *Ledger) QueryCollectionsConfig(reqCtx reqContext.Context, chaincodeName string, targets []fab.ProposalProcessor, verifier ResponseVerifier) ([]*common.CollectionConfigPackage, error) {
 	cir := createCollectionsConfigInvokeRequest(chaincodeName)
-	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier)
+	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier, c.headerOpts...)
 
 	responses := []*common.CollectionConfigPackage{}
 	for _, tpr := range tprs {
@@ -234,6 +240,42 @@ func createCollectionsConfigQueryResponse(tpr *fab.TransactionProposalResponse)
 	return &response, nil
 }
 
+// QueryChaincodeData queries the chaincode data (version, endorsement policy, etc.) of a chaincode
+// instantiated on this channel.
+func (c *Ledger) QueryChaincodeData(reqCtx reqContext.Context, chaincodeName string, targets []fab.ProposalProcessor, verifier ResponseVerifier) ([]*ccprovider.ChaincodeData, error) {
+	cir := createChaincodeLookupInvokeRequest(lsccChaincodeData, c.chName, chaincodeName)
+	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier, c.headerOpts...)
+
+	responses := []*ccprovider.ChaincodeData{}
+	for _, tpr := range tprs {
+		r := &ccprovider.ChaincodeData{}
+		if err := proto.Unmarshal(tpr.ProposalResponse.GetResponse().Payload, r); err != nil {
+			errs = multi.Append(errs, errors.WithMessage(errors.Wrap(err, "unmarshal of chaincode data failed"), "From target: "+tpr.Endorser))
+		} else {
+			responses = append(responses, r)
+		}
+	}
+	return responses, errs
+}
+
+// QueryChaincodeDeploymentSpec queries the deployment spec of a chaincode instantiated on this channel.
+// The code package is read by the peer from its installed chaincodes.
+func (c *Ledger) QueryChaincodeDeploymentSpec(reqCtx reqContext.Context, chaincodeName string, targets []fab.ProposalProcessor, verifier ResponseVerifier) ([]*pb.ChaincodeDeploymentSpec, error) {
+	cir := createChaincodeLookupInvokeRequest(lsccDeploymentSpec, c.chName, chaincodeName)
+	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier, c.headerOpts...)
+
+	responses := []*pb.ChaincodeDeploymentSpec{}
+	for _, tpr := range tprs {
+		r := &pb.ChaincodeDeploymentSpec{}
+		if err := proto.Unmarshal(tpr.ProposalResponse.GetResponse().Payload, r); err != nil {
+			errs = multi.Append(errs, errors.WithMessage(errors.Wrap(err, "unmarshal of chaincode deployment spec failed"), "From target: "+tpr.Endorser))
+		} else {
+			responses = append(responses, r)
+		}
+	}
+	return responses, errs
+}
+
 // QueryConfigBlock returns the current configuration block for the specified channel. If the
 // peer doesn't belong to the channel, return error
 func (c *Ledger) QueryConfigBlock(reqCtx reqContext.Context, targets []fab.ProposalProcessor, verifier ResponseVerifier) (*common.Block, error) {
@@ -242,7 +284,7 @@ func (c *Ledger) QueryConfigBlock(reqCtx reqContext.Context, targets []fab.Propo
 	}
 
 	cir := createConfigBlockInvokeRequest(c.chName)
-	tprs, err := queryChaincode(reqCtx, c.chName, cir, targets, verifier)
+	tprs, err := queryChaincode(reqCtx, c.chName, cir, targets, verifier, c.headerOpts...)
 	if err != nil && len(tprs) == 0 {
 		return nil, errors.WithMessage(err, "queryChaincode failed")
 	}
@@ -255,12 +297,12 @@ func (c *Ledger) QueryConfigBlock(reqCtx reqContext.Context, targets []fab.Propo
 	return createCommonBlock(tprs[0])
 }
 
-func queryChaincode(reqCtx reqContext.Context, channelID string, request fab.ChaincodeInvokeRequest, targets []fab.ProposalProcessor, verifier ResponseVerifier) ([]*fab.TransactionProposalResponse, error) {
+func queryChaincode(reqCtx reqContext.Context, channelID string, request fab.ChaincodeInvokeRequest, targets []fab.ProposalProcessor, verifier ResponseVerifier, opts ...fab.TxnHeaderOpt) ([]*fab.TransactionProposalResponse, error) {
 	ctx, ok := contextImpl.RequestClientContext(reqCtx)
 	if !ok {
 		return nil, errors.New("failed get client context from reqContext for signProposal")
 	}
-	txh, err := txn.NewHeader(ctx, channelID)
+	txh, err := txn.NewHeader(ctx, channelID, opts...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "creation of transaction ID failed")
 	}
@@ -301,6 +343,15 @@ func createChaincodeInvokeRequest() fab.ChaincodeInvokeRequest {
 	return cir
 }
 
+func createChaincodeLookupInvokeRequest(fcn, channelID, chaincodeName string) fab.ChaincodeInvokeRequest {
+	cir := fab.ChaincodeInvokeRequest{
+		ChaincodeID: lscc,
+		Fcn:         fcn,
+		Args:        [][]byte{[]byte(channelID), []byte(chaincodeName)},
+	}
+	return cir
+}
+
 func createCollectionsConfigInvokeRequest(chaincodeName string) fab.ChaincodeInvokeRequest {
 	cir := fab.ChaincodeInvokeRequest{
 		ChaincodeID: lscc,
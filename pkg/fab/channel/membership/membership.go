@@ -69,6 +69,16 @@ func (i *identityImpl) Verify(serializedID []byte, msg []byte, sig []byte) error
 	return id.Verify(msg, sig)
 }
 
+// SatisfiesPrincipal checks whether the given identity satisfies the given principal
+func (i *identityImpl) SatisfiesPrincipal(serializedID []byte, principal *mb.MSPPrincipal) error {
+	id, err := i.mspManager.DeserializeIdentity(serializedID)
+	if err != nil {
+		return err
+	}
+
+	return id.SatisfiesPrincipal(principal)
+}
+
 func (i *identityImpl) ContainsMSP(msp string) bool {
 	for _, v := range i.msps {
 		if v == strings.ToLower(msp) {
@@ -9,8 +9,10 @@ package membership
 import (
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
 	"github.com/pkg/errors"
 )
 
@@ -67,6 +69,19 @@ func (ref *Ref) ContainsMSP(msp string) bool {
 	return membership.ContainsMSP(msp)
 }
 
+// SatisfiesPrincipal calls SatisfiesPrincipal on the underlying reference
+func (ref *Ref) SatisfiesPrincipal(serializedID []byte, principal *mb.MSPPrincipal) error {
+	membership, err := ref.get()
+	if err != nil {
+		return err
+	}
+	validator, ok := membership.(verifier.PrincipalValidator)
+	if !ok {
+		return errors.New("membership does not support principal validation")
+	}
+	return validator.SatisfiesPrincipal(serializedID, principal)
+}
+
 func (ref *Ref) get() (fab.ChannelMembership, error) {
 	m, err := ref.Get()
 	if err != nil {
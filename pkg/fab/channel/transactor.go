@@ -24,6 +24,7 @@ type Transactor struct {
 	reqCtx    reqContext.Context
 	ChannelID string
 	orderers  []fab.Orderer
+	hashing   string
 }
 
 // NewTransactor returns a Transactor for the current context and channel config.
@@ -47,6 +48,7 @@ func NewTransactor(reqCtx reqContext.Context, cfg fab.ChannelCfg) (*Transactor,
 		reqCtx:    reqCtx,
 		ChannelID: cfg.ID(),
 		orderers:  orderers,
+		hashing:   cfg.HashingAlgorithm(),
 	}
 	return &t, nil
 }
@@ -147,7 +149,10 @@ func (t *Transactor) CreateTransactionHeader(opts ...fab.TxnHeaderOpt) (fab.Tran
 		return nil, errors.New("failed get client context from reqContext for txn Header")
 	}
 
-	txh, err := txn.NewHeader(ctx, t.ChannelID, opts...)
+	// The channel's hashing algorithm is used unless overridden by the given options
+	headerOpts := append([]fab.TxnHeaderOpt{fab.WithHashingAlgorithm(t.hashing)}, opts...)
+
+	txh, err := txn.NewHeader(ctx, t.ChannelID, headerOpts...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "new transaction ID failed")
 	}
@@ -10,6 +10,7 @@ import (
 	reqContext "context"
 	"math/rand"
 	"regexp"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
@@ -24,6 +25,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
 )
@@ -67,6 +69,11 @@ type ChannelCfg struct {
 	orderers     []string
 	versions     *fab.Versions
 	capabilities map[fab.ConfigGroupKey]map[string]bool
+	hashing      string
+	policies     map[string]*fab.ChannelPolicy
+	ordererCfg   *fab.ChannelOrdererConfig
+	acls         map[string]string
+	consortium   string
 }
 
 // NewChannelCfg creates channel cfg
@@ -105,6 +112,31 @@ func (cfg *ChannelCfg) Versions() *fab.Versions {
 	return cfg.versions
 }
 
+// HashingAlgorithm returns the hashing algorithm of the channel
+func (cfg *ChannelCfg) HashingAlgorithm() string {
+	return cfg.hashing
+}
+
+// Policies returns the channel policies keyed by their fully qualified path
+func (cfg *ChannelCfg) Policies() map[string]*fab.ChannelPolicy {
+	return cfg.policies
+}
+
+// OrdererConfig returns the orderer settings of the channel
+func (cfg *ChannelCfg) OrdererConfig() *fab.ChannelOrdererConfig {
+	return cfg.ordererCfg
+}
+
+// ACLs returns the application ACLs
+func (cfg *ChannelCfg) ACLs() map[string]string {
+	return cfg.acls
+}
+
+// Consortium returns the consortium name
+func (cfg *ChannelCfg) Consortium() string {
+	return cfg.consortium
+}
+
 // HasCapability indicates whether or not the given group has the given capability
 func (cfg *ChannelCfg) HasCapability(group fab.ConfigGroupKey, capability string) bool {
 	groupCapabilities, ok := cfg.capabilities[group]
@@ -339,6 +371,14 @@ func prepareOpts(options ...Option) (Opts, error) {
 	return opts, nil
 }
 
+// FromBlock returns the channel config contained in the given config block
+func FromBlock(channelID string, block *common.Block) (*ChannelCfg, error) {
+	if block == nil || block.Data == nil || len(block.Data.Data) == 0 {
+		return nil, errors.New("expected config block")
+	}
+	return extractConfig(channelID, block)
+}
+
 func extractConfig(channelID string, block *common.Block) (*ChannelCfg, error) {
 	if block.Header == nil {
 		return nil, errors.New("expected header in block")
@@ -363,9 +403,11 @@ func extractConfig(channelID string, block *common.Block) (*ChannelCfg, error) {
 		orderers:     []string{},
 		versions:     versions,
 		capabilities: make(map[fab.ConfigGroupKey]map[string]bool),
+		policies:     make(map[string]*fab.ChannelPolicy),
+		acls:         make(map[string]string),
 	}
 
-	err = loadConfig(config, config.versions.Channel, group, "", "")
+	err = loadConfig(config, config.versions.Channel, group, "/"+channelConfig.RootGroupKey, "", "")
 	if err != nil {
 		return nil, errors.WithMessage(err, "load config items from config group failed")
 	}
@@ -376,7 +418,7 @@ func extractConfig(channelID string, block *common.Block) (*ChannelCfg, error) {
 
 }
 
-func loadConfig(configItems *ChannelCfg, versionsGroup *common.ConfigGroup, group *common.ConfigGroup, name string, org string) error {
+func loadConfig(configItems *ChannelCfg, versionsGroup *common.ConfigGroup, group *common.ConfigGroup, path string, name string, org string) error {
 	if group == nil {
 		return nil
 	}
@@ -388,7 +430,7 @@ func loadConfig(configItems *ChannelCfg, versionsGroup *common.ConfigGroup, grou
 			logger.Debugf("loadConfigGroup - %s - found config group ==> %s", name, key)
 			// The Application group is where config settings are that we want to find
 			versionsGroup.Groups[key] = &common.ConfigGroup{}
-			err := loadConfig(configItems, versionsGroup.Groups[key], configGroup, key, key)
+			err := loadConfig(configItems, versionsGroup.Groups[key], configGroup, path+"/"+key, key, key)
 			if err != nil {
 				return err
 			}
@@ -408,19 +450,22 @@ func loadConfig(configItems *ChannelCfg, versionsGroup *common.ConfigGroup, grou
 		}
 	}
 
-	return loadConfigGroupPolicies(versionsGroup, group)
+	return loadConfigGroupPolicies(configItems, versionsGroup, group, path)
 }
 
-func loadConfigGroupPolicies(versionsGroup *common.ConfigGroup, group *common.ConfigGroup) error {
+func loadConfigGroupPolicies(configItems *ChannelCfg, versionsGroup *common.ConfigGroup, group *common.ConfigGroup, path string) error {
 	policies := group.GetPolicies()
 	if policies != nil {
 		versionsGroup.Policies = make(map[string]*common.ConfigPolicy)
 		for key, configPolicy := range policies {
 			versionsGroup.Policies[key] = &common.ConfigPolicy{}
-			err := loadConfigPolicy(versionsGroup.Policies[key], configPolicy)
+			policy, err := loadConfigPolicy(versionsGroup.Policies[key], configPolicy)
 			if err != nil {
 				return err
 			}
+			if policy != nil {
+				configItems.policies[path+"/"+key] = policy
+			}
 		}
 	}
 
@@ -428,23 +473,34 @@ func loadConfigGroupPolicies(versionsGroup *common.ConfigGroup, group *common.Co
 
 }
 
-func loadConfigPolicy(versionsPolicy *common.ConfigPolicy, configPolicy *common.ConfigPolicy) error {
+func loadConfigPolicy(versionsPolicy *common.ConfigPolicy, configPolicy *common.ConfigPolicy) (*fab.ChannelPolicy, error) {
 	versionsPolicy.Version = configPolicy.Version
-	return loadPolicy(configPolicy.Policy)
+	versionsPolicy.Policy = configPolicy.Policy
+	if configPolicy.Policy == nil {
+		return nil, nil
+	}
+
+	policy, err := loadPolicy(configPolicy.Policy)
+	if err != nil {
+		return nil, err
+	}
+	policy.ModPolicy = configPolicy.ModPolicy
+	return policy, nil
 }
 
-func loadPolicy(policy *common.Policy) error {
+func loadPolicy(policy *common.Policy) (*fab.ChannelPolicy, error) {
 
 	policyType := common.Policy_PolicyType(policy.Type)
+	channelPolicy := &fab.ChannelPolicy{Type: policyType}
 
 	switch policyType {
 	case common.Policy_SIGNATURE:
 		sigPolicyEnv := &common.SignaturePolicyEnvelope{}
 		err := proto.Unmarshal(policy.Value, sigPolicyEnv)
 		if err != nil {
-			return errors.Wrap(err, "unmarshal signature policy envelope from config failed")
+			return nil, errors.Wrap(err, "unmarshal signature policy envelope from config failed")
 		}
-		// TODO: Do something with this value
+		channelPolicy.Signature = sigPolicyEnv
 
 	case common.Policy_MSP:
 		// TODO: Not implemented yet
@@ -453,16 +509,17 @@ func loadPolicy(policy *common.Policy) error {
 		implicitMetaPolicy := &common.ImplicitMetaPolicy{}
 		err := proto.Unmarshal(policy.Value, implicitMetaPolicy)
 		if err != nil {
-			return errors.Wrap(err, "unmarshal implicit meta policy from config failed")
+			return nil, errors.Wrap(err, "unmarshal implicit meta policy from config failed")
 		}
-		// TODO: Do something with this value
+		channelPolicy.ImplicitMeta = implicitMetaPolicy
+
 	case common.Policy_UNKNOWN:
 		// TODO: Not implemented yet
 
 	default:
-		return errors.Errorf("unknown policy type %v", policyType)
+		return nil, errors.Errorf("unknown policy type %v", policyType)
 	}
-	return nil
+	return channelPolicy, nil
 }
 
 func loadAnchorPeers(configValue *common.ConfigValue, configItems *ChannelCfg, org string) error {
@@ -511,6 +568,95 @@ func loadOrdererAddressesKey(configValue *common.ConfigValue, configItems *Chann
 
 }
 
+func loadHashingAlgorithm(configValue *common.ConfigValue, configItems *ChannelCfg) error {
+	hashingAlgorithm := &common.HashingAlgorithm{}
+	err := proto.Unmarshal(configValue.Value, hashingAlgorithm)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal hashing algorithm from config failed")
+	}
+	configItems.hashing = hashingAlgorithm.Name
+	return nil
+}
+
+func loadConsortium(configValue *common.ConfigValue, configItems *ChannelCfg) error {
+	consortium := &common.Consortium{}
+	err := proto.Unmarshal(configValue.Value, consortium)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal consortium from config failed")
+	}
+	configItems.consortium = consortium.Name
+	return nil
+}
+
+func loadACLs(configValue *common.ConfigValue, configItems *ChannelCfg) error {
+	acls := &pb.ACLs{}
+	err := proto.Unmarshal(configValue.Value, acls)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal ACLs from config failed")
+	}
+	for resource, apiResource := range acls.Acls {
+		configItems.acls[resource] = apiResource.GetPolicyRef()
+	}
+	return nil
+}
+
+// ordererConfig returns the orderer config of the channel, creating it if necessary
+func (cfg *ChannelCfg) ordererConfig() *fab.ChannelOrdererConfig {
+	if cfg.ordererCfg == nil {
+		cfg.ordererCfg = &fab.ChannelOrdererConfig{}
+	}
+	return cfg.ordererCfg
+}
+
+func loadConsensusType(configValue *common.ConfigValue, configItems *ChannelCfg) error {
+	consensusType := &ab.ConsensusType{}
+	err := proto.Unmarshal(configValue.Value, consensusType)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal ConsensusType from config failed")
+	}
+	configItems.ordererConfig().ConsensusType = consensusType.Type
+	configItems.ordererConfig().ConsensusMetadata = consensusType.Metadata
+	return nil
+}
+
+func loadBatchSize(configValue *common.ConfigValue, configItems *ChannelCfg) error {
+	batchSize := &ab.BatchSize{}
+	err := proto.Unmarshal(configValue.Value, batchSize)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal batch size from config failed")
+	}
+	configItems.ordererConfig().BatchSize = fab.BatchSize{
+		MaxMessageCount:   batchSize.MaxMessageCount,
+		AbsoluteMaxBytes:  batchSize.AbsoluteMaxBytes,
+		PreferredMaxBytes: batchSize.PreferredMaxBytes,
+	}
+	return nil
+}
+
+func loadBatchTimeout(configValue *common.ConfigValue, configItems *ChannelCfg) error {
+	batchTimeout := &ab.BatchTimeout{}
+	err := proto.Unmarshal(configValue.Value, batchTimeout)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal batch timeout from config failed")
+	}
+	timeout, err := time.ParseDuration(batchTimeout.Timeout)
+	if err != nil {
+		return errors.Wrapf(err, "invalid batch timeout [%s]", batchTimeout.Timeout)
+	}
+	configItems.ordererConfig().BatchTimeout = timeout
+	return nil
+}
+
+func loadChannelRestrictions(configValue *common.ConfigValue, configItems *ChannelCfg) error {
+	channelRestrictions := &ab.ChannelRestrictions{}
+	err := proto.Unmarshal(configValue.Value, channelRestrictions)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal channel restrictions from config failed")
+	}
+	configItems.ordererConfig().MaxChannels = channelRestrictions.MaxCount
+	return nil
+}
+
 func loadCapabilities(configValue *common.ConfigValue, configItems *ChannelCfg, groupName string) error {
 	capabilities := &common.Capabilities{}
 	err := proto.Unmarshal(configValue.Value, capabilities)
@@ -541,55 +687,35 @@ func loadConfigValue(configItems *ChannelCfg, key string, versionsValue *common.
 		if err := loadCapabilities(configValue, configItems, groupName); err != nil {
 			return err
 		}
-	//case channelConfig.ConsensusTypeKey:
-	//	consensusType := &ab.ConsensusType{}
-	//	err := proto.Unmarshal(configValue.Value, consensusType)
-	//	if err != nil {
-	//		return errors.Wrap(err, "unmarshal ConsensusType from config failed")
-	//	}
-	//
-	//	logger.Debugf("loadConfigValue - %s   - Consensus type value :: %s", groupName, consensusType.Type)
-	//	// TODO: Do something with this value
-	//case channelConfig.BatchSizeKey:
-	//	batchSize := &ab.BatchSize{}
-	//	err := proto.Unmarshal(configValue.Value, batchSize)
-	//	if err != nil {
-	//		return errors.Wrap(err, "unmarshal batch size from config failed")
-	//	}
-	//
-	//	// TODO: Do something with this value
-
-	//case channelConfig.BatchTimeoutKey:
-	//	batchTimeout := &ab.BatchTimeout{}
-	//	err := proto.Unmarshal(configValue.Value, batchTimeout)
-	//	if err != nil {
-	//		return errors.Wrap(err, "unmarshal batch timeout from config failed")
-	//	}
-	//	// TODO: Do something with this value
-
-	//case channelConfig.ChannelRestrictionsKey:
-	//	channelRestrictions := &ab.ChannelRestrictions{}
-	//	err := proto.Unmarshal(configValue.Value, channelRestrictions)
-	//	if err != nil {
-	//		return errors.Wrap(err, "unmarshal channel restrictions from config failed")
-	//	}
-	//	// TODO: Do something with this value
-
-	//case channelConfig.HashingAlgorithmKey:
-	//	hashingAlgorithm := &common.HashingAlgorithm{}
-	//	err := proto.Unmarshal(configValue.Value, hashingAlgorithm)
-	//	if err != nil {
-	//		return errors.Wrap(err, "unmarshal hashing algorithm from config failed")
-	//	}
-	//	// TODO: Do something with this value
+	case channelConfig.ConsensusTypeKey:
+		if err := loadConsensusType(configValue, configItems); err != nil {
+			return err
+		}
+	case channelConfig.BatchSizeKey:
+		if err := loadBatchSize(configValue, configItems); err != nil {
+			return err
+		}
+	case channelConfig.BatchTimeoutKey:
+		if err := loadBatchTimeout(configValue, configItems); err != nil {
+			return err
+		}
+	case channelConfig.ChannelRestrictionsKey:
+		if err := loadChannelRestrictions(configValue, configItems); err != nil {
+			return err
+		}
+	case channelConfig.HashingAlgorithmKey:
+		if err := loadHashingAlgorithm(configValue, configItems); err != nil {
+			return err
+		}
 
-	//case channelConfig.ConsortiumKey:
-	//	consortium := &common.Consortium{}
-	//	err := proto.Unmarshal(configValue.Value, consortium)
-	//	if err != nil {
-	//		return errors.Wrap(err, "unmarshal consortium from config failed")
-	//	}
-	//	// TODO: Do something with this value
+	case channelConfig.ConsortiumKey:
+		if err := loadConsortium(configValue, configItems); err != nil {
+			return err
+		}
+	case channelConfig.ACLsKey:
+		if err := loadACLs(configValue, configItems); err != nil {
+			return err
+		}
 
 	//case channelConfig.BlockDataHashingStructureKey:
 	//	bdhstruct := &common.BlockDataHashingStructure{}
@@ -20,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 	"github.com/hyperledger/fabric-sdk-go/test/metadata"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/pkg/errors"
 
 	"strings"
@@ -236,6 +237,61 @@ func TestResolveOptsDefaultValuesWithInvalidChannel(t *testing.T) {
 	testResolveOptsDefaultValues(t, "INVALID-CHANNEL-ID")
 }
 
+func TestHashingAlgorithm(t *testing.T) {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:        "Admins",
+			MSPNames:         []string{"Org1MSP"},
+			OrdererAddress:   "localhost:9999",
+			RootCA:           validRootCA,
+			HashingAlgorithm: "SM3",
+		},
+	}
+
+	chConfig, err := extractConfig("mychannel", builder.Build())
+	require.NoError(t, err)
+	assert.Equal(t, "SM3", chConfig.HashingAlgorithm())
+}
+
+func TestChannelConfigModel(t *testing.T) {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{"Org1MSP"},
+			OrdererAddress: "localhost:9999",
+			RootCA:         validRootCA,
+			Consortium:     "SampleConsortium",
+			ACLs:           map[string]string{"peer/Propose": "/Channel/Application/Writers"},
+		},
+	}
+
+	chConfig, err := extractConfig("mychannel", builder.Build())
+	require.NoError(t, err)
+
+	assert.Equal(t, "SampleConsortium", chConfig.Consortium())
+	assert.Equal(t, map[string]string{"peer/Propose": "/Channel/Application/Writers"}, chConfig.ACLs())
+
+	ordererConfig := chConfig.OrdererConfig()
+	require.NotNil(t, ordererConfig)
+	assert.Equal(t, "sample-Consensus-Type", ordererConfig.ConsensusType)
+	assert.Equal(t, uint32(10), ordererConfig.BatchSize.MaxMessageCount)
+	assert.Equal(t, uint32(103809024), ordererConfig.BatchSize.AbsoluteMaxBytes)
+	assert.Equal(t, uint32(524288), ordererConfig.BatchSize.PreferredMaxBytes)
+	assert.Equal(t, 2*time.Second, ordererConfig.BatchTimeout)
+	assert.Equal(t, uint64(200), ordererConfig.MaxChannels)
+
+	policies := chConfig.Policies()
+	require.Contains(t, policies, "/Channel/Admins")
+	require.Contains(t, policies, "/Channel/Orderer/BlockValidation")
+
+	writers, ok := policies["/Channel/Application/Writers"]
+	require.True(t, ok)
+	assert.Equal(t, common.Policy_SIGNATURE, writers.Type)
+	assert.Equal(t, "Admins", writers.ModPolicy)
+	assert.NotNil(t, writers.Signature)
+	assert.Nil(t, writers.ImplicitMeta)
+}
+
 func TestCapabilities(t *testing.T) {
 	capability1 := "V1_1_PVTDATA_EXPERIMENTAL"
 	capability2 := "V1_1_RESOURCETREE_EXPERIMENTAL"
@@ -24,6 +24,7 @@ type params struct {
 	failFast        bool
 	insecure        bool
 	connectTimeout  time.Duration
+	dialOptionSets  []string
 }
 
 func defaultParams() *params {
@@ -88,6 +89,15 @@ func WithInsecure() options.Opt {
 	}
 }
 
+// WithDialOptionSets selects the named dial option sets that are applied to the connection
+func WithDialOptionSets(names ...string) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(dialOptionSetsSetter); ok {
+			setter.SetDialOptionSets(names...)
+		}
+	}
+}
+
 func (p *params) SetHostOverride(value string) {
 	logger.Debugf("HostOverride: %s", value)
 	p.hostOverride = value
@@ -122,6 +132,11 @@ func (p *params) SetInsecure(value bool) {
 	p.insecure = value
 }
 
+func (p *params) SetDialOptionSets(names ...string) {
+	logger.Debugf("DialOptionSets: %v", names)
+	p.dialOptionSets = names
+}
+
 type hostOverrideSetter interface {
 	SetHostOverride(value string)
 }
@@ -146,6 +161,10 @@ type connectTimeoutSetter interface {
 	SetConnectTimeout(value time.Duration)
 }
 
+type dialOptionSetsSetter interface {
+	SetDialOptionSets(names ...string)
+}
+
 // OptsFromPeerConfig returns a set of connection options from the given peer config
 func OptsFromPeerConfig(peerCfg *fab.PeerConfig) []options.Opt {
 
@@ -158,6 +177,9 @@ func OptsFromPeerConfig(peerCfg *fab.PeerConfig) []options.Opt {
 	if isInsecureAllowed(peerCfg) {
 		opts = append(opts, WithInsecure())
 	}
+	if names := DialOptionSetNames(peerCfg.GRPCOptions); len(names) > 0 {
+		opts = append(opts, WithDialOptionSets(names...))
+	}
 
 	return opts
 }
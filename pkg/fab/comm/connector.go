@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
@@ -42,6 +43,11 @@ type CachingConnector struct {
 	waitgroup     sync.WaitGroup
 	janitorDone   chan bool
 	janitorClosed chan bool
+	// dialOpts are applied to all connections
+	dialOpts []grpc.DialOption
+	// dialOptionSets are applied to the connections which select them (see DialOptionSets)
+	dialOptionSets map[string][]grpc.DialOption
+	metrics        *metrics.ClientMetrics
 }
 
 type cachedConn struct {
@@ -55,12 +61,14 @@ type cachedConn struct {
 // sweepTime and idleTime.
 func NewCachingConnector(sweepTime time.Duration, idleTime time.Duration) *CachingConnector {
 	cc := CachingConnector{
-		conns:         map[string]*cachedConn{},
-		index:         map[*grpc.ClientConn]*cachedConn{},
-		janitorDone:   make(chan bool, 1),
-		janitorClosed: make(chan bool, 1),
-		sweepTime:     sweepTime,
-		idleTime:      idleTime,
+		conns:          map[string]*cachedConn{},
+		index:          map[*grpc.ClientConn]*cachedConn{},
+		dialOptionSets: map[string][]grpc.DialOption{},
+		metrics:        metrics.NewDisabledClientMetrics(),
+		janitorDone:    make(chan bool, 1),
+		janitorClosed:  make(chan bool, 1),
+		sweepTime:      sweepTime,
+		idleTime:       idleTime,
 	}
 
 	// cc.janitorClosed determines if a goroutine needs to be spun up.
@@ -72,6 +80,34 @@ func NewCachingConnector(sweepTime time.Duration, idleTime time.Duration) *Cachi
 	return &cc
 }
 
+// SetDialOptions adds dial options which are applied to all new connections. The options may include
+// UnaryInterceptors and StreamInterceptors, which are chained with the SDK's interceptors.
+func (cc *CachingConnector) SetDialOptions(opts ...grpc.DialOption) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	cc.dialOpts = append(cc.dialOpts, opts...)
+}
+
+// SetDialOptionSet registers a named set of dial options. The set is applied to new connections
+// whose dial options include DialOptionSets with the given name, which is the case for endpoints
+// that list the name under the "dial-option-sets" grpcOptions key in the config.
+func (cc *CachingConnector) SetDialOptionSet(name string, opts ...grpc.DialOption) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	cc.dialOptionSets[name] = append(cc.dialOptionSets[name], opts...)
+}
+
+// SetMetrics sets the metrics used to record connection statistics
+func (cc *CachingConnector) SetMetrics(m *metrics.ClientMetrics) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	cc.metrics = m
+	cc.updateConnectionMetrics()
+}
+
 // Close cleans up cached connections.
 func (cc *CachingConnector) Close() {
 	cc.lock.RLock()
@@ -104,15 +140,43 @@ func (cc *CachingConnector) Close() {
 	}
 
 	cc.flush()
+	cc.metrics.ConnectionsOpen.Set(0)
+	cc.metrics.ConnectionsIdle.Set(0)
 	close(cc.janitorClosed)
 	close(cc.janitorDone)
 	cc.janitorDone = nil
 }
 
+// Evict removes all connections from the cache so that subsequent calls to DialContext create new
+// connections (for example, after the TLS certificates of an endpoint have changed). Idle connections
+// are closed immediately. Connections which are still in use are closed by the janitor once released.
+func (cc *CachingConnector) Evict() {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	if cc.janitorDone == nil {
+		logger.Debug("Connector already closed")
+		return
+	}
+
+	logger.Debugf("evicting cached connections [%d]", len(cc.conns))
+	for target, c := range cc.conns {
+		delete(cc.conns, target)
+		if c.open == 0 {
+			logger.Debugf("closing idle connection [%s]", target)
+			delete(cc.index, c.conn)
+			closeConn(c.conn)
+		}
+	}
+	cc.updateConnectionMetrics()
+}
+
 // DialContext is a wrapper for grpc.DialContext where connections are cached.
 func (cc *CachingConnector) DialContext(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	logger.Debugf("DialContext: %s", target)
 
+	startTime := time.Now()
+
 	cc.lock.Lock()
 	c, ok := cc.loadConn(target)
 	if !ok {
@@ -123,15 +187,24 @@ func (cc *CachingConnector) DialContext(ctx context.Context, target string, opts
 		}
 		c = createdConn
 	}
+	cc.updateConnectionMetrics()
 
 	cc.lock.Unlock()
 
 	if err := cc.openConn(ctx, c); err != nil {
 		cc.lock.Lock()
 		setClosed(c)
+		cc.updateConnectionMetrics()
 		cc.lock.Unlock()
 		return nil, errors.Errorf("dialing connection timed out [%s]", target)
 	}
+
+	if !ok {
+		cc.lock.RLock()
+		cc.metrics.ConnectionDialDuration.With("target", target).Observe(time.Since(startTime).Seconds())
+		cc.lock.RUnlock()
+	}
+
 	return c.conn, nil
 }
 
@@ -162,6 +235,7 @@ func (cc *CachingConnector) ReleaseConn(conn *grpc.ClientConn) {
 	logger.Debugf("ReleaseConn [%s]", cconn.target)
 
 	setClosed(cconn)
+	cc.updateConnectionMetrics()
 
 	cc.ensureJanitorStarted()
 }
@@ -192,7 +266,7 @@ func (cc *CachingConnector) createConn(ctx context.Context, target string, opts
 	}
 
 	logger.Debugf("creating connection [%s]", target)
-	conn, err := grpc.DialContext(ctx, target, opts...)
+	conn, err := grpc.DialContext(ctx, target, cc.resolveDialOpts(target, opts)...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "dialing node failed")
 	}
@@ -243,8 +317,7 @@ func (cc *CachingConnector) shutdownConn(cconn *cachedConn) {
 	}
 
 	logger.Debugf("connection was shutdown [%s]", cconn.target)
-	delete(cc.conns, cconn.target)
-	delete(cc.index, cconn.conn)
+	cc.deleteConn(cconn)
 
 	cc.ensureJanitorStarted()
 }
@@ -264,13 +337,21 @@ func (cc *CachingConnector) sweepAndRemove() {
 
 func (cc *CachingConnector) removeConn(c *cachedConn) {
 	logger.Debugf("removing connection [%s]", c.target)
-	delete(cc.index, c.conn)
-	delete(cc.conns, c.target)
+	cc.deleteConn(c)
 	if err := c.conn.Close(); err != nil {
 		logger.Debugf("unable to close connection [%s]", err)
 	}
 }
 
+// deleteConn removes the connection from the cache. The target entry is only removed if it still
+// refers to the given connection since it may have been replaced after an eviction.
+func (cc *CachingConnector) deleteConn(c *cachedConn) {
+	delete(cc.index, c.conn)
+	if cc.conns[c.target] == c {
+		delete(cc.conns, c.target)
+	}
+}
+
 func (cc *CachingConnector) ensureJanitorStarted() {
 	select {
 	case <-cc.janitorClosed:
@@ -307,6 +388,7 @@ func (cc *CachingConnector) janitor() {
 		case <-ticker.C:
 			cc.lock.Lock()
 			cc.sweepAndRemove()
+			cc.updateConnectionMetrics()
 			numConn := len(cc.index)
 			cc.lock.Unlock()
 			if numConn == 0 {
@@ -337,6 +419,20 @@ func closeConn(conn *grpc.ClientConn) {
 	cancel()
 }
 
+// updateConnectionMetrics records the number of open and idle connections. The lock must be held.
+func (cc *CachingConnector) updateConnectionMetrics() {
+	var open, idle int
+	for _, c := range cc.index {
+		if c.open > 0 {
+			open++
+		} else {
+			idle++
+		}
+	}
+	cc.metrics.ConnectionsOpen.Set(float64(open))
+	cc.metrics.ConnectionsIdle.Set(float64(idle))
+}
+
 func setClosed(cconn *cachedConn) {
 	if cconn.open > 0 {
 		cconn.lastClose = time.Now()
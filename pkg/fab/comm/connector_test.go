@@ -14,6 +14,8 @@ import (
 	"time"
 	"unsafe"
 
+	commonmetrics "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -275,6 +277,102 @@ func TestConnectorConcurrentSweep(t *testing.T) {
 	}
 }
 
+func TestConnectorEvict(t *testing.T) {
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime)
+	defer connector.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn1, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	require.NoError(t, err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn2, err := connector.DialContext(ctx, endorserAddr[1], grpc.WithInsecure())
+	cancel()
+	require.NoError(t, err)
+	connector.ReleaseConn(conn2)
+
+	connector.Evict()
+
+	assert.Equal(t, connectivity.Shutdown, conn2.GetState(), "idle connection should be closed")
+	assert.NotEqual(t, connectivity.Shutdown, conn1.GetState(), "connection in use should not be closed")
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn3, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	require.NoError(t, err)
+	assert.NotEqual(t, unsafe.Pointer(conn1), unsafe.Pointer(conn3), "a new connection should have been created")
+
+	connector.ReleaseConn(conn1)
+
+	connector.lock.RLock()
+	defer connector.lock.RUnlock()
+	assert.Len(t, connector.index, 2, "evicted connection should remain indexed until swept")
+	assert.Equal(t, conn3, connector.conns[endorserAddr[0]].conn)
+}
+
+func TestConnectorMetrics(t *testing.T) {
+	openGauge := &testGauge{}
+	idleGauge := &testGauge{}
+
+	clientMetrics := metrics.NewDisabledClientMetrics()
+	clientMetrics.ConnectionsOpen = openGauge
+	clientMetrics.ConnectionsIdle = idleGauge
+
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime)
+	defer connector.Close()
+	connector.SetMetrics(clientMetrics)
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn1, err := connector.DialContext(ctx, endorserAddr[4], grpc.WithInsecure())
+	cancel()
+	require.NoError(t, err)
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn2, err := connector.DialContext(ctx, endorserAddr[5], grpc.WithInsecure())
+	cancel()
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(2), openGauge.Value())
+	assert.Equal(t, float64(0), idleGauge.Value())
+
+	connector.ReleaseConn(conn1)
+	assert.Equal(t, float64(1), openGauge.Value())
+	assert.Equal(t, float64(1), idleGauge.Value())
+
+	connector.ReleaseConn(conn2)
+	assert.Equal(t, float64(0), openGauge.Value())
+	assert.Equal(t, float64(2), idleGauge.Value())
+}
+
+// testGauge is a gauge that records the last value that was set
+type testGauge struct {
+	mutex sync.RWMutex
+	value float64
+}
+
+func (g *testGauge) With(labelValues ...string) commonmetrics.Gauge {
+	return g
+}
+
+func (g *testGauge) Add(delta float64) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.value += delta
+}
+
+func (g *testGauge) Set(value float64) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.value = value
+}
+
+func (g *testGauge) Value() float64 {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+	return g.value
+}
+
 func testDial(t *testing.T, wg *sync.WaitGroup, errChan chan error, connector *CachingConnector, addr string, minSleepBeforeRelease int, maxSleepBeforeRelease int) {
 	defer wg.Done()
 	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout*10)
@@ -0,0 +1,176 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
+	"google.golang.org/grpc"
+)
+
+// DialOptionSetsKey is the grpcOptions key (in the peer/orderer config) which lists the names of the
+// dial option sets that are applied to connections to the endpoint. The value may be a single name,
+// a comma-separated list of names or a list of names.
+const DialOptionSetsKey = "dial-option-sets"
+
+// UnaryInterceptors returns a dial option which adds the given unary interceptors to the connection.
+// Unlike grpc.WithUnaryInterceptor, the interceptors are chained with the interceptors provided by
+// the SDK and by other UnaryInterceptors options (in the order in which they are provided).
+// Note that this option is only honoured by the CachingConnector.
+func UnaryInterceptors(interceptors ...grpc.UnaryClientInterceptor) grpc.DialOption {
+	return &unaryInterceptorsOption{interceptors: interceptors}
+}
+
+// StreamInterceptors returns a dial option which adds the given stream interceptors to the connection.
+// Unlike grpc.WithStreamInterceptor, the interceptors are chained with the interceptors provided by
+// the SDK and by other StreamInterceptors options (in the order in which they are provided).
+// Note that this option is only honoured by the CachingConnector.
+func StreamInterceptors(interceptors ...grpc.StreamClientInterceptor) grpc.DialOption {
+	return &streamInterceptorsOption{interceptors: interceptors}
+}
+
+// DialOptionSets returns a dial option which selects the named dial option sets (registered
+// with the CachingConnector) that are to be applied to the connection.
+func DialOptionSets(names ...string) grpc.DialOption {
+	return &dialOptionSetsOption{names: names}
+}
+
+// DialOptionSetNames returns the names of the dial option sets that are configured
+// in the given grpcOptions (see DialOptionSetsKey)
+func DialOptionSetNames(grpcOptions map[string]interface{}) []string {
+	var names []string
+	switch value := grpcOptions[DialOptionSetsKey].(type) {
+	case string:
+		names = strings.Split(value, ",")
+	case []string:
+		names = value
+	case []interface{}:
+		for _, v := range value {
+			if name, ok := v.(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	var result []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+type unaryInterceptorsOption struct {
+	grpc.EmptyDialOption
+	interceptors []grpc.UnaryClientInterceptor
+}
+
+type streamInterceptorsOption struct {
+	grpc.EmptyDialOption
+	interceptors []grpc.StreamClientInterceptor
+}
+
+type dialOptionSetsOption struct {
+	grpc.EmptyDialOption
+	names []string
+}
+
+// resolveDialOpts expands the given dial options with the connector's global dial options followed by
+// the options of the selected dial option sets (so that endpoint-specific options take precedence).
+// All interceptors are chained into a single unary and stream interceptor, the first of which is the
+// SDK's tracing interceptor.
+func (cc *CachingConnector) resolveDialOpts(target string, opts []grpc.DialOption) []grpc.DialOption {
+	var resolved []grpc.DialOption
+	var unary []grpc.UnaryClientInterceptor
+	var stream []grpc.StreamClientInterceptor
+	var sets []string
+
+	expand := func(opts []grpc.DialOption) {
+		for _, opt := range opts {
+			switch o := opt.(type) {
+			case *unaryInterceptorsOption:
+				unary = append(unary, o.interceptors...)
+			case *streamInterceptorsOption:
+				stream = append(stream, o.interceptors...)
+			case *dialOptionSetsOption:
+				sets = append(sets, o.names...)
+			default:
+				resolved = append(resolved, opt)
+			}
+		}
+	}
+
+	expand(opts)
+	expand(cc.dialOpts)
+
+	applied := make(map[string]bool)
+	for i := 0; i < len(sets); i++ {
+		name := sets[i]
+		if applied[name] {
+			continue
+		}
+		applied[name] = true
+
+		setOpts, ok := cc.dialOptionSets[name]
+		if !ok {
+			logger.Warnf("dial option set [%s] not found for target [%s]", name, target)
+			continue
+		}
+		logger.Debugf("applying dial option set [%s] to target [%s]", name, target)
+		expand(setOpts)
+	}
+
+	if len(unary) > 0 {
+		resolved = append(resolved, grpc.WithUnaryInterceptor(
+			chainUnaryInterceptors(append([]grpc.UnaryClientInterceptor{tracing.UnaryClientInterceptor()}, unary...)...)))
+	}
+	if len(stream) > 0 {
+		resolved = append(resolved, grpc.WithStreamInterceptor(
+			chainStreamInterceptors(append([]grpc.StreamClientInterceptor{tracing.StreamClientInterceptor()}, stream...)...)))
+	}
+
+	return resolved
+}
+
+// chainUnaryInterceptors chains the given interceptors into a single interceptor.
+// The first interceptor is the outermost one.
+func chainUnaryInterceptors(interceptors ...grpc.UnaryClientInterceptor) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		chained := invoker
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			chained = unaryInvoker(interceptors[i], chained)
+		}
+		return chained(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func unaryInvoker(interceptor grpc.UnaryClientInterceptor, next grpc.UnaryInvoker) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return interceptor(ctx, method, req, reply, cc, next, opts...)
+	}
+}
+
+// chainStreamInterceptors chains the given interceptors into a single interceptor.
+// The first interceptor is the outermost one.
+func chainStreamInterceptors(interceptors ...grpc.StreamClientInterceptor) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		chained := streamer
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			chained = streamInvoker(interceptors[i], chained)
+		}
+		return chained(ctx, desc, cc, method, opts...)
+	}
+}
+
+func streamInvoker(interceptor grpc.StreamClientInterceptor, next grpc.Streamer) grpc.Streamer {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return interceptor(ctx, desc, cc, method, next, opts...)
+	}
+}
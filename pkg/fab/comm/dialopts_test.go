@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestConnectorInterceptors(t *testing.T) {
+	var mutex sync.Mutex
+	var invoked []string
+
+	interceptor := func(name string) grpc.UnaryClientInterceptor {
+		return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			mutex.Lock()
+			invoked = append(invoked, name)
+			mutex.Unlock()
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+	}
+
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime)
+	defer connector.Close()
+
+	connector.SetDialOptions(UnaryInterceptors(interceptor("global1"), interceptor("global2")))
+	connector.SetDialOptionSet("set1", UnaryInterceptors(interceptor("set1")))
+
+	// Connection which selects the dial option set
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn, err := connector.DialContext(ctx, endorserAddr[2], grpc.WithInsecure(), DialOptionSets("set1"))
+	cancel()
+	require.NoError(t, err)
+	defer connector.ReleaseConn(conn)
+
+	_, err = pb.NewEndorserClient(conn).ProcessProposal(context.Background(), &pb.SignedProposal{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"global1", "global2", "set1"}, invoked)
+
+	// Connection which doesn't select the dial option set
+	invoked = nil
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn2, err := connector.DialContext(ctx, endorserAddr[3], grpc.WithInsecure(), DialOptionSets("unknown"))
+	cancel()
+	require.NoError(t, err)
+	defer connector.ReleaseConn(conn2)
+
+	_, err = pb.NewEndorserClient(conn2).ProcessProposal(context.Background(), &pb.SignedProposal{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"global1", "global2"}, invoked)
+}
+
+func TestChainStreamInterceptors(t *testing.T) {
+	var invoked []string
+
+	interceptor := func(name string) grpc.StreamClientInterceptor {
+		return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			invoked = append(invoked, name)
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+	}
+
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		invoked = append(invoked, "streamer")
+		return nil, nil
+	}
+
+	chained := chainStreamInterceptors(interceptor("first"), interceptor("second"))
+	_, err := chained(context.Background(), &grpc.StreamDesc{}, nil, "method", streamer)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", "streamer"}, invoked)
+}
+
+func TestDialOptionSetNames(t *testing.T) {
+	assert.Empty(t, DialOptionSetNames(nil))
+	assert.Equal(t, []string{"set1"}, DialOptionSetNames(map[string]interface{}{DialOptionSetsKey: "set1"}))
+	assert.Equal(t, []string{"set1", "set2"}, DialOptionSetNames(map[string]interface{}{DialOptionSetsKey: "set1, set2"}))
+	assert.Equal(t, []string{"set1", "set2"}, DialOptionSetNames(map[string]interface{}{DialOptionSetsKey: []interface{}{"set1", "set2"}}))
+	assert.Equal(t, []string{"set1", "set2"}, DialOptionSetNames(map[string]interface{}{DialOptionSetsKey: []string{"set1", "", "set2"}}))
+}
@@ -13,13 +13,17 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/discovery"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
 	"github.com/pkg/errors"
 )
 
 // MockDiscoveryServer is a mock Discovery server
 type MockDiscoveryServer struct {
-	localPeersByOrg map[string]*discovery.Peers
-	peersByOrg      map[string]*discovery.Peers
+	localPeersByOrg   map[string]*discovery.Peers
+	peersByOrg        map[string]*discovery.Peers
+	config            *discovery.ConfigResult
+	endorsersByGroups map[string]*discovery.Peers
+	layouts           []*discovery.Layout
 }
 
 // MockDiscoveryServerOpt is an option for the MockDiscoveryServer
@@ -39,6 +43,32 @@ func WithLocalPeers(peers ...*MockDiscoveryPeerEndpoint) MockDiscoveryServerOpt
 	}
 }
 
+// WithConfig sets the config returned by the MockDiscoveryServer for a config query
+func WithConfig(config *discovery.ConfigResult) MockDiscoveryServerOpt {
+	return func(s *MockDiscoveryServer) {
+		s.config = config
+	}
+}
+
+// WithEndorsers sets the endorsers (by group) and the layouts (each of which maps a group to
+// a number of endorsers) returned by the MockDiscoveryServer for a chaincode query
+func WithEndorsers(endorsersByGroups map[string][]*MockDiscoveryPeerEndpoint, layouts ...map[string]uint32) MockDiscoveryServerOpt {
+	return func(s *MockDiscoveryServer) {
+		s.endorsersByGroups = make(map[string]*discovery.Peers)
+		for grp, peers := range endorsersByGroups {
+			endorsers := &discovery.Peers{}
+			for _, p := range peers {
+				endorsers.Peers = append(endorsers.Peers, asDiscoveryPeer(p))
+			}
+			s.endorsersByGroups[grp] = endorsers
+		}
+		s.layouts = nil
+		for _, layout := range layouts {
+			s.layouts = append(s.layouts, &discovery.Layout{QuantitiesByGroup: layout})
+		}
+	}
+}
+
 // NewServer returns a new MockDiscoveryServer
 func NewServer(opts ...MockDiscoveryServerOpt) *MockDiscoveryServer {
 	s := &MockDiscoveryServer{}
@@ -91,7 +121,7 @@ func (s *MockDiscoveryServer) processQuery(q *discovery.Query) *discovery.QueryR
 			return s.getConfigQueryResult()
 		}
 		if query := q.GetCcQuery(); query != nil {
-			return s.getCCQueryResult()
+			return s.getCCQueryResult(query)
 		}
 	}
 	return nil
@@ -136,6 +166,13 @@ func (s *MockDiscoveryServer) getPeerQueryResult() *discovery.QueryResult {
 }
 
 func (s *MockDiscoveryServer) getConfigQueryResult() *discovery.QueryResult {
+	if s.config != nil {
+		return &discovery.QueryResult{
+			Result: &discovery.QueryResult_ConfigResult{
+				ConfigResult: s.config,
+			},
+		}
+	}
 	return &discovery.QueryResult{
 		Result: &discovery.QueryResult_Error{
 			Error: &discovery.Error{
@@ -145,7 +182,24 @@ func (s *MockDiscoveryServer) getConfigQueryResult() *discovery.QueryResult {
 	}
 }
 
-func (s *MockDiscoveryServer) getCCQueryResult() *discovery.QueryResult {
+func (s *MockDiscoveryServer) getCCQueryResult(query *discovery.ChaincodeQuery) *discovery.QueryResult {
+	if s.endorsersByGroups != nil {
+		var descriptors []*discovery.EndorsementDescriptor
+		for _, interest := range query.Interests {
+			descriptors = append(descriptors, &discovery.EndorsementDescriptor{
+				Chaincode:         interest.Chaincodes[0].Name,
+				EndorsersByGroups: s.endorsersByGroups,
+				Layouts:           s.layouts,
+			})
+		}
+		return &discovery.QueryResult{
+			Result: &discovery.QueryResult_CcQueryRes{
+				CcQueryRes: &discovery.ChaincodeQueryResult{
+					Content: descriptors,
+				},
+			},
+		}
+	}
 	return &discovery.QueryResult{
 		Result: &discovery.QueryResult_Error{
 			Error: &discovery.Error{
@@ -178,7 +232,7 @@ func asDiscoveryPeer(p *MockDiscoveryPeerEndpoint) *discovery.Peer {
 		Content: &gossip.GossipMessage_StateInfo{
 			StateInfo: &gossip.StateInfo{
 				Properties: &gossip.Properties{
-					Chaincodes:   nil,
+					Chaincodes:   p.Chaincodes,
 					LedgerHeight: p.LedgerHeight,
 				},
 				Timestamp: &gossip.PeerTime{
@@ -193,6 +247,11 @@ func asDiscoveryPeer(p *MockDiscoveryPeerEndpoint) *discovery.Peer {
 		panic(err.Error())
 	}
 
+	identity, err := proto.Marshal(&msp.SerializedIdentity{Mspid: p.MSPID})
+	if err != nil {
+		panic(err.Error())
+	}
+
 	return &discovery.Peer{
 		MembershipInfo: &gossip.Envelope{
 			Payload: memInfoPayload,
@@ -200,6 +259,7 @@ func asDiscoveryPeer(p *MockDiscoveryPeerEndpoint) *discovery.Peer {
 		StateInfo: &gossip.Envelope{
 			Payload: stateInfoPayload,
 		},
+		Identity: identity,
 	}
 }
 
@@ -208,6 +268,7 @@ type MockDiscoveryPeerEndpoint struct {
 	MSPID        string
 	Endpoint     string
 	LedgerHeight uint64
+	Chaincodes   []*gossip.Chaincode
 }
 
 func asPeersByOrg(peers []*MockDiscoveryPeerEndpoint) map[string]*discovery.Peers {
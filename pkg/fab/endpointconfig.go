@@ -63,6 +63,7 @@ const (
 	defaultReconnectBlockHeightLagThreshold = 10
 	defaultPeerMonitor                      = "" // The peer monitor will be enabled if necessary
 	defaultPeerMonitorPeriod                = 5 * time.Second
+	defaultConsensusPeers                   = 3
 
 	//default grpc opts
 	defaultKeepAliveTime    = 0
@@ -95,6 +96,8 @@ var (
 			BlockHeightLagThreshold: defaultBlockHeightLagThreshold,
 		},
 		EventService: EventServicePolicy{
+			Type:                             string(fab.DeliverEventServiceType),
+			ConsensusPeers:                   defaultConsensusPeers,
 			ResolverStrategy:                 string(fab.PreferOrgStrategy),
 			MinBlockHeightResolverMode:       string(defaultMinBlockHeightResolverMode),
 			Balancer:                         Random,
@@ -507,7 +510,7 @@ func (c *EndpointConfig) loadEndpointConfigEntities(configEntity *endpointConfig
 func (c *EndpointConfig) loadDefaultChannel() {
 	defChCfg, ok := c.networkConfig.Channels[defaultEntity]
 	if ok {
-		c.defaultChannel = &fab.ChannelEndpointConfig{Peers: defChCfg.Peers, Orderers: defChCfg.Orderers, Policies: defChCfg.Policies}
+		c.defaultChannel = &fab.ChannelEndpointConfig{Peers: defChCfg.Peers, Orderers: defChCfg.Orderers, Policies: defChCfg.Policies, Chaincodes: defChCfg.Chaincodes}
 		delete(c.networkConfig.Channels, defaultEntity)
 	} else {
 		logger.Debugf("No default config. Returning hard-coded defaults.")
@@ -633,11 +636,25 @@ func (c *EndpointConfig) loadChannelEndpointConfig(chNwCfg ChannelEndpointConfig
 		}
 	}
 
+	chNwCfgChaincodes := chNwCfg.Chaincodes
+	if len(chNwCfgChaincodes) == 0 {
+		//fill chaincodes in with default channel chaincodes
+		chNwCfgChaincodes = defChNwCfg.Chaincodes
+	}
+
+	chChaincodes := make(map[string]fab.ChaincodeConfig)
+	for ccName, ccCfg := range chNwCfgChaincodes {
+		chChaincodes[strings.ToLower(ccName)] = fab.ChaincodeConfig{
+			Policy: ccCfg.Policy,
+		}
+	}
+
 	// Policies use default channel policies if info is missing
 	return fab.ChannelEndpointConfig{
-		Peers:    chPeers,
-		Orderers: chOrderers,
-		Policies: c.addMissingChannelPoliciesItems(chNwCfg),
+		Peers:      chPeers,
+		Orderers:   chOrderers,
+		Policies:   c.addMissingChannelPoliciesItems(chNwCfg),
+		Chaincodes: chChaincodes,
 	}
 }
 
@@ -664,6 +681,9 @@ func (c *EndpointConfig) getChannelPolicies(policies *ChannelPolicies) fab.Chann
 	}
 
 	eventServicePolicy := fab.EventServicePolicy{
+		Type:                             fab.EventServiceType(policies.EventService.Type),
+		ConsensusPeers:                   policies.EventService.ConsensusPeers,
+		ConsensusQuorum:                  policies.EventService.ConsensusQuorum,
 		ResolverStrategy:                 fab.ResolverStrategy(policies.EventService.ResolverStrategy),
 		MinBlockHeightResolverMode:       fab.MinBlockHeightResolverMode(policies.EventService.MinBlockHeightResolverMode),
 		Balancer:                         fab.BalancerType(policies.EventService.Balancer),
@@ -749,6 +769,15 @@ func (c *EndpointConfig) addMissingQueryChannelConfigPolicyInfo(policy fab.Query
 }
 
 func (c *EndpointConfig) addMissingEventServicePolicyInfo(policy fab.EventServicePolicy) fab.EventServicePolicy {
+	if policy.Type == "" {
+		policy.Type = c.defaultChannelPolicies.EventService.Type
+	}
+	if policy.ConsensusPeers == 0 {
+		policy.ConsensusPeers = c.defaultChannelPolicies.EventService.ConsensusPeers
+	}
+	if policy.ConsensusQuorum == 0 {
+		policy.ConsensusQuorum = c.defaultChannelPolicies.EventService.ConsensusQuorum
+	}
 	if policy.Balancer == "" {
 		policy.Balancer = c.defaultChannelPolicies.EventService.Balancer
 	}
@@ -825,9 +854,10 @@ func (c *EndpointConfig) loadAllPeerConfigs(networkConfig *fab.NetworkConfig, en
 			return errors.WithMessage(err, "failed to load peer network config")
 		}
 		networkConfig.Peers[name] = c.addMissingPeerConfigItems(name, fab.PeerConfig{
-			URL:         peerConfig.URL,
-			GRPCOptions: peerConfig.GRPCOptions,
-			TLSCACert:   tlsCert,
+			URL:           peerConfig.URL,
+			GRPCOptions:   peerConfig.GRPCOptions,
+			TLSCACert:     tlsCert,
+			OperationsURL: peerConfig.OperationsURL,
 		})
 	}
 	return nil
@@ -1026,6 +1056,14 @@ func (c *EndpointConfig) loadDefaultQueryChannelPolicy(policy *fab.QueryChannelC
 }
 
 func (c *EndpointConfig) loadDefaultEventServicePolicy(policy *fab.EventServicePolicy) {
+	if policy.Type == "" {
+		policy.Type = fab.DeliverEventServiceType
+	}
+
+	if policy.ConsensusPeers == 0 {
+		policy.ConsensusPeers = defaultConsensusPeers
+	}
+
 	if policy.ResolverStrategy == "" {
 		policy.ResolverStrategy = defaultResolverStrategy
 	}
@@ -1438,9 +1476,10 @@ func (c *EndpointConfig) tryMatchingPeerConfig(peerSearchKey string, searchByURL
 		for _, staticPeerConfig := range c.networkConfig.Peers {
 			if strings.EqualFold(staticPeerConfig.URL, peerSearchKey) {
 				return &fab.PeerConfig{
-					URL:         staticPeerConfig.URL,
-					GRPCOptions: staticPeerConfig.GRPCOptions,
-					TLSCACert:   staticPeerConfig.TLSCACert,
+					URL:           staticPeerConfig.URL,
+					GRPCOptions:   staticPeerConfig.GRPCOptions,
+					TLSCACert:     staticPeerConfig.TLSCACert,
+					OperationsURL: staticPeerConfig.OperationsURL,
 				}, true
 			}
 		}
@@ -1506,9 +1545,10 @@ func (c *EndpointConfig) getMappedPeer(host string) *fab.PeerConfig {
 	}
 
 	mappedConfig := fab.PeerConfig{
-		URL:         peerConfig.URL,
-		TLSCACert:   peerConfig.TLSCACert,
-		GRPCOptions: make(map[string]interface{}),
+		URL:           peerConfig.URL,
+		TLSCACert:     peerConfig.TLSCACert,
+		GRPCOptions:   make(map[string]interface{}),
+		OperationsURL: peerConfig.OperationsURL,
 	}
 
 	for key, val := range peerConfig.GRPCOptions {
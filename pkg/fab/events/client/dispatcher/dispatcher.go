@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/greylist"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
@@ -17,6 +18,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/peerresolver"
 	esdispatcher "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/pkg/errors"
 )
 
@@ -39,6 +41,9 @@ type Dispatcher struct {
 	peerMonitorDone        chan struct{}
 	peer                   fab.Peer
 	lock                   sync.RWMutex
+	metrics                *metrics.ClientMetrics
+	connectedBefore        bool
+	greylist               *greylist.Filter
 }
 
 // New creates a new dispatcher
@@ -46,13 +51,20 @@ func New(context context.Client, chConfig fab.ChannelCfg, discoveryService fab.D
 	params := defaultParams(context, chConfig.ID())
 	options.Apply(params, opts)
 
+	clientMetrics := context.GetMetrics()
+
+	esOpts := append([]options.Opt{}, opts...)
+	esOpts = append(esOpts, esdispatcher.WithDroppedEventsCounter(clientMetrics.EventsDropped.With("channel", chConfig.ID())))
+
 	dispatcher := &Dispatcher{
-		Dispatcher:         esdispatcher.New(opts...),
+		Dispatcher:         esdispatcher.New(esOpts...),
 		params:             *params,
 		context:            context,
 		chConfig:           chConfig,
 		discoveryService:   discoveryService,
 		connectionProvider: connectionProvider,
+		metrics:            clientMetrics,
+		greylist:           greylist.New(context.EndpointConfig().Timeout(fab.DiscoveryGreylistExpiry)),
 	}
 	dispatcher.peerResolver = params.peerResolverProvider(dispatcher, context, chConfig.ID(), opts...)
 
@@ -115,6 +127,8 @@ func (ed *Dispatcher) HandleConnectEvent(e esdispatcher.Event) {
 		return
 	}
 
+	peers = ed.filterGreylisted(peers)
+
 	if len(peers) == 0 {
 		evt.ErrCh <- errors.New("no peers to connect to")
 		return
@@ -136,6 +150,12 @@ func (ed *Dispatcher) HandleConnectEvent(e esdispatcher.Event) {
 	ed.connection = conn
 	ed.setConnectedPeer(peer)
 
+	ed.setConnectionState(true)
+	if ed.connectedBefore {
+		ed.metrics.EventReconnects.With("channel", ed.chConfig.ID()).Add(1)
+	}
+	ed.connectedBefore = true
+
 	go ed.connection.Receive(eventch)
 
 	evt.ErrCh <- nil
@@ -155,6 +175,7 @@ func (ed *Dispatcher) HandleDisconnectEvent(e esdispatcher.Event) {
 	ed.connection.Close()
 	ed.connection = nil
 	ed.setConnectedPeer(nil)
+	ed.setConnectionState(false)
 
 	evt.Errch <- nil
 }
@@ -202,6 +223,7 @@ func (ed *Dispatcher) HandleDisconnectedEvent(e esdispatcher.Event) {
 		ed.connection.Close()
 		ed.connection = nil
 	}
+	ed.setConnectionState(false)
 
 	if ed.connectionRegistration != nil {
 		logger.Debugf("Disconnected from event server: %s", evt.Err)
@@ -278,6 +300,8 @@ func (ed *Dispatcher) disconnected() bool {
 		return false
 	}
 
+	ed.updateBlockLag(connectedPeer, peers)
+
 	if !ed.peerResolver.ShouldDisconnect(peers, connectedPeer) {
 		logger.Debugf("Event client will not disconnect from peer [%s] on channel [%s]...", connectedPeer.URL(), ed.chConfig.ID())
 		return false
@@ -294,6 +318,34 @@ func (ed *Dispatcher) disconnected() bool {
 	return true
 }
 
+// updateBlockLag records the number of blocks by which the connected peer lags
+// behind the peer with the highest block height. The peers are the ones already
+// resolved by the peer monitor so no additional discovery call is made.
+func (ed *Dispatcher) updateBlockLag(peer fab.Peer, peers []fab.Peer) {
+	connectedPeer, ok := peer.(fab.PeerState)
+	if !ok {
+		return
+	}
+
+	blockHeight := connectedPeer.BlockHeight()
+	maxBlockHeight := blockHeight
+	for _, p := range peers {
+		if ps, ok := p.(fab.PeerState); ok && ps.BlockHeight() > maxBlockHeight {
+			maxBlockHeight = ps.BlockHeight()
+		}
+	}
+
+	ed.metrics.EventBlockLag.With("channel", ed.chConfig.ID()).Set(float64(maxBlockHeight - blockHeight))
+}
+
+func (ed *Dispatcher) setConnectionState(connected bool) {
+	var state float64
+	if connected {
+		state = 1
+	}
+	ed.metrics.EventConnectionState.With("channel", ed.chConfig.ID()).Set(state)
+}
+
 func (ed *Dispatcher) disconnect() error {
 	eventch, err := ed.EventCh()
 	if err != nil {
@@ -312,6 +364,22 @@ func (ed *Dispatcher) disconnect() error {
 	return nil
 }
 
+// GreylistPeer greylists the peer with the given URL so that it isn't chosen
+// (for the greylist expiry period) when the client reconnects
+func (ed *Dispatcher) GreylistPeer(url string) {
+	ed.greylist.GreylistURL(url)
+}
+
+func (ed *Dispatcher) filterGreylisted(peers []fab.Peer) []fab.Peer {
+	var filtered []fab.Peer
+	for _, peer := range peers {
+		if ed.greylist.Accept(peer) {
+			filtered = append(filtered, peer)
+		}
+	}
+	return filtered
+}
+
 func (ed *Dispatcher) setConnectedPeer(peer fab.Peer) {
 	ed.lock.Lock()
 	defer ed.lock.Unlock()
@@ -0,0 +1,38 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package consensusclient
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/consensusclient/dispatcher"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+)
+
+// Client connects to multiple peers (across different orgs where possible) and receives channel events, such as
+// block, filtered block, chaincode, and transaction status events. Events for a block are only published once a
+// quorum of the peers has delivered an identical block header (or, for filtered blocks, an identical filtered block).
+type Client struct {
+	*deliverclient.Client
+}
+
+// New returns a new consensus event client. The options of the deliver client (such as deliverclient.WithSeekType
+// and deliverclient.WithBlockNum) are also supported.
+func New(context fabcontext.Client, chConfig fab.ChannelCfg, discoveryService fab.DiscoveryService, opts ...options.Opt) (*Client, error) {
+	dispatcherProvider := func(discoveryService fab.DiscoveryService, connectionProvider api.ConnectionProvider) deliverclient.Dispatcher {
+		return dispatcher.New(context, chConfig, discoveryService, connectionProvider, opts...)
+	}
+
+	client, err := deliverclient.NewWithDispatcher(context, chConfig, discoveryService, dispatcherProvider, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{Client: client}, nil
+}
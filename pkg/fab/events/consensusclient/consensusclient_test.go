@@ -0,0 +1,115 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package consensusclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	clientmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	delivermocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
+	fabmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const channelID = "mychannel"
+
+func TestClient(t *testing.T) {
+	peer1 := newMockPeer("peer1", "peer1.org1.com:7051", "Org1MSP")
+	peer2 := newMockPeer("peer2", "peer2.org2.com:7051", "Org2MSP")
+	peer3 := newMockPeer("peer3", "peer3.org3.com:7051", "Org3MSP")
+
+	ledgers := make(map[string]*servicemocks.MockLedger)
+	conns := make(map[string]*delivermocks.MockConnection)
+	for _, peer := range []fab.Peer{peer1, peer2, peer3} {
+		ledger := servicemocks.NewMockLedger(delivermocks.BlockEventFactory, peer.URL())
+		ledgers[peer.URL()] = ledger
+		conns[peer.URL()] = delivermocks.NewConnection(clientmocks.WithLedger(ledger), clientmocks.WithSourceURL(peer.URL()))
+	}
+
+	eventClient, err := New(
+		fabmocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP")),
+		fabmocks.NewMockChannelCfg(channelID),
+		clientmocks.NewDiscoveryService(peer1, peer2, peer3),
+		client.WithBlockEvents(),
+		withConnectionProvider(func(ctx context.Client, cfg fab.ChannelCfg, peer fab.Peer) (api.Connection, error) {
+			conn, ok := conns[peer.URL()]
+			if !ok {
+				return nil, errors.Errorf("unable to connect to [%s]", peer.URL())
+			}
+			return conn, nil
+		}),
+		WithPeers(3),
+		WithQuorum(3),
+		deliverclient.WithSeekType(seek.FromBlock),
+		deliverclient.WithBlockNum(0),
+	)
+	require.NoError(t, err)
+	defer eventClient.Close()
+
+	require.NoError(t, eventClient.Connect())
+	assert.Equal(t, client.Connected, eventClient.ConnectionState())
+
+	reg, eventch, err := eventClient.RegisterBlockEvent()
+	require.NoError(t, err)
+	defer eventClient.Unregister(reg)
+
+	block := servicemocks.NewBlock(channelID, servicemocks.NewTransaction("txid0", pb.TxValidationCode_VALID, cb.HeaderType_ENDORSER_TRANSACTION))
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+
+	ledgers[peer1.URL()].Store(servicemocks.NewBlockWrapper(block))
+	ledgers[peer2.URL()].Store(servicemocks.NewBlockWrapper(block))
+
+	select {
+	case event := <-eventch:
+		t.Fatalf("unexpected block event for block %d before a quorum of peers delivered the block", event.Block.Header.Number)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	ledgers[peer3.URL()].Store(servicemocks.NewBlockWrapper(block))
+
+	select {
+	case event := <-eventch:
+		assert.Equal(t, uint64(0), event.Block.Header.Number)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for block event")
+	}
+}
+
+// withConnectionProvider is used only for testing
+func withConnectionProvider(connProvider api.ConnectionProvider) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(connectionProviderSetter); ok {
+			setter.SetConnectionProvider(connProvider)
+		}
+	}
+}
+
+// connectionProviderSetter is only used in unit tests
+type connectionProviderSetter interface {
+	SetConnectionProvider(value api.ConnectionProvider)
+}
+
+func newMockPeer(name, url, mspID string) *fabmocks.MockPeer {
+	peer := fabmocks.NewMockPeer(name, url)
+	peer.SetMSPID(mspID)
+	return peer
+}
@@ -0,0 +1,566 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"bytes"
+	"math"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/greylist"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
+	clientdisp "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/connection"
+	deliverdisp "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/dispatcher"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	esdispatcher "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/fab")
+
+type dsConnection interface {
+	api.Connection
+	Send(seekInfo *ab.SeekInfo) error
+}
+
+// source is a connected peer from which blocks are received
+type source struct {
+	peer fab.Peer
+	conn dsConnection
+}
+
+// candidate is a block which has been delivered by one or more peers
+type candidate struct {
+	block   interface{}
+	sources map[string]struct{}
+}
+
+// Dispatcher maintains deliver connections to multiple peers (across different orgs where possible) and
+// publishes events for a block only once a quorum of the peers has delivered a byte-identical block header
+// (or, for filtered blocks, an identical filtered block). Blocks are de-duplicated by block number.
+// All events are processed in a single Go routine so no synchronization is required.
+type Dispatcher struct {
+	*clientdisp.Dispatcher
+	params
+	context            fabcontext.Client
+	discoveryService   fab.DiscoveryService
+	connectionProvider api.ConnectionProvider
+	greylist           *greylist.Filter
+	blockHasher        *verifier.BlockHasher
+	sources            map[string]*source
+	candidates         map[uint64]map[string]*candidate
+	seekInfo           *ab.SeekInfo
+}
+
+// New returns a new consensus dispatcher
+func New(context fabcontext.Client, chConfig fab.ChannelCfg, discoveryService fab.DiscoveryService, connectionProvider api.ConnectionProvider, opts ...options.Opt) *Dispatcher {
+	params := defaultParams(context, chConfig.ID())
+	options.Apply(params, opts)
+	params.normalize()
+
+	// The connected peers are managed by this dispatcher so the peer monitor of the client dispatcher is disabled
+	clientOpts := append([]options.Opt{}, opts...)
+	clientOpts = append(clientOpts, clientdisp.WithPeerMonitorPeriod(0))
+
+	return &Dispatcher{
+		Dispatcher:         clientdisp.New(context, chConfig, discoveryService, connectionProvider, clientOpts...),
+		params:             *params,
+		context:            context,
+		discoveryService:   discoveryService,
+		connectionProvider: connectionProvider,
+		greylist:           greylist.New(context.EndpointConfig().Timeout(fab.DiscoveryGreylistExpiry)),
+		blockHasher:        verifier.NewBlockHasher(context.CryptoSuite(), chConfig.HashingAlgorithm()),
+		sources:            make(map[string]*source),
+		candidates:         make(map[uint64]map[string]*candidate),
+	}
+}
+
+// Start starts the dispatcher
+func (ed *Dispatcher) Start() error {
+	ed.registerHandlers()
+	if err := ed.Dispatcher.Start(); err != nil {
+		return errors.WithMessage(err, "error starting consensus event dispatcher")
+	}
+	return nil
+}
+
+// Quorum returns the number of peers which must deliver an identical block before the block is published
+func (ed *Dispatcher) Quorum() int {
+	return ed.quorum
+}
+
+// HandleConnectEvent connects to the configured number of peers. An error is returned
+// if fewer than a quorum of peers could be connected.
+func (ed *Dispatcher) HandleConnectEvent(e esdispatcher.Event) {
+	evt := e.(*clientdisp.ConnectEvent)
+
+	if len(ed.sources) > 0 {
+		// Already connected. No error.
+		evt.ErrCh <- nil
+		return
+	}
+
+	peers, err := ed.candidatePeers()
+	if err != nil {
+		evt.ErrCh <- err
+		return
+	}
+
+	ed.connectPeers(peers)
+
+	if len(ed.sources) < ed.quorum {
+		ed.closeSources()
+		evt.ErrCh <- errors.Errorf("unable to connect to a quorum of %d peers on channel [%s]", ed.quorum, ed.ChannelConfig().ID())
+		return
+	}
+
+	logger.Debugf("Connected to %d peers on channel [%s]", len(ed.sources), ed.ChannelConfig().ID())
+
+	evt.ErrCh <- nil
+}
+
+// HandleDisconnectEvent disconnects from all peers
+func (ed *Dispatcher) HandleDisconnectEvent(e esdispatcher.Event) {
+	evt := e.(*clientdisp.DisconnectEvent)
+
+	if len(ed.sources) == 0 {
+		evt.Errch <- errors.New("connection already closed")
+		return
+	}
+
+	logger.Debug("Closing connections due to disconnect event...")
+
+	ed.closeSources()
+
+	evt.Errch <- nil
+}
+
+// HandleDisconnectedEvent closes all connections and notifies the client that it has been disconnected
+func (ed *Dispatcher) HandleDisconnectedEvent(e esdispatcher.Event) {
+	ed.closeSources()
+	ed.Dispatcher.HandleDisconnectedEvent(e)
+}
+
+// HandleStopEvent closes all connections and stops the dispatcher
+func (ed *Dispatcher) HandleStopEvent(e esdispatcher.Event) {
+	ed.closeSources()
+	ed.Dispatcher.HandleStopEvent(e)
+}
+
+func (ed *Dispatcher) handleSeekEvent(e esdispatcher.Event) {
+	evt := e.(*deliverdisp.SeekEvent)
+
+	if len(ed.sources) == 0 {
+		evt.ErrCh <- errors.Errorf("unable to send seek info for channel [%s] since no connection was established", ed.ChannelConfig().ID())
+		return
+	}
+
+	ed.seekInfo = evt.SeekInfo
+
+	for url, s := range ed.sources {
+		if err := s.conn.Send(evt.SeekInfo); err != nil {
+			logger.Warnf("Error sending seek info to [%s]: %s", url, err)
+			ed.greylist.GreylistURL(url)
+			ed.removeSource(url)
+		}
+	}
+
+	if len(ed.sources) < ed.quorum {
+		evt.ErrCh <- errors.Errorf("unable to send seek info to a quorum of %d peers on channel [%s]", ed.quorum, ed.ChannelConfig().ID())
+		return
+	}
+
+	evt.ErrCh <- nil
+}
+
+func (ed *Dispatcher) handleEvent(e esdispatcher.Event) {
+	delevent := e.(*connection.Event)
+
+	if _, ok := ed.sources[delevent.SourceURL]; !ok {
+		logger.Debugf("Ignoring event from [%s] since the peer is no longer connected", delevent.SourceURL)
+		return
+	}
+
+	evt := delevent.Event.(*pb.DeliverResponse)
+	switch response := evt.Type.(type) {
+	case *pb.DeliverResponse_Status:
+		ed.handleDeliverResponseStatus(response, delevent.SourceURL)
+	case *pb.DeliverResponse_Block:
+		ed.handleBlock(response.Block, delevent.SourceURL)
+	case *pb.DeliverResponse_FilteredBlock:
+		ed.handleFilteredBlock(response.FilteredBlock, delevent.SourceURL)
+	default:
+		logger.Errorf("handler not found for deliver response type %T", response)
+	}
+}
+
+func (ed *Dispatcher) handleSourceDisconnectedEvent(e esdispatcher.Event) {
+	evt := e.(*sourceDisconnectedEvent)
+	ed.sourceFailed(evt.sourceURL, evt.event)
+}
+
+func (ed *Dispatcher) handleDeliverResponseStatus(evt *pb.DeliverResponse_Status, sourceURL string) {
+	logger.Debugf("Got deliver response status event from [%s]: %#v", sourceURL, evt)
+
+	if evt.Status == cb.Status_SUCCESS {
+		return
+	}
+
+	err := errors.Errorf("got error status from deliver server [%s]: %s", sourceURL, evt.Status)
+	if evt.Status == cb.Status_FORBIDDEN {
+		ed.sourceFailed(sourceURL, clientdisp.NewFatalDisconnectedEvent(err))
+	} else {
+		ed.sourceFailed(sourceURL, clientdisp.NewDisconnectedEvent(err))
+	}
+}
+
+func (ed *Dispatcher) handleBlock(block *cb.Block, sourceURL string) {
+	if block.Header == nil || block.Data == nil {
+		ed.sourceFailed(sourceURL, clientdisp.NewDisconnectedEvent(errors.Errorf("received incomplete block from [%s]", sourceURL)))
+		return
+	}
+
+	// The header is only compared so make sure that it matches the block data
+	dataHash, err := ed.blockHasher.DataHash(block.Data)
+	if err != nil {
+		ed.sourceFailed(sourceURL, clientdisp.NewDisconnectedEvent(errors.WithMessagef(err, "unable to compute data hash of block [%d] from [%s]", block.Header.Number, sourceURL)))
+		return
+	}
+
+	if !bytes.Equal(dataHash, block.Header.DataHash) {
+		ed.sourceFailed(sourceURL, clientdisp.NewDisconnectedEvent(errors.Errorf("data hash of block [%d] from [%s] does not match the block data", block.Header.Number, sourceURL)))
+		return
+	}
+
+	ed.vote(block.Header.Number, blockKey(block), block, sourceURL)
+}
+
+// blockKey returns the key under which votes for the given block are counted. The header does not cover the
+// block metadata so the transactions filter (which contains the validation codes set by the peer) is appended.
+func blockKey(block *cb.Block) string {
+	key := protoutil.BlockHeaderBytes(block.Header)
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		key = append(key, block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER]...)
+	}
+	return string(key)
+}
+
+func (ed *Dispatcher) handleFilteredBlock(fblock *pb.FilteredBlock, sourceURL string) {
+	key, err := proto.Marshal(fblock)
+	if err != nil {
+		logger.Errorf("Error marshalling filtered block [%d] from [%s]: %s", fblock.Number, sourceURL, err)
+		return
+	}
+
+	ed.vote(fblock.Number, string(key), fblock, sourceURL)
+}
+
+// vote records that the given peer delivered the given block. The block is published once a quorum of peers
+// has delivered an identical block. Any peers that delivered a different block with the same number are disconnected.
+// Blocks are published in order: if a quorum is reached for a block before the preceding block was published
+// then all connections are closed so that the client reconnects and seeks from the missing block.
+func (ed *Dispatcher) vote(blockNum uint64, key string, block interface{}, sourceURL string) {
+	if lastBlockNum := ed.LastBlockNum(); lastBlockNum != math.MaxUint64 && blockNum <= lastBlockNum {
+		logger.Debugf("Ignoring block [%d] from [%s] since block [%d] was already delivered", blockNum, sourceURL, lastBlockNum)
+		return
+	}
+
+	candidates, ok := ed.candidates[blockNum]
+	if !ok {
+		candidates = make(map[string]*candidate)
+		ed.candidates[blockNum] = candidates
+	}
+
+	for _, c := range candidates {
+		if _, ok := c.sources[sourceURL]; ok {
+			logger.Warnf("Ignoring duplicate block [%d] from [%s]", blockNum, sourceURL)
+			return
+		}
+	}
+
+	c, ok := candidates[key]
+	if !ok {
+		c = &candidate{block: block, sources: make(map[string]struct{})}
+		candidates[key] = c
+	}
+	c.sources[sourceURL] = struct{}{}
+
+	if len(c.sources) < ed.quorum {
+		logger.Debugf("Block [%d] has been delivered by %d of the required %d peers", blockNum, len(c.sources), ed.quorum)
+		return
+	}
+
+	logger.Debugf("Block [%d] has been delivered by a quorum of %d peers", blockNum, ed.quorum)
+
+	if lastBlockNum := ed.LastBlockNum(); lastBlockNum != math.MaxUint64 && blockNum > lastBlockNum+1 {
+		// The blocks in between never reached a quorum (e.g. some of their votes came from peers which have since been
+		// replaced). Rather than skipping them, disconnect so that the client reconnects and seeks from the first missing block.
+		ed.HandleDisconnectedEvent(clientdisp.NewDisconnectedEvent(errors.Errorf("block [%d] was delivered by a quorum of peers before block [%d]", blockNum, lastBlockNum+1)))
+		return
+	}
+
+	var dissenters []string
+	for k, other := range candidates {
+		if k == key {
+			continue
+		}
+		for url := range other.sources {
+			dissenters = append(dissenters, url)
+		}
+	}
+
+	ed.prune(blockNum)
+
+	switch b := c.block.(type) {
+	case *cb.Block:
+		ed.HandleBlock(b, sourceURL)
+	case *pb.FilteredBlock:
+		ed.HandleFilteredBlock(b, sourceURL)
+	}
+
+	for _, url := range dissenters {
+		ed.sourceFailed(url, clientdisp.NewDisconnectedEvent(errors.Errorf("peer [%s] delivered a block [%d] which differs from the block delivered by a quorum of peers", url, blockNum)))
+	}
+}
+
+// prune removes the candidates for all blocks up to and including the given block number
+func (ed *Dispatcher) prune(blockNum uint64) {
+	for n := range ed.candidates {
+		if n <= blockNum {
+			delete(ed.candidates, n)
+		}
+	}
+}
+
+// sourceFailed disconnects and greylists the given peer and attempts to connect to another peer in its place.
+// If the remaining peers can no longer form a quorum then all connections are closed and the client is
+// notified that it has been disconnected (so that it can reconnect).
+func (ed *Dispatcher) sourceFailed(sourceURL string, evt *clientdisp.DisconnectedEvent) {
+	if _, ok := ed.sources[sourceURL]; !ok {
+		logger.Debugf("Peer [%s] is already disconnected: %s", sourceURL, evt.Err)
+		return
+	}
+
+	logger.Warnf("Disconnecting from peer [%s]: %s", sourceURL, evt.Err)
+
+	ed.greylist.GreylistURL(sourceURL)
+	ed.removeSource(sourceURL)
+	ed.replaceSources()
+
+	if len(ed.sources) >= ed.quorum {
+		return
+	}
+
+	logger.Warnf("Only %d peers are connected but a quorum of %d is required. Disconnecting...", len(ed.sources), ed.quorum)
+
+	ed.HandleDisconnectedEvent(evt)
+}
+
+// replaceSources connects to additional peers (up to the configured number of peers) and sends them
+// a seek request starting from the block after the last block that was published
+func (ed *Dispatcher) replaceSources() {
+	if ed.seekInfo == nil {
+		// Still connecting. The seek request is sent to all peers once connected.
+		return
+	}
+
+	peers, err := ed.candidatePeers()
+	if err != nil {
+		logger.Warnf("Error getting peers: %s", err)
+		return
+	}
+
+	seekInfo := ed.seekInfo
+	if lastBlockNum := ed.LastBlockNum(); lastBlockNum != math.MaxUint64 {
+		seekInfo = seek.InfoFrom(lastBlockNum + 1)
+	}
+
+	for _, url := range ed.connectPeers(peers) {
+		if err := ed.sources[url].conn.Send(seekInfo); err != nil {
+			logger.Warnf("Error sending seek info to [%s]: %s", url, err)
+			ed.greylist.GreylistURL(url)
+			ed.removeSource(url)
+		}
+	}
+}
+
+// candidatePeers returns the peers which aren't connected or greylisted. The peers are
+// ordered such that peers from orgs with the fewest connected peers come first and
+// consecutive peers are from different orgs. Within an org, peers with a higher block height come first.
+func (ed *Dispatcher) candidatePeers() ([]fab.Peer, error) {
+	peers, err := ed.discoveryService.GetPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	connectedByOrg := make(map[string]int)
+	for _, s := range ed.sources {
+		connectedByOrg[s.peer.MSPID()]++
+	}
+
+	var mspIDs []string
+	peersByOrg := make(map[string][]fab.Peer)
+	for _, peer := range peers {
+		if _, ok := ed.sources[peer.URL()]; ok || !ed.greylist.Accept(peer) {
+			continue
+		}
+		if _, ok := peersByOrg[peer.MSPID()]; !ok {
+			mspIDs = append(mspIDs, peer.MSPID())
+		}
+		peersByOrg[peer.MSPID()] = append(peersByOrg[peer.MSPID()], peer)
+	}
+
+	sort.SliceStable(mspIDs, func(i, j int) bool {
+		return connectedByOrg[mspIDs[i]] < connectedByOrg[mspIDs[j]]
+	})
+
+	maxPeersInOrg := 0
+	for _, orgPeers := range peersByOrg {
+		sort.SliceStable(orgPeers, func(i, j int) bool {
+			return blockHeight(orgPeers[i]) > blockHeight(orgPeers[j])
+		})
+		if len(orgPeers) > maxPeersInOrg {
+			maxPeersInOrg = len(orgPeers)
+		}
+	}
+
+	var candidates []fab.Peer
+	for i := 0; i < maxPeersInOrg; i++ {
+		for _, mspID := range mspIDs {
+			if orgPeers := peersByOrg[mspID]; i < len(orgPeers) {
+				candidates = append(candidates, orgPeers[i])
+			}
+		}
+	}
+
+	return candidates, nil
+}
+
+// connectPeers connects to the given peers (in order) until the configured number of peers
+// are connected. The URLs of the newly connected peers are returned.
+func (ed *Dispatcher) connectPeers(peers []fab.Peer) []string {
+	eventch, err := ed.EventCh()
+	if err != nil {
+		logger.Warnf("Unable to get event dispatcher channel: %s", err)
+		return nil
+	}
+
+	var connected []string
+	for _, peer := range peers {
+		if len(ed.sources) >= ed.peers {
+			break
+		}
+
+		conn, err := ed.connectionProvider(ed.context, ed.ChannelConfig(), peer)
+		if err != nil {
+			logger.Warnf("Error creating connection to [%s]: %s", peer.URL(), err)
+			ed.greylist.GreylistURL(peer.URL())
+			continue
+		}
+
+		dsconn, ok := conn.(dsConnection)
+		if !ok {
+			logger.Warnf("Connection to [%s] is not a deliver connection: %T", peer.URL(), conn)
+			conn.Close()
+			continue
+		}
+
+		logger.Debugf("Connected to peer [%s] on channel [%s]", peer.URL(), ed.ChannelConfig().ID())
+
+		ed.sources[peer.URL()] = &source{peer: peer, conn: dsconn}
+		connected = append(connected, peer.URL())
+
+		go receive(peer.URL(), dsconn, eventch)
+	}
+
+	return connected
+}
+
+// removeSource closes the connection to the given peer and discards the votes that it cast for pending blocks
+func (ed *Dispatcher) removeSource(sourceURL string) {
+	s, ok := ed.sources[sourceURL]
+	if !ok {
+		return
+	}
+
+	logger.Debugf("Closing connection to [%s]", sourceURL)
+
+	s.conn.Close()
+	delete(ed.sources, sourceURL)
+
+	// The votes of a removed peer must no longer count towards a quorum
+	for blockNum, candidates := range ed.candidates {
+		for key, c := range candidates {
+			delete(c.sources, sourceURL)
+			if len(c.sources) == 0 {
+				delete(candidates, key)
+			}
+		}
+		if len(candidates) == 0 {
+			delete(ed.candidates, blockNum)
+		}
+	}
+}
+
+func (ed *Dispatcher) closeSources() {
+	for url := range ed.sources {
+		ed.removeSource(url)
+	}
+	ed.candidates = make(map[uint64]map[string]*candidate)
+}
+
+func (ed *Dispatcher) registerHandlers() {
+	// Override the handlers of the client dispatcher
+	ed.RegisterHandler(&esdispatcher.StopEvent{}, ed.HandleStopEvent)
+	ed.RegisterHandler(&clientdisp.ConnectEvent{}, ed.HandleConnectEvent)
+	ed.RegisterHandler(&clientdisp.DisconnectEvent{}, ed.HandleDisconnectEvent)
+	ed.RegisterHandler(&clientdisp.DisconnectedEvent{}, ed.HandleDisconnectedEvent)
+
+	// Register new handlers
+	ed.RegisterHandler(&deliverdisp.SeekEvent{}, ed.handleSeekEvent)
+	ed.RegisterHandler(&connection.Event{}, ed.handleEvent)
+	ed.RegisterHandler(&sourceDisconnectedEvent{}, ed.handleSourceDisconnectedEvent)
+}
+
+// receive forwards the events received from the given connection to the dispatcher. A disconnected
+// event from the connection is converted to a sourceDisconnectedEvent so that only the one peer is disconnected.
+func receive(sourceURL string, conn api.Connection, eventch chan<- interface{}) {
+	sourcech := make(chan interface{})
+
+	go func() {
+		conn.Receive(sourcech)
+		close(sourcech)
+	}()
+
+	for e := range sourcech {
+		if evt, ok := e.(*clientdisp.DisconnectedEvent); ok {
+			eventch <- newSourceDisconnectedEvent(sourceURL, evt)
+			continue
+		}
+		eventch <- e
+	}
+
+	logger.Debugf("Exiting event listener for [%s]", sourceURL)
+}
+
+func blockHeight(peer fab.Peer) uint64 {
+	if ps, ok := peer.(fab.PeerState); ok {
+		return ps.BlockHeight()
+	}
+	return 0
+}
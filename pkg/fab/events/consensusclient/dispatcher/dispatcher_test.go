@@ -0,0 +1,423 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
+	clientdisp "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
+	clientmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/mocks"
+	deliverdisp "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/dispatcher"
+	delivermocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/blockfilter"
+	esdispatcher "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
+	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
+	fabmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const channelID = "testchannel"
+
+var (
+	peer1 = newMockPeer("peer1", "grpcs://peer1.org1.com:7051", "Org1MSP")
+	peer2 = newMockPeer("peer2", "grpcs://peer2.org1.com:7051", "Org1MSP")
+	peer3 = newMockPeer("peer3", "grpcs://peer3.org2.com:7051", "Org2MSP")
+	peer4 = newMockPeer("peer4", "grpcs://peer4.org3.com:7051", "Org3MSP")
+)
+
+func TestConsensus(t *testing.T) {
+	n := newTestNetwork(delivermocks.BlockEventFactory, peer1, peer3, peer4)
+
+	dispatcher := n.newDispatcher()
+	require.NoError(t, dispatcher.Start())
+	assert.Equal(t, 2, dispatcher.Quorum())
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	require.NoError(t, err)
+
+	conneventch := registerConnectionEvents(t, dispatcherEventch)
+	connect(t, dispatcherEventch)
+	sendSeek(t, dispatcherEventch)
+
+	eventch := make(chan *fab.BlockEvent, 10)
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+	dispatcherEventch <- esdispatcher.NewRegisterBlockEvent(blockfilter.AcceptAny, eventch, regch, errch)
+	select {
+	case <-regch:
+	case err := <-errch:
+		t.Fatalf("error registering for block events: %s", err)
+	}
+
+	block0 := newBlock(0, "txid0")
+
+	n.store(block0, peer1)
+	assertNoBlockEvent(t, eventch)
+
+	n.store(block0, peer3)
+	assertBlockEvent(t, eventch, 0)
+
+	// A duplicate block should not be published
+	n.store(block0, peer4)
+	assertNoBlockEvent(t, eventch)
+
+	// The peer that delivers a different block should be disconnected
+	n.store(newBlock(1, "txid1-other"), peer4)
+	assertNoBlockEvent(t, eventch)
+
+	block1 := newBlock(1, "txid1")
+	n.store(block1, peer1)
+	n.store(block1, peer3)
+	assertBlockEvent(t, eventch, 1)
+
+	// The dissenting peer is disconnected after the block is published
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, n.conns[peer4.URL()].Closed())
+	assert.False(t, n.conns[peer1.URL()].Closed())
+	assert.False(t, n.conns[peer3.URL()].Closed())
+
+	t.Run("Quorum lost", func(t *testing.T) {
+		dispatcherEventch <- newSourceDisconnectedEvent(peer1.URL(), clientdisp.NewDisconnectedEvent(errors.New("connection lost")))
+
+		select {
+		case event := <-conneventch:
+			assert.False(t, event.Connected)
+			assert.EqualError(t, event.Err, "connection lost")
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for disconnected event")
+		}
+
+		assert.True(t, n.conns[peer1.URL()].Closed())
+		assert.True(t, n.conns[peer3.URL()].Closed())
+	})
+}
+
+func TestMissedBlock(t *testing.T) {
+	n := newTestNetwork(delivermocks.BlockEventFactory, peer1, peer3, peer4)
+
+	dispatcher := n.newDispatcher()
+	require.NoError(t, dispatcher.Start())
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	require.NoError(t, err)
+
+	conneventch := registerConnectionEvents(t, dispatcherEventch)
+	connect(t, dispatcherEventch)
+	sendSeek(t, dispatcherEventch)
+
+	eventch := make(chan *fab.BlockEvent, 10)
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+	dispatcherEventch <- esdispatcher.NewRegisterBlockEvent(blockfilter.AcceptAny, eventch, regch, errch)
+	select {
+	case <-regch:
+	case err := <-errch:
+		t.Fatalf("error registering for block events: %s", err)
+	}
+
+	block0 := newBlock(0, "txid0")
+	n.store(block0, peer1)
+	n.store(block0, peer3)
+	assertBlockEvent(t, eventch, 0)
+
+	// Block 1 is only delivered by one peer but block 2 reaches a quorum
+	n.store(newBlock(1, "txid1"), peer1)
+	block2 := newBlock(2, "txid2")
+	n.store(block2, peer3)
+	n.store(block2, peer4)
+	assertNoBlockEvent(t, eventch)
+
+	// The client is disconnected so that it reconnects and seeks from block 1
+	select {
+	case event := <-conneventch:
+		assert.False(t, event.Connected)
+		assert.EqualError(t, event.Err, "block [2] was delivered by a quorum of peers before block [1]")
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for disconnected event")
+	}
+	assert.Equal(t, uint64(0), dispatcher.LastBlockNum())
+}
+
+func TestRemovedSourceVotes(t *testing.T) {
+	n := newTestNetwork(delivermocks.BlockEventFactory, peer1, peer3, peer4)
+
+	dispatcher := n.newDispatcher()
+	require.NoError(t, dispatcher.Start())
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	require.NoError(t, err)
+
+	connect(t, dispatcherEventch)
+	sendSeek(t, dispatcherEventch)
+
+	eventch := make(chan *fab.BlockEvent, 10)
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+	dispatcherEventch <- esdispatcher.NewRegisterBlockEvent(blockfilter.AcceptAny, eventch, regch, errch)
+	select {
+	case <-regch:
+	case err := <-errch:
+		t.Fatalf("error registering for block events: %s", err)
+	}
+
+	block0 := newBlock(0, "txid0")
+	n.store(block0, peer1)
+	n.store(block0, peer3)
+	assertBlockEvent(t, eventch, 0)
+
+	// Peer4 votes for block 1 and is then removed
+	block1 := newBlock(1, "txid1")
+	n.store(block1, peer4)
+	assertNoBlockEvent(t, eventch)
+
+	dispatcherEventch <- newSourceDisconnectedEvent(peer4.URL(), clientdisp.NewDisconnectedEvent(errors.New("connection lost")))
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, n.conns[peer4.URL()].Closed())
+
+	// The vote from the removed peer must not count towards the quorum
+	n.store(block1, peer1)
+	assertNoBlockEvent(t, eventch)
+
+	n.store(block1, peer3)
+	assertBlockEvent(t, eventch, 1)
+}
+
+func TestTransactionsFilterConsensus(t *testing.T) {
+	n := newTestNetwork(delivermocks.BlockEventFactory, peer1, peer3, peer4)
+
+	dispatcher := n.newDispatcher()
+	require.NoError(t, dispatcher.Start())
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	require.NoError(t, err)
+
+	connect(t, dispatcherEventch)
+	sendSeek(t, dispatcherEventch)
+
+	eventch := make(chan *fab.BlockEvent, 10)
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+	dispatcherEventch <- esdispatcher.NewRegisterBlockEvent(blockfilter.AcceptAny, eventch, regch, errch)
+	select {
+	case <-regch:
+	case err := <-errch:
+		t.Fatalf("error registering for block events: %s", err)
+	}
+
+	// The blocks have the same header but different validation codes
+	block0 := newBlock(0, "txid0")
+	otherBlock0 := newBlock(0, "txid0")
+	otherBlock0.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{uint8(pb.TxValidationCode_MVCC_READ_CONFLICT)}
+	require.Equal(t, block0.Header, otherBlock0.Header)
+
+	n.store(block0, peer1)
+	n.store(otherBlock0, peer3)
+	assertNoBlockEvent(t, eventch)
+
+	n.store(block0, peer4)
+	select {
+	case event := <-eventch:
+		assert.Equal(t, []byte{uint8(pb.TxValidationCode_VALID)}, event.Block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for block event")
+	}
+}
+
+func TestFilteredBlockConsensus(t *testing.T) {
+	n := newTestNetwork(delivermocks.FilteredBlockEventFactory, peer1, peer3)
+
+	dispatcher := n.newDispatcher()
+	require.NoError(t, dispatcher.Start())
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	require.NoError(t, err)
+
+	connect(t, dispatcherEventch)
+	sendSeek(t, dispatcherEventch)
+
+	eventch := make(chan *fab.FilteredBlockEvent, 10)
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+	dispatcherEventch <- esdispatcher.NewRegisterFilteredBlockEvent(eventch, regch, errch)
+	select {
+	case <-regch:
+	case err := <-errch:
+		t.Fatalf("error registering for filtered block events: %s", err)
+	}
+
+	fblock := servicemocks.NewFilteredBlock(channelID, servicemocks.NewFilteredTx("txid0", pb.TxValidationCode_VALID))
+
+	n.ledgers[peer1.URL()].Store(servicemocks.NewFilteredBlockWrapper(fblock))
+	select {
+	case event := <-eventch:
+		t.Fatalf("unexpected filtered block event for block %d", event.FilteredBlock.Number)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	n.ledgers[peer3.URL()].Store(servicemocks.NewFilteredBlockWrapper(fblock))
+	select {
+	case event := <-eventch:
+		assert.Equal(t, "txid0", event.FilteredBlock.FilteredTransactions[0].Txid)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for filtered block event")
+	}
+}
+
+func TestConnect(t *testing.T) {
+	t.Run("Across orgs", func(t *testing.T) {
+		n := newTestNetwork(delivermocks.BlockEventFactory, peer1, peer2, peer3, peer4)
+
+		dispatcher := n.newDispatcher()
+		require.NoError(t, dispatcher.Start())
+
+		dispatcherEventch, err := dispatcher.EventCh()
+		require.NoError(t, err)
+
+		connect(t, dispatcherEventch)
+
+		// One peer from each org should have been chosen
+		assert.False(t, n.conns[peer1.URL()].Closed())
+		assert.False(t, n.conns[peer3.URL()].Closed())
+		assert.False(t, n.conns[peer4.URL()].Closed())
+		assert.Len(t, dispatcher.sources, 3)
+		assert.NotContains(t, dispatcher.sources, peer2.URL())
+	})
+
+	t.Run("No quorum", func(t *testing.T) {
+		n := newTestNetwork(delivermocks.BlockEventFactory, peer1)
+		n.discovery = clientmocks.NewDiscoveryService(peer1, peer3, peer4)
+
+		dispatcher := n.newDispatcher()
+		require.NoError(t, dispatcher.Start())
+
+		dispatcherEventch, err := dispatcher.EventCh()
+		require.NoError(t, err)
+
+		errch := make(chan error)
+		dispatcherEventch <- clientdisp.NewConnectEvent(errch)
+		err = <-errch
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to connect to a quorum of 2 peers")
+		assert.True(t, n.conns[peer1.URL()].Closed())
+	})
+}
+
+type testNetwork struct {
+	discovery fab.DiscoveryService
+	ledgers   map[string]*servicemocks.MockLedger
+	conns     map[string]*delivermocks.MockConnection
+}
+
+func newTestNetwork(eventFactory servicemocks.EventFactory, peers ...fab.Peer) *testNetwork {
+	n := &testNetwork{
+		discovery: clientmocks.NewDiscoveryService(peers...),
+		ledgers:   make(map[string]*servicemocks.MockLedger),
+		conns:     make(map[string]*delivermocks.MockConnection),
+	}
+
+	for _, peer := range peers {
+		ledger := servicemocks.NewMockLedger(eventFactory, peer.URL())
+		n.ledgers[peer.URL()] = ledger
+		n.conns[peer.URL()] = delivermocks.NewConnection(clientmocks.WithLedger(ledger), clientmocks.WithSourceURL(peer.URL()))
+	}
+
+	return n
+}
+
+func (n *testNetwork) newDispatcher() *Dispatcher {
+	return New(
+		fabmocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP")),
+		fabmocks.NewMockChannelCfg(channelID),
+		n.discovery,
+		n.connectionProvider(),
+	)
+}
+
+func (n *testNetwork) connectionProvider() api.ConnectionProvider {
+	return func(ctx context.Client, cfg fab.ChannelCfg, peer fab.Peer) (api.Connection, error) {
+		conn, ok := n.conns[peer.URL()]
+		if !ok {
+			return nil, errors.Errorf("unable to connect to [%s]", peer.URL())
+		}
+		return conn, nil
+	}
+}
+
+func (n *testNetwork) store(block *cb.Block, peer fab.Peer) {
+	n.ledgers[peer.URL()].Store(servicemocks.NewBlockWrapper(block))
+}
+
+func registerConnectionEvents(t *testing.T, dispatcherEventch chan<- interface{}) chan *clientdisp.ConnectionEvent {
+	errch := make(chan error)
+	regch := make(chan fab.Registration)
+	conneventch := make(chan *clientdisp.ConnectionEvent, 5)
+	dispatcherEventch <- clientdisp.NewRegisterConnectionEvent(conneventch, regch, errch)
+	select {
+	case <-regch:
+	case err := <-errch:
+		t.Fatalf("error registering for connection events: %s", err)
+	}
+	return conneventch
+}
+
+func connect(t *testing.T, dispatcherEventch chan<- interface{}) {
+	errch := make(chan error)
+	dispatcherEventch <- clientdisp.NewConnectEvent(errch)
+	require.NoError(t, <-errch)
+}
+
+func sendSeek(t *testing.T, dispatcherEventch chan<- interface{}) {
+	errch := make(chan error)
+	dispatcherEventch <- deliverdisp.NewSeekEvent(seek.InfoOldest(), errch)
+	select {
+	case err := <-errch:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for seek response")
+	}
+}
+
+func assertBlockEvent(t *testing.T, eventch chan *fab.BlockEvent, expectedBlockNum uint64) {
+	select {
+	case event := <-eventch:
+		assert.Equal(t, expectedBlockNum, event.Block.Header.Number)
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for block event for block %d", expectedBlockNum)
+	}
+}
+
+func assertNoBlockEvent(t *testing.T, eventch chan *fab.BlockEvent) {
+	select {
+	case event := <-eventch:
+		t.Fatalf("unexpected block event for block %d", event.Block.Header.Number)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func newBlock(blockNum uint64, txID string) *cb.Block {
+	block := servicemocks.NewBlock(channelID, servicemocks.NewTransaction(txID, pb.TxValidationCode_VALID, cb.HeaderType_ENDORSER_TRANSACTION))
+	block.Header.Number = blockNum
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+	return block
+}
+
+func newMockPeer(name, url, mspID string) *fabmocks.MockPeer {
+	peer := fabmocks.NewMockPeer(name, url)
+	peer.SetMSPID(mspID)
+	return peer
+}
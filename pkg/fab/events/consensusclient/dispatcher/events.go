@@ -0,0 +1,21 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	clientdisp "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
+)
+
+// sourceDisconnectedEvent indicates that the connection to one of the peers has been lost
+type sourceDisconnectedEvent struct {
+	sourceURL string
+	event     *clientdisp.DisconnectedEvent
+}
+
+func newSourceDisconnectedEvent(sourceURL string, event *clientdisp.DisconnectedEvent) *sourceDisconnectedEvent {
+	return &sourceDisconnectedEvent{sourceURL: sourceURL, event: event}
+}
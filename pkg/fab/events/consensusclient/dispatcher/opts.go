@@ -0,0 +1,56 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+)
+
+const (
+	defaultPeers = 3
+)
+
+type params struct {
+	peers  int
+	quorum int
+}
+
+func defaultParams(context context.Client, channelID string) *params {
+	policy := context.EndpointConfig().ChannelConfig(channelID).Policies.EventService
+
+	return &params{
+		peers:  policy.ConsensusPeers,
+		quorum: policy.ConsensusQuorum,
+	}
+}
+
+func (p *params) SetConsensusPeers(value int) {
+	logger.Debugf("ConsensusPeers: %d", value)
+	p.peers = value
+}
+
+func (p *params) SetConsensusQuorum(value int) {
+	logger.Debugf("ConsensusQuorum: %d", value)
+	p.quorum = value
+}
+
+// normalize applies defaults to unset values. The quorum defaults to a majority of the peers.
+func (p *params) normalize() {
+	if p.peers <= 0 {
+		logger.Debugf("Invalid number of consensus peers: %d. Using default: %d.", p.peers, defaultPeers)
+		p.peers = defaultPeers
+	}
+
+	if p.quorum <= 0 {
+		p.quorum = p.peers/2 + 1
+	}
+
+	if p.quorum > p.peers {
+		logger.Warnf("Consensus quorum [%d] is greater than the number of consensus peers [%d]. Using quorum: %d.", p.quorum, p.peers, p.peers)
+		p.quorum = p.peers
+	}
+}
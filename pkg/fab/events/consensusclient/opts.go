@@ -0,0 +1,39 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package consensusclient
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+)
+
+// WithPeers specifies the number of peers to which the client connects.
+// If not specified then the value is taken from the channel's event service policy.
+func WithPeers(value int) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(consensusPeersSetter); ok {
+			setter.SetConsensusPeers(value)
+		}
+	}
+}
+
+// WithQuorum specifies the number of peers which must deliver an identical block before events for the block are published.
+// If not specified then the value is taken from the channel's event service policy (which defaults to a majority of the peers).
+func WithQuorum(value int) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(consensusQuorumSetter); ok {
+			setter.SetConsensusQuorum(value)
+		}
+	}
+}
+
+type consensusPeersSetter interface {
+	SetConsensusPeers(value int)
+}
+
+type consensusQuorumSetter interface {
+	SetConsensusQuorum(value int)
+}
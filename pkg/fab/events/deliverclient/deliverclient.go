@@ -20,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/dispatcher"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/endpoint"
+	eventservice "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service"
 	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
 	"github.com/pkg/errors"
 )
@@ -58,8 +59,29 @@ type Client struct {
 	params
 }
 
+// Dispatcher is the event dispatcher used by a deliver event client. The dispatcher must handle seek events
+// (dispatcher.SeekEvent) by sending the seek request to the deliver server(s).
+type Dispatcher interface {
+	eventservice.Dispatcher
+	UpdateLastBlockInfoOnly()
+}
+
+// DispatcherProvider creates the dispatcher for a deliver event client from the given discovery service
+// and the connection provider selected by the client options
+type DispatcherProvider func(discoveryService fab.DiscoveryService, connectionProvider api.ConnectionProvider) Dispatcher
+
 // New returns a new deliver event client
 func New(context fabcontext.Client, chConfig fab.ChannelCfg, discoveryService fab.DiscoveryService, opts ...options.Opt) (*Client, error) {
+	dispatcherProvider := func(discoveryService fab.DiscoveryService, connectionProvider api.ConnectionProvider) Dispatcher {
+		return dispatcher.New(context, chConfig, discoveryService, connectionProvider, opts...)
+	}
+	return NewWithDispatcher(context, chConfig, discoveryService, dispatcherProvider, opts...)
+}
+
+// NewWithDispatcher returns a new deliver event client which uses the dispatcher created by the given provider.
+// The client sends the seek request to the dispatcher after connecting and, on reconnect, seeks from the
+// block after the last block received.
+func NewWithDispatcher(context fabcontext.Client, chConfig fab.ChannelCfg, discoveryService fab.DiscoveryService, dispatcherProvider DispatcherProvider, opts ...options.Opt) (*Client, error) {
 	params := defaultParams()
 	options.Apply(params, opts)
 
@@ -70,7 +92,7 @@ func New(context fabcontext.Client, chConfig fab.ChannelCfg, discoveryService fa
 		return nil, err
 	}
 
-	dispatcher := dispatcher.New(context, chConfig, discoveryWrapper, params.connProvider, opts...)
+	dispatcher := dispatcherProvider(discoveryWrapper, params.connProvider)
 
 	//default seek type is `Newest`
 	if params.seekType == "" {
@@ -7,10 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package dispatcher
 
 import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel/membership"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
 	clientdisp "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/connection"
@@ -34,17 +37,39 @@ type dsConnection interface {
 // This also avoids the need for synchronization.
 type Dispatcher struct {
 	*clientdisp.Dispatcher
+	params
+	context       fabcontext.Client
+	blockVerifier blockVerifier
+}
+
+// blockVerifier verifies the integrity of blocks received from the deliver server
+type blockVerifier interface {
+	Verify(block *cb.Block) error
+	Previous() *cb.BlockHeader
 }
 
 // New returns a new deliver dispatcher
 func New(context fabcontext.Client, chConfig fab.ChannelCfg, discoveryService fab.DiscoveryService, connectionProvider api.ConnectionProvider, opts ...options.Opt) *Dispatcher {
+	params := &params{}
+	options.Apply(params, opts)
+
 	return &Dispatcher{
 		Dispatcher: clientdisp.New(context, chConfig, discoveryService, connectionProvider, opts...),
+		params:     *params,
+		context:    context,
 	}
 }
 
 // Start starts the dispatcher
 func (ed *Dispatcher) Start() error {
+	if ed.blockVerification && ed.blockVerifier == nil {
+		blockVerifier, err := ed.newBlockVerifier()
+		if err != nil {
+			return errors.WithMessage(err, "error creating block verifier")
+		}
+		ed.blockVerifier = blockVerifier
+	}
+
 	ed.registerHandlers()
 	if err := ed.Dispatcher.Start(); err != nil {
 		return errors.WithMessage(err, "error starting deliver event dispatcher")
@@ -78,7 +103,9 @@ func (ed *Dispatcher) handleEvent(e esdispatcher.Event) {
 	case *pb.DeliverResponse_Status:
 		ed.handleDeliverResponseStatus(response)
 	case *pb.DeliverResponse_Block:
-		ed.HandleBlock(response.Block, delevent.SourceURL)
+		if ed.verifyBlock(response.Block, delevent.SourceURL) {
+			ed.HandleBlock(response.Block, delevent.SourceURL)
+		}
 	case *pb.DeliverResponse_FilteredBlock:
 		ed.HandleFilteredBlock(response.FilteredBlock, delevent.SourceURL)
 	default:
@@ -95,6 +122,66 @@ func (ed *Dispatcher) handleDeliverResponseStatus(evt *pb.DeliverResponse_Status
 
 	logger.Warnf("Got deliver response status event: %#v. Disconnecting...", evt)
 
+	ed.disconnect(disconnectedEventFromStatus(evt.Status))
+}
+
+// verifyBlock verifies the given block (if block verification is enabled) and returns true if
+// the block may be dispatched. If the block fails verification then the peer which sent the block is
+// greylisted and the client is disconnected (and subsequently reconnects to another peer).
+func (ed *Dispatcher) verifyBlock(block *cb.Block, sourceURL string) bool {
+	if ed.blockVerifier == nil {
+		return true
+	}
+
+	if previous := ed.blockVerifier.Previous(); previous != nil && block.Header != nil && block.Header.Number <= previous.Number {
+		logger.Debugf("Ignoring block #%d from [%s] since block #%d was already verified", block.Header.Number, sourceURL, previous.Number)
+		return false
+	}
+
+	err := ed.blockVerifier.Verify(block)
+	if err == nil {
+		return true
+	}
+
+	if ed.Connection() == nil {
+		logger.Debugf("Ignoring block from [%s] which failed verification since the client is already disconnected: %s", sourceURL, err)
+		return false
+	}
+
+	logger.Warnf("Block received from [%s] failed verification: %s. Disconnecting...", sourceURL, err)
+
+	ed.GreylistPeer(sourceURL)
+	ed.disconnect(clientdisp.NewDisconnectedEvent(err))
+
+	return false
+}
+
+// newBlockVerifier returns a verifier which evaluates the orderer signatures against the BlockValidation policy of
+// the current channel config. The policy is reloaded from every config block received, so the verifier tracks
+// config updates while connected and, when seeking from the genesis block, walks the chain's config history.
+func (ed *Dispatcher) newBlockVerifier() (*verifier.Block, error) {
+	chService, err := ed.context.ChannelProvider().ChannelService(ed.context, ed.ChannelConfig().ID())
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to get channel service")
+	}
+
+	chConfig, err := chService.ChannelConfig()
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to get channel config")
+	}
+
+	return verifier.NewConfigBlock(ed.context.CryptoSuite(), chConfig, ed.configFromBlock, ed.channelMembership)
+}
+
+func (ed *Dispatcher) configFromBlock(block *cb.Block) (fab.ChannelCfg, error) {
+	return chconfig.FromBlock(ed.ChannelConfig().ID(), block)
+}
+
+func (ed *Dispatcher) channelMembership(cfg fab.ChannelCfg) (fab.ChannelMembership, error) {
+	return membership.New(membership.Context{Providers: ed.context, EndpointConfig: ed.context.EndpointConfig()}, cfg)
+}
+
+func (ed *Dispatcher) disconnect(disconnectedEvent *clientdisp.DisconnectedEvent) {
 	errch := make(chan error, 1)
 	ed.Dispatcher.HandleDisconnectEvent(&clientdisp.DisconnectEvent{
 		Errch: errch,
@@ -104,7 +191,7 @@ func (ed *Dispatcher) handleDeliverResponseStatus(evt *pb.DeliverResponse_Status
 		logger.Warnf("Error disconnecting: %s", err)
 	}
 
-	ed.Dispatcher.HandleDisconnectedEvent(disconnectedEventFromStatus(evt.Status))
+	ed.Dispatcher.HandleDisconnectedEvent(disconnectedEvent)
 }
 
 func (ed *Dispatcher) registerHandlers() {
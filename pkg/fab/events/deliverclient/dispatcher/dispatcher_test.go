@@ -20,6 +20,7 @@ import (
 	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
 	fabmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
@@ -318,3 +319,109 @@ func checkFilteredBlockEvents(eventch chan *fab.FilteredBlockEvent, t *testing.T
 		t.Fatal("timed out waiting for filtered block event")
 	}
 }
+
+func TestBlockVerification(t *testing.T) {
+	channelID := "testchannel"
+	ledger := servicemocks.NewMockLedger(delivermocks.BlockEventFactory, sourceURL)
+	sourcePeer := fabmocks.NewMockPeer("peer3", "grpcs://"+sourceURL)
+
+	dispatcher := New(
+		fabmocks.NewMockContext(
+			mspmocks.NewMockSigningIdentity("user1", "Org1MSP"),
+		),
+		fabmocks.NewMockChannelCfg(channelID),
+		clientmocks.NewDiscoveryService(sourcePeer),
+		clientmocks.NewProviderFactory().Provider(
+			delivermocks.NewConnection(
+				clientmocks.WithLedger(ledger),
+			),
+		),
+	)
+
+	// Reject block 1
+	dispatcher.blockVerifier = &mockBlockVerifier{failBlockNum: 1}
+
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("Error starting dispatcher: %s", err)
+	}
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	if err != nil {
+		t.Fatalf("Error getting event channel from dispatcher: %s", err)
+	}
+
+	// Register connection event
+	errch := make(chan error)
+	regch := make(chan fab.Registration)
+	conneventch := make(chan *clientdisp.ConnectionEvent, 5)
+	dispatcherEventch <- clientdisp.NewRegisterConnectionEvent(conneventch, regch, errch)
+
+	checkErrorFromReg(errch, t, regch)
+
+	// Connect
+	dispatcherEventch <- clientdisp.NewConnectEvent(errch)
+	if err := <-errch; err != nil {
+		t.Fatalf("Error connecting: %s", err)
+	}
+
+	// Register for block events
+	eventch := make(chan *fab.BlockEvent, 10)
+	dispatcherEventch <- esdispatcher.NewRegisterBlockEvent(blockfilter.AcceptAny, eventch, regch, errch)
+
+	select {
+	case <-regch:
+	case err := <-errch:
+		t.Fatalf("Error registering for block events: %s", err)
+	}
+
+	ledger.NewBlock(channelID)
+	checkBlockEvents(eventch, t)
+
+	// Block 1 fails verification - the client should be disconnected and no event should be published
+	ledger.NewBlock(channelID)
+
+	select {
+	case event := <-conneventch:
+		assert.False(t, event.Connected)
+		assert.Error(t, event.Err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for disconnected event")
+	}
+
+	select {
+	case <-eventch:
+		t.Fatal("block event should not have been published for block which failed verification")
+	default:
+	}
+	assert.Equal(t, uint64(0), dispatcher.LastBlockNum())
+
+	// The peer should be greylisted
+	dispatcherEventch <- clientdisp.NewConnectEvent(errch)
+	err = <-errch
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no peers to connect to")
+
+	// Stop
+	stopResp := make(chan error)
+	dispatcherEventch <- esdispatcher.NewStopEvent(stopResp)
+	if err := <-stopResp; err != nil {
+		t.Fatalf("Error stopping dispatcher: %s", err)
+	}
+}
+
+type mockBlockVerifier struct {
+	failBlockNum uint64
+	previous     *cb.BlockHeader
+}
+
+func (v *mockBlockVerifier) Verify(block *cb.Block) error {
+	if block.Header.Number == v.failBlockNum {
+		return errors.Errorf("block %d is invalid", block.Header.Number)
+	}
+	v.previous = block.Header
+	return nil
+}
+
+func (v *mockBlockVerifier) Previous() *cb.BlockHeader {
+	return v.previous
+}
@@ -0,0 +1,16 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+type params struct {
+	blockVerification bool
+}
+
+func (p *params) EnableBlockVerification() {
+	logger.Debug("EnableBlockVerification")
+	p.blockVerification = true
+}
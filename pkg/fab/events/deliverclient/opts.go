@@ -48,6 +48,24 @@ func WithBlockNum(value uint64) options.Opt {
 	}
 }
 
+// WithBlockVerification enables verification of the blocks received from the peer. The block header hash
+// chain is checked against the previously received block and the orderer signatures are verified against the
+// channel's BlockValidation policy before any block, chaincode or transaction status events are published.
+// The policy is reloaded from each config block received. The genesis block is trusted, so only its data hash is checked.
+// If a block fails verification then the peer is greylisted and the client reconnects to another peer.
+// Note that full blocks are required for verification, so the caller must have sufficient privileges for block events.
+func WithBlockVerification() options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(blockVerificationSetter); ok {
+			setter.EnableBlockVerification()
+		}
+	}
+}
+
+type blockVerificationSetter interface {
+	EnableBlockVerification()
+}
+
 type seekTypeSetter interface {
 	SetSeekType(value seek.Type)
 }
@@ -61,6 +79,12 @@ func (p *params) PermitBlockEvents() {
 	p.connProvider = deliverProvider
 }
 
+func (p *params) EnableBlockVerification() {
+	logger.Debug("EnableBlockVerification")
+	// Filtered blocks can't be verified
+	p.connProvider = deliverProvider
+}
+
 // SetConnectionProvider is only used in unit tests
 func (p *params) SetConnectionProvider(connProvider api.ConnectionProvider) {
 	logger.Debugf("ConnectionProvider: %#v", connProvider)
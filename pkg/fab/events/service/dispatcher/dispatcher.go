@@ -526,6 +526,7 @@ func (ed *Dispatcher) publishBlockEvents(block *cb.Block, sourceURL string) {
 			case reg.Eventch <- NewBlockEvent(block, sourceURL):
 			default:
 				logger.Warn("Unable to send to block event channel.")
+				ed.eventDropped("block")
 			}
 		} else if ed.eventConsumerTimeout == 0 {
 			reg.Eventch <- NewBlockEvent(block, sourceURL)
@@ -534,6 +535,7 @@ func (ed *Dispatcher) publishBlockEvents(block *cb.Block, sourceURL string) {
 			case reg.Eventch <- NewBlockEvent(block, sourceURL):
 			case <-time.After(ed.eventConsumerTimeout):
 				logger.Warn("Timed out sending block event.")
+				ed.eventDropped("block")
 			}
 		}
 	}
@@ -579,6 +581,7 @@ func checkFilteredBlockRegistrations(ed *Dispatcher, fblock *pb.FilteredBlock, s
 			case reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL):
 			default:
 				logger.Warn("Unable to send to filtered block event channel.")
+				ed.eventDropped("filteredblock")
 			}
 		} else if ed.eventConsumerTimeout == 0 {
 			reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL)
@@ -587,6 +590,7 @@ func checkFilteredBlockRegistrations(ed *Dispatcher, fblock *pb.FilteredBlock, s
 			case reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL):
 			case <-time.After(ed.eventConsumerTimeout):
 				logger.Warn("Timed out sending filtered block event.")
+				ed.eventDropped("filteredblock")
 			}
 		}
 	}
@@ -602,6 +606,7 @@ func (ed *Dispatcher) publishTxStatusEvents(tx *pb.FilteredTransaction, blockNum
 			case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL):
 			default:
 				logger.Warn("Unable to send to Tx Status event channel.")
+				ed.eventDropped("txstatus")
 			}
 		} else if ed.eventConsumerTimeout == 0 {
 			reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL)
@@ -610,6 +615,7 @@ func (ed *Dispatcher) publishTxStatusEvents(tx *pb.FilteredTransaction, blockNum
 			case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL):
 			case <-time.After(ed.eventConsumerTimeout):
 				logger.Warn("Timed out sending Tx Status event.")
+				ed.eventDropped("txstatus")
 			}
 		}
 	}
@@ -626,6 +632,7 @@ func (ed *Dispatcher) publishCCEvents(ccEvent *pb.ChaincodeEvent, blockNum uint6
 				case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL):
 				default:
 					logger.Warn("Unable to send to CC event channel.")
+					ed.eventDropped("ccevent")
 				}
 			} else if ed.eventConsumerTimeout == 0 {
 				reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL)
@@ -634,12 +641,20 @@ func (ed *Dispatcher) publishCCEvents(ccEvent *pb.ChaincodeEvent, blockNum uint6
 				case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL):
 				case <-time.After(ed.eventConsumerTimeout):
 					logger.Warn("Timed out sending CC event.")
+					ed.eventDropped("ccevent")
 				}
 			}
 		}
 	}
 }
 
+// eventDropped records that an event of the given type could not be delivered to a consumer
+func (ed *Dispatcher) eventDropped(eventType string) {
+	if ed.droppedEventsCounter != nil {
+		ed.droppedEventsCounter.With("event", eventType).Add(1)
+	}
+}
+
 // RegisterHandler registers an event handler
 func (ed *Dispatcher) RegisterHandler(t interface{}, h Handler) {
 	htype := reflect.TypeOf(t)
@@ -8,11 +8,13 @@ package dispatcher
 
 import (
 	"bytes"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/blockfilter"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/blockfilter/headertypefilter"
@@ -89,6 +91,77 @@ func TestBlockEvents(t *testing.T) {
 	}
 }
 
+func TestDroppedEvents(t *testing.T) {
+	counter := newTestCounter()
+
+	dispatcher := New(
+		WithEventConsumerTimeout(-1),
+		WithDroppedEventsCounter(counter),
+	)
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("Error starting dispatcher: %s", err)
+	}
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	if err != nil {
+		t.Fatalf("Error getting event channel from dispatcher: %s", err)
+	}
+
+	// Nobody reads from the unbuffered event channel so the event is dropped
+	eventch := make(chan *fab.BlockEvent)
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+
+	dispatcherEventch <- NewRegisterBlockEvent(blockfilter.AcceptAny, eventch, regch, errch)
+
+	select {
+	case <-regch:
+	case err := <-errch:
+		t.Fatalf("Error registering for block events: %s", err)
+	}
+
+	dispatcherEventch <- NewBlockEvent(servicemocks.NewBlockProducer().NewBlock("testchannel"), sourceURL)
+
+	stopResp := make(chan error)
+	dispatcherEventch <- NewStopEvent(stopResp)
+	if err := <-stopResp; err != nil {
+		t.Fatalf("Error stopping dispatcher: %s", err)
+	}
+
+	require.Equal(t, float64(1), counter.count("block"))
+}
+
+// testCounter is a counter that records the count for each event type
+type testCounter struct {
+	*testCounts
+	eventType string
+}
+
+type testCounts struct {
+	mutex  sync.Mutex
+	counts map[string]float64
+}
+
+func newTestCounter() *testCounter {
+	return &testCounter{testCounts: &testCounts{counts: make(map[string]float64)}}
+}
+
+func (c *testCounter) With(labelValues ...string) metrics.Counter {
+	return &testCounter{testCounts: c.testCounts, eventType: labelValues[1]}
+}
+
+func (c *testCounter) Add(delta float64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.counts[c.eventType] += delta
+}
+
+func (c *testCounts) count(eventType string) float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.counts[eventType]
+}
+
 func TestBlockEventsWithFilter(t *testing.T) {
 	channelID := "testchannel"
 	dispatcher := New()
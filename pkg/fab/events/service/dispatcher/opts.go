@@ -9,6 +9,7 @@ package dispatcher
 import (
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/pkg/errors"
@@ -22,6 +23,7 @@ type params struct {
 	initialFilteredBlockRegistrations []*FilteredBlockReg
 	initialCCRegistrations            []*ChaincodeReg
 	initialTxStatusRegistrations      []*TxStatusReg
+	droppedEventsCounter              metrics.Counter
 }
 
 func defaultParams() *params {
@@ -52,6 +54,16 @@ func WithEventConsumerTimeout(value time.Duration) options.Opt {
 	}
 }
 
+// WithDroppedEventsCounter sets the counter that is incremented (with the label "event" set to the type of event)
+// whenever an event can't be delivered to a registered consumer.
+func WithDroppedEventsCounter(value metrics.Counter) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(droppedEventsCounterSetter); ok {
+			setter.SetDroppedEventsCounter(value)
+		}
+	}
+}
+
 // WithSnapshot sets the given TxStatus registrations.
 func WithSnapshot(value fab.EventSnapshot) options.Opt {
 	return func(p options.Params) {
@@ -82,6 +94,14 @@ func (p *params) SetEventConsumerTimeout(value time.Duration) {
 	p.eventConsumerTimeout = value
 }
 
+type droppedEventsCounterSetter interface {
+	SetDroppedEventsCounter(value metrics.Counter)
+}
+
+func (p *params) SetDroppedEventsCounter(value metrics.Counter) {
+	p.droppedEventsCounter = value
+}
+
 type snapshotSetter interface {
 	SetSnapshot(value fab.EventSnapshot) error
 }
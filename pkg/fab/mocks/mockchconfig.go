@@ -16,14 +16,19 @@ import (
 
 // MockChannelCfg contains mock channel configuration
 type MockChannelCfg struct {
-	MockID           string
-	MockBlockNumber  uint64
-	MockMSPs         []*msp.MSPConfig
-	MockAnchorPeers  []*fab.OrgAnchorPeer
-	MockOrderers     []string
-	MockVersions     *fab.Versions
-	MockMembership   fab.ChannelMembership
-	MockCapabilities map[fab.ConfigGroupKey]map[string]bool
+	MockID               string
+	MockBlockNumber      uint64
+	MockMSPs             []*msp.MSPConfig
+	MockAnchorPeers      []*fab.OrgAnchorPeer
+	MockOrderers         []string
+	MockVersions         *fab.Versions
+	MockMembership       fab.ChannelMembership
+	MockCapabilities     map[fab.ConfigGroupKey]map[string]bool
+	MockHashingAlgorithm string
+	MockPolicies         map[string]*fab.ChannelPolicy
+	MockOrdererConfig    *fab.ChannelOrdererConfig
+	MockACLs             map[string]string
+	MockConsortium       string
 }
 
 // NewMockChannelCfg ...
@@ -78,6 +83,31 @@ func (cfg *MockChannelCfg) HasCapability(group fab.ConfigGroupKey, capability st
 	return capabilities[capability]
 }
 
+// HashingAlgorithm returns the hashing algorithm
+func (cfg *MockChannelCfg) HashingAlgorithm() string {
+	return cfg.MockHashingAlgorithm
+}
+
+// Policies returns the channel policies
+func (cfg *MockChannelCfg) Policies() map[string]*fab.ChannelPolicy {
+	return cfg.MockPolicies
+}
+
+// OrdererConfig returns the orderer settings of the channel
+func (cfg *MockChannelCfg) OrdererConfig() *fab.ChannelOrdererConfig {
+	return cfg.MockOrdererConfig
+}
+
+// ACLs returns the application ACLs
+func (cfg *MockChannelCfg) ACLs() map[string]string {
+	return cfg.MockACLs
+}
+
+// Consortium returns the consortium name
+func (cfg *MockChannelCfg) Consortium() string {
+	return cfg.MockConsortium
+}
+
 // MockChannelConfig mockcore query channel configuration
 type MockChannelConfig struct {
 	channelID string
@@ -15,6 +15,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
@@ -206,9 +207,14 @@ func (pc *MockProviderContext) SetCustomInfraProvider(customInfraProvider fab.In
 	pc.infraProvider = customInfraProvider
 }
 
-// GetMetrics not used in this mockcontext
+// GetMetrics returns disabled metrics
 func (pc *MockProviderContext) GetMetrics() *metrics.ClientMetrics {
-	return &metrics.ClientMetrics{}
+	return metrics.NewDisabledClientMetrics()
+}
+
+// Tracer returns a no-op tracer
+func (pc *MockProviderContext) Tracer() tracing.Tracer {
+	return &tracing.NoopTracer{}
 }
 
 // MockContext holds core providers and identity to enable mocking.
@@ -304,9 +310,14 @@ func (c *MockChannelContext) ChannelID() string {
 	return c.channelID
 }
 
-// GetMetrics not used in this mockcontext
+// GetMetrics returns disabled metrics
 func (c *MockChannelContext) GetMetrics() *metrics.ClientMetrics {
-	return &metrics.ClientMetrics{}
+	return metrics.NewDisabledClientMetrics()
+}
+
+// Tracer returns a no-op tracer
+func (c *MockChannelContext) Tracer() tracing.Tracer {
+	return &tracing.NoopTracer{}
 }
 
 // MockTransactionHeader supplies a transaction ID and metadata.
@@ -64,6 +64,9 @@ type MockConfigGroupBuilder struct {
 	ChannelCapabilities     []string
 	ApplicationCapabilities []string
 	OrdererCapabilities     []string
+	HashingAlgorithm        string
+	Consortium              string
+	ACLs                    map[string]string
 }
 
 // MockConfigBlockBuilder is used to build a mock Chain configuration block
@@ -172,6 +175,14 @@ func (b *MockConfigBlockBuilder) buildConfig() *common.Config {
 }
 
 func (b *MockConfigGroupBuilder) buildConfigGroup() *common.ConfigGroup {
+	values := map[string]*common.ConfigValue{
+		channelConfig.OrdererAddressesKey: b.buildOrdererAddressesConfigValue(),
+		channelConfig.CapabilitiesKey:     b.buildCapabilitiesConfigValue(b.ChannelCapabilities),
+	}
+	if b.Consortium != "" {
+		values[channelConfig.ConsortiumKey] = b.buildConsortiumConfigValue()
+	}
+
 	return &common.ConfigGroup{
 		Groups: map[string]*common.ConfigGroup{
 			"Orderer":     b.buildOrdererGroup(),
@@ -183,10 +194,7 @@ func (b *MockConfigGroupBuilder) buildConfigGroup() *common.ConfigGroup {
 			"Readers":         b.buildBasicConfigPolicy(),
 			"Admins":          b.buildBasicConfigPolicy(),
 		},
-		Values: map[string]*common.ConfigValue{
-			channelConfig.OrdererAddressesKey: b.buildOrdererAddressesConfigValue(),
-			channelConfig.CapabilitiesKey:     b.buildCapabilitiesConfigValue(b.ChannelCapabilities),
-		},
+		Values:    values,
 		Version:   b.Version,
 		ModPolicy: b.ModPolicy,
 	}
@@ -297,6 +305,24 @@ func (b *MockConfigGroupBuilder) buildHashingAlgorithmConfigValue() *common.Conf
 		Value:     marshalOrPanic(b.buildHashingAlgorithm())}
 }
 
+func (b *MockConfigGroupBuilder) buildConsortiumConfigValue() *common.ConfigValue {
+	return &common.ConfigValue{
+		Version:   b.Version,
+		ModPolicy: b.ModPolicy,
+		Value:     marshalOrPanic(&common.Consortium{Name: b.Consortium})}
+}
+
+func (b *MockConfigGroupBuilder) buildACLsConfigValue() *common.ConfigValue {
+	acls := make(map[string]*pp.APIResource)
+	for resource, policyRef := range b.ACLs {
+		acls[resource] = &pp.APIResource{PolicyRef: policyRef}
+	}
+	return &common.ConfigValue{
+		Version:   b.Version,
+		ModPolicy: b.ModPolicy,
+		Value:     marshalOrPanic(&pp.ACLs{Acls: acls})}
+}
+
 func (b *MockConfigGroupBuilder) buildBlockDataHashingStructureConfigValue() *common.ConfigValue {
 	return &common.ConfigValue{
 		Version:   b.Version,
@@ -334,7 +360,7 @@ func (b *MockConfigGroupBuilder) buildConsensusType() *ab.ConsensusType {
 
 func (b *MockConfigGroupBuilder) buildBatchTimeout() *ab.BatchTimeout {
 	return &ab.BatchTimeout{
-		Timeout: "123",
+		Timeout: "2s",
 	}
 }
 
@@ -345,8 +371,12 @@ func (b *MockConfigGroupBuilder) buildChannelRestrictions() *ab.ChannelRestricti
 }
 
 func (b *MockConfigGroupBuilder) buildHashingAlgorithm() *common.HashingAlgorithm {
+	name := b.HashingAlgorithm
+	if name == "" {
+		name = "SHA256"
+	}
 	return &common.HashingAlgorithm{
-		Name: "SHA2",
+		Name: name,
 	}
 }
 
@@ -422,6 +452,15 @@ func (b *MockConfigGroupBuilder) buildApplicationGroup() *common.ConfigGroup {
 		groups[name] = b.buildMSPGroup(name)
 	}
 
+	values := map[string]*common.ConfigValue{
+		channelConfig.BatchSizeKey:    b.buildBatchSizeConfigValue(),
+		channelConfig.CapabilitiesKey: b.buildCapabilitiesConfigValue(b.ApplicationCapabilities),
+		// TODO: More
+	}
+	if len(b.ACLs) > 0 {
+		values[channelConfig.ACLsKey] = b.buildACLsConfigValue()
+	}
+
 	return &common.ConfigGroup{
 		Groups: groups,
 		Policies: map[string]*common.ConfigPolicy{
@@ -429,11 +468,7 @@ func (b *MockConfigGroupBuilder) buildApplicationGroup() *common.ConfigGroup {
 			"Writers": b.buildSignatureConfigPolicy(),
 			"Readers": b.buildSignatureConfigPolicy(),
 		},
-		Values: map[string]*common.ConfigValue{
-			channelConfig.BatchSizeKey:    b.buildBatchSizeConfigValue(),
-			channelConfig.CapabilitiesKey: b.buildCapabilitiesConfigValue(b.ApplicationCapabilities),
-			// TODO: More
-		},
+		Values: values,
 		Version:   b.Version,
 		ModPolicy: b.ModPolicy,
 	}
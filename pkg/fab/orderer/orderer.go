@@ -25,9 +25,11 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	fabcomm "github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
 	"github.com/tjfoc/gmsm/sm2"
@@ -53,6 +55,7 @@ type Orderer struct {
 	failFast       bool
 	allowInsecure  bool
 	commManager    fab.CommManager
+	dialOptionSets []string
 }
 
 // Option describes a functional parameter for the New constructor
@@ -95,6 +98,13 @@ func New(config fab.EndpointConfig, opts ...Option) (*Orderer, error) {
 	grpcOpts = append(grpcOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxCallRecvMsgSize),
 		grpc.MaxCallSendMsgSize(maxCallSendMsgSize)))
 
+	grpcOpts = append(grpcOpts, grpc.WithUnaryInterceptor(tracing.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(tracing.StreamClientInterceptor()))
+
+	if len(orderer.dialOptionSets) > 0 {
+		grpcOpts = append(grpcOpts, fabcomm.DialOptionSets(orderer.dialOptionSets...))
+	}
+
 	orderer.dialTimeout = config.Timeout(fab.OrdererConnection)
 	orderer.url = endpoint.ToAddress(orderer.url)
 	orderer.grpcDialOption = grpcOpts
@@ -158,6 +168,7 @@ func FromOrdererConfig(ordererCfg *fab.OrdererConfig) Option {
 		o.kap = getKeepAliveOptions(ordererCfg)
 		o.failFast = getFailFast(ordererCfg)
 		o.allowInsecure = isInsecureConnectionAllowed(ordererCfg)
+		o.dialOptionSets = fabcomm.DialOptionSetNames(ordererCfg.GRPCOptions)
 
 		return nil
 	}
@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fabcomm "github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 )
 
 var logger = logging.NewLogger("fabsdk/fab")
@@ -36,6 +37,8 @@ type Peer struct {
 	failFast    bool
 	inSecure    bool
 	commManager fab.CommManager
+	// dialOptionSets are the names of the dial option sets applied to the connection
+	dialOptionSets []string
 }
 
 // Option describes a functional parameter for the New constructor
@@ -67,6 +70,7 @@ func New(config fab.EndpointConfig, opts ...Option) (*Peer, error) {
 			failFast:           peer.failFast,
 			allowInsecure:      peer.inSecure,
 			commManager:        peer.commManager,
+			dialOptionSets:     peer.dialOptionSets,
 		}
 		processor, err := newPeerEndorser(&endorseRequest)
 
@@ -147,6 +151,7 @@ func FromPeerConfig(peerCfg *fab.NetworkPeer) Option {
 		p.mspID = peerCfg.MSPID
 		p.kap = getKeepAliveOptions(peerCfg)
 		p.failFast = getFailFast(peerCfg)
+		p.dialOptionSets = fabcomm.DialOptionSetNames(peerCfg.GRPCOptions)
 		return nil
 	}
 }
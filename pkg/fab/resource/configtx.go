@@ -7,14 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package resource
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
 
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
-	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/tools/protolator"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/sdkinternal/configtxgen/encoder"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/sdkinternal/configtxlator/update"
@@ -23,8 +21,10 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/sdkinternal/configtxgen/localconfig"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/genesisconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/protojson"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
 // See https://github.com/hyperledger/fabric/blob/be235fd3a236f792a525353d9f9586c8b0d4a61a/cmd/configtxgen/main.go
@@ -79,12 +79,11 @@ func InspectBlock(data []byte) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("error unmarshaling to block: %s", err)
 	}
-	var buf bytes.Buffer
-	err = protolator.DeepMarshalJSON(&buf, block)
+	jsonBytes, err := protojson.Marshal(block)
 	if err != nil {
 		return "", fmt.Errorf("malformed block contents: %s", err)
 	}
-	return buf.String(), nil
+	return string(jsonBytes), nil
 }
 
 // CreateChannelCreateTx creates a Fabric transaction for creating a channel
@@ -121,12 +120,11 @@ func InspectChannelCreateTx(data []byte) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("Error unmarshaling envelope: %s", err)
 	}
-	var buf bytes.Buffer
-	err = protolator.DeepMarshalJSON(&buf, env)
+	jsonBytes, err := protojson.Marshal(env)
 	if err != nil {
 		return "", fmt.Errorf("malformed transaction contents: %s", err)
 	}
-	return buf.String(), nil
+	return string(jsonBytes), nil
 }
 
 // CreateAnchorPeersUpdate creates an anchor peers update transaction
@@ -177,3 +175,71 @@ func CreateAnchorPeersUpdate(conf *genesisconfig.Profile, channelID string, asOr
 	return protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, channelID, nil, newConfigUpdateEnv, 0, 0)
 
 }
+
+// CreateAnchorPeersConfigUpdate computes the config update which replaces the anchor peers of an
+// application organization in the given (current) channel config. The organization is identified by
+// the name of its config group. The returned bytes are the marshalled config update, as expected by
+// CreateChannelRequest.Config.
+func CreateAnchorPeersConfigUpdate(config *common.Config, channelID string, org string, anchorPeers []*pb.AnchorPeer) ([]byte, error) {
+	if org == "" {
+		return nil, errors.New("must specify an organization to update the anchor peers for")
+	}
+	if config == nil || config.ChannelGroup == nil {
+		return nil, errors.New("channel config is required")
+	}
+
+	application, ok := config.ChannelGroup.Groups[channelconfig.ApplicationGroupKey]
+	if !ok {
+		return nil, errors.New("cannot update anchor peers without an application group")
+	}
+	if _, ok := application.Groups[org]; !ok {
+		return nil, errors.Errorf("org with name '%s' does not exist in config", org)
+	}
+
+	updated := proto.Clone(config).(*cb.Config)
+	orgGroup := updated.ChannelGroup.Groups[channelconfig.ApplicationGroupKey].Groups[org]
+	if err := setConfigValue(orgGroup, channelconfig.AnchorPeersKey, &pb.AnchorPeers{AnchorPeers: anchorPeers}); err != nil {
+		return nil, err
+	}
+
+	return computeConfigUpdate(config, updated, channelID)
+}
+
+// setConfigValue sets the value with the given key in the config group. A new value gets the Admins mod policy.
+func setConfigValue(group *cb.ConfigGroup, key string, msg proto.Message) error {
+	value, err := proto.Marshal(msg)
+	if err != nil {
+		return errors.Wrapf(err, "marshal %s failed", key)
+	}
+
+	if group.Values == nil {
+		group.Values = make(map[string]*cb.ConfigValue)
+	}
+	if configValue, ok := group.Values[key]; ok {
+		configValue.Value = value
+	} else {
+		group.Values[key] = &cb.ConfigValue{
+			Value:     value,
+			ModPolicy: channelconfig.AdminsPolicyKey,
+		}
+	}
+	return nil
+}
+
+// computeConfigUpdate returns the marshalled config update from the original to the updated config.
+// The update is marshalled deterministically so that signatures created over an update computed
+// from the same configs remain valid when the update is computed again.
+func computeConfigUpdate(original, updated *cb.Config, channelID string) ([]byte, error) {
+	updt, err := update.Compute(original, updated)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not compute update")
+	}
+	updt.ChannelId = channelID
+
+	buffer := proto.NewBuffer(nil)
+	buffer.SetDeterministic(true)
+	if err := buffer.Marshal(updt); err != nil {
+		return nil, errors.Wrap(err, "marshal config update failed")
+	}
+	return buffer.Bytes(), nil
+}
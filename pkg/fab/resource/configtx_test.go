@@ -11,7 +11,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
 	"github.com/hyperledger/fabric-sdk-go/test/metadata"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/genesisconfig"
 	"github.com/stretchr/testify/require"
@@ -313,3 +318,48 @@ func TestBadAnchorPeersUpdates(t *testing.T) {
 	_, err = CreateAnchorPeersUpdate(config, "foo", "SampleOrg")
 	require.Error(t, err, "Bad anchorPeerUpdate request - fake org")
 }
+
+func TestCreateAnchorPeersConfigUpdate(t *testing.T) {
+	original := &pb.AnchorPeers{AnchorPeers: []*pb.AnchorPeer{{Host: "peer0.org1.example.com", Port: 7051}}}
+	config := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				channelconfig.ApplicationGroupKey: {
+					Groups: map[string]*cb.ConfigGroup{
+						"SampleOrg": {
+							Values: map[string]*cb.ConfigValue{
+								channelconfig.AnchorPeersKey: {Value: protoutil.MarshalOrPanic(original), ModPolicy: channelconfig.AdminsPolicyKey},
+							},
+							ModPolicy: channelconfig.AdminsPolicyKey,
+						},
+					},
+					ModPolicy: channelconfig.AdminsPolicyKey,
+				},
+			},
+		},
+	}
+
+	anchorPeers := []*pb.AnchorPeer{{Host: "peer1.org1.example.com", Port: 7051}}
+	data, err := CreateAnchorPeersConfigUpdate(config, "foo", "SampleOrg", anchorPeers)
+	require.NoError(t, err)
+
+	configUpdate := &cb.ConfigUpdate{}
+	require.NoError(t, proto.Unmarshal(data, configUpdate))
+	require.Equal(t, "foo", configUpdate.ChannelId)
+
+	value := configUpdate.WriteSet.Groups[channelconfig.ApplicationGroupKey].Groups["SampleOrg"].Values[channelconfig.AnchorPeersKey]
+	require.NotNil(t, value)
+	require.Equal(t, uint64(1), value.Version)
+	updated := &pb.AnchorPeers{}
+	require.NoError(t, proto.Unmarshal(value.Value, updated))
+	require.Len(t, updated.AnchorPeers, 1)
+	require.Equal(t, "peer1.org1.example.com", updated.AnchorPeers[0].Host)
+
+	_, err = CreateAnchorPeersConfigUpdate(config, "foo", "FakeOrg", anchorPeers)
+	require.Error(t, err, "Bad anchor peers config update - fake org")
+	_, err = CreateAnchorPeersConfigUpdate(config, "foo", "", anchorPeers)
+	require.Error(t, err, "Bad anchor peers config update - org empty")
+
+	_, err = CreateAnchorPeersConfigUpdate(config, "foo", "SampleOrg", original.AnchorPeers)
+	require.Error(t, err, "Bad anchor peers config update - no differences")
+}
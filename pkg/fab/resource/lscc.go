@@ -18,6 +18,7 @@ const (
 	lscc                    = "lscc"
 	lsccInstall             = "install"
 	lsccInstalledChaincodes = "getinstalledchaincodes"
+	lsccInstalledPackage    = "getinstalledccpackage"
 )
 
 // ChaincodeInstallRequest requests chaincode installation on the network
@@ -73,3 +74,12 @@ func createInstalledChaincodesInvokeRequest() fab.ChaincodeInvokeRequest {
 	}
 	return cir
 }
+
+func createInstalledChaincodePackageInvokeRequest(name, version string) fab.ChaincodeInvokeRequest {
+	cir := fab.ChaincodeInvokeRequest{
+		ChaincodeID: lscc,
+		Fcn:         lsccInstalledPackage,
+		Args:        [][]byte{[]byte(name), []byte(version)},
+	}
+	return cir
+}
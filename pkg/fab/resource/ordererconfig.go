@@ -0,0 +1,163 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	"github.com/pkg/errors"
+)
+
+const etcdraftConsensusType = "etcdraft"
+
+// CreateAddConsortiumMemberConfigUpdate computes the config update which adds an organization to a consortium
+// in the given (current) config of the orderer system channel. orgGroup is the config group of the organization
+// (e.g. as printed by configtxgen -printOrg).
+func CreateAddConsortiumMemberConfigUpdate(config *cb.Config, channelID string, consortium string, org string, orgGroup *cb.ConfigGroup) ([]byte, error) {
+	if org == "" || orgGroup == nil {
+		return nil, errors.New("must specify the organization and its config group")
+	}
+
+	group, err := consortiumGroup(config, consortium)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := group.Groups[org]; ok {
+		return nil, errors.Errorf("org with name '%s' is already a member of consortium '%s'", org, consortium)
+	}
+
+	updated := proto.Clone(config).(*cb.Config)
+	updatedGroup, _ := consortiumGroup(updated, consortium)
+	if updatedGroup.Groups == nil {
+		updatedGroup.Groups = make(map[string]*cb.ConfigGroup)
+	}
+	updatedGroup.Groups[org] = orgGroup
+
+	return computeConfigUpdate(config, updated, channelID)
+}
+
+// CreateRemoveConsortiumMemberConfigUpdate computes the config update which removes an organization from
+// a consortium in the given (current) config of the orderer system channel
+func CreateRemoveConsortiumMemberConfigUpdate(config *cb.Config, channelID string, consortium string, org string) ([]byte, error) {
+	group, err := consortiumGroup(config, consortium)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := group.Groups[org]; !ok {
+		return nil, errors.Errorf("org with name '%s' is not a member of consortium '%s'", org, consortium)
+	}
+
+	updated := proto.Clone(config).(*cb.Config)
+	updatedGroup, _ := consortiumGroup(updated, consortium)
+	delete(updatedGroup.Groups, org)
+
+	return computeConfigUpdate(config, updated, channelID)
+}
+
+// CreateOrdererAddressesConfigUpdate computes the config update which replaces the orderer addresses
+// (host:port) in the given (current) channel config
+func CreateOrdererAddressesConfigUpdate(config *cb.Config, channelID string, addresses []string) ([]byte, error) {
+	if len(addresses) == 0 {
+		return nil, errors.New("must specify at least one orderer address")
+	}
+	if config == nil || config.ChannelGroup == nil {
+		return nil, errors.New("channel config is required")
+	}
+
+	updated := proto.Clone(config).(*cb.Config)
+	if err := setConfigValue(updated.ChannelGroup, channelconfig.OrdererAddressesKey, &cb.OrdererAddresses{Addresses: addresses}); err != nil {
+		return nil, err
+	}
+
+	return computeConfigUpdate(config, updated, channelID)
+}
+
+// Consenters returns the etcdraft consenters of the given channel config. An error is returned if
+// the consensus type of the channel is not etcdraft.
+func Consenters(config *cb.Config) ([]*etcdraft.Consenter, error) {
+	_, metadata, err := etcdraftConsensus(config)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.Consenters, nil
+}
+
+// CreateConsentersConfigUpdate computes the config update which replaces the etcdraft consenters
+// in the given (current) channel config. Other etcdraft options are unchanged.
+func CreateConsentersConfigUpdate(config *cb.Config, channelID string, consenters []*etcdraft.Consenter) ([]byte, error) {
+	if len(consenters) == 0 {
+		return nil, errors.New("must specify at least one consenter")
+	}
+
+	consensusType, metadata, err := etcdraftConsensus(config)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata.Consenters = consenters
+	consensusType.Metadata, err = proto.Marshal(metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal etcdraft metadata failed")
+	}
+
+	updated := proto.Clone(config).(*cb.Config)
+	ordererGroup := updated.ChannelGroup.Groups[channelconfig.OrdererGroupKey]
+	if err := setConfigValue(ordererGroup, channelconfig.ConsensusTypeKey, consensusType); err != nil {
+		return nil, err
+	}
+
+	return computeConfigUpdate(config, updated, channelID)
+}
+
+func consortiumGroup(config *cb.Config, consortium string) (*cb.ConfigGroup, error) {
+	if config == nil || config.ChannelGroup == nil {
+		return nil, errors.New("channel config is required")
+	}
+
+	consortiums, ok := config.ChannelGroup.Groups[channelconfig.ConsortiumsGroupKey]
+	if !ok {
+		return nil, errors.New("config has no consortiums group (not the orderer system channel)")
+	}
+	group, ok := consortiums.Groups[consortium]
+	if !ok {
+		return nil, errors.Errorf("consortium with name '%s' does not exist in config", consortium)
+	}
+	return group, nil
+}
+
+// etcdraftConsensus returns the consensus type of the channel config and its etcdraft metadata
+func etcdraftConsensus(config *cb.Config) (*ab.ConsensusType, *etcdraft.ConfigMetadata, error) {
+	if config == nil || config.ChannelGroup == nil {
+		return nil, nil, errors.New("channel config is required")
+	}
+
+	ordererGroup, ok := config.ChannelGroup.Groups[channelconfig.OrdererGroupKey]
+	if !ok {
+		return nil, nil, errors.New("config has no orderer group")
+	}
+	value, ok := ordererGroup.Values[channelconfig.ConsensusTypeKey]
+	if !ok {
+		return nil, nil, errors.New("config has no consensus type")
+	}
+
+	consensusType := &ab.ConsensusType{}
+	if err := proto.Unmarshal(value.Value, consensusType); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal consensus type failed")
+	}
+	if consensusType.Type != etcdraftConsensusType {
+		return nil, nil, errors.Errorf("consensus type is '%s', not %s", consensusType.Type, etcdraftConsensusType)
+	}
+
+	metadata := &etcdraft.ConfigMetadata{}
+	if err := proto.Unmarshal(consensusType.Metadata, metadata); err != nil {
+		return nil, nil, errors.Wrap(err, "unmarshal etcdraft metadata failed")
+	}
+	return consensusType, metadata, nil
+}
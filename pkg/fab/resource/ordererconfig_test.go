@@ -0,0 +1,150 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package resource
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsortiumMemberConfigUpdates(t *testing.T) {
+	config := systemChannelConfig()
+	org2 := &cb.ConfigGroup{
+		Values:    map[string]*cb.ConfigValue{channelconfig.MSPKey: {Value: []byte("msp"), ModPolicy: channelconfig.AdminsPolicyKey}},
+		ModPolicy: channelconfig.AdminsPolicyKey,
+	}
+
+	data, err := CreateAddConsortiumMemberConfigUpdate(config, "system-channel", "SampleConsortium", "Org2", org2)
+	require.NoError(t, err)
+	configUpdate := unmarshalConfigUpdate(t, data)
+	require.Equal(t, "system-channel", configUpdate.ChannelId)
+	consortium := configUpdate.WriteSet.Groups[channelconfig.ConsortiumsGroupKey].Groups["SampleConsortium"]
+	require.Equal(t, uint64(1), consortium.Version)
+	require.Contains(t, consortium.Groups, "Org1")
+	require.Contains(t, consortium.Groups, "Org2")
+
+	_, err = CreateAddConsortiumMemberConfigUpdate(config, "system-channel", "SampleConsortium", "Org1", org2)
+	require.Error(t, err, "org is already a member")
+	_, err = CreateAddConsortiumMemberConfigUpdate(config, "system-channel", "OtherConsortium", "Org2", org2)
+	require.Error(t, err, "consortium does not exist")
+	_, err = CreateAddConsortiumMemberConfigUpdate(config, "system-channel", "SampleConsortium", "Org2", nil)
+	require.Error(t, err, "org group is required")
+
+	data, err = CreateRemoveConsortiumMemberConfigUpdate(config, "system-channel", "SampleConsortium", "Org1")
+	require.NoError(t, err)
+	consortium = unmarshalConfigUpdate(t, data).WriteSet.Groups[channelconfig.ConsortiumsGroupKey].Groups["SampleConsortium"]
+	require.Equal(t, uint64(1), consortium.Version)
+	require.Empty(t, consortium.Groups)
+
+	_, err = CreateRemoveConsortiumMemberConfigUpdate(config, "system-channel", "SampleConsortium", "Org2")
+	require.Error(t, err, "org is not a member")
+
+	delete(config.ChannelGroup.Groups, channelconfig.ConsortiumsGroupKey)
+	_, err = CreateRemoveConsortiumMemberConfigUpdate(config, "mychannel", "SampleConsortium", "Org1")
+	require.Error(t, err, "not the system channel")
+}
+
+func TestCreateOrdererAddressesConfigUpdate(t *testing.T) {
+	config := systemChannelConfig()
+
+	data, err := CreateOrdererAddressesConfigUpdate(config, "system-channel", []string{"orderer.example.com:7050", "orderer2.example.com:7050"})
+	require.NoError(t, err)
+	value := unmarshalConfigUpdate(t, data).WriteSet.Values[channelconfig.OrdererAddressesKey]
+	require.NotNil(t, value)
+	require.Equal(t, uint64(1), value.Version)
+	addresses := &cb.OrdererAddresses{}
+	require.NoError(t, proto.Unmarshal(value.Value, addresses))
+	require.Equal(t, []string{"orderer.example.com:7050", "orderer2.example.com:7050"}, addresses.Addresses)
+
+	_, err = CreateOrdererAddressesConfigUpdate(config, "system-channel", []string{"orderer.example.com:7050"})
+	require.Error(t, err, "no differences")
+	_, err = CreateOrdererAddressesConfigUpdate(config, "system-channel", nil)
+	require.Error(t, err, "addresses are required")
+}
+
+func TestCreateConsentersConfigUpdate(t *testing.T) {
+	config := systemChannelConfig()
+
+	consenters, err := Consenters(config)
+	require.NoError(t, err)
+	require.Len(t, consenters, 1)
+
+	consenters = append(consenters, &etcdraft.Consenter{Host: "orderer2.example.com", Port: 7050, ClientTlsCert: []byte("client"), ServerTlsCert: []byte("server")})
+	data, err := CreateConsentersConfigUpdate(config, "system-channel", consenters)
+	require.NoError(t, err)
+
+	value := unmarshalConfigUpdate(t, data).WriteSet.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey]
+	require.NotNil(t, value)
+	require.Equal(t, uint64(1), value.Version)
+	consensusType := &ab.ConsensusType{}
+	require.NoError(t, proto.Unmarshal(value.Value, consensusType))
+	require.Equal(t, "etcdraft", consensusType.Type)
+	metadata := &etcdraft.ConfigMetadata{}
+	require.NoError(t, proto.Unmarshal(consensusType.Metadata, metadata))
+	require.Len(t, metadata.Consenters, 2)
+	require.Equal(t, "500ms", metadata.Options.TickInterval)
+
+	_, err = CreateConsentersConfigUpdate(config, "system-channel", nil)
+	require.Error(t, err, "consenters are required")
+
+	config.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey].Value = protoutil.MarshalOrPanic(&ab.ConsensusType{Type: "solo"})
+	_, err = Consenters(config)
+	require.Error(t, err, "consensus type is not etcdraft")
+	_, err = CreateConsentersConfigUpdate(config, "system-channel", consenters)
+	require.Error(t, err, "consensus type is not etcdraft")
+}
+
+// systemChannelConfig returns a minimal system channel config with an etcdraft orderer and one consortium
+func systemChannelConfig() *cb.Config {
+	metadata := &etcdraft.ConfigMetadata{
+		Consenters: []*etcdraft.Consenter{{Host: "orderer.example.com", Port: 7050, ClientTlsCert: []byte("client"), ServerTlsCert: []byte("server")}},
+		Options:    &etcdraft.Options{TickInterval: "500ms"},
+	}
+	return &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				channelconfig.OrdererGroupKey: {
+					Values: map[string]*cb.ConfigValue{
+						channelconfig.ConsensusTypeKey: {
+							Value:     protoutil.MarshalOrPanic(&ab.ConsensusType{Type: "etcdraft", Metadata: protoutil.MarshalOrPanic(metadata)}),
+							ModPolicy: channelconfig.AdminsPolicyKey,
+						},
+					},
+					ModPolicy: channelconfig.AdminsPolicyKey,
+				},
+				channelconfig.ConsortiumsGroupKey: {
+					Groups: map[string]*cb.ConfigGroup{
+						"SampleConsortium": {
+							Groups:    map[string]*cb.ConfigGroup{"Org1": {ModPolicy: channelconfig.AdminsPolicyKey}},
+							ModPolicy: "/Channel/Orderer/Admins",
+						},
+					},
+					ModPolicy: "/Channel/Orderer/Admins",
+				},
+			},
+			Values: map[string]*cb.ConfigValue{
+				channelconfig.OrdererAddressesKey: {
+					Value:     protoutil.MarshalOrPanic(&cb.OrdererAddresses{Addresses: []string{"orderer.example.com:7050"}}),
+					ModPolicy: "/Channel/Orderer/Admins",
+				},
+			},
+		},
+	}
+}
+
+func unmarshalConfigUpdate(t *testing.T, data []byte) *cb.ConfigUpdate {
+	configUpdate := &cb.ConfigUpdate{}
+	require.NoError(t, proto.Unmarshal(data, configUpdate))
+	return configUpdate
+}
@@ -315,6 +315,28 @@ func QueryInstalledChaincodes(reqCtx reqContext.Context, peer fab.ProposalProces
 	return response, nil
 }
 
+// QueryInstalledChaincodePackage queries the package of a chaincode installed on a peer.
+// Returns the package bytes as stored by the peer.
+func QueryInstalledChaincodePackage(reqCtx reqContext.Context, peer fab.ProposalProcessor, name, version string, opts ...Opt) ([]byte, error) {
+
+	if peer == nil {
+		return nil, errors.New("peer required")
+	}
+	if name == "" || version == "" {
+		return nil, errors.New("chaincode name and version required")
+	}
+
+	optionsValue := getOpts(opts...)
+
+	cir := createInstalledChaincodePackageInvokeRequest(name, version)
+	payload, err := queryChaincodeWithTarget(reqCtx, cir, peer, optionsValue)
+	if err != nil {
+		return nil, errors.WithMessage(err, "lscc.getinstalledccpackage failed")
+	}
+
+	return payload, nil
+}
+
 // InstallChaincode sends an install proposal to one or more endorsing peers.
 func InstallChaincode(reqCtx reqContext.Context, req InstallChaincodeRequest, targets []fab.ProposalProcessor, opts ...Opt) ([]*fab.TransactionProposalResponse, fab.TransactionID, error) {
 
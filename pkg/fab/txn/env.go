@@ -78,7 +78,11 @@ func NewHeader(ctx contextApi.Client, channelID string, opts ...fab.TxnHeaderOpt
 		}
 	}
 
-	ho := cryptosuite.GetSHA256Opts() // TODO: make configurable
+	ho, err := cryptosuite.GetHashOpts(options.HashingAlgorithm)
+	if err != nil {
+		return nil, errors.WithMessage(err, "hash options creation failed")
+	}
+
 	h, err := ctx.CryptoSuite().GetHash(ho)
 	if err != nil {
 		return nil, errors.WithMessage(err, "hash function creation failed")
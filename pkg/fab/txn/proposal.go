@@ -9,6 +9,7 @@ package txn
 import (
 	reqContext "context"
 	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
@@ -17,7 +18,9 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
@@ -117,9 +120,19 @@ func SendProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal,
 		go func(processor fab.ProposalProcessor) {
 			defer wg.Done()
 
+			target := "unknown"
+			span, spanCtx := tracing.StartSpan(reqCtx, tracing.SpanEndorsement)
+			if peer, ok := processor.(fab.Peer); ok {
+				target = peer.URL()
+				span.SetTag(tracing.TagEndpoint, target)
+			}
+
 			// TODO: The RPC should be timed-out.
 			//resp, err := processor.ProcessTransactionProposal(context.NewRequestOLD(ctx), request)
-			resp, err := processor.ProcessTransactionProposal(reqCtx, request)
+			startTime := time.Now()
+			resp, err := processor.ProcessTransactionProposal(spanCtx, request)
+			ctx.GetMetrics().EndorsementDuration.With("peer", endpoint.ToAddress(target), "status", metricStatus(err)).Observe(time.Since(startTime).Seconds())
+			tracing.Finish(span, err)
 			if err != nil {
 				logger.Debugf("Received error response from txn proposal processing: %s", err)
 				responseMtx.Lock()
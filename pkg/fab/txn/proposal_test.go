@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 package txn
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"reflect"
 	"strings"
@@ -20,12 +22,15 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	mock_context "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockfab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/gm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/tjfoc/gmsm/sm3"
 )
 
 const (
@@ -55,6 +60,39 @@ func TestNewHeader(t *testing.T) {
 	require.NotEmpty(t, txh.id)
 }
 
+func TestNewHeaderHashingAlgorithm(t *testing.T) {
+	suite, err := gm.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := &cryptoSuiteContext{MockContext: mocks.NewMockContext(user), cryptoSuite: suite}
+
+	creator := []byte("creator")
+	nonce := []byte("123456")
+
+	txh, err := NewHeader(ctx, testChannel, fab.WithCreator(creator), fab.WithNonce(nonce))
+	require.NoError(t, err)
+	sha256Digest := sha256.Sum256(append(nonce, creator...))
+	assert.Equal(t, hex.EncodeToString(sha256Digest[:]), string(txh.TransactionID()))
+
+	txh, err = NewHeader(ctx, testChannel, fab.WithCreator(creator), fab.WithNonce(nonce), fab.WithHashingAlgorithm("SM3"))
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(sm3.Sm3Sum(append(nonce, creator...))), string(txh.TransactionID()))
+
+	_, err = NewHeader(ctx, testChannel, fab.WithHashingAlgorithm("MD5"))
+	assert.Error(t, err)
+}
+
+// cryptoSuiteContext overrides the crypto suite of the mock context
+type cryptoSuiteContext struct {
+	*mocks.MockContext
+	cryptoSuite core.CryptoSuite
+}
+
+func (c *cryptoSuiteContext) CryptoSuite() core.CryptoSuite {
+	return c.cryptoSuite
+}
+
 func TestNewTransactionProposal(t *testing.T) {
 	user := mspmocks.NewMockSigningIdentity("test", "1234")
 	ctx := mocks.NewMockContext(user)
@@ -10,6 +10,7 @@ package txn
 import (
 	reqContext "context"
 	"math/rand"
+	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
 	"github.com/pkg/errors"
@@ -18,7 +19,9 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	ctxprovider "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
@@ -133,7 +136,12 @@ func Send(reqCtx reqContext.Context, tx *fab.Transaction, orderers []fab.Orderer
 	// create the payload
 	payload := common.Payload{Header: hdr, Data: txBytes}
 
-	transactionResponse, err := BroadcastPayload(reqCtx, &payload, orderers)
+	span, spanCtx := tracing.StartSpan(reqCtx, tracing.SpanBroadcast)
+	transactionResponse, err := BroadcastPayload(spanCtx, &payload, orderers)
+	if transactionResponse != nil {
+		span.SetTag(tracing.TagEndpoint, transactionResponse.Orderer)
+	}
+	tracing.Finish(span, err)
 	if err != nil {
 		return nil, err
 	}
@@ -200,7 +208,10 @@ func sendBroadcast(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orde
 	defer cancel()
 
 	// Send request
-	if _, err := orderer.SendBroadcast(childCtx, envelope); err != nil {
+	startTime := time.Now()
+	_, err := orderer.SendBroadcast(childCtx, envelope)
+	client.GetMetrics().BroadcastDuration.With("orderer", endpoint.ToAddress(orderer.URL()), "status", metricStatus(err)).Observe(time.Since(startTime).Seconds())
+	if err != nil {
 		logger.Debugf("Receive Error Response from orderer: %s\n", err)
 		return nil, errors.Wrapf(err, "calling orderer '%s' failed", orderer.URL())
 	}
@@ -275,3 +286,11 @@ read:
 
 	return block, err.ToError()
 }
+
+// metricStatus returns the value of the "status" metrics label for the given error
+func metricStatus(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
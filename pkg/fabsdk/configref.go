@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	tls "github.com/tjfoc/gmtls"
+)
+
+// reloadableConfigs holds the configuration which may be replaced by ReloadConfig
+type reloadableConfigs struct {
+	configBackend  []core.ConfigBackend
+	endpointConfig fab.EndpointConfig
+	identityConfig msp.IdentityConfig
+}
+
+// configRef holds a reference to the current reloadable configuration. The endpoint and
+// identity configs handed to the providers delegate to this reference so that all providers
+// see the new configuration as soon as it is swapped.
+type configRef struct {
+	value atomic.Value
+}
+
+func newConfigRef(cfg *reloadableConfigs) *configRef {
+	ref := &configRef{}
+	ref.store(cfg)
+	return ref
+}
+
+func (r *configRef) load() *reloadableConfigs {
+	return r.value.Load().(*reloadableConfigs)
+}
+
+func (r *configRef) store(cfg *reloadableConfigs) {
+	r.value.Store(cfg)
+}
+
+// endpointConfigRef implements fab.EndpointConfig by delegating to the current endpoint config
+type endpointConfigRef struct {
+	ref *configRef
+}
+
+func (c *endpointConfigRef) config() fab.EndpointConfig {
+	return c.ref.load().endpointConfig
+}
+
+// Timeout reads timeouts for the given timeout type
+func (c *endpointConfigRef) Timeout(tType fab.TimeoutType) time.Duration {
+	return c.config().Timeout(tType)
+}
+
+// OrderersConfig returns a list of defined orderers
+func (c *endpointConfigRef) OrderersConfig() []fab.OrdererConfig {
+	return c.config().OrderersConfig()
+}
+
+// OrdererConfig returns the requested orderer
+func (c *endpointConfigRef) OrdererConfig(nameOrURL string) (*fab.OrdererConfig, bool) {
+	return c.config().OrdererConfig(nameOrURL)
+}
+
+// PeersConfig Retrieves the fabric peers for the specified org from the config
+func (c *endpointConfigRef) PeersConfig(org string) ([]fab.PeerConfig, bool) {
+	return c.config().PeersConfig(org)
+}
+
+// PeerConfig Retrieves a specific peer from the configuration by name or url
+func (c *endpointConfigRef) PeerConfig(nameOrURL string) (*fab.PeerConfig, bool) {
+	return c.config().PeerConfig(nameOrURL)
+}
+
+// NetworkConfig returns the network configuration defined in the config
+func (c *endpointConfigRef) NetworkConfig() *fab.NetworkConfig {
+	return c.config().NetworkConfig()
+}
+
+// NetworkPeers returns the network peers configuration, all the peers from all the orgs in config
+func (c *endpointConfigRef) NetworkPeers() []fab.NetworkPeer {
+	return c.config().NetworkPeers()
+}
+
+// ChannelConfig returns the channel configuration
+func (c *endpointConfigRef) ChannelConfig(name string) *fab.ChannelEndpointConfig {
+	return c.config().ChannelConfig(name)
+}
+
+// ChannelPeers returns the channel peers configuration
+func (c *endpointConfigRef) ChannelPeers(name string) []fab.ChannelPeer {
+	return c.config().ChannelPeers(name)
+}
+
+// ChannelOrderers returns a list of channel orderers
+func (c *endpointConfigRef) ChannelOrderers(name string) []fab.OrdererConfig {
+	return c.config().ChannelOrderers(name)
+}
+
+// TLSCACertPool returns the configured cert pool
+func (c *endpointConfigRef) TLSCACertPool() fab.CertPool {
+	return c.config().TLSCACertPool()
+}
+
+// TLSClientCerts loads the client's certs for mutual TLS
+func (c *endpointConfigRef) TLSClientCerts() []tls.Certificate {
+	return c.config().TLSClientCerts()
+}
+
+// CryptoConfigPath returns the cryptoConfigPath
+func (c *endpointConfigRef) CryptoConfigPath() string {
+	return c.config().CryptoConfigPath()
+}
+
+// identityConfigRef implements msp.IdentityConfig by delegating to the current identity config
+type identityConfigRef struct {
+	ref *configRef
+}
+
+func (c *identityConfigRef) config() msp.IdentityConfig {
+	return c.ref.load().identityConfig
+}
+
+// Client returns the Client config
+func (c *identityConfigRef) Client() *msp.ClientConfig {
+	return c.config().Client()
+}
+
+// CAConfig returns the CA configuration.
+func (c *identityConfigRef) CAConfig(org string) (*msp.CAConfig, bool) {
+	return c.config().CAConfig(org)
+}
+
+// CAServerCerts returns the certificates of the CA servers of the given org
+func (c *identityConfigRef) CAServerCerts(org string) ([][]byte, bool) {
+	return c.config().CAServerCerts(org)
+}
+
+// CAClientKey returns the client key of the CA of the given org
+func (c *identityConfigRef) CAClientKey(org string) ([]byte, bool) {
+	return c.config().CAClientKey(org)
+}
+
+// CAClientCert returns the client cert of the CA of the given org
+func (c *identityConfigRef) CAClientCert(org string) ([]byte, bool) {
+	return c.config().CAClientCert(org)
+}
+
+// CAKeyStorePath returns the same path as KeyStorePath() without the
+// 'keystore' directory added.
+func (c *identityConfigRef) CAKeyStorePath() string {
+	return c.config().CAKeyStorePath()
+}
+
+// CredentialStorePath returns the user store path
+func (c *identityConfigRef) CredentialStorePath() string {
+	return c.config().CredentialStorePath()
+}
@@ -9,6 +9,7 @@ package fabsdk
 
 import (
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/operations"
@@ -18,16 +19,19 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
 	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	sdkApi "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	metricsCfg "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics/cfg"
 	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
 )
 
 var logger = logging.NewLogger("fabsdk")
@@ -38,7 +42,10 @@ type FabricSDK struct {
 	provider      *context.Provider
 	cryptoSuite   core.CryptoSuite
 	system        *operations.System
+	ownsSystem    bool
 	clientMetrics *metrics.ClientMetrics
+	config        *configRef
+	reloadLock    sync.Mutex
 }
 
 type configs struct {
@@ -59,6 +66,7 @@ type options struct {
 	ConfigBackend     []core.ConfigBackend
 	ProviderOpts      []coptions.Opt // Provider options are passed along to the various providers
 	metricsConfig     metricsCfg.MetricsConfig
+	Tracer            tracing.Tracer
 }
 
 // Option configures the SDK.
@@ -72,6 +80,10 @@ type contextCloseable interface {
 	CloseContext(ctxt fab.ClientContext)
 }
 
+type configUpdateListener interface {
+	ConfigUpdated()
+}
+
 // New initializes the SDK based on the set of options provided.
 // ConfigOptions provides the application configuration.
 func New(configProvider core.ConfigProvider, opts ...Option) (*FabricSDK, error) {
@@ -207,6 +219,16 @@ func WithMetricsConfig(metricsConfigs ...interface{}) Option {
 	}
 }
 
+// WithTracer injects a Tracer into the SDK. Spans are created for the steps of
+// each channel client request and the trace context is propagated to peers and
+// orderers as gRPC metadata. If not provided then a no-op tracer is used.
+func WithTracer(tracer tracing.Tracer) Option {
+	return func(opts *options) error {
+		opts.Tracer = tracer
+		return nil
+	}
+}
+
 // WithProviderOpts adds options which are propagated to the various providers.
 func WithProviderOpts(sopts ...coptions.Opt) Option {
 	return func(opts *options) error {
@@ -222,6 +244,27 @@ func WithErrorHandler(value fab.ErrorHandler) Option {
 	return WithProviderOpts(withErrorHandlerProviderOpt(value))
 }
 
+// WithDialOptions adds gRPC dial options which are applied to every peer, orderer, discovery and
+// deliver connection created by the SDK. Interceptors may be added using comm.UnaryInterceptors and
+// comm.StreamInterceptors (from package fab/comm), which are chained with the SDK's own interceptors.
+func WithDialOptions(dialOpts ...grpc.DialOption) Option {
+	return WithProviderOpts(withDialOptionsProviderOpt(dialOpts))
+}
+
+// WithGRPCInterceptors adds unary and stream client interceptors to every connection created by the SDK.
+// The interceptors are invoked in the order provided, after the SDK's own interceptors.
+func WithGRPCInterceptors(unary []grpc.UnaryClientInterceptor, stream []grpc.StreamClientInterceptor) Option {
+	return WithDialOptions(comm.UnaryInterceptors(unary...), comm.StreamInterceptors(stream...))
+}
+
+// WithDialOptionSet registers a named set of gRPC dial options (which may include interceptors). The set
+// is applied to the connections of the endpoints which list its name under the "dial-option-sets" key of
+// their grpcOptions in the config. Since entityMatchers copy the grpcOptions of the mapped host, a set
+// may also be applied to a group of endpoints using entityMatchers.
+func WithDialOptionSet(name string, dialOpts ...grpc.DialOption) Option {
+	return WithProviderOpts(withDialOptionSetProviderOpt(name, dialOpts))
+}
+
 // providerInit interface allows for initializing providers
 // TODO: minimize interface
 type providerInit interface {
@@ -248,6 +291,16 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		return errors.WithMessage(err, "failed to initialize configuration")
 	}
 
+	// The providers are given references to the endpoint and identity configs so that
+	// they pick up the new configuration after a call to ReloadConfig
+	sdk.config = newConfigRef(&reloadableConfigs{
+		configBackend:  sdk.opts.ConfigBackend,
+		endpointConfig: cfg.endpointConfig,
+		identityConfig: cfg.identityConfig,
+	})
+	cfg.endpointConfig = &endpointConfigRef{ref: sdk.config}
+	cfg.identityConfig = &identityConfigRef{ref: sdk.config}
+
 	// Initialize rand (TODO: should probably be optional)
 	rand.Seed(time.Now().UnixNano())
 
@@ -275,6 +328,8 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		return errors.WithMessage(err, "failed to create infra provider")
 	}
 
+	coptions.Apply(infraProvider, sdk.opts.ProviderOpts)
+
 	// Initialize local discovery provider
 	localDiscoveryProvider, err := sdk.opts.Service.CreateLocalDiscoveryProvider(cfg.endpointConfig)
 	if err != nil {
@@ -300,6 +355,7 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		context.WithInfraProvider(infraProvider),
 		context.WithChannelProvider(channelProvider),
 		context.WithClientMetrics(sdk.clientMetrics),
+		context.WithTracer(sdk.opts.Tracer),
 	)
 
 	//initialize
@@ -338,6 +394,7 @@ func (sdk *FabricSDK) Close() {
 		pvdr.Close()
 	}
 	sdk.provider.InfraProvider().Close()
+	sdk.closeMetrics()
 }
 
 // CloseContext frees up caches being maintained by the SDK for the given context
@@ -353,12 +410,74 @@ func (sdk *FabricSDK) CloseContext(ctxt fab.ClientContext) {
 	}
 }
 
+// ReloadConfig reloads the endpoint and identity configuration from the given config provider and
+// applies it to the SDK without a restart. The new configuration is validated before it is applied;
+// if it is invalid then an error is returned and the current configuration is retained.
+//
+// Once the configuration has been swapped, cached connections are evicted and the channel services
+// (channel config, membership, discovery and selection) are recreated on next use. Event services are
+// retained so that existing event registrations are not lost; event clients use the new configuration
+// when they next reconnect.
+//
+// The crypto suite, metrics and client organization may not be changed by a reload. Configs which were
+// passed in using WithEndpointConfig or WithIdentityConfig continue to take priority.
+func (sdk *FabricSDK) ReloadConfig(configProvider core.ConfigProvider) error {
+	if configProvider == nil {
+		return errors.New("config provider is nil")
+	}
+
+	sdk.reloadLock.Lock()
+	defer sdk.reloadLock.Unlock()
+
+	configBackend, err := configProvider()
+	if err != nil {
+		return errors.WithMessage(err, "unable to load config backend")
+	}
+
+	endpointConfig, err := sdk.loadEndpointConfig(configBackend...)
+	if err != nil {
+		return errors.WithMessage(err, "unable to load endpoint config")
+	}
+
+	identityConfig, err := sdk.loadIdentityConfig(configBackend...)
+	if err != nil {
+		return errors.WithMessage(err, "unable to load identity config")
+	}
+
+	current := sdk.config.load()
+	if identityConfig.Client().Organization != current.identityConfig.Client().Organization {
+		return errors.Errorf("client organization may not be changed from [%s] to [%s] without a restart",
+			current.identityConfig.Client().Organization, identityConfig.Client().Organization)
+	}
+
+	sdk.config.store(&reloadableConfigs{
+		configBackend:  configBackend,
+		endpointConfig: endpointConfig,
+		identityConfig: identityConfig,
+	})
+
+	logger.Debug("SDK configuration reloaded - notifying providers...")
+
+	if pvdr, ok := sdk.provider.InfraProvider().(configUpdateListener); ok {
+		pvdr.ConfigUpdated()
+	}
+	if pvdr, ok := sdk.provider.LocalDiscoveryProvider().(configUpdateListener); ok {
+		pvdr.ConfigUpdated()
+	}
+	if pvdr, ok := sdk.provider.ChannelProvider().(configUpdateListener); ok {
+		pvdr.ConfigUpdated()
+	}
+
+	return nil
+}
+
 //Config returns config backend used by all SDK config types
 func (sdk *FabricSDK) Config() (core.ConfigBackend, error) {
-	if sdk.opts.ConfigBackend == nil {
+	configBackend := sdk.config.load().configBackend
+	if configBackend == nil {
 		return nil, errors.New("unable to find config backend")
 	}
-	return lookup.New(sdk.opts.ConfigBackend...), nil
+	return lookup.New(configBackend...), nil
 }
 
 //Context creates and returns context client which has all the necessary providers
@@ -567,3 +686,29 @@ func withErrorHandlerProviderOpt(value fab.ErrorHandler) coptions.Opt {
 type errHandlerSetter interface {
 	SetErrorHandler(value fab.ErrorHandler)
 }
+
+func withDialOptionsProviderOpt(dialOpts []grpc.DialOption) coptions.Opt {
+	return func(p coptions.Params) {
+		if setter, ok := p.(dialOptionsSetter); ok {
+			logger.Debugf("... setting dial options")
+			setter.SetDialOptions(dialOpts...)
+		}
+	}
+}
+
+type dialOptionsSetter interface {
+	SetDialOptions(dialOpts ...grpc.DialOption)
+}
+
+func withDialOptionSetProviderOpt(name string, dialOpts []grpc.DialOption) coptions.Opt {
+	return func(p coptions.Params) {
+		if setter, ok := p.(dialOptionSetSetter); ok {
+			logger.Debugf("... setting dial option set [%s]", name)
+			setter.SetDialOptionSet(name, dialOpts...)
+		}
+	}
+}
+
+type dialOptionSetSetter interface {
+	SetDialOptionSet(name string, dialOpts ...grpc.DialOption)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/operations"
+	flogging "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/sdkpatch/logbridge"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
+)
+
+const (
+	metricsProviderPrometheus = "prometheus"
+	metricsProviderStatsd     = "statsd"
+)
+
+// The Prometheus provider registers its metrics with the global Prometheus registry, which doesn't
+// allow the same metric to be registered more than once. So a single operations system (and set of
+// client metrics) is shared by all SDK instances that use Prometheus. The system is stopped when the
+// last of these SDK instances is closed; the client metrics stay registered and are reused.
+var prometheusMetrics struct {
+	sync.Mutex
+	system        *operations.System
+	clientMetrics *metrics.ClientMetrics
+	refs          int
+}
+
+// initMetrics initializes the SDK's operations system and client metrics according to the
+// metrics provider in the config (prometheus, statsd or disabled).
+func (sdk *FabricSDK) initMetrics(configs *configs) {
+	if sdk.system != nil {
+		return
+	}
+
+	provider := ""
+	if configs != nil && configs.metricsConfig != nil {
+		provider = configs.metricsConfig.MetricCfg().Provider
+	}
+
+	switch provider {
+	case metricsProviderPrometheus:
+		prometheusMetrics.Lock()
+		defer prometheusMetrics.Unlock()
+
+		if prometheusMetrics.system == nil {
+			prometheusMetrics.system = newOperationsSystem(configs)
+			startOperationsSystem(prometheusMetrics.system)
+		}
+		if prometheusMetrics.clientMetrics == nil {
+			prometheusMetrics.clientMetrics = metrics.NewClientMetrics(prometheusMetrics.system.Provider)
+		}
+		prometheusMetrics.refs++
+		sdk.system = prometheusMetrics.system
+		sdk.clientMetrics = prometheusMetrics.clientMetrics
+	case metricsProviderStatsd:
+		sdk.system = newOperationsSystem(configs)
+		sdk.ownsSystem = true
+		startOperationsSystem(sdk.system)
+		sdk.clientMetrics = metrics.NewClientMetrics(sdk.system.Provider)
+	default:
+		logger.Debugf("Metrics are disabled")
+		sdk.clientMetrics = metrics.NewDisabledClientMetrics()
+	}
+}
+
+// closeMetrics stops the operations system if it's owned by this SDK instance, or releases the shared
+// Prometheus operations system (which is stopped once it's no longer used by any SDK instance)
+func (sdk *FabricSDK) closeMetrics() {
+	if sdk.system == nil {
+		return
+	}
+
+	system := sdk.system
+	sdk.system = nil
+
+	if !sdk.ownsSystem {
+		system = releasePrometheusSystem(system)
+		if system == nil {
+			return
+		}
+	}
+
+	if err := system.Stop(); err != nil {
+		logger.Warnf("Error stopping operations system: %s", err)
+	}
+}
+
+// releasePrometheusSystem releases a reference to the shared Prometheus operations system and returns
+// the system if it's no longer referenced (and must be stopped)
+func releasePrometheusSystem(system *operations.System) *operations.System {
+	prometheusMetrics.Lock()
+	defer prometheusMetrics.Unlock()
+
+	if system != prometheusMetrics.system {
+		return nil
+	}
+
+	prometheusMetrics.refs--
+	if prometheusMetrics.refs > 0 {
+		return nil
+	}
+
+	prometheusMetrics.system = nil
+	return system
+}
+
+// startOperationsSystem starts the operations system. A failure to start the operations HTTP endpoint
+// (e.g. because the listen address is in use) is not fatal since metrics are still collected.
+func startOperationsSystem(system *operations.System) {
+	if err := system.Start(); err != nil {
+		logger.Warnf("Operations system failed to start: %s", err)
+	}
+}
+
+func newOperationsSystem(configs *configs) *operations.System {
+	opsConfig := configs.metricsConfig.OperationCfg()
+	metricsConfig := configs.metricsConfig.MetricCfg()
+	return operations.NewSystem(operations.Options{
+		Logger:        flogging.MustGetLogger("operations.runner"),
+		ListenAddress: opsConfig.ListenAddress,
+		Metrics: operations.MetricsOptions{
+			Provider: metricsConfig.Provider,
+			Statsd: &operations.Statsd{
+				Network:       metricsConfig.Statsd.Network,
+				Address:       metricsConfig.Statsd.Address,
+				WriteInterval: metricsConfig.Statsd.WriteInterval,
+				Prefix:        metricsConfig.Statsd.Prefix,
+			},
+		},
+		TLS: operations.TLS{
+			Enabled:            opsConfig.TLSEnabled,
+			CertFile:           opsConfig.TLSCertFile,
+			KeyFile:            opsConfig.TLSKeyFile,
+			ClientCertRequired: opsConfig.ClientAuthRequired,
+			ClientCACertFiles:  opsConfig.ClientRootCAs,
+		},
+		Version: "latest", // TODO expose version somewhere, Fabric uses 'metadata.Version'
+	})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reloadConfigTemplate = `
+client:
+  organization: %s
+  peer:
+    timeout:
+      connection: %s
+organizations:
+  org1:
+    mspid: Org1MSP
+    cryptoPath: /tmp/msp/org1
+    peers:
+      - %s
+  org2:
+    mspid: Org2MSP
+    cryptoPath: /tmp/msp/org2
+peers:
+  peer0.org1.example.com:
+    url: grpc://peer0.org1.example.com:7051
+  peer1.org1.example.com:
+    url: grpc://peer1.org1.example.com:7051
+`
+
+func reloadConfigBytes(org, timeout string, peers ...string) []byte {
+	return []byte(fmt.Sprintf(reloadConfigTemplate, org, timeout, strings.Join(peers, "\n      - ")))
+}
+
+func TestReloadConfig(t *testing.T) {
+	sdk, err := New(configImpl.FromRaw(reloadConfigBytes("org1", "5s", "peer0.org1.example.com"), "yaml"))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	ctx, err := sdk.Context()()
+	require.NoError(t, err)
+
+	peers, ok := ctx.EndpointConfig().PeersConfig("org1")
+	require.True(t, ok)
+	assert.Len(t, peers, 1)
+	assert.Equal(t, 5*time.Second, ctx.EndpointConfig().Timeout(fab.PeerConnection))
+
+	err = sdk.ReloadConfig(configImpl.FromRaw(reloadConfigBytes("org1", "7s", "peer0.org1.example.com", "peer1.org1.example.com"), "yaml"))
+	require.NoError(t, err)
+
+	// Contexts created before the reload should see the new configuration
+	peers, ok = ctx.EndpointConfig().PeersConfig("org1")
+	require.True(t, ok)
+	assert.Len(t, peers, 2)
+	assert.Equal(t, 7*time.Second, ctx.EndpointConfig().Timeout(fab.PeerConnection))
+	assert.Equal(t, "org1", ctx.IdentityConfig().Client().Organization)
+
+	configBackend, err := sdk.Config()
+	require.NoError(t, err)
+	value, ok := configBackend.Lookup("client.peer.timeout.connection")
+	require.True(t, ok)
+	assert.Equal(t, "7s", value)
+}
+
+func TestReloadConfigInvalid(t *testing.T) {
+	sdk, err := New(configImpl.FromRaw(reloadConfigBytes("org1", "5s", "peer0.org1.example.com"), "yaml"))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	err = sdk.ReloadConfig(nil)
+	require.EqualError(t, err, "config provider is nil")
+
+	err = sdk.ReloadConfig(configImpl.FromFile("notarealfile"))
+	require.Error(t, err)
+
+	err = sdk.ReloadConfig(configImpl.FromRaw(reloadConfigBytes("org2", "7s", "peer0.org1.example.com"), "yaml"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "client organization may not be changed")
+
+	// The current configuration should be retained
+	ctx, err := sdk.Context()()
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, ctx.EndpointConfig().Timeout(fab.PeerConnection))
+	assert.Equal(t, "org1", ctx.IdentityConfig().Client().Organization)
+}
@@ -9,6 +9,7 @@ SPDX-License-Identifier: Apache-2.0
 package fabsdk
 
 import (
+	"net"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -29,8 +30,10 @@ import (
 	discmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/factory/defsvc"
+	metricsCfg "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics/cfg"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/chpvdr"
 	mockapisdk "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/test/mocksdkapi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabtest"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp"
 	"github.com/hyperledger/fabric-sdk-go/test/metadata"
 	"github.com/pkg/errors"
@@ -86,6 +89,50 @@ func TestDoubleClose(t *testing.T) {
 	sdk.Close()
 }
 
+func TestPrometheusMetricsClose(t *testing.T) {
+	network, err := fabtest.NewNetwork()
+	require.NoError(t, err)
+	defer network.Stop()
+
+	newSDK := func() *FabricSDK {
+		sdk, err := New(network.ConfigProvider(),
+			WithMetricsConfig(&prometheusOperationConfig{}, &prometheusMetricConfig{}))
+		require.NoError(t, err)
+		require.NotNil(t, sdk.system)
+		return sdk
+	}
+
+	sdk1 := newSDK()
+	sdk2 := newSDK()
+	require.True(t, sdk1.system == sdk2.system, "expecting the Prometheus operations system to be shared")
+	addr := sdk1.system.Addr()
+
+	sdk1.Close()
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err, "expecting operations system to be running while still in use")
+	conn.Close()
+
+	sdk2.Close()
+	_, err = net.Dial("tcp", addr)
+	assert.Error(t, err, "expecting operations system to be stopped")
+
+	// A new operations system is started for the next SDK
+	sdk3 := newSDK()
+	sdk3.Close()
+}
+
+type prometheusOperationConfig struct{}
+
+func (m *prometheusOperationConfig) OperationCfg() metricsCfg.OperationConfig {
+	return metricsCfg.OperationConfig{ListenAddress: "127.0.0.1:0"}
+}
+
+type prometheusMetricConfig struct{}
+
+func (m *prometheusMetricConfig) MetricCfg() metricsCfg.MetricConfig {
+	return metricsCfg.MetricConfig{Provider: metricsProviderPrometheus}
+}
+
 func TestWithCorePkg(t *testing.T) {
 	// Test New SDK with valid config file
 	configPath := filepath.Join(metadata.GetProjectPath(), metadata.SDKConfigPath, sdkConfigFile)
@@ -6,7 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 
 package metrics
 
-import "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/metrics"
+import (
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/metrics/disabled"
+)
 
 var (
 	// for now, only channel clients require metrics tracking. TODO: update to generalize metrics for other client types if needed.
@@ -66,6 +69,91 @@ var (
 		LabelNames:   []string{"chaincode", "Fcn"},
 		StatsdFormat: "%{#fqname}.%{type}.%{channel}.%{execution}",
 	}
+
+	eventConnectionState = metrics.GaugeOpts{
+		Namespace:    "eventservice",
+		Name:         "connection_state",
+		Help:         "The state of the event service connection (1 = connected, 0 = disconnected).",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	eventReconnects = metrics.CounterOpts{
+		Namespace:    "eventservice",
+		Name:         "reconnects",
+		Help:         "The number of times the event service has reconnected.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	eventBlockLag = metrics.GaugeOpts{
+		Namespace:    "eventservice",
+		Name:         "block_lag",
+		Help:         "The number of blocks by which the connected peer lags behind the peer with the highest block height.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	eventsDropped = metrics.CounterOpts{
+		Namespace:    "eventservice",
+		Name:         "events_dropped",
+		Help:         "The number of events that could not be delivered to a registered consumer.",
+		LabelNames:   []string{"channel", "event"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{event}",
+	}
+	connectionsOpen = metrics.GaugeOpts{
+		Namespace:    "comm",
+		Name:         "connections_open",
+		Help:         "The number of cached gRPC connections that are in use.",
+		StatsdFormat: "%{#fqname}",
+	}
+	connectionsIdle = metrics.GaugeOpts{
+		Namespace:    "comm",
+		Name:         "connections_idle",
+		Help:         "The number of cached gRPC connections that are idle.",
+		StatsdFormat: "%{#fqname}",
+	}
+	// The target, peer and orderer labels below hold endpoint addresses (host:port) so the number of series
+	// is bounded by the number of endpoints of the network. Error messages are never used as label values.
+	connectionDialDuration = metrics.HistogramOpts{
+		Namespace:    "comm",
+		Name:         "dial_duration",
+		Help:         "The time to establish a gRPC connection.",
+		LabelNames:   []string{"target"},
+		StatsdFormat: "%{#fqname}.%{target}",
+	}
+	selectionCacheHits = metrics.CounterOpts{
+		Namespace:    "selection",
+		Name:         "cache_hits",
+		Help:         "The number of endorser lookups that were served from the selection cache.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	selectionCacheMisses = metrics.CounterOpts{
+		Namespace:    "selection",
+		Name:         "cache_misses",
+		Help:         "The number of endorser lookups that required a query to the discovery service.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	selectionRefreshErrors = metrics.CounterOpts{
+		Namespace:    "selection",
+		Name:         "refresh_errors",
+		Help:         "The number of failed discovery queries when loading or refreshing the selection cache.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	endorsementDuration = metrics.HistogramOpts{
+		Namespace:    "peer",
+		Name:         "endorsement_duration",
+		Help:         "The time for a peer to endorse a proposal.",
+		LabelNames:   []string{"peer", "status"},
+		StatsdFormat: "%{#fqname}.%{peer}.%{status}",
+	}
+	broadcastDuration = metrics.HistogramOpts{
+		Namespace:    "orderer",
+		Name:         "broadcast_duration",
+		Help:         "The time for an orderer to accept a broadcast transaction.",
+		LabelNames:   []string{"orderer", "status"},
+		StatsdFormat: "%{#fqname}.%{orderer}.%{status}",
+	}
 )
 
 // ClientMetrics contains the metrics used in the (channel) client
@@ -78,6 +166,22 @@ type ClientMetrics struct {
 	ExecutionsFailed   metrics.Counter
 	ExecutionDuration  metrics.Histogram
 	ExecutionTimeouts  metrics.Counter
+
+	EventConnectionState metrics.Gauge
+	EventReconnects      metrics.Counter
+	EventBlockLag        metrics.Gauge
+	EventsDropped        metrics.Counter
+
+	ConnectionsOpen        metrics.Gauge
+	ConnectionsIdle        metrics.Gauge
+	ConnectionDialDuration metrics.Histogram
+
+	SelectionCacheHits     metrics.Counter
+	SelectionCacheMisses   metrics.Counter
+	SelectionRefreshErrors metrics.Counter
+
+	EndorsementDuration metrics.Histogram
+	BroadcastDuration   metrics.Histogram
 }
 
 // NewClientMetrics builds a new instance of ClientMetrics
@@ -91,5 +195,27 @@ func NewClientMetrics(p metrics.Provider) *ClientMetrics {
 		ExecutionsFailed:   p.NewCounter(executionsFailed),
 		ExecutionDuration:  p.NewHistogram(executionDuration),
 		ExecutionTimeouts:  p.NewCounter(executionTimeouts),
+
+		EventConnectionState: p.NewGauge(eventConnectionState),
+		EventReconnects:      p.NewCounter(eventReconnects),
+		EventBlockLag:        p.NewGauge(eventBlockLag),
+		EventsDropped:        p.NewCounter(eventsDropped),
+
+		ConnectionsOpen:        p.NewGauge(connectionsOpen),
+		ConnectionsIdle:        p.NewGauge(connectionsIdle),
+		ConnectionDialDuration: p.NewHistogram(connectionDialDuration),
+
+		SelectionCacheHits:     p.NewCounter(selectionCacheHits),
+		SelectionCacheMisses:   p.NewCounter(selectionCacheMisses),
+		SelectionRefreshErrors: p.NewCounter(selectionRefreshErrors),
+
+		EndorsementDuration: p.NewHistogram(endorsementDuration),
+		BroadcastDuration:   p.NewHistogram(broadcastDuration),
 	}
 }
+
+// NewDisabledClientMetrics returns ClientMetrics which don't record anything. They are used
+// when metrics are disabled.
+func NewDisabledClientMetrics() *ClientMetrics {
+	return NewClientMetrics(&disabled.Provider{})
+}
@@ -99,6 +99,7 @@ func (k *eventCacheKey) String() string {
 
 type params struct {
 	permitBlockEvents bool
+	blockVerification bool
 }
 
 func defaultParams() *params {
@@ -109,8 +110,13 @@ func (p *params) PermitBlockEvents() {
 	p.permitBlockEvents = true
 }
 
+func (p *params) EnableBlockVerification() {
+	p.blockVerification = true
+}
+
 func (p *params) getOptKey() string {
 	//	Construct opts portion
 	optKey := "blockEvents:" + strconv.FormatBool(p.permitBlockEvents)
+	optKey += ",blockVerification:" + strconv.FormatBool(p.blockVerification)
 	return optKey
 }
@@ -53,6 +53,15 @@ func (cp *ChannelProvider) Close() {
 	cp.ctxtCaches.Close()
 }
 
+// ConfigUpdated is invoked after the SDK configuration has been reloaded. The channel services of all
+// contexts are recreated using the updated endpoint configuration.
+func (cp *ChannelProvider) ConfigUpdated() {
+	logger.Debug("Updating context caches after config update...")
+	cp.ctxtCaches.ForEach(func(value interface{}) {
+		value.(*contextCache).ConfigUpdated()
+	})
+}
+
 // CloseContext frees resources and caches for the given context.
 func (cp *ChannelProvider) CloseContext(ctx fab.ClientContext) {
 	key, err := newCtxtCacheKey(ctx)
@@ -172,6 +172,74 @@ func TestCloseContext(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestConfigUpdated(t *testing.T) {
+	testChannelCfg := mocks.NewMockChannelCfg("testchannel")
+	testChannelCfg.MockCapabilities[fab.ApplicationGroupKey][fab.V1_2Capability] = true
+
+	SetChannelConfig(chconfig.NewChannelCfg(""), testChannelCfg)
+
+	discClient := clientmocks.NewMockDiscoveryClient()
+	dynamicdiscovery.SetClientProvider(func(ctx context.Client) (dynamicdiscovery.DiscoveryClient, error) {
+		return discClient, nil
+	})
+
+	discClient.SetResponses(
+		&clientmocks.MockDiscoverEndpointResponse{
+			PeerEndpoints: []*discmocks.MockDiscoveryPeerEndpoint{},
+		},
+	)
+
+	channelProvider := getChannelProvider(t, mocks.NewMockProviderContext(),
+		dynamicdiscovery.WithRefreshInterval(5*time.Millisecond),
+	)
+	defer channelProvider.Close()
+
+	clientCtxt := newMockClientContext("user1", "org")
+
+	channelService, err := channelProvider.ChannelService(clientCtxt, "testchannel")
+	require.NoError(t, err)
+
+	discovery1, err := channelService.Discovery()
+	require.NoError(t, err)
+	selection1, err := channelService.Selection()
+	require.NoError(t, err)
+	eventService1, err := channelService.EventService()
+	require.NoError(t, err)
+
+	gracePeriod := retiredCachesGracePeriod
+	retiredCachesGracePeriod = func(fab.EndpointConfig) time.Duration { return 200 * time.Millisecond }
+	defer func() { retiredCachesGracePeriod = gracePeriod }()
+
+	channelProvider.ConfigUpdated()
+
+	// The old discovery service should remain open for in-flight users until the grace period expires
+	_, err = discovery1.GetPeers()
+	require.NoError(t, err)
+
+	time.Sleep(500 * time.Millisecond)
+
+	_, err = discovery1.GetPeers()
+	require.Error(t, err)
+	assert.Equal(t, "Discovery client has been closed", err.Error())
+
+	// New services should be provided by the existing channel service
+	discovery2, err := channelService.Discovery()
+	require.NoError(t, err)
+	assert.False(t, discovery1 == discovery2, "expecting a new discovery service after config update")
+	_, err = discovery2.GetPeers()
+	require.NoError(t, err)
+
+	selection2, err := channelService.Selection()
+	require.NoError(t, err)
+	assert.False(t, selection1 == selection2, "expecting a new selection service after config update")
+
+	// The event service should be retained
+	eventService2, err := channelService.EventService()
+	require.NoError(t, err)
+	assert.True(t, eventService1 == eventService2, "expecting the event service to be retained after config update")
+	assert.False(t, eventService2.(*EventClientRef).Closed())
+}
+
 func newMockClientContext(userID, mspID string) fab.ClientContext {
 	user := mspmocks.NewMockSigningIdentity(userID, mspID)
 	return &mockClientContext{
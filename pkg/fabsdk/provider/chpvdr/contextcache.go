@@ -7,14 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package chpvdr
 
 import (
+	"sync"
+	"time"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/dynamicdiscovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/staticdiscovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/fabricselection"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/staticselection"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel/membership"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/consensusclient"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazycache"
 	"github.com/pkg/errors"
@@ -26,8 +31,17 @@ type cache interface {
 }
 
 type contextCache struct {
-	ctx                   fab.ClientContext
-	eventServiceCache     cache
+	ctx               fab.ClientContext
+	opts              []options.Opt
+	eventServiceCache cache
+	lock              sync.RWMutex
+	channelCaches     *channelCaches
+	retiredCaches     map[*channelCaches]*time.Timer
+}
+
+// channelCaches holds the caches which are derived from the endpoint configuration
+// and which are recreated after a configuration update
+type channelCaches struct {
 	discoveryServiceCache cache
 	selectionServiceCache cache
 	chCfgCache            cache
@@ -38,33 +52,22 @@ var cfgCacheProvider = func(opts ...options.Opt) cache {
 	return chconfig.NewRefCache(opts...)
 }
 
+// retiredCachesGracePeriod returns the period for which the caches replaced by a configuration update are kept
+// open, so that the services which were already handed out (for example to in-flight requests) continue to work
+var retiredCachesGracePeriod = func(config fab.EndpointConfig) time.Duration {
+	return config.Timeout(fab.Execute)
+}
+
 func newContextCache(ctx fab.ClientContext, opts []options.Opt) *contextCache {
 	eventIdleTime := ctx.EndpointConfig().Timeout(fab.EventServiceIdle)
-	chConfigRefresh := ctx.EndpointConfig().Timeout(fab.ChannelConfigRefresh)
-	membershipRefresh := ctx.EndpointConfig().Timeout(fab.ChannelMembershipRefresh)
 
 	c := &contextCache{
-		ctx: ctx,
+		ctx:           ctx,
+		opts:          opts,
+		retiredCaches: make(map[*channelCaches]*time.Timer),
 	}
 
-	c.chCfgCache = cfgCacheProvider(append(opts, chconfig.WithRefreshInterval(chConfigRefresh))...)
-	c.membershipCache = membership.NewRefCache(membershipRefresh)
-
-	c.discoveryServiceCache = lazycache.New(
-		"Discovery_Service_Cache",
-		func(key lazycache.Key) (interface{}, error) {
-			ck := key.(*cacheKey)
-			return c.createDiscoveryService(ck.channelConfig, opts...)
-		},
-	)
-
-	c.selectionServiceCache = lazycache.New(
-		"Selection_Service_Cache",
-		func(key lazycache.Key) (interface{}, error) {
-			ck := key.(*cacheKey)
-			return c.createSelectionService(ck.channelConfig, opts...)
-		},
-	)
+	c.channelCaches = c.newChannelCaches()
 
 	c.eventServiceCache = lazycache.New(
 		"Event_Service_Cache",
@@ -82,29 +85,111 @@ func newContextCache(ctx fab.ClientContext, opts []options.Opt) *contextCache {
 	return c
 }
 
+func (c *contextCache) newChannelCaches() *channelCaches {
+	chConfigRefresh := c.ctx.EndpointConfig().Timeout(fab.ChannelConfigRefresh)
+	membershipRefresh := c.ctx.EndpointConfig().Timeout(fab.ChannelMembershipRefresh)
+
+	return &channelCaches{
+		chCfgCache:      cfgCacheProvider(append(c.opts, chconfig.WithRefreshInterval(chConfigRefresh))...),
+		membershipCache: membership.NewRefCache(membershipRefresh),
+		discoveryServiceCache: lazycache.New(
+			"Discovery_Service_Cache",
+			func(key lazycache.Key) (interface{}, error) {
+				ck := key.(*cacheKey)
+				return c.createDiscoveryService(ck.channelConfig, c.opts...)
+			},
+		),
+		selectionServiceCache: lazycache.New(
+			"Selection_Service_Cache",
+			func(key lazycache.Key) (interface{}, error) {
+				ck := key.(*cacheKey)
+				return c.createSelectionService(ck.channelConfig, c.opts...)
+			},
+		),
+	}
+}
+
+func (c *contextCache) caches() *channelCaches {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.channelCaches
+}
+
 func (c *contextCache) Close() {
 	logger.Debug("Closing event service cache...")
 	c.eventServiceCache.Close()
 
+	c.lock.Lock()
+	retiredCaches := c.retiredCaches
+	c.retiredCaches = make(map[*channelCaches]*time.Timer)
+	c.lock.Unlock()
+
+	for cc, timer := range retiredCaches {
+		timer.Stop()
+		cc.close()
+	}
+
+	c.caches().close()
+}
+
+// ConfigUpdated replaces the channel configuration, membership, discovery and selection caches so
+// that the services are recreated using the updated endpoint configuration. The replaced caches are
+// closed after a grace period (the execute timeout) so that services which are in use aren't closed
+// from under their callers. The event services are retained so that existing registrations are not lost.
+// Event clients look up the discovery service each time they connect, so they use the updated
+// configuration the next time they reconnect.
+func (c *contextCache) ConfigUpdated() {
+	gracePeriod := retiredCachesGracePeriod(c.ctx.EndpointConfig())
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	old := c.channelCaches
+	c.channelCaches = c.newChannelCaches()
+	c.retiredCaches[old] = time.AfterFunc(gracePeriod, func() {
+		c.closeRetiredCaches(old)
+	})
+}
+
+func (c *contextCache) closeRetiredCaches(cc *channelCaches) {
+	c.lock.Lock()
+	_, ok := c.retiredCaches[cc]
+	delete(c.retiredCaches, cc)
+	c.lock.Unlock()
+
+	if ok {
+		logger.Debug("Closing caches replaced by config update...")
+		cc.close()
+	}
+}
+
+func (cc *channelCaches) close() {
 	logger.Debug("Closing membership cache...")
-	c.membershipCache.Close()
+	cc.membershipCache.Close()
 
 	logger.Debug("Closing channel configuration cache...")
-	c.chCfgCache.Close()
+	cc.chCfgCache.Close()
 
 	logger.Debug("Closing selection service cache...")
-	c.selectionServiceCache.Close()
+	cc.selectionServiceCache.Close()
 
 	logger.Debug("Closing discovery service cache...")
-	c.discoveryServiceCache.Close()
+	cc.discoveryServiceCache.Close()
 }
 
 func (c *contextCache) createEventClient(chConfig fab.ChannelCfg, opts ...options.Opt) (fab.EventClient, error) {
-	discovery, err := c.GetDiscoveryService(chConfig.ID())
-	if err != nil {
+	if _, err := c.GetDiscoveryService(chConfig.ID()); err != nil {
 		return nil, errors.WithMessage(err, "could not get discovery service")
 	}
 
+	discovery := &channelDiscovery{ctxtCache: c, channelID: chConfig.ID()}
+
+	if c.ctx.EndpointConfig().ChannelConfig(chConfig.ID()).Policies.EventService.Type == fab.ConsensusEventServiceType {
+		logger.Debugf("Using consensus events for channel [%s]", chConfig.ID())
+		return consensusclient.New(c.ctx, chConfig, discovery, opts...)
+	}
+
 	logger.Debugf("Using deliver events for channel [%s]", chConfig.ID())
 	return deliverclient.New(c.ctx, chConfig, discovery, opts...)
 }
@@ -127,7 +212,7 @@ func (c *contextCache) GetDiscoveryService(channelID string) (fab.DiscoveryServi
 		return nil, err
 	}
 	key := newCacheKey(chnlCfg)
-	discoveryService, err := c.discoveryServiceCache.Get(key)
+	discoveryService, err := c.caches().discoveryServiceCache.Get(key)
 	if err != nil {
 		return nil, err
 	}
@@ -140,6 +225,21 @@ func (c *contextCache) createSelectionService(chConfig fab.ChannelCfg, opts ...o
 		return nil, err
 	}
 
+	fallback, err := c.createDiscoverySelectionService(chConfig, discovery, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Configured chaincode policies take precedence over the discovery-based selection service, which
+	// is only used for chaincodes without a configured policy
+	if chaincodes := c.ctx.EndpointConfig().ChannelConfig(chConfig.ID()).Chaincodes; len(chaincodes) > 0 {
+		logger.Debugf("Using Static Selection with the configured chaincode policies.")
+		return staticselection.NewService(discovery, staticselection.WithChaincodePolicies(chaincodes), staticselection.WithFallback(fallback))
+	}
+	return fallback, nil
+}
+
+func (c *contextCache) createDiscoverySelectionService(chConfig fab.ChannelCfg, discovery fab.DiscoveryService, opts ...options.Opt) (fab.SelectionService, error) {
 	if chConfig.HasCapability(fab.ApplicationGroupKey, fab.V1_2Capability) {
 		logger.Debugf("Using Fabric Selection based on V1_2 capability.")
 		return fabricselection.New(c.ctx, chConfig.ID(), discovery, opts...)
@@ -153,7 +253,7 @@ func (c *contextCache) GetSelectionService(channelID string) (fab.SelectionServi
 		return nil, err
 	}
 	key := newCacheKey(chnlCfg)
-	selectionService, err := c.selectionServiceCache.Get(key)
+	selectionService, err := c.caches().selectionServiceCache.Get(key)
 	if err != nil {
 		return nil, err
 	}
@@ -199,7 +299,7 @@ func (c *contextCache) loadChannelCfgRef(channelID string) (*chconfig.Ref, error
 	if err != nil {
 		return nil, err
 	}
-	cfg, err := c.chCfgCache.Get(key)
+	cfg, err := c.caches().chCfgCache.Get(key)
 	if err != nil {
 		return nil, err
 	}
@@ -217,10 +317,26 @@ func (c *contextCache) GetMembership(channelID string) (fab.ChannelMembership, e
 	if err != nil {
 		return nil, err
 	}
-	ref, err := c.membershipCache.Get(key)
+	ref, err := c.caches().membershipCache.Get(key)
 	if err != nil {
 		return nil, err
 	}
 
 	return ref.(*membership.Ref), nil
 }
+
+// channelDiscovery is the discovery service given to event clients. It resolves the
+// channel's discovery service on each call so that a long-lived event client uses the
+// current discovery service after a configuration update.
+type channelDiscovery struct {
+	ctxtCache *contextCache
+	channelID string
+}
+
+func (d *channelDiscovery) GetPeers() ([]fab.Peer, error) {
+	discovery, err := d.ctxtCache.GetDiscoveryService(d.channelID)
+	if err != nil {
+		return nil, err
+	}
+	return discovery.GetPeers()
+}
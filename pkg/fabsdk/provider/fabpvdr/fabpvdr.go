@@ -14,6 +14,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
 	peerImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
 )
 
 var logger = logging.NewLogger("fabsdk")
@@ -37,6 +38,7 @@ func New(config fab.EndpointConfig) *InfraProvider {
 // Initialize sets the provider context
 func (f *InfraProvider) Initialize(providers context.Providers) error {
 	f.providerContext = providers
+	f.commManager.SetMetrics(providers.GetMetrics())
 	return nil
 }
 
@@ -46,11 +48,28 @@ func (f *InfraProvider) Close() {
 	f.commManager.Close()
 }
 
+// ConfigUpdated is invoked after the SDK configuration has been reloaded. Cached connections are evicted
+// so that new connections are created using the updated endpoint configuration.
+func (f *InfraProvider) ConfigUpdated() {
+	logger.Debug("Evicting cached connections after config update...")
+	f.commManager.Evict()
+}
+
 // CommManager provides comm support such as GRPC onnections
 func (f *InfraProvider) CommManager() fab.CommManager {
 	return f.commManager
 }
 
+// SetDialOptions adds dial options which are applied to all connections created by the comm manager
+func (f *InfraProvider) SetDialOptions(opts ...grpc.DialOption) {
+	f.commManager.SetDialOptions(opts...)
+}
+
+// SetDialOptionSet registers a named set of dial options with the comm manager
+func (f *InfraProvider) SetDialOptionSet(name string, opts ...grpc.DialOption) {
+	f.commManager.SetDialOptionSet(name, opts...)
+}
+
 // CreatePeerFromConfig returns a new default implementation of Peer based configuration
 func (f *InfraProvider) CreatePeerFromConfig(peerCfg *fab.NetworkPeer) (fab.Peer, error) {
 	return peerImpl.New(f.providerContext.EndpointConfig(), peerImpl.FromPeerConfig(peerCfg))
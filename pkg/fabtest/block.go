@@ -0,0 +1,141 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// committedTx is a transaction of a block which is being committed
+type committedTx struct {
+	txID           string
+	headerType     common.HeaderType
+	rwSets         []*rwsetutil.NsRwSet
+	event          *pb.ChaincodeEvent
+	validationCode pb.TxValidationCode
+}
+
+// parseTransaction extracts the read-write sets and chaincode event of a transaction. If certificate
+// authorities are given then the endorsement signatures are verified against them.
+func parseTransaction(data []byte, cas map[string]*certificateAuthority) *committedTx {
+	tx := &committedTx{validationCode: pb.TxValidationCode_BAD_PAYLOAD}
+
+	envelope, err := protoutil.UnmarshalEnvelope(data)
+	if err != nil {
+		return tx
+	}
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	if err != nil || payload.Header == nil {
+		return tx
+	}
+	channelHeader, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return tx
+	}
+
+	tx.txID = channelHeader.TxId
+	tx.headerType = common.HeaderType(channelHeader.Type)
+
+	switch tx.headerType {
+	case common.HeaderType_CONFIG:
+		tx.validationCode = pb.TxValidationCode_VALID
+	case common.HeaderType_ENDORSER_TRANSACTION:
+		tx.validationCode = parseEndorserTransaction(tx, payload.Data, cas)
+	default:
+		tx.validationCode = pb.TxValidationCode_UNKNOWN_TX_TYPE
+	}
+
+	return tx
+}
+
+func parseEndorserTransaction(tx *committedTx, data []byte, cas map[string]*certificateAuthority) pb.TxValidationCode {
+	transaction, err := protoutil.GetTransaction(data)
+	if err != nil || len(transaction.Actions) == 0 {
+		return pb.TxValidationCode_BAD_PAYLOAD
+	}
+
+	for _, action := range transaction.Actions {
+		ccActionPayload, err := protoutil.GetChaincodeActionPayload(action.Payload)
+		if err != nil || ccActionPayload.Action == nil {
+			return pb.TxValidationCode_BAD_PAYLOAD
+		}
+
+		endorsedAction := ccActionPayload.Action
+		if len(endorsedAction.Endorsements) == 0 {
+			return pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+		}
+		if cas != nil {
+			for _, endorsement := range endorsedAction.Endorsements {
+				msg := util.ConcatenateBytes(endorsedAction.ProposalResponsePayload, endorsement.Endorser)
+				if err := verifySignature(cas, endorsement.Endorser, msg, endorsement.Signature); err != nil {
+					return pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+				}
+			}
+		}
+
+		prp, err := protoutil.GetProposalResponsePayload(endorsedAction.ProposalResponsePayload)
+		if err != nil {
+			return pb.TxValidationCode_BAD_RESPONSE_PAYLOAD
+		}
+		ccAction, err := protoutil.GetChaincodeAction(prp.Extension)
+		if err != nil {
+			return pb.TxValidationCode_BAD_RESPONSE_PAYLOAD
+		}
+
+		txRwSet := &rwsetutil.TxRwSet{}
+		if err := txRwSet.FromProtoBytes(ccAction.Results); err != nil {
+			return pb.TxValidationCode_BAD_RWSET
+		}
+		tx.rwSets = append(tx.rwSets, txRwSet.NsRwSets...)
+
+		if len(ccAction.Events) > 0 {
+			event := &pb.ChaincodeEvent{}
+			if err := proto.Unmarshal(ccAction.Events, event); err != nil {
+				return pb.TxValidationCode_INVALID_OTHER_REASON
+			}
+			tx.event = event
+		}
+	}
+
+	return pb.TxValidationCode_VALID
+}
+
+// filteredBlock converts a committed block into a filtered block. Chaincode event payloads are
+// not included, as in Fabric.
+func filteredBlock(channelID string, block *common.Block) *pb.FilteredBlock {
+	filter := block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]
+
+	fb := &pb.FilteredBlock{ChannelId: channelID, Number: block.Header.Number}
+	for i, data := range block.Data.Data {
+		tx := parseTransaction(data, nil)
+
+		ftx := &pb.FilteredTransaction{Txid: tx.txID, Type: tx.headerType}
+		if i < len(filter) {
+			ftx.TxValidationCode = pb.TxValidationCode(filter[i])
+		}
+		if tx.event != nil {
+			ftx.Data = &pb.FilteredTransaction_TransactionActions{
+				TransactionActions: &pb.FilteredTransactionActions{
+					ChaincodeActions: []*pb.FilteredChaincodeAction{{
+						ChaincodeEvent: &pb.ChaincodeEvent{
+							ChaincodeId: tx.event.ChaincodeId,
+							TxId:        tx.event.TxId,
+							EventName:   tx.event.EventName,
+						},
+					}},
+				},
+			}
+		}
+		fb.FilteredTransactions = append(fb.FilteredTransactions, ftx)
+	}
+	return fb
+}
@@ -0,0 +1,379 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
+)
+
+// identitiesEndpoint serves the /identities endpoints
+func (s *CAServer) identitiesEndpoint(req *caRequest) (interface{}, error) {
+	if req.caller.attr(attrRegistrarRoles) == "" {
+		return nil, newCAError(http.StatusForbidden, "identity [%s] is not a registrar", req.caller.id)
+	}
+
+	switch {
+	case req.name == "" && req.http.Method == http.MethodGet:
+		return s.getIdentities(req), nil
+	case req.name == "" && req.http.Method == http.MethodPost:
+		return s.addIdentity(req)
+	case req.name != "" && req.http.Method == http.MethodGet:
+		return s.getIdentity(req)
+	case req.name != "" && req.http.Method == http.MethodPut:
+		return s.modifyIdentity(req)
+	case req.name != "" && req.http.Method == http.MethodDelete:
+		return s.removeIdentity(req)
+	default:
+		return nil, newCAError(http.StatusMethodNotAllowed, "method [%s] is not allowed", req.http.Method)
+	}
+}
+
+// getIdentities returns the identities which the caller may act on
+func (s *CAServer) getIdentities(req *caRequest) *api.GetAllIDsResponse {
+	resp := &api.GetAllIDsResponse{Identities: []api.IdentityInfo{}, CAName: s.caName}
+	for _, id := range s.sortedIdentities() {
+		if s.canAccess(req.caller, id) {
+			resp.Identities = append(resp.Identities, identityInfo(id))
+		}
+	}
+	return resp
+}
+
+func (s *CAServer) getIdentity(req *caRequest) (*api.GetIDResponse, error) {
+	id, err := s.accessIdentity(req)
+	if err != nil {
+		return nil, err
+	}
+	info := identityInfo(id)
+	return &api.GetIDResponse{
+		ID:             info.ID,
+		Type:           info.Type,
+		Affiliation:    info.Affiliation,
+		Attributes:     info.Attributes,
+		MaxEnrollments: info.MaxEnrollments,
+		CAName:         s.caName,
+	}, nil
+}
+
+func (s *CAServer) addIdentity(req *caRequest) (*api.IdentityResponse, error) {
+	r := &api.AddIdentityRequest{}
+	if err := req.unmarshal(r); err != nil {
+		return nil, err
+	}
+	id, err := s.registerIdentity(req.caller, r.ID, r.Type, r.Affiliation, r.Secret, r.MaxEnrollments, r.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	resp := s.identityResponse(id)
+	resp.Secret = id.secret
+	return resp, nil
+}
+
+// modifyIdentity updates the non-empty fields of the request. An affiliation of "." moves the
+// identity to the root affiliation and attributes with an empty value are removed.
+func (s *CAServer) modifyIdentity(req *caRequest) (*api.IdentityResponse, error) {
+	id, err := s.accessIdentity(req)
+	if err != nil {
+		return nil, err
+	}
+	r := &api.ModifyIdentityRequest{}
+	if err := req.unmarshal(r); err != nil {
+		return nil, err
+	}
+
+	typ, affiliation := id.typ, id.affiliation
+	if r.Type != "" {
+		typ = r.Type
+	}
+	switch r.Affiliation {
+	case "":
+	case ".":
+		affiliation = ""
+	default:
+		affiliation = r.Affiliation
+	}
+	if err := s.checkRegistrar(req.caller, typ, affiliation, r.Attributes); err != nil {
+		return nil, err
+	}
+	if r.MaxEnrollments != 0 && s.maxEnrollments > 0 && (r.MaxEnrollments < 0 || r.MaxEnrollments > s.maxEnrollments) {
+		return nil, newCAError(http.StatusBadRequest, "max enrollments must not exceed %d", s.maxEnrollments)
+	}
+
+	id.typ, id.affiliation = typ, affiliation
+	if r.MaxEnrollments != 0 {
+		id.maxEnrollments = r.MaxEnrollments
+	}
+	if r.Secret != "" {
+		id.secret = r.Secret
+	}
+	id.attrs = mergeAttributes(mergeAttributes(id.attrs, defaultAttributes(id)), r.Attributes)
+
+	resp := s.identityResponse(id)
+	resp.Secret = r.Secret
+	return resp, nil
+}
+
+// removeIdentity removes the identity and revokes its certificates. The caller may only remove
+// its own identity if force is set.
+func (s *CAServer) removeIdentity(req *caRequest) (*api.IdentityResponse, error) {
+	id, err := s.accessIdentity(req)
+	if err != nil {
+		return nil, err
+	}
+	if id == req.caller && !req.force() {
+		return nil, newCAError(http.StatusBadRequest, "identity [%s] may only remove itself with force", id.id)
+	}
+
+	s.removeIdentityByID(id.id)
+	return s.identityResponse(id), nil
+}
+
+func (s *CAServer) removeIdentityByID(id string) {
+	delete(s.identities, id)
+	s.revokeCerts(id, revocationReasons["unspecified"])
+	logger.Debugf("Removed identity [%s]", id)
+}
+
+// accessIdentity returns the identity of the request path if the caller may act on it
+func (s *CAServer) accessIdentity(req *caRequest) (*caIdentity, error) {
+	id, ok := s.identities[req.name]
+	if !ok {
+		return nil, newCAError(http.StatusNotFound, "identity [%s] is not registered", req.name)
+	}
+	if !s.canAccess(req.caller, id) {
+		return nil, newCAError(http.StatusForbidden, "identity [%s] may not act on identity [%s]", req.caller.id, id.id)
+	}
+	return id, nil
+}
+
+func (s *CAServer) identityResponse(id *caIdentity) *api.IdentityResponse {
+	info := identityInfo(id)
+	return &api.IdentityResponse{
+		ID:             info.ID,
+		Type:           info.Type,
+		Affiliation:    info.Affiliation,
+		Attributes:     info.Attributes,
+		MaxEnrollments: info.MaxEnrollments,
+		CAName:         s.caName,
+	}
+}
+
+func (s *CAServer) sortedIdentities() []*caIdentity {
+	var ids []*caIdentity
+	for _, id := range s.identities {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].id < ids[j].id })
+	return ids
+}
+
+func identityInfo(id *caIdentity) api.IdentityInfo {
+	return api.IdentityInfo{
+		ID:             id.id,
+		Type:           id.typ,
+		Affiliation:    id.affiliation,
+		Attributes:     append([]api.Attribute{}, id.attrs...),
+		MaxEnrollments: id.maxEnrollments,
+	}
+}
+
+// affiliationsEndpoint serves the /affiliations endpoints
+func (s *CAServer) affiliationsEndpoint(req *caRequest) (interface{}, error) {
+	if req.http.Method != http.MethodGet && req.caller.attr(attrAffiliationMgr) != "true" {
+		return nil, newCAError(http.StatusForbidden, "identity [%s] may not manage affiliations", req.caller.id)
+	}
+
+	switch {
+	case req.name == "" && req.http.Method == http.MethodGet:
+		return s.affiliationResponse(s.affiliationInfo(req.caller.affiliation, false)), nil
+	case req.name == "" && req.http.Method == http.MethodPost:
+		return s.addAffiliationRequest(req)
+	case req.name != "" && req.http.Method == http.MethodGet:
+		if err := s.accessAffiliation(req.caller, req.name); err != nil {
+			return nil, err
+		}
+		return s.affiliationResponse(s.affiliationInfo(req.name, false)), nil
+	case req.name != "" && req.http.Method == http.MethodPut:
+		return s.modifyAffiliation(req)
+	case req.name != "" && req.http.Method == http.MethodDelete:
+		return s.removeAffiliation(req)
+	default:
+		return nil, newCAError(http.StatusMethodNotAllowed, "method [%s] is not allowed", req.http.Method)
+	}
+}
+
+// addAffiliationRequest adds an affiliation. The parent affiliation must exist unless force is set,
+// in which case missing parents are created.
+func (s *CAServer) addAffiliationRequest(req *caRequest) (*api.AffiliationResponse, error) {
+	r := &api.AddAffiliationRequest{}
+	if err := req.unmarshal(r); err != nil {
+		return nil, err
+	}
+	if r.Name == "" {
+		return nil, newCAError(http.StatusBadRequest, "affiliation name is required")
+	}
+	if _, exists := s.affiliations[r.Name]; exists {
+		return nil, newCAError(http.StatusConflict, "affiliation [%s] already exists", r.Name)
+	}
+	if parent := parentAffiliation(r.Name); parent != "" && !req.force() {
+		if _, exists := s.affiliations[parent]; !exists {
+			return nil, newCAError(http.StatusBadRequest, "parent affiliation [%s] does not exist", parent)
+		}
+	}
+	if !isAffiliated(req.caller.affiliation, r.Name) {
+		return nil, newCAError(http.StatusForbidden, "identity [%s] may not act on affiliation [%s]", req.caller.id, r.Name)
+	}
+
+	s.addAffiliation(r.Name)
+	return s.affiliationResponse(s.affiliationInfo(r.Name, false)), nil
+}
+
+// modifyAffiliation renames the affiliation and its descendants. If identities belong to any of
+// them then force is required and the identities are moved to the new affiliations.
+func (s *CAServer) modifyAffiliation(req *caRequest) (*api.AffiliationResponse, error) {
+	if err := s.accessAffiliation(req.caller, req.name); err != nil {
+		return nil, err
+	}
+	r := &api.ModifyAffiliationRequest{}
+	if err := req.unmarshal(r); err != nil {
+		return nil, err
+	}
+	if r.NewName == "" {
+		return nil, newCAError(http.StatusBadRequest, "new affiliation name is required")
+	}
+	if _, exists := s.affiliations[r.NewName]; exists {
+		return nil, newCAError(http.StatusConflict, "affiliation [%s] already exists", r.NewName)
+	}
+	if parent := parentAffiliation(r.NewName); parent != "" {
+		if _, exists := s.affiliations[parent]; !exists {
+			return nil, newCAError(http.StatusBadRequest, "parent affiliation [%s] does not exist", parent)
+		}
+	}
+	if !isAffiliated(req.caller.affiliation, r.NewName) {
+		return nil, newCAError(http.StatusForbidden, "identity [%s] may not act on affiliation [%s]", req.caller.id, r.NewName)
+	}
+
+	ids := s.affiliatedIdentities(req.name)
+	if len(ids) > 0 && !req.force() {
+		return nil, newCAError(http.StatusBadRequest, "affiliation [%s] has identities, force is required to rename it", req.name)
+	}
+
+	rename := func(affiliation string) string {
+		return r.NewName + strings.TrimPrefix(affiliation, req.name)
+	}
+	for affiliation := range s.affiliations {
+		if isAffiliated(req.name, affiliation) {
+			delete(s.affiliations, affiliation)
+			s.affiliations[rename(affiliation)] = struct{}{}
+		}
+	}
+	for _, id := range ids {
+		id.affiliation = rename(id.affiliation)
+		id.attrs = mergeAttributes(id.attrs, defaultAttributes(id))
+	}
+
+	return s.affiliationResponse(s.affiliationInfo(r.NewName, len(ids) > 0)), nil
+}
+
+// removeAffiliation removes the affiliation. If it has descendants or identities then force is
+// required and these are removed as well.
+func (s *CAServer) removeAffiliation(req *caRequest) (*api.AffiliationResponse, error) {
+	if err := s.accessAffiliation(req.caller, req.name); err != nil {
+		return nil, err
+	}
+	if req.name == req.caller.affiliation {
+		return nil, newCAError(http.StatusForbidden, "identity [%s] may not remove its own affiliation", req.caller.id)
+	}
+
+	ids := s.affiliatedIdentities(req.name)
+	if (len(ids) > 0 || len(s.childAffiliations(req.name)) > 0) && !req.force() {
+		return nil, newCAError(http.StatusBadRequest, "affiliation [%s] has sub-affiliations or identities, force is required to remove it", req.name)
+	}
+
+	info := s.affiliationInfo(req.name, true)
+	for affiliation := range s.affiliations {
+		if isAffiliated(req.name, affiliation) {
+			delete(s.affiliations, affiliation)
+		}
+	}
+	for _, id := range ids {
+		s.removeIdentityByID(id.id)
+	}
+	return s.affiliationResponse(info), nil
+}
+
+// accessAffiliation verifies that the affiliation exists and that the caller may act on it
+func (s *CAServer) accessAffiliation(caller *caIdentity, affiliation string) error {
+	if _, exists := s.affiliations[affiliation]; !exists {
+		return newCAError(http.StatusNotFound, "affiliation [%s] does not exist", affiliation)
+	}
+	if !isAffiliated(caller.affiliation, affiliation) {
+		return newCAError(http.StatusForbidden, "identity [%s] may not act on affiliation [%s]", caller.id, affiliation)
+	}
+	return nil
+}
+
+// addAffiliation adds the affiliation and its parents
+func (s *CAServer) addAffiliation(affiliation string) {
+	parts := strings.Split(affiliation, ".")
+	for i := range parts {
+		s.affiliations[strings.Join(parts[:i+1], ".")] = struct{}{}
+	}
+}
+
+// affiliationInfo returns the affiliation tree below the affiliation ("" is the root)
+func (s *CAServer) affiliationInfo(affiliation string, withIdentities bool) api.AffiliationInfo {
+	info := api.AffiliationInfo{Name: affiliation}
+	for _, child := range s.childAffiliations(affiliation) {
+		info.Affiliations = append(info.Affiliations, s.affiliationInfo(child, withIdentities))
+	}
+	if withIdentities {
+		for _, id := range s.sortedIdentities() {
+			if id.affiliation == affiliation {
+				info.Identities = append(info.Identities, identityInfo(id))
+			}
+		}
+	}
+	return info
+}
+
+func (s *CAServer) affiliationResponse(info api.AffiliationInfo) *api.AffiliationResponse {
+	return &api.AffiliationResponse{AffiliationInfo: info, CAName: s.caName}
+}
+
+func (s *CAServer) childAffiliations(affiliation string) []string {
+	var children []string
+	for a := range s.affiliations {
+		if parentAffiliation(a) == affiliation {
+			children = append(children, a)
+		}
+	}
+	sort.Strings(children)
+	return children
+}
+
+// affiliatedIdentities returns the identities of the affiliation and its descendants
+func (s *CAServer) affiliatedIdentities(affiliation string) []*caIdentity {
+	var ids []*caIdentity
+	for _, id := range s.sortedIdentities() {
+		if id.affiliation != "" && isAffiliated(affiliation, id.affiliation) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func parentAffiliation(affiliation string) string {
+	if i := strings.LastIndex(affiliation, "."); i >= 0 {
+		return affiliation[:i]
+	}
+	return ""
+}
@@ -0,0 +1,982 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cfsslapi "github.com/cloudflare/cfssl/api"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib/attrmgr"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib/common"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/pkg/errors"
+	"github.com/tjfoc/gmsm/sm2"
+	"github.com/tjfoc/gmsm/sm3"
+)
+
+const (
+	defaultCAName          = "ca.org1.example.com"
+	defaultRegistrarID     = "admin"
+	defaultRegistrarSecret = "adminpw"
+	caVersion              = "1.4.9"
+	ecertValidity          = 365 * 24 * time.Hour
+	crlValidity            = 24 * time.Hour
+)
+
+// defaultAffiliations are the affiliations of a fabric-ca-server with the default configuration
+var defaultAffiliations = []string{"org1.department1", "org1.department2", "org2.department1"}
+
+// Attributes which control what a caller may do on the CA
+const (
+	attrRegistrarRoles      = "hf.Registrar.Roles"
+	attrRegistrarAttributes = "hf.Registrar.Attributes"
+	attrRevoker             = "hf.Revoker"
+	attrGenCRL              = "hf.GenCRL"
+	attrAffiliationMgr      = "hf.AffiliationMgr"
+	attrEnrollmentID        = "hf.EnrollmentID"
+	attrType                = "hf.Type"
+	attrAffiliation         = "hf.Affiliation"
+)
+
+// caIdentity is an identity registered with the CA server
+type caIdentity struct {
+	id             string
+	typ            string
+	affiliation    string
+	secret         string
+	maxEnrollments int
+	enrollments    int
+	attrs          []api.Attribute
+	revoked        bool
+}
+
+// attr returns the value of the given attribute or "" if the identity doesn't have it
+func (id *caIdentity) attr(name string) string {
+	for _, a := range id.attrs {
+		if a.Name == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// issuedCert is an enrollment certificate issued by the CA server
+type issuedCert struct {
+	id        string
+	serial    *big.Int
+	notAfter  time.Time
+	revokedAt time.Time
+	reason    int
+}
+
+// CAServer is an in-process Fabric CA server which implements the subset of the fabric-ca REST
+// API used by the SDK: enroll, reenroll, register, revoke and the identities and affiliations
+// endpoints. Certificates are real ECDSA (or SM2) certificates signed by the server's root
+// certificate and contain the identity's attributes, so that msp.Client flows can be tested
+// without Docker. State is kept in memory and authorization follows the hf.* attributes of
+// the caller in a simplified form.
+type CAServer struct {
+	caName          string
+	registrarID     string
+	registrarSecret string
+	affiliationList []string
+	maxEnrollments  int
+	gm              bool
+
+	cert    *sm2.Certificate
+	certPEM []byte
+	key     crypto.Signer
+
+	mutex        sync.Mutex
+	identities   map[string]*caIdentity
+	affiliations map[string]struct{}
+	certs        map[string]*issuedCert
+
+	listener net.Listener
+	server   *http.Server
+	stopOnce sync.Once
+}
+
+// CAOption configures the CA server
+type CAOption func(s *CAServer)
+
+// WithCAName sets the name of the CA (default "ca.org1.example.com")
+func WithCAName(name string) CAOption {
+	return func(s *CAServer) {
+		s.caName = name
+	}
+}
+
+// WithRegistrar sets the enrollment ID and secret of the bootstrap identity (default "admin"/"adminpw").
+// The bootstrap identity may register any type of identity and attribute, revoke certificates,
+// generate CRLs and manage affiliations.
+func WithRegistrar(enrollID, enrollSecret string) CAOption {
+	return func(s *CAServer) {
+		s.registrarID = enrollID
+		s.registrarSecret = enrollSecret
+	}
+}
+
+// WithAffiliations sets the initial affiliations (default "org1.department1", "org1.department2"
+// and "org2.department1"). Parent affiliations are created implicitly.
+func WithAffiliations(affiliations ...string) CAOption {
+	return func(s *CAServer) {
+		s.affiliationList = affiliations
+	}
+}
+
+// WithMaxEnrollments sets the maximum number of enrollments of an identity if none is specified
+// at registration (default -1, which means unlimited)
+func WithMaxEnrollments(max int) CAOption {
+	return func(s *CAServer) {
+		s.maxEnrollments = max
+	}
+}
+
+// WithSM2 creates an SM2 root key so that certificates are signed with SM2-SM3. This is
+// required for clients which use the GM crypto suite.
+func WithSM2() CAOption {
+	return func(s *CAServer) {
+		s.gm = true
+	}
+}
+
+// NewCAServer creates and starts a CA server which listens on a random localhost port
+func NewCAServer(opts ...CAOption) (*CAServer, error) {
+	s := &CAServer{
+		caName:          defaultCAName,
+		registrarID:     defaultRegistrarID,
+		registrarSecret: defaultRegistrarSecret,
+		affiliationList: defaultAffiliations,
+		maxEnrollments:  -1,
+		identities:      make(map[string]*caIdentity),
+		affiliations:    make(map[string]struct{}),
+		certs:           make(map[string]*issuedCert),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.registrarID == "" || s.registrarSecret == "" {
+		return nil, errors.New("registrar enrollment ID and secret are required")
+	}
+	if s.maxEnrollments == 0 || s.maxEnrollments < -1 {
+		return nil, errors.New("max enrollments must be -1 (unlimited) or greater than zero")
+	}
+
+	for _, affiliation := range s.affiliationList {
+		s.addAffiliation(affiliation)
+	}
+	s.identities[s.registrarID] = &caIdentity{
+		id:             s.registrarID,
+		typ:            "client",
+		secret:         s.registrarSecret,
+		maxEnrollments: -1,
+		attrs: []api.Attribute{
+			{Name: attrRegistrarRoles, Value: "*"},
+			{Name: attrRegistrarAttributes, Value: "*"},
+			{Name: "hf.Registrar.DelegateRoles", Value: "*"},
+			{Name: attrRevoker, Value: "true"},
+			{Name: attrGenCRL, Value: "true"},
+			{Name: attrAffiliationMgr, Value: "true"},
+			{Name: "hf.IntermediateCA", Value: "true"},
+		},
+	}
+
+	if err := s.createRootCert(); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to listen")
+	}
+	s.listener = listener
+	s.server = &http.Server{Handler: http.HandlerFunc(s.serveHTTP)}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Warnf("CA server [%s] stopped: %s", s.URL(), err)
+		}
+	}()
+
+	return s, nil
+}
+
+// URL returns the URL of the CA server
+func (s *CAServer) URL() string {
+	return "http://" + s.listener.Addr().String()
+}
+
+// CAName returns the name of the CA
+func (s *CAServer) CAName() string {
+	return s.caName
+}
+
+// CACertificate returns the PEM encoded root certificate of the CA
+func (s *CAServer) CACertificate() []byte {
+	return s.certPEM
+}
+
+// CAProfile returns a connection profile entry for the CA, including the registrar credentials.
+// The CA doesn't use TLS but the SDK requires TLS CA certificates, so the root certificate is used.
+func (s *CAServer) CAProfile() config.CAProfile {
+	return config.CAProfile{
+		URL:        s.URL(),
+		CAName:     s.caName,
+		TLSCACerts: config.MutualTLSProfile{Pem: []string{string(s.certPEM)}},
+		Registrar:  config.RegistrarProfile{EnrollID: s.registrarID, EnrollSecret: s.registrarSecret},
+	}
+}
+
+// CRL returns a PEM encoded certificate revocation list with all unexpired revoked certificates
+func (s *CAServer) CRL() ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.generateCRL()
+}
+
+// Stop stops the CA server
+func (s *CAServer) Stop() {
+	s.stopOnce.Do(func() {
+		if err := s.server.Close(); err != nil {
+			logger.Debugf("Failed to close CA server [%s]: %s", s.URL(), err)
+		}
+	})
+}
+
+func (s *CAServer) createRootCert() error {
+	var pub interface{}
+	if s.gm {
+		key, err := sm2.GenerateKey()
+		if err != nil {
+			return errors.Wrap(err, "failed to generate CA key")
+		}
+		s.key, pub = key, &key.PublicKey
+	} else {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate CA key")
+		}
+		s.key, pub = key, &key.PublicKey
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	template := &sm2.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: s.caName, Organization: []string{"Hyperledger"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(certValidity),
+		KeyUsage:              sm2.KeyUsageCertSign | sm2.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SubjectKeyId:          publicKeyID(pub),
+		SignatureAlgorithm:    s.signatureAlgorithm(),
+	}
+
+	der, err := sm2.CreateCertificate(rand.Reader, template, template, pub, s.key)
+	if err != nil {
+		return errors.Wrap(err, "failed to create CA certificate")
+	}
+	s.cert, err = sm2.ParseCertificate(der)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse CA certificate")
+	}
+	s.certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return nil
+}
+
+// issue creates an enrollment certificate for the identity from the PEM encoded certificate request
+func (s *CAServer) issue(id *caIdentity, req *api.EnrollmentRequestNet) ([]byte, error) {
+	block, _ := pem.Decode([]byte(req.Request))
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, newCAError(http.StatusBadRequest, "certificate request is not PEM encoded")
+	}
+	csr, err := sm2.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, newCAError(http.StatusBadRequest, "failed to parse certificate request: %s", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, newCAError(http.StatusBadRequest, "invalid certificate request signature: %s", err)
+	}
+
+	ext, err := s.attributesExtension(id, req.AttrReqs)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	subject := csr.Subject
+	subject.CommonName = id.id
+	subject.OrganizationalUnit = []string{id.typ}
+	if id.affiliation != "" {
+		subject.OrganizationalUnit = append(subject.OrganizationalUnit, strings.Split(id.affiliation, ".")...)
+	}
+
+	now := time.Now()
+	template := &sm2.Certificate{
+		SerialNumber:          serial,
+		Subject:               subject,
+		NotBefore:             now.Add(-5 * time.Minute),
+		NotAfter:              now.Add(ecertValidity),
+		KeyUsage:              sm2.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		SubjectKeyId:          publicKeyID(csr.PublicKey),
+		AuthorityKeyId:        s.cert.SubjectKeyId,
+		SignatureAlgorithm:    s.signatureAlgorithm(),
+	}
+	for _, host := range req.Hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	if ext != nil {
+		template.ExtraExtensions = []pkix.Extension{*ext}
+	}
+
+	der, err := sm2.CreateCertificate(rand.Reader, template, s.cert, csr.PublicKey, s.key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create certificate for [%s]", id.id)
+	}
+
+	s.certs[serialKey(serial)] = &issuedCert{id: id.id, serial: serial, notAfter: template.NotAfter}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), nil
+}
+
+// attributesExtension returns the certificate extension with the requested attributes. If no
+// attributes are requested then the attributes which were registered with ecert=true are added.
+func (s *CAServer) attributesExtension(id *caIdentity, attrReqs []*api.AttributeRequest) (*pkix.Extension, error) {
+	var attrs *attrmgr.Attributes
+	if attrReqs == nil {
+		attrs = &attrmgr.Attributes{Attrs: make(map[string]string)}
+		for _, a := range id.attrs {
+			if a.ECert {
+				attrs.Attrs[a.Name] = a.Value
+			}
+		}
+	} else {
+		var requests []attrmgr.AttributeRequest
+		for _, r := range attrReqs {
+			requests = append(requests, r)
+		}
+		var attributes []attrmgr.Attribute
+		for i := range id.attrs {
+			attributes = append(attributes, &id.attrs[i])
+		}
+
+		var err error
+		attrs, err = attrmgr.New().ProcessAttributeRequests(requests, attributes)
+		if err != nil {
+			return nil, newCAError(http.StatusBadRequest, "%s", err)
+		}
+	}
+
+	if len(attrs.Attrs) == 0 {
+		return nil, nil
+	}
+	value, err := json.Marshal(attrs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal attributes")
+	}
+	return &pkix.Extension{Id: attrmgr.AttrOID, Value: value}, nil
+}
+
+// revokeCerts revokes all unrevoked certificates of the identity and returns them
+func (s *CAServer) revokeCerts(id string, reason int) []*issuedCert {
+	var revoked []*issuedCert
+	for _, c := range s.certs {
+		if c.id == id && c.revokedAt.IsZero() {
+			c.revokedAt, c.reason = time.Now(), reason
+			revoked = append(revoked, c)
+		}
+	}
+	sort.Slice(revoked, func(i, j int) bool { return revoked[i].serial.Cmp(revoked[j].serial) < 0 })
+	return revoked
+}
+
+func (s *CAServer) generateCRL() ([]byte, error) {
+	now := time.Now()
+
+	var revoked []pkix.RevokedCertificate
+	for _, c := range s.certs {
+		if !c.revokedAt.IsZero() && c.notAfter.After(now) {
+			revoked = append(revoked, pkix.RevokedCertificate{SerialNumber: c.serial, RevocationTime: c.revokedAt})
+		}
+	}
+	sort.Slice(revoked, func(i, j int) bool { return revoked[i].SerialNumber.Cmp(revoked[j].SerialNumber) < 0 })
+
+	der, err := s.cert.CreateCRL(rand.Reader, s.key, revoked, now, now.Add(crlValidity))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CRL")
+	}
+	if s.gm {
+		if der, err = s.resignCRL(der); err != nil {
+			return nil, err
+		}
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), nil
+}
+
+// resignCRL replaces the signature of an SM2 CRL. sm2.CreateCRL hashes the CRL before signing
+// although SM2 signing hashes the message itself, so the signature would not verify.
+func (s *CAServer) resignCRL(der []byte) ([]byte, error) {
+	crl, err := sm2.ParseCRL(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse CRL")
+	}
+	sig, err := s.key.Sign(rand.Reader, crl.TBSCertList.Raw, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign CRL")
+	}
+	crl.SignatureValue = asn1.BitString{Bytes: sig, BitLength: len(sig) * 8}
+
+	der, err = asn1.Marshal(*crl)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal CRL")
+	}
+	return der, nil
+}
+
+// signatureAlgorithm returns the signature algorithm of issued certificates. SM2-SM3 must be set
+// explicitly since sm2.CreateCertificate otherwise hashes the certificate twice.
+func (s *CAServer) signatureAlgorithm() sm2.SignatureAlgorithm {
+	if s.gm {
+		return sm2.SM2WithSM3
+	}
+	return sm2.UnknownSignatureAlgorithm
+}
+
+// authenticateToken returns the identity which signed the request. The token in the authorization
+// header consists of the caller's enrollment certificate and its signature over the request.
+func (s *CAServer) authenticateToken(r *http.Request, body []byte) (*caIdentity, error) {
+	token := r.Header.Get("authorization")
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, newCAError(http.StatusUnauthorized, "invalid authorization token")
+	}
+	certPEM, err := base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, newCAError(http.StatusUnauthorized, "invalid certificate in authorization token")
+	}
+	sig, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, newCAError(http.StatusUnauthorized, "invalid signature in authorization token")
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, newCAError(http.StatusUnauthorized, "invalid certificate in authorization token")
+	}
+	cert, err := sm2.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, newCAError(http.StatusUnauthorized, "invalid certificate in authorization token: %s", err)
+	}
+	if err := cert.CheckSignatureFrom(s.cert); err != nil {
+		return nil, newCAError(http.StatusUnauthorized, "certificate was not issued by this CA")
+	}
+	if c, ok := s.certs[serialKey(cert.SerialNumber)]; !ok || !c.revokedAt.IsZero() {
+		return nil, newCAError(http.StatusUnauthorized, "certificate has been revoked")
+	}
+
+	payload := []byte(r.Method + "." + base64.StdEncoding.EncodeToString([]byte(r.URL.RequestURI())) + "." +
+		base64.StdEncoding.EncodeToString(body) + "." + parts[0])
+	if !verify(cert.PublicKey, payload, sig) {
+		return nil, newCAError(http.StatusUnauthorized, "invalid signature in authorization token")
+	}
+
+	id, ok := s.identities[cert.Subject.CommonName]
+	if !ok || id.revoked {
+		return nil, newCAError(http.StatusUnauthorized, "identity [%s] is not registered or has been revoked", cert.Subject.CommonName)
+	}
+	return id, nil
+}
+
+// authenticateSecret returns the identity of the basic authentication credentials
+func (s *CAServer) authenticateSecret(r *http.Request) (*caIdentity, error) {
+	user, secret, ok := r.BasicAuth()
+	if !ok {
+		return nil, newCAError(http.StatusUnauthorized, "basic authentication is required")
+	}
+	id, exists := s.identities[user]
+	if !exists || id.secret != secret {
+		return nil, newCAError(http.StatusUnauthorized, "authentication failure")
+	}
+	if id.revoked {
+		return nil, newCAError(http.StatusUnauthorized, "identity [%s] has been revoked", user)
+	}
+	if id.maxEnrollments > 0 && id.enrollments >= id.maxEnrollments {
+		return nil, newCAError(http.StatusUnauthorized, "identity [%s] has already enrolled %d times", user, id.enrollments)
+	}
+	return id, nil
+}
+
+// verify verifies the signature over the message. The SW crypto suite hashes the message with
+// SHA-256 and signs with ECDSA. The GM crypto suite hashes with SM3 and signs with SM2, also
+// if the key is an ECDSA key.
+func verify(pub interface{}, msg, sig []byte) bool {
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		if r, s, err := utils.UnmarshalECDSASignature(sig); err == nil {
+			digest := sha256.Sum256(msg)
+			if ecdsa.Verify(key, digest[:], r, s) {
+				return true
+			}
+		}
+		return (&sm2.PublicKey{Curve: key.Curve, X: key.X, Y: key.Y}).Verify(sm3.Sm3Sum(msg), sig)
+	case *sm2.PublicKey:
+		return key.Verify(sm3.Sm3Sum(msg), sig)
+	default:
+		return false
+	}
+}
+
+func publicKeyID(pub interface{}) []byte {
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		return subjectKeyID(key)
+	case *sm2.PublicKey:
+		ski := sha256.Sum256(elliptic.Marshal(key.Curve, key.X, key.Y))
+		return ski[:]
+	default:
+		return nil
+	}
+}
+
+func newSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate serial number")
+	}
+	return serial, nil
+}
+
+func serialKey(serial *big.Int) string {
+	return serial.Text(16)
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 9)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate secret")
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// caError is an error which is returned to the client with the given HTTP status
+type caError struct {
+	status int
+	msg    string
+}
+
+func newCAError(status int, format string, args ...interface{}) error {
+	return &caError{status: status, msg: fmt.Sprintf(format, args...)}
+}
+
+func (e *caError) Error() string {
+	return e.msg
+}
+
+// serveHTTP authenticates the request, dispatches it to the endpoint and writes the
+// result in the cfssl response format used by fabric-ca
+func (s *CAServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	result, err := s.process(r)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if caErr, ok := err.(*caError); ok {
+			status = caErr.status
+		}
+		logger.Debugf("CA request [%s %s] failed: %s", r.Method, r.URL.Path, err)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(cfsslapi.NewErrorResponse(err.Error(), status)); err != nil {
+			logger.Debugf("Failed to write CA response: %s", err)
+		}
+		return
+	}
+	if err := cfsslapi.SendResponse(w, result); err != nil {
+		logger.Debugf("Failed to write CA response: %s", err)
+	}
+}
+
+func (s *CAServer) process(r *http.Request) (interface{}, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, newCAError(http.StatusBadRequest, "failed to read request body")
+	}
+
+	path := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/v1"), "/")
+	resource, name := path, ""
+	if i := strings.Index(path, "/"); i >= 0 {
+		resource, name = path[:i], path[i+1:]
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch resource {
+	case "cainfo":
+		return s.caInfo(), nil
+	case "enroll":
+		id, err := s.authenticateSecret(r)
+		if err != nil {
+			return nil, err
+		}
+		return s.enroll(id, body, true)
+	}
+
+	caller, err := s.authenticateToken(r, body)
+	if err != nil {
+		return nil, err
+	}
+	req := &caRequest{http: r, body: body, caller: caller, name: name}
+	if err := s.checkCAName(req); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resource == "reenroll" && r.Method == http.MethodPost:
+		return s.enroll(caller, body, false)
+	case resource == "register" && r.Method == http.MethodPost:
+		return s.register(req)
+	case resource == "revoke" && r.Method == http.MethodPost:
+		return s.revoke(req)
+	case resource == "identities":
+		return s.identitiesEndpoint(req)
+	case resource == "affiliations":
+		return s.affiliationsEndpoint(req)
+	default:
+		return nil, newCAError(http.StatusNotFound, "endpoint [%s %s] is not supported", r.Method, r.URL.Path)
+	}
+}
+
+// caRequest is an authenticated request to the CA server
+type caRequest struct {
+	http   *http.Request
+	body   []byte
+	caller *caIdentity
+	name   string
+}
+
+func (r *caRequest) unmarshal(v interface{}) error {
+	if err := json.Unmarshal(r.body, v); err != nil {
+		return newCAError(http.StatusBadRequest, "failed to parse request body: %s", err)
+	}
+	return nil
+}
+
+func (r *caRequest) force() bool {
+	return r.http.URL.Query().Get("force") == "true"
+}
+
+// checkCAName verifies the CA name of the query parameters
+func (s *CAServer) checkCAName(req *caRequest) error {
+	if ca := req.http.URL.Query().Get("ca"); ca != "" && ca != s.caName {
+		return newCAError(http.StatusBadRequest, "CA [%s] does not exist", ca)
+	}
+	return nil
+}
+
+func (s *CAServer) caInfo() *common.CAInfoResponseNet {
+	return &common.CAInfoResponseNet{
+		CAName:  s.caName,
+		CAChain: base64.StdEncoding.EncodeToString(s.certPEM),
+		Version: caVersion,
+	}
+}
+
+func (s *CAServer) enroll(id *caIdentity, body []byte, count bool) (interface{}, error) {
+	req := &api.EnrollmentRequestNet{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, newCAError(http.StatusBadRequest, "failed to parse enrollment request: %s", err)
+	}
+	if req.CAName != "" && req.CAName != s.caName {
+		return nil, newCAError(http.StatusBadRequest, "CA [%s] does not exist", req.CAName)
+	}
+
+	certPEM, err := s.issue(id, req)
+	if err != nil {
+		return nil, err
+	}
+	if count {
+		id.enrollments++
+	}
+
+	return &common.EnrollmentResponseNet{
+		Cert:       base64.StdEncoding.EncodeToString(certPEM),
+		ServerInfo: *s.caInfo(),
+	}, nil
+}
+
+func (s *CAServer) register(req *caRequest) (interface{}, error) {
+	r := &api.RegistrationRequest{}
+	if err := req.unmarshal(r); err != nil {
+		return nil, err
+	}
+	id, err := s.registerIdentity(req.caller, r.Name, r.Type, r.Affiliation, r.Secret, r.MaxEnrollments, r.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	return &api.RegistrationResponse{Secret: id.secret}, nil
+}
+
+func (s *CAServer) registerIdentity(caller *caIdentity, name, typ, affiliation, secret string, maxEnrollments int, attrs []api.Attribute) (*caIdentity, error) {
+	if name == "" {
+		return nil, newCAError(http.StatusBadRequest, "identity name is required")
+	}
+	if _, exists := s.identities[name]; exists {
+		return nil, newCAError(http.StatusConflict, "identity [%s] is already registered", name)
+	}
+	if typ == "" {
+		typ = "client"
+	}
+	if err := s.checkRegistrar(caller, typ, affiliation, attrs); err != nil {
+		return nil, err
+	}
+	if maxEnrollments == 0 {
+		maxEnrollments = s.maxEnrollments
+	} else if s.maxEnrollments > 0 && (maxEnrollments < 0 || maxEnrollments > s.maxEnrollments) {
+		return nil, newCAError(http.StatusBadRequest, "max enrollments must not exceed %d", s.maxEnrollments)
+	}
+	if secret == "" {
+		var err error
+		if secret, err = newSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	id := &caIdentity{
+		id:             name,
+		typ:            typ,
+		affiliation:    affiliation,
+		secret:         secret,
+		maxEnrollments: maxEnrollments,
+	}
+	id.attrs = mergeAttributes(defaultAttributes(id), attrs)
+	s.identities[name] = id
+
+	logger.Debugf("Registered identity [%s] of type [%s] with affiliation [%s]", name, typ, affiliation)
+	return id, nil
+}
+
+// checkRegistrar verifies that the caller may register or modify an identity with the given type,
+// affiliation and attributes
+func (s *CAServer) checkRegistrar(caller *caIdentity, typ, affiliation string, attrs []api.Attribute) error {
+	if !hasValue(caller.attr(attrRegistrarRoles), typ) {
+		return newCAError(http.StatusForbidden, "identity [%s] may not act on identities of type [%s]", caller.id, typ)
+	}
+	if affiliation != "" {
+		if _, ok := s.affiliations[affiliation]; !ok {
+			return newCAError(http.StatusBadRequest, "affiliation [%s] does not exist", affiliation)
+		}
+	}
+	if !isAffiliated(caller.affiliation, affiliation) {
+		return newCAError(http.StatusForbidden, "identity [%s] may not act on affiliation [%s]", caller.id, affiliation)
+	}
+	for _, a := range attrs {
+		if isDefaultAttribute(a.Name) {
+			return newCAError(http.StatusBadRequest, "attribute [%s] is reserved", a.Name)
+		}
+		if !hasValue(caller.attr(attrRegistrarAttributes), a.Name) {
+			return newCAError(http.StatusForbidden, "identity [%s] may not register attribute [%s]", caller.id, a.Name)
+		}
+	}
+	return nil
+}
+
+// canAccess returns true if the caller may act on the identity
+func (s *CAServer) canAccess(caller, id *caIdentity) bool {
+	return hasValue(caller.attr(attrRegistrarRoles), id.typ) && isAffiliated(caller.affiliation, id.affiliation)
+}
+
+func (s *CAServer) revoke(req *caRequest) (interface{}, error) {
+	r := &api.RevocationRequest{}
+	if err := req.unmarshal(r); err != nil {
+		return nil, err
+	}
+	if r.CAName != "" && r.CAName != s.caName {
+		return nil, newCAError(http.StatusBadRequest, "CA [%s] does not exist", r.CAName)
+	}
+	reason, err := revocationReason(r.Reason)
+	if err != nil {
+		return nil, err
+	}
+	if r.GenCRL && req.caller.attr(attrGenCRL) != "true" {
+		return nil, newCAError(http.StatusForbidden, "identity [%s] may not generate a CRL", req.caller.id)
+	}
+
+	var revoked []*issuedCert
+	switch {
+	case r.Name != "":
+		id, ok := s.identities[r.Name]
+		if !ok {
+			return nil, newCAError(http.StatusNotFound, "identity [%s] is not registered", r.Name)
+		}
+		if err := s.checkRevoker(req.caller, id); err != nil {
+			return nil, err
+		}
+		id.revoked = true
+		revoked = s.revokeCerts(id.id, reason)
+	case r.Serial != "" && r.AKI != "":
+		if !strings.EqualFold(r.AKI, hex.EncodeToString(s.cert.SubjectKeyId)) {
+			return nil, newCAError(http.StatusNotFound, "certificate with AKI [%s] was not issued by this CA", r.AKI)
+		}
+		serial, ok := new(big.Int).SetString(r.Serial, 16)
+		if !ok {
+			return nil, newCAError(http.StatusBadRequest, "invalid serial number [%s]", r.Serial)
+		}
+		c, ok := s.certs[serialKey(serial)]
+		if !ok {
+			return nil, newCAError(http.StatusNotFound, "certificate with serial number [%s] does not exist", r.Serial)
+		}
+		if !c.revokedAt.IsZero() {
+			return nil, newCAError(http.StatusBadRequest, "certificate with serial number [%s] is already revoked", r.Serial)
+		}
+		if c.id != req.caller.id {
+			if err := s.checkRevoker(req.caller, s.identities[c.id]); err != nil {
+				return nil, err
+			}
+		}
+		c.revokedAt, c.reason = time.Now(), reason
+		revoked = []*issuedCert{c}
+	default:
+		return nil, newCAError(http.StatusBadRequest, "either the identity name or the serial number and AKI are required")
+	}
+
+	aki := hex.EncodeToString(s.cert.SubjectKeyId)
+	resp := &struct {
+		RevokedCerts []api.RevokedCert
+		CRL          string
+	}{RevokedCerts: []api.RevokedCert{}}
+	for _, c := range revoked {
+		resp.RevokedCerts = append(resp.RevokedCerts, api.RevokedCert{Serial: serialKey(c.serial), AKI: aki})
+	}
+	if r.GenCRL {
+		crl, err := s.generateCRL()
+		if err != nil {
+			return nil, err
+		}
+		resp.CRL = base64.StdEncoding.EncodeToString(crl)
+	}
+	return resp, nil
+}
+
+func (s *CAServer) checkRevoker(caller, id *caIdentity) error {
+	if caller.attr(attrRevoker) != "true" {
+		return newCAError(http.StatusForbidden, "identity [%s] may not revoke certificates", caller.id)
+	}
+	if id == nil || !s.canAccess(caller, id) {
+		return newCAError(http.StatusForbidden, "identity [%s] may not revoke the certificates of another identity", caller.id)
+	}
+	return nil
+}
+
+// revocationReasons are the reason codes of RFC 5280 by the names accepted by fabric-ca
+var revocationReasons = map[string]int{
+	"unspecified":          0,
+	"keycompromise":        1,
+	"cacompromise":         2,
+	"affiliationchange":    3,
+	"superseded":           4,
+	"cessationofoperation": 5,
+	"certificatehold":      6,
+	"removefromcrl":        8,
+	"privilegewithdrawn":   9,
+	"aacompromise":         10,
+}
+
+func revocationReason(reason string) (int, error) {
+	if reason == "" {
+		return 0, nil
+	}
+	code, ok := revocationReasons[strings.ToLower(reason)]
+	if !ok {
+		return 0, newCAError(http.StatusBadRequest, "invalid revocation reason [%s]", reason)
+	}
+	return code, nil
+}
+
+// defaultAttributes are the attributes which fabric-ca adds to every identity
+func defaultAttributes(id *caIdentity) []api.Attribute {
+	return []api.Attribute{
+		{Name: attrEnrollmentID, Value: id.id, ECert: true},
+		{Name: attrType, Value: id.typ, ECert: true},
+		{Name: attrAffiliation, Value: id.affiliation, ECert: true},
+	}
+}
+
+func isDefaultAttribute(name string) bool {
+	return name == attrEnrollmentID || name == attrType || name == attrAffiliation
+}
+
+// mergeAttributes adds or replaces the attributes. Attributes with an empty value are removed.
+func mergeAttributes(attrs, updates []api.Attribute) []api.Attribute {
+	for _, u := range updates {
+		i := 0
+		for i < len(attrs) && attrs[i].Name != u.Name {
+			i++
+		}
+		switch {
+		case u.Value == "" && i < len(attrs):
+			attrs = append(attrs[:i], attrs[i+1:]...)
+		case u.Value == "":
+		case i < len(attrs):
+			attrs[i] = u
+		default:
+			attrs = append(attrs, u)
+		}
+	}
+	return attrs
+}
+
+// hasValue returns true if the comma separated list contains the value or "*"
+func hasValue(list, value string) bool {
+	for _, v := range strings.Split(list, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || v == value || (strings.HasSuffix(v, "*") && strings.HasPrefix(value, strings.TrimSuffix(v, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAffiliated returns true if the affiliation is the parent affiliation or one of its descendants
+func isAffiliated(parent, affiliation string) bool {
+	return parent == "" || affiliation == parent || strings.HasPrefix(affiliation, parent+".")
+}
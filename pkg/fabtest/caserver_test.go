@@ -0,0 +1,269 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib/attrmgr"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tjfoc/gmsm/sm2"
+)
+
+func TestCAServer(t *testing.T) {
+	ca, err := NewCAServer()
+	require.NoError(t, err)
+	defer ca.Stop()
+
+	profile := caProfile(t, ca, "SW")
+	defer os.RemoveAll(profile.Client.CredentialStore.CryptoStore.Path)
+
+	sdk, err := fabsdk.New(config.FromProfile(profile))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	client, err := msp.New(sdk.Context())
+	require.NoError(t, err)
+
+	info, err := client.GetCAInfo()
+	require.NoError(t, err)
+	assert.Equal(t, ca.CAName(), info.CAName)
+
+	secret, err := client.Register(&msp.RegistrationRequest{
+		Name:        "user1",
+		Type:        "client",
+		Affiliation: "org1.department1",
+		Attributes: []msp.Attribute{
+			{Name: "app.role", Value: "auditor", ECert: true},
+			{Name: "app.level", Value: "3"},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Error(t, client.Enroll("user1", msp.WithSecret("wrong")))
+	require.NoError(t, client.Enroll("user1", msp.WithSecret(secret)))
+
+	attrs := certAttributes(t, client, "user1")
+	assert.Equal(t, "auditor", attrs["app.role"])
+	assert.Equal(t, "user1", attrs["hf.EnrollmentID"])
+	assert.NotContains(t, attrs, "app.level")
+
+	require.NoError(t, client.Reenroll("user1", msp.WithAttributeRequests([]*msp.AttributeRequest{{Name: "app.level"}})))
+	attrs = certAttributes(t, client, "user1")
+	assert.Equal(t, map[string]string{"app.level": "3"}, attrs)
+
+	err = client.Reenroll("user1", msp.WithAttributeRequests([]*msp.AttributeRequest{{Name: "app.missing"}}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "required attributes are missing")
+
+	// Identities
+	id, err := client.GetIdentity("user1")
+	require.NoError(t, err)
+	assert.Equal(t, "org1.department1", id.Affiliation)
+
+	id, err = client.ModifyIdentity(&msp.IdentityRequest{ID: "user1", Affiliation: "org1.department2"})
+	require.NoError(t, err)
+	assert.Equal(t, "org1.department2", id.Affiliation)
+
+	id, err = client.CreateIdentity(&msp.IdentityRequest{ID: "user2", Affiliation: "org2.department1", Type: "peer"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, id.Secret)
+
+	ids, err := client.GetAllIdentities()
+	require.NoError(t, err)
+	require.Len(t, ids, 3)
+	assert.Equal(t, "user2", ids[2].ID)
+
+	_, err = client.RemoveIdentity(&msp.RemoveIdentityRequest{ID: "user2"})
+	require.NoError(t, err)
+	_, err = client.GetIdentity("user2")
+	require.Error(t, err)
+
+	// Affiliations
+	affs, err := client.GetAllAffiliations()
+	require.NoError(t, err)
+	require.Len(t, affs.Affiliations, 2)
+	assert.Equal(t, "org1", affs.Affiliations[0].Name)
+	assert.Len(t, affs.Affiliations[0].Affiliations, 2)
+
+	_, err = client.AddAffiliation(&msp.AffiliationRequest{Name: "org3.department1"})
+	require.Error(t, err)
+	_, err = client.AddAffiliation(&msp.AffiliationRequest{Name: "org3.department1", Force: true})
+	require.NoError(t, err)
+
+	aff, err := client.ModifyAffiliation(&msp.ModifyAffiliationRequest{AffiliationRequest: msp.AffiliationRequest{Name: "org3"}, NewName: "org4"})
+	require.NoError(t, err)
+	assert.Equal(t, "org4.department1", aff.Affiliations[0].Name)
+
+	_, err = client.RemoveAffiliation(&msp.AffiliationRequest{Name: "org4"})
+	require.Error(t, err)
+	_, err = client.RemoveAffiliation(&msp.AffiliationRequest{Name: "org4", Force: true})
+	require.NoError(t, err)
+	_, err = client.GetAffiliation("org4")
+	require.Error(t, err)
+
+	// Revocation
+	resp, err := client.Revoke(&msp.RevocationRequest{Name: "user1", Reason: "keycompromise"})
+	require.NoError(t, err)
+	assert.Len(t, resp.RevokedCerts, 2)
+
+	err = client.Enroll("user1", msp.WithSecret(secret))
+	require.Error(t, err)
+
+	crl := parseCRL(t, ca)
+	assert.Len(t, crl.TBSCertList.RevokedCertificates, 2)
+}
+
+func TestCAServerMaxEnrollments(t *testing.T) {
+	_, err := NewCAServer(WithMaxEnrollments(0))
+	assert.Error(t, err)
+
+	ca, err := NewCAServer(WithCAName("ca.org2.example.com"), WithRegistrar("registrar", "registrarpw"),
+		WithAffiliations("org2"), WithMaxEnrollments(1))
+	require.NoError(t, err)
+	defer ca.Stop()
+
+	profile := caProfile(t, ca, "SW")
+	defer os.RemoveAll(profile.Client.CredentialStore.CryptoStore.Path)
+
+	sdk, err := fabsdk.New(config.FromProfile(profile))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	client, err := msp.New(sdk.Context())
+	require.NoError(t, err)
+
+	_, err = client.Register(&msp.RegistrationRequest{Name: "user1", Affiliation: "org1"})
+	require.Error(t, err)
+
+	secret, err := client.Register(&msp.RegistrationRequest{Name: "user1", Affiliation: "org2"})
+	require.NoError(t, err)
+	require.NoError(t, client.Enroll("user1", msp.WithSecret(secret)))
+	require.Error(t, client.Enroll("user1", msp.WithSecret(secret)))
+}
+
+func TestCAServerSM2(t *testing.T) {
+	ca, err := NewCAServer(WithSM2())
+	require.NoError(t, err)
+	defer ca.Stop()
+
+	profile := caProfile(t, ca, "GM")
+	defer os.RemoveAll(profile.Client.CredentialStore.CryptoStore.Path)
+
+	sdk, err := fabsdk.New(config.FromProfile(profile))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	client, err := msp.New(sdk.Context())
+	require.NoError(t, err)
+
+	secret, err := client.Register(&msp.RegistrationRequest{Name: "user1", Affiliation: "org1"})
+	require.NoError(t, err)
+	require.NoError(t, client.Enroll("user1", msp.WithSecret(secret)))
+	require.NoError(t, client.Reenroll("user1"))
+
+	signingIdentity, err := client.GetSigningIdentity("user1")
+	require.NoError(t, err)
+	block, _ := pem.Decode(signingIdentity.EnrollmentCertificate())
+	require.NotNil(t, block)
+	cert, err := sm2.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, sm2.SM2WithSM3, cert.SignatureAlgorithm)
+
+	_, err = client.Revoke(&msp.RevocationRequest{Name: "user1"})
+	require.NoError(t, err)
+	crl := parseCRL(t, ca)
+	assert.Len(t, crl.TBSCertList.RevokedCertificates, 2)
+}
+
+func TestHasValue(t *testing.T) {
+	assert.True(t, hasValue("*", "peer"))
+	assert.True(t, hasValue("client, peer", "peer"))
+	assert.True(t, hasValue("app.*", "app.role"))
+	assert.False(t, hasValue("client", "peer"))
+	assert.False(t, hasValue("", "peer"))
+}
+
+// caProfile returns a minimal connection profile for an organization whose CA is the given server.
+// The SDK requires at least one embedded user, which is issued by an unrelated CA. Enrolled keys
+// are stored in a temporary directory which must be removed by the caller.
+func caProfile(t *testing.T, ca *CAServer, provider string) *config.Profile {
+	org, err := newOrganization("Org1", "Org1MSP", "org1.example.com")
+	require.NoError(t, err)
+	cryptoStore, err := ioutil.TempDir("", "fabtest")
+	require.NoError(t, err)
+
+	enabled := true
+	return &config.Profile{
+		Version: "1.0.0",
+		Client: config.ClientProfile{
+			Organization: "Org1",
+			Logging:      config.LoggingProfile{Level: "info"},
+			BCCSP: config.BCCSPProfile{
+				Security: config.SecurityProfile{
+					Enabled:       &enabled,
+					Default:       config.ProviderProfile{Provider: provider},
+					HashAlgorithm: "SHA2",
+					SoftVerify:    &enabled,
+					Level:         256,
+					Ephemeral:     &enabled,
+				},
+			},
+			CredentialStore: config.CredentialStoreProfile{CryptoStore: config.PathProfile{Path: cryptoStore}},
+		},
+		Organizations: map[string]config.OrganizationProfile{
+			"Org1": {
+				MSPID:                  "Org1MSP",
+				Users:                  map[string]config.TLSKeyPairProfile{UserName: keyPairProfile(org.user)},
+				CertificateAuthorities: []string{ca.CAName()},
+			},
+		},
+		CertificateAuthorities: map[string]config.CAProfile{
+			ca.CAName(): ca.CAProfile(),
+		},
+	}
+}
+
+func certAttributes(t *testing.T, client *msp.Client, id string) map[string]string {
+	signingIdentity, err := client.GetSigningIdentity(id)
+	require.NoError(t, err)
+
+	block, _ := pem.Decode(signingIdentity.EnrollmentCertificate())
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	attrs, err := attrmgr.New().GetAttributesFromCert(cert)
+	require.NoError(t, err)
+	return attrs.Attrs
+}
+
+// parseCRL returns the CRL of the CA server after verifying its signature
+func parseCRL(t *testing.T, ca *CAServer) *pkix.CertificateList {
+	crlPEM, err := ca.CRL()
+	require.NoError(t, err)
+	block, _ := pem.Decode(crlPEM)
+	require.NotNil(t, block)
+	crl, err := sm2.ParseCRL(block.Bytes)
+	require.NoError(t, err)
+
+	caBlock, _ := pem.Decode(ca.CACertificate())
+	require.NotNil(t, caBlock)
+	caCert, err := sm2.ParseCertificate(caBlock.Bytes)
+	require.NoError(t, err)
+	require.NoError(t, caCert.CheckCRLSignature(crl))
+	return crl
+}
@@ -0,0 +1,271 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// Chaincode is a chaincode implemented as a Go function. The returned bytes are the payload
+// of the response. If an error is returned then the proposal fails with status 500 and the
+// error message, and none of the writes are endorsed.
+type Chaincode func(stub *Stub) ([]byte, error)
+
+// Init returns a successful response without invoking the function. Chaincode functions are
+// stateless so there is nothing to initialize.
+func (cc Chaincode) Init(stub *Stub) pb.Response {
+	return Success(nil)
+}
+
+// Invoke invokes the function and converts the result into a response
+func (cc Chaincode) Invoke(stub *Stub) pb.Response {
+	payload, err := cc(stub)
+	if err != nil {
+		return Error(err.Error())
+	}
+	return Success(payload)
+}
+
+// ShimChaincode is a chaincode with the Init and Invoke functions of a Fabric shim chaincode.
+// The functions are given a Stub instead of the shim's stub interface. Responses with a status
+// of 400 or above are errors, as in Fabric.
+type ShimChaincode interface {
+	Init(stub *Stub) pb.Response
+	Invoke(stub *Stub) pb.Response
+}
+
+// Success returns a response with status 200 and the given payload
+func Success(payload []byte) pb.Response {
+	return pb.Response{Status: 200, Payload: payload}
+}
+
+// Error returns a response with status 500 and the given message
+func Error(msg string) pb.Response {
+	return pb.Response{Status: 500, Message: msg}
+}
+
+// KV is a key and value returned by a range query
+type KV struct {
+	Key   string
+	Value []byte
+}
+
+// stateReader provides the committed state against which chaincodes are simulated
+type stateReader interface {
+	State(namespace, key string) *versionedValue
+	StateRange(namespace, startKey, endKey string) []string
+}
+
+// Stub gives a chaincode access to the invocation arguments and the world state. Reads return
+// committed state (i.e. a transaction does not see its own writes, as in Fabric).
+type Stub struct {
+	channelID string
+	txID      string
+	creator   []byte
+	args      [][]byte
+	transient map[string][]byte
+	namespace string
+	state     stateReader
+	reads     map[string]*kvrwset.KVRead
+	writes    map[string]*kvrwset.KVWrite
+	event     *pb.ChaincodeEvent
+}
+
+func newStub(channelID, txID, namespace string, creator []byte, args [][]byte, transient map[string][]byte, state stateReader) *Stub {
+	return &Stub{
+		channelID: channelID,
+		txID:      txID,
+		creator:   creator,
+		args:      args,
+		transient: transient,
+		namespace: namespace,
+		state:     state,
+		reads:     make(map[string]*kvrwset.KVRead),
+		writes:    make(map[string]*kvrwset.KVWrite),
+	}
+}
+
+// ChannelID returns the channel of the proposal
+func (s *Stub) ChannelID() string {
+	return s.channelID
+}
+
+// TxID returns the transaction ID of the proposal
+func (s *Stub) TxID() string {
+	return s.txID
+}
+
+// Creator returns the serialized identity of the client which submitted the proposal
+func (s *Stub) Creator() []byte {
+	return s.creator
+}
+
+// Args returns the invocation arguments, including the function name
+func (s *Stub) Args() [][]byte {
+	return s.args
+}
+
+// Function returns the function name (the first argument)
+func (s *Stub) Function() string {
+	if len(s.args) == 0 {
+		return ""
+	}
+	return string(s.args[0])
+}
+
+// Parameters returns the arguments following the function name as strings
+func (s *Stub) Parameters() []string {
+	var params []string
+	for i := 1; i < len(s.args); i++ {
+		params = append(params, string(s.args[i]))
+	}
+	return params
+}
+
+// Transient returns the transient data of the proposal
+func (s *Stub) Transient() map[string][]byte {
+	return s.transient
+}
+
+// GetState returns the committed value of the given key or nil if the key doesn't exist
+func (s *Stub) GetState(key string) ([]byte, error) {
+	if key == "" {
+		return nil, errors.New("key must not be empty")
+	}
+
+	value := s.state.State(s.namespace, key)
+	s.addRead(key, value)
+	if value == nil {
+		return nil, nil
+	}
+	return value.value, nil
+}
+
+// GetStateByRange returns the committed keys and values in the range [startKey, endKey).
+// An empty endKey means that the range is unbounded.
+func (s *Stub) GetStateByRange(startKey, endKey string) ([]*KV, error) {
+	var kvs []*KV
+	for _, key := range s.state.StateRange(s.namespace, startKey, endKey) {
+		value := s.state.State(s.namespace, key)
+		if value == nil {
+			continue
+		}
+		s.addRead(key, value)
+		kvs = append(kvs, &KV{Key: key, Value: value.value})
+	}
+	return kvs, nil
+}
+
+// PutState writes the given value when the transaction is committed
+func (s *Stub) PutState(key string, value []byte) error {
+	if key == "" {
+		return errors.New("key must not be empty")
+	}
+	if value == nil {
+		return errors.Errorf("value for key [%s] must not be nil", key)
+	}
+	s.writes[key] = &kvrwset.KVWrite{Key: key, Value: value}
+	return nil
+}
+
+// DelState deletes the given key when the transaction is committed
+func (s *Stub) DelState(key string) error {
+	if key == "" {
+		return errors.New("key must not be empty")
+	}
+	s.writes[key] = &kvrwset.KVWrite{Key: key, IsDelete: true}
+	return nil
+}
+
+// SetEvent sets the chaincode event of the transaction. Only one event may be set per
+// transaction so subsequent calls replace the event.
+func (s *Stub) SetEvent(name string, payload []byte) error {
+	if name == "" {
+		return errors.New("event name must not be empty")
+	}
+	s.event = &pb.ChaincodeEvent{EventName: name, Payload: payload}
+	return nil
+}
+
+func (s *Stub) addRead(key string, value *versionedValue) {
+	if _, ok := s.reads[key]; ok {
+		return
+	}
+	read := &kvrwset.KVRead{Key: key}
+	if value != nil {
+		read.Version = value.version
+	}
+	s.reads[key] = read
+}
+
+// rwSet returns the read-write set of the simulation with reads and writes sorted by key
+func (s *Stub) rwSet() *rwsetutil.TxRwSet {
+	kvRWSet := &kvrwset.KVRWSet{}
+	for _, key := range sortedKeys(s.reads) {
+		kvRWSet.Reads = append(kvRWSet.Reads, s.reads[key])
+	}
+	for _, key := range sortedKeys(s.writes) {
+		kvRWSet.Writes = append(kvRWSet.Writes, s.writes[key])
+	}
+	return &rwsetutil.TxRwSet{
+		NsRwSets: []*rwsetutil.NsRwSet{{NameSpace: s.namespace, KvRwSet: kvRWSet}},
+	}
+}
+
+// simulate calls the chaincode function (Init or Invoke) and returns the chaincode action containing
+// the response, the read-write set and the chaincode event. Error responses have no results.
+func simulate(fn func(stub *Stub) pb.Response, stub *Stub, ccID *pb.ChaincodeID) (*pb.ChaincodeAction, error) {
+	resp := fn(stub)
+	if resp.Status >= 400 {
+		return &pb.ChaincodeAction{Response: &resp, ChaincodeId: ccID}, nil
+	}
+
+	results, err := stub.rwSet().ToProtoBytes()
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal read-write set failed")
+	}
+
+	var events []byte
+	if stub.event != nil {
+		event := *stub.event
+		event.ChaincodeId = ccID.Name
+		event.TxId = stub.txID
+		events, err = proto.Marshal(&event)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal chaincode event failed")
+		}
+	}
+
+	return &pb.ChaincodeAction{
+		Results:     results,
+		Events:      events,
+		Response:    &resp,
+		ChaincodeId: ccID,
+	}, nil
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch v := m.(type) {
+	case map[string]*kvrwset.KVRead:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	case map[string]*kvrwset.KVWrite:
+		for key := range v {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,323 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"math"
+	"net"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/cauthdsl"
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer/etcdraft"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	channelCapability     = "V1_4_3"
+	ordererCapability     = "V1_4_2"
+	applicationCapability = "V1_3"
+	soloConsensus         = "solo"
+	etcdRaftConsensus     = "etcdraft"
+	consortium            = "SampleConsortium"
+	modPolicy             = channelConfig.AdminsPolicyKey
+)
+
+// newGenesisBlock returns the config block (block 0) of the given channel
+func (n *Network) newGenesisBlock(channelID string) (*common.Block, error) {
+	var channelGroup *common.ConfigGroup
+	var err error
+	if channelID == n.systemChannelID {
+		channelGroup, err = n.systemChannelGroup()
+	} else {
+		channelGroup, err = n.channelGroup()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signatureHeader, err := n.orderer.identity.NewSignatureHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	channelHeader := protoutil.MakeChannelHeader(common.HeaderType_CONFIG, 0, channelID, 0)
+	payload := &common.Payload{
+		Header: protoutil.MakePayloadHeader(channelHeader, signatureHeader),
+		Data: protoutil.MarshalOrPanic(&common.ConfigEnvelope{
+			Config: &common.Config{ChannelGroup: channelGroup},
+		}),
+	}
+	payloadBytes := protoutil.MarshalOrPanic(payload)
+	signature, err := n.orderer.identity.Sign(payloadBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	block := protoutil.NewBlock(0, nil)
+	block.Data.Data = [][]byte{protoutil.MarshalOrPanic(&common.Envelope{Payload: payloadBytes, Signature: signature})}
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+	return block, nil
+}
+
+func (n *Network) channelGroup() (*common.ConfigGroup, error) {
+	ordererGroup, err := n.ordererGroup()
+	if err != nil {
+		return nil, err
+	}
+	applicationGroup, err := n.applicationGroup()
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.ConfigGroup{
+		Groups: map[string]*common.ConfigGroup{
+			channelConfig.OrdererGroupKey:     ordererGroup,
+			channelConfig.ApplicationGroupKey: applicationGroup,
+		},
+		Values: map[string]*common.ConfigValue{
+			channelConfig.HashingAlgorithmKey:          configValue(&common.HashingAlgorithm{Name: "SHA256"}),
+			channelConfig.BlockDataHashingStructureKey: configValue(&common.BlockDataHashingStructure{Width: math.MaxUint32}),
+			channelConfig.OrdererAddressesKey:          configValue(&common.OrdererAddresses{Addresses: []string{n.orderer.Address()}}),
+			channelConfig.ConsortiumKey:                configValue(&common.Consortium{Name: consortium}),
+			channelConfig.CapabilitiesKey:              capabilitiesValue(channelCapability),
+		},
+		Policies:  implicitMetaPolicies(),
+		ModPolicy: modPolicy,
+	}, nil
+}
+
+// systemChannelGroup returns the config of the orderer system channel, which has a consortium
+// (holding the peer organizations) instead of an application group
+func (n *Network) systemChannelGroup() (*common.ConfigGroup, error) {
+	ordererGroup, err := n.ordererGroup()
+	if err != nil {
+		return nil, err
+	}
+
+	orgGroups := make(map[string]*common.ConfigGroup)
+	for _, org := range n.orgs {
+		group, err := orgGroup(org)
+		if err != nil {
+			return nil, err
+		}
+		orgGroups[org.name] = group
+	}
+
+	consortiumsGroup := &common.ConfigGroup{
+		Groups: map[string]*common.ConfigGroup{
+			consortium: {
+				Groups: orgGroups,
+				Values: map[string]*common.ConfigValue{
+					channelConfig.ChannelCreationPolicyKey: configValue(implicitMetaPolicy(channelConfig.AdminsPolicyKey, common.ImplicitMetaPolicy_ANY).Policy),
+				},
+				ModPolicy: modPolicy,
+			},
+		},
+		Policies: map[string]*common.ConfigPolicy{
+			channelConfig.AdminsPolicyKey: signaturePolicy(cauthdsl.AcceptAllPolicy),
+		},
+		ModPolicy: modPolicy,
+	}
+
+	return &common.ConfigGroup{
+		Groups: map[string]*common.ConfigGroup{
+			channelConfig.OrdererGroupKey:     ordererGroup,
+			channelConfig.ConsortiumsGroupKey: consortiumsGroup,
+		},
+		Values: map[string]*common.ConfigValue{
+			channelConfig.HashingAlgorithmKey:          configValue(&common.HashingAlgorithm{Name: "SHA256"}),
+			channelConfig.BlockDataHashingStructureKey: configValue(&common.BlockDataHashingStructure{Width: math.MaxUint32}),
+			channelConfig.OrdererAddressesKey:          configValue(&common.OrdererAddresses{Addresses: []string{n.orderer.Address()}}),
+			channelConfig.CapabilitiesKey:              capabilitiesValue(channelCapability),
+		},
+		Policies:  implicitMetaPolicies(),
+		ModPolicy: modPolicy,
+	}, nil
+}
+
+func (n *Network) ordererGroup() (*common.ConfigGroup, error) {
+	orgGroup, err := orgGroup(n.orderer.org)
+	if err != nil {
+		return nil, err
+	}
+	consensusType, err := n.consensusType()
+	if err != nil {
+		return nil, err
+	}
+
+	policies := implicitMetaPolicies()
+	policies["BlockValidation"] = implicitMetaPolicy(channelConfig.WritersPolicyKey, common.ImplicitMetaPolicy_ANY)
+
+	return &common.ConfigGroup{
+		Groups: map[string]*common.ConfigGroup{
+			n.orderer.org.name: orgGroup,
+		},
+		Values: map[string]*common.ConfigValue{
+			channelConfig.ConsensusTypeKey: configValue(consensusType),
+			channelConfig.BatchSizeKey: configValue(&ab.BatchSize{
+				MaxMessageCount:   n.batchSize,
+				AbsoluteMaxBytes:  10 * 1024 * 1024,
+				PreferredMaxBytes: 2 * 1024 * 1024,
+			}),
+			channelConfig.BatchTimeoutKey:        configValue(&ab.BatchTimeout{Timeout: n.batchTimeout.String()}),
+			channelConfig.ChannelRestrictionsKey: configValue(&ab.ChannelRestrictions{}),
+			channelConfig.CapabilitiesKey:        capabilitiesValue(ordererCapability),
+		},
+		Policies:  policies,
+		ModPolicy: modPolicy,
+	}, nil
+}
+
+func (n *Network) applicationGroup() (*common.ConfigGroup, error) {
+	groups := make(map[string]*common.ConfigGroup)
+	for _, org := range n.orgs {
+		group, err := orgGroup(org)
+		if err != nil {
+			return nil, err
+		}
+
+		var anchorPeers []*pb.AnchorPeer
+		if len(org.peers) > 0 {
+			anchorPeer, err := toAnchorPeer(org.peers[0].Address())
+			if err != nil {
+				return nil, err
+			}
+			anchorPeers = append(anchorPeers, anchorPeer)
+		}
+		group.Values[channelConfig.AnchorPeersKey] = configValue(&pb.AnchorPeers{AnchorPeers: anchorPeers})
+
+		groups[org.name] = group
+	}
+
+	return &common.ConfigGroup{
+		Groups: groups,
+		Values: map[string]*common.ConfigValue{
+			channelConfig.CapabilitiesKey: capabilitiesValue(applicationCapability),
+		},
+		Policies:  implicitMetaPolicies(),
+		ModPolicy: modPolicy,
+	}, nil
+}
+
+// consensusType returns the consensus type of the orderer. The orderer is the only etcdraft consenter
+// and, since the network doesn't use TLS, its certificate stands in for the consenter's TLS certificates.
+func (n *Network) consensusType() (*ab.ConsensusType, error) {
+	if !n.etcdRaft {
+		return &ab.ConsensusType{Type: soloConsensus}, nil
+	}
+
+	host, portStr, err := net.SplitHostPort(n.orderer.Address())
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid orderer address [%s]", n.orderer.Address())
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid port in orderer address [%s]", n.orderer.Address())
+	}
+	metadata := &etcdraft.ConfigMetadata{
+		Consenters: []*etcdraft.Consenter{{
+			Host:          host,
+			Port:          uint32(port),
+			ClientTlsCert: n.orderer.identity.certPEM,
+			ServerTlsCert: n.orderer.identity.certPEM,
+		}},
+	}
+	return &ab.ConsensusType{Type: etcdRaftConsensus, Metadata: protoutil.MarshalOrPanic(metadata)}, nil
+}
+
+// orgGroup returns the config group of an organization containing its MSP and policies
+func orgGroup(org *organization) (*common.ConfigGroup, error) {
+	return &common.ConfigGroup{
+		Values: map[string]*common.ConfigValue{
+			channelConfig.MSPKey: configValue(org.mspConfig()),
+		},
+		Policies: map[string]*common.ConfigPolicy{
+			channelConfig.ReadersPolicyKey: signaturePolicy(cauthdsl.SignedByMspMember(org.mspID)),
+			channelConfig.WritersPolicyKey: signaturePolicy(cauthdsl.SignedByMspMember(org.mspID)),
+			channelConfig.AdminsPolicyKey:  signaturePolicy(cauthdsl.SignedByMspAdmin(org.mspID)),
+		},
+		ModPolicy: modPolicy,
+	}, nil
+}
+
+func implicitMetaPolicies() map[string]*common.ConfigPolicy {
+	return map[string]*common.ConfigPolicy{
+		channelConfig.ReadersPolicyKey: implicitMetaPolicy(channelConfig.ReadersPolicyKey, common.ImplicitMetaPolicy_ANY),
+		channelConfig.WritersPolicyKey: implicitMetaPolicy(channelConfig.WritersPolicyKey, common.ImplicitMetaPolicy_ANY),
+		channelConfig.AdminsPolicyKey:  implicitMetaPolicy(channelConfig.AdminsPolicyKey, common.ImplicitMetaPolicy_MAJORITY),
+	}
+}
+
+func implicitMetaPolicy(subPolicy string, rule common.ImplicitMetaPolicy_Rule) *common.ConfigPolicy {
+	return &common.ConfigPolicy{
+		Policy: &common.Policy{
+			Type:  int32(common.Policy_IMPLICIT_META),
+			Value: protoutil.MarshalOrPanic(&common.ImplicitMetaPolicy{SubPolicy: subPolicy, Rule: rule}),
+		},
+		ModPolicy: modPolicy,
+	}
+}
+
+func signaturePolicy(envelope *common.SignaturePolicyEnvelope) *common.ConfigPolicy {
+	return &common.ConfigPolicy{
+		Policy: &common.Policy{
+			Type:  int32(common.Policy_SIGNATURE),
+			Value: protoutil.MarshalOrPanic(envelope),
+		},
+		ModPolicy: modPolicy,
+	}
+}
+
+func capabilitiesValue(capabilities ...string) *common.ConfigValue {
+	value := &common.Capabilities{Capabilities: make(map[string]*common.Capability)}
+	for _, c := range capabilities {
+		value.Capabilities[c] = &common.Capability{}
+	}
+	return configValue(value)
+}
+
+func configValue(msg proto.Message) *common.ConfigValue {
+	return &common.ConfigValue{
+		Value:     protoutil.MarshalOrPanic(msg),
+		ModPolicy: modPolicy,
+	}
+}
+
+// mspConfig returns the MSP config of the organization. The organization's admin is listed
+// as an MSP admin since NodeOUs are not used.
+func (org *organization) mspConfig() *mb.MSPConfig {
+	fabricMSPConfig := &mb.FabricMSPConfig{
+		Name:         org.mspID,
+		RootCerts:    [][]byte{org.ca.certPEM},
+		Admins:       [][]byte{org.admin.certPEM},
+		TlsRootCerts: [][]byte{org.ca.certPEM},
+		CryptoConfig: &mb.FabricCryptoConfig{
+			SignatureHashFamily:            "SHA2",
+			IdentityIdentifierHashFunction: "SHA256",
+		},
+	}
+	return &mb.MSPConfig{Config: protoutil.MarshalOrPanic(fabricMSPConfig)}
+}
+
+func toAnchorPeer(address string) (*pb.AnchorPeer, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid address [%s]", address)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid port in address [%s]", address)
+	}
+	return &pb.AnchorPeer{Host: host, Port: int32(port)}, nil
+}
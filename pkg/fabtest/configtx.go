@@ -0,0 +1,181 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// configBlockChaincode is the name of the chaincode which creates the transactions around the config block
+const configBlockChaincode = "fabtestcc"
+
+// NewNetworkWithConfigBlock creates and starts an in-process network (see NewNetwork) and then appends
+// a transaction block, a config block and another transaction block to each channel, so that the
+// channels hold the genesis block (0), a transaction block (1), a config block (2) and a transaction
+// block (3). The config block updates the anchor peers of the first organization. This is useful for
+// testing block processing (e.g. verification) which must handle config blocks within the chain.
+func NewNetworkWithConfigBlock(opts ...Option) (*Network, error) {
+	n, err := NewNetwork(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, channelID := range n.channelIDs {
+		if err := n.appendConfigBlock(channelID); err != nil {
+			n.Stop()
+			return nil, errors.WithMessagef(err, "failed to append config block to channel [%s]", channelID)
+		}
+	}
+	return n, nil
+}
+
+func (n *Network) appendConfigBlock(channelID string) error {
+	org := n.orgs[0]
+
+	h, err := NewChaincodeHarness(configBlockChaincode, Chaincode(func(stub *Stub) ([]byte, error) { return nil, nil }), WithEndorser(org.peers[0]))
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.Init(channelID); err != nil {
+		return err
+	}
+	if err := n.waitForHeight(channelID, 2); err != nil {
+		return err
+	}
+
+	if err := n.updateAnchorPeers(channelID, org); err != nil {
+		return err
+	}
+	if err := n.waitForHeight(channelID, 3); err != nil {
+		return err
+	}
+
+	if _, err := h.Init(channelID); err != nil {
+		return err
+	}
+	return n.waitForHeight(channelID, 4)
+}
+
+// updateAnchorPeers submits a config update, signed by the organization's admin, which sets the
+// anchor peers of the organization to all of its peers
+func (n *Network) updateAnchorPeers(channelID string, org *organization) error {
+	ch := n.orderer.channel(channelID)
+	if ch == nil {
+		return errors.Errorf("channel [%s] not found", channelID)
+	}
+
+	ch.mutex.Lock()
+	current, err := ch.currentConfig()
+	ch.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	appGroup := current.ChannelGroup.Groups[channelConfig.ApplicationGroupKey]
+	if appGroup == nil || appGroup.Groups[org.name] == nil {
+		return errors.Errorf("organization [%s] not found in the config of channel [%s]", org.name, channelID)
+	}
+	orgGroup := appGroup.Groups[org.name]
+
+	var anchorPeers []*pb.AnchorPeer
+	for _, p := range org.peers {
+		anchorPeer, err := toAnchorPeer(p.Address())
+		if err != nil {
+			return err
+		}
+		anchorPeers = append(anchorPeers, anchorPeer)
+	}
+	anchorPeersValue := configValue(&pb.AnchorPeers{AnchorPeers: anchorPeers})
+	if cur, ok := orgGroup.Values[channelConfig.AnchorPeersKey]; ok {
+		anchorPeersValue.Version = cur.Version + 1
+	}
+
+	readSet := orgPath(current.ChannelGroup.Version, appGroup.Version, org.name, &common.ConfigGroup{Version: orgGroup.Version})
+	writeSet := orgPath(current.ChannelGroup.Version, appGroup.Version, org.name, &common.ConfigGroup{
+		Version:   orgGroup.Version,
+		ModPolicy: orgGroup.ModPolicy,
+		Values:    map[string]*common.ConfigValue{channelConfig.AnchorPeersKey: anchorPeersValue},
+	})
+
+	configUpdate, err := proto.Marshal(&common.ConfigUpdate{ChannelId: channelID, ReadSet: readSet, WriteSet: writeSet})
+	if err != nil {
+		return errors.Wrap(err, "marshal config update failed")
+	}
+
+	signatureHeader, err := org.admin.NewSignatureHeader()
+	if err != nil {
+		return err
+	}
+	signatureHeaderBytes := protoutil.MarshalOrPanic(signatureHeader)
+	signature, err := org.admin.Sign(util.ConcatenateBytes(signatureHeaderBytes, configUpdate))
+	if err != nil {
+		return err
+	}
+
+	channelHeader := protoutil.MakeChannelHeader(common.HeaderType_CONFIG_UPDATE, 0, channelID, 0)
+	payload := protoutil.MarshalOrPanic(&common.Payload{
+		Header: protoutil.MakePayloadHeader(channelHeader, signatureHeader),
+		Data: protoutil.MarshalOrPanic(&common.ConfigUpdateEnvelope{
+			ConfigUpdate: configUpdate,
+			Signatures:   []*common.ConfigSignature{{SignatureHeader: signatureHeaderBytes, Signature: signature}},
+		}),
+	})
+	payloadSignature, err := org.admin.Sign(payload)
+	if err != nil {
+		return err
+	}
+
+	if status, info := n.orderer.enqueue(&common.Envelope{Payload: payload, Signature: payloadSignature}); status != common.Status_SUCCESS {
+		return errors.Errorf("orderer rejected config update with status %s: %s", status, info)
+	}
+	return nil
+}
+
+// orgPath returns the channel group containing only the given organization group of the application group
+func orgPath(channelVersion, appVersion uint64, orgName string, orgGroup *common.ConfigGroup) *common.ConfigGroup {
+	return &common.ConfigGroup{
+		Version: channelVersion,
+		Groups: map[string]*common.ConfigGroup{
+			channelConfig.ApplicationGroupKey: {
+				Version: appVersion,
+				Groups:  map[string]*common.ConfigGroup{orgName: orgGroup},
+			},
+		},
+	}
+}
+
+// waitForHeight waits until all peers have committed the given number of blocks of the channel
+func (n *Network) waitForHeight(channelID string, height uint64) error {
+	timeout := time.After(commitTimeout)
+	for _, p := range n.Peers() {
+		store := p.blockStore(channelID)
+		if store == nil {
+			return errors.Errorf("peer [%s] has not joined channel [%s]", p.name, channelID)
+		}
+		for {
+			updated := store.Updated()
+			if store.Height() >= height {
+				break
+			}
+			select {
+			case <-updated:
+			case <-timeout:
+				return errors.Errorf("timed out waiting for block [%d] of channel [%s]", height-1, channelID)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// configTransaction validates a config update against the current config of the channel and returns
+// the config transaction (signed by the orderer) containing the resulting config. The signatures of the
+// update must be valid but, unlike Fabric, the modification policies are not evaluated.
+func (o *Orderer) configTransaction(ch *ordererChannel, updateEnvelope *common.Envelope, data []byte) (*common.Envelope, error) {
+	configUpdateEnvelope := &common.ConfigUpdateEnvelope{}
+	if err := proto.Unmarshal(data, configUpdateEnvelope); err != nil {
+		return nil, errors.Wrap(err, "unmarshal config update envelope failed")
+	}
+	configUpdate := &common.ConfigUpdate{}
+	if err := proto.Unmarshal(configUpdateEnvelope.ConfigUpdate, configUpdate); err != nil {
+		return nil, errors.Wrap(err, "unmarshal config update failed")
+	}
+	if configUpdate.ChannelId != ch.id {
+		return nil, errors.Errorf("config update for channel [%s] was sent to channel [%s]", configUpdate.ChannelId, ch.id)
+	}
+	if configUpdate.ReadSet == nil || configUpdate.WriteSet == nil {
+		return nil, errors.New("config update has no read set or write set")
+	}
+
+	if len(configUpdateEnvelope.Signatures) == 0 {
+		return nil, errors.New("config update is not signed")
+	}
+	for _, sig := range configUpdateEnvelope.Signatures {
+		signatureHeader, err := protoutil.GetSignatureHeader(sig.SignatureHeader)
+		if err != nil {
+			return nil, err
+		}
+		msg := util.ConcatenateBytes(sig.SignatureHeader, configUpdateEnvelope.ConfigUpdate)
+		if err := verifySignature(o.network.certificateAuthorities(), signatureHeader.Creator, msg, sig.Signature); err != nil {
+			return nil, errors.WithMessage(err, "invalid config update signature")
+		}
+	}
+
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+
+	current, err := ch.currentConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkReadSet(configUpdate.ReadSet, current.ChannelGroup, "Channel"); err != nil {
+		return nil, err
+	}
+	channelGroup, err := applyWriteSet(current.ChannelGroup, configUpdate.WriteSet, "Channel")
+	if err != nil {
+		return nil, err
+	}
+	config := &common.Config{Sequence: current.Sequence + 1, ChannelGroup: channelGroup}
+
+	signatureHeader, err := o.identity.NewSignatureHeader()
+	if err != nil {
+		return nil, err
+	}
+	channelHeader := protoutil.MakeChannelHeader(common.HeaderType_CONFIG, 0, ch.id, 0)
+	payload := protoutil.MarshalOrPanic(&common.Payload{
+		Header: protoutil.MakePayloadHeader(channelHeader, signatureHeader),
+		Data:   protoutil.MarshalOrPanic(&common.ConfigEnvelope{Config: config, LastUpdate: updateEnvelope}),
+	})
+	signature, err := o.identity.Sign(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	ch.config = config
+	return &common.Envelope{Payload: payload, Signature: signature}, nil
+}
+
+// currentConfig returns the config resulting from the last accepted config transaction. The caller
+// must hold the channel's lock.
+func (ch *ordererChannel) currentConfig() (*common.Config, error) {
+	if ch.config != nil {
+		return ch.config, nil
+	}
+
+	if len(ch.blocks) == 0 {
+		return nil, errors.Errorf("channel [%s] has no config block", ch.id)
+	}
+	envelope, err := protoutil.ExtractEnvelope(ch.blocks[0], 0)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+	configEnvelope := &common.ConfigEnvelope{}
+	if err := proto.Unmarshal(payload.Data, configEnvelope); err != nil {
+		return nil, errors.Wrap(err, "unmarshal config envelope failed")
+	}
+	ch.config = configEnvelope.Config
+	return ch.config, nil
+}
+
+// checkReadSet verifies that every element of the read set exists in the current config at the same version
+func checkReadSet(readSet, current *common.ConfigGroup, path string) error {
+	if current == nil || readSet.Version != current.Version {
+		return errors.Errorf("read set version mismatch for group [%s]", path)
+	}
+	for key, group := range readSet.Groups {
+		if err := checkReadSet(group, current.Groups[key], path+"/"+key); err != nil {
+			return err
+		}
+	}
+	for key, value := range readSet.Values {
+		if cur, ok := current.Values[key]; !ok || cur.Version != value.Version {
+			return errors.Errorf("read set version mismatch for value [%s/%s]", path, key)
+		}
+	}
+	for key, policy := range readSet.Policies {
+		if cur, ok := current.Policies[key]; !ok || cur.Version != policy.Version {
+			return errors.Errorf("read set version mismatch for policy [%s/%s]", path, key)
+		}
+	}
+	return nil
+}
+
+// applyWriteSet returns the group resulting from applying the write set to the current group, as in
+// Fabric: elements whose version is unchanged are kept and modified elements must increment the version.
+// If the version of the group itself is unchanged then its members are kept, otherwise the write set
+// defines the members.
+func applyWriteSet(current, writeSet *common.ConfigGroup, path string) (*common.ConfigGroup, error) {
+	var result *common.ConfigGroup
+	switch {
+	case current != nil && writeSet.Version == current.Version:
+		result = proto.Clone(current).(*common.ConfigGroup)
+	case writeSet.Version == nextVersion(current != nil, current.GetVersion()):
+		result = &common.ConfigGroup{Version: writeSet.Version, ModPolicy: writeSet.ModPolicy}
+	default:
+		return nil, errors.Errorf("invalid version %d for group [%s]", writeSet.Version, path)
+	}
+	if result.Groups == nil {
+		result.Groups = make(map[string]*common.ConfigGroup)
+	}
+	if result.Values == nil {
+		result.Values = make(map[string]*common.ConfigValue)
+	}
+	if result.Policies == nil {
+		result.Policies = make(map[string]*common.ConfigPolicy)
+	}
+
+	for key, group := range writeSet.Groups {
+		var cur *common.ConfigGroup
+		if current != nil {
+			cur = current.Groups[key]
+		}
+		updated, err := applyWriteSet(cur, group, path+"/"+key)
+		if err != nil {
+			return nil, err
+		}
+		result.Groups[key] = updated
+	}
+
+	for key, value := range writeSet.Values {
+		var cur *common.ConfigValue
+		if current != nil {
+			cur = current.Values[key]
+		}
+		switch {
+		case cur != nil && value.Version == cur.Version:
+			result.Values[key] = cur
+		case value.Version == nextVersion(cur != nil, cur.GetVersion()):
+			result.Values[key] = value
+		default:
+			return nil, errors.Errorf("invalid version %d for value [%s/%s]", value.Version, path, key)
+		}
+	}
+
+	for key, policy := range writeSet.Policies {
+		var cur *common.ConfigPolicy
+		if current != nil {
+			cur = current.Policies[key]
+		}
+		switch {
+		case cur != nil && policy.Version == cur.Version:
+			result.Policies[key] = cur
+		case policy.Version == nextVersion(cur != nil, cur.GetVersion()):
+			result.Policies[key] = policy
+		default:
+			return nil, errors.Errorf("invalid version %d for policy [%s/%s]", policy.Version, path, key)
+		}
+	}
+
+	return result, nil
+}
+
+// nextVersion returns the version of a modified element (zero for a new element)
+func nextVersion(exists bool, version uint64) uint64 {
+	if !exists {
+		return 0
+	}
+	return version + 1
+}
+
+// isConfigTransaction returns true if the envelope is a config transaction
+func isConfigTransaction(envelope *common.Envelope) bool {
+	channelHeader, err := protoutil.ChannelHeader(envelope)
+	return err == nil && common.HeaderType(channelHeader.Type) == common.HeaderType_CONFIG
+}
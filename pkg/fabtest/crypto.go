@@ -0,0 +1,207 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+const certValidity = 10 * 365 * 24 * time.Hour
+
+// certificateAuthority issues the certificates of an organization
+type certificateAuthority struct {
+	cert    *x509.Certificate
+	certPEM []byte
+	key     *ecdsa.PrivateKey
+}
+
+// signingIdentity is an ECDSA identity issued by an organization's certificate authority
+type signingIdentity struct {
+	mspID   string
+	cert    *x509.Certificate
+	certPEM []byte
+	keyPEM  []byte
+	key     *ecdsa.PrivateKey
+}
+
+func newCertificateAuthority(domain string) (*certificateAuthority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate CA key")
+	}
+
+	template, err := newCertTemplate("ca."+domain, domain, "")
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+	template.SubjectKeyId = subjectKeyID(&key.PublicKey)
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA certificate")
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse CA certificate")
+	}
+
+	return &certificateAuthority{
+		cert:    cert,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		key:     key,
+	}, nil
+}
+
+// issue creates a new identity with the given common name and organizational unit
+func (ca *certificateAuthority) issue(mspID, commonName, ou string) (*signingIdentity, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate key")
+	}
+
+	template, err := newCertTemplate(commonName, ca.cert.Subject.Organization[0], ou)
+	if err != nil {
+		return nil, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.SubjectKeyId = subjectKeyID(&key.PublicKey)
+	template.AuthorityKeyId = ca.cert.SubjectKeyId
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create certificate for [%s]", commonName)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse certificate for [%s]", commonName)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal key for [%s]", commonName)
+	}
+
+	return &signingIdentity{
+		mspID:   mspID,
+		cert:    cert,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+		key:     key,
+	}, nil
+}
+
+// Serialize returns the serialized identity (MSP ID and certificate)
+func (id *signingIdentity) Serialize() []byte {
+	return protoutil.MarshalOrPanic(&mb.SerializedIdentity{Mspid: id.mspID, IdBytes: id.certPEM})
+}
+
+// Sign signs the SHA-256 digest of the message. Signatures are low-S, as required by Fabric.
+func (id *signingIdentity) Sign(msg []byte) ([]byte, error) {
+	digest := sha256.Sum256(msg)
+	r, s, err := ecdsa.Sign(rand.Reader, id.key, digest[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "signing failed")
+	}
+
+	s, _, err = utils.ToLowS(&id.key.PublicKey, s)
+	if err != nil {
+		return nil, err
+	}
+	return utils.MarshalECDSASignature(r, s)
+}
+
+// NewSignatureHeader returns a signature header for this identity with a random nonce
+func (id *signingIdentity) NewSignatureHeader() (*common.SignatureHeader, error) {
+	nonce, err := protoutil.CreateNonce()
+	if err != nil {
+		return nil, err
+	}
+	return &common.SignatureHeader{Creator: id.Serialize(), Nonce: nonce}, nil
+}
+
+// verifySignature verifies that the signature over the message was made by the given serialized identity
+// and that the identity was issued by one of the given certificate authorities (keyed by MSP ID)
+func verifySignature(cas map[string]*certificateAuthority, serializedID, msg, sig []byte) error {
+	sID := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, sID); err != nil {
+		return errors.Wrap(err, "unmarshal serialized identity failed")
+	}
+
+	ca, ok := cas[sID.Mspid]
+	if !ok {
+		return errors.Errorf("MSP [%s] is unknown", sID.Mspid)
+	}
+
+	block, _ := pem.Decode(sID.IdBytes)
+	if block == nil {
+		return errors.Errorf("identity of MSP [%s] does not contain a PEM encoded certificate", sID.Mspid)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "parse certificate failed")
+	}
+	if err := cert.CheckSignatureFrom(ca.cert); err != nil {
+		return errors.Wrapf(err, "certificate was not issued by MSP [%s]", sID.Mspid)
+	}
+
+	pubKey, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return errors.Errorf("unsupported public key type %T", cert.PublicKey)
+	}
+	r, s, err := utils.UnmarshalECDSASignature(sig)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256(msg)
+	if !ecdsa.Verify(pubKey, digest[:], r, s) {
+		return errors.New("signature is invalid")
+	}
+	return nil
+}
+
+func newCertTemplate(commonName, organization, ou string) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate serial number")
+	}
+
+	subject := pkix.Name{CommonName: commonName, Organization: []string{organization}}
+	if ou != "" {
+		subject.OrganizationalUnit = []string{ou}
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      subject,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(certValidity),
+	}, nil
+}
+
+func subjectKeyID(key *ecdsa.PublicKey) []byte {
+	ski := sha256.Sum256(elliptic.Marshal(key.Curve, key.X, key.Y))
+	return ski[:]
+}
@@ -0,0 +1,147 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"context"
+	"io"
+	"math"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+)
+
+// deliverStream abstracts the peer and orderer deliver streams
+type deliverStream interface {
+	Context() context.Context
+	Recv() (*common.Envelope, error)
+}
+
+// deliverHandler serves seek requests against the block stores of a peer or orderer
+type deliverHandler struct {
+	cas        func() map[string]*certificateAuthority
+	blockStore func(channelID string) *blockStore
+	sendBlock  func(channelID string, block *common.Block) error
+	sendStatus func(status common.Status) error
+}
+
+// handle serves seek requests until the stream is closed. Each seek request is answered with
+// the requested blocks followed by a status response (unless the request never completes).
+func (h *deliverHandler) handle(stream deliverStream) error {
+	for {
+		envelope, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		status, err := h.deliver(stream.Context(), envelope)
+		if err != nil {
+			return err
+		}
+		if err := h.sendStatus(status); err != nil {
+			return err
+		}
+	}
+}
+
+func (h *deliverHandler) deliver(ctx context.Context, envelope *common.Envelope) (common.Status, error) {
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	if err != nil || payload.Header == nil {
+		return common.Status_BAD_REQUEST, nil
+	}
+	channelHeader, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return common.Status_BAD_REQUEST, nil
+	}
+	signatureHeader, err := protoutil.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return common.Status_BAD_REQUEST, nil
+	}
+
+	if err := verifySignature(h.cas(), signatureHeader.Creator, envelope.Payload, envelope.Signature); err != nil {
+		logger.Warnf("Deliver request for channel [%s] rejected: %s", channelHeader.ChannelId, err)
+		return common.Status_FORBIDDEN, nil
+	}
+
+	store := h.blockStore(channelHeader.ChannelId)
+	if store == nil {
+		return common.Status_NOT_FOUND, nil
+	}
+
+	seekInfo := &ab.SeekInfo{}
+	if err := proto.Unmarshal(payload.Data, seekInfo); err != nil {
+		return common.Status_BAD_REQUEST, nil
+	}
+
+	height := store.Height()
+	start, ok := seekPosition(seekInfo.Start, height)
+	if !ok {
+		return common.Status_BAD_REQUEST, nil
+	}
+	stop, ok := seekPosition(seekInfo.Stop, height)
+	if !ok || stop < start {
+		return common.Status_BAD_REQUEST, nil
+	}
+
+	if seekInfo.Behavior == ab.SeekInfo_FAIL_IF_NOT_READY && start >= height {
+		return common.Status_NOT_FOUND, nil
+	}
+
+	for number := start; number <= stop; number++ {
+		block, err := waitForBlock(ctx, store, number)
+		if err != nil {
+			return common.Status_SERVICE_UNAVAILABLE, err
+		}
+		if err := h.sendBlock(channelHeader.ChannelId, block); err != nil {
+			return common.Status_SERVICE_UNAVAILABLE, err
+		}
+		if number == math.MaxUint64 {
+			break
+		}
+	}
+
+	return common.Status_SUCCESS, nil
+}
+
+// seekPosition returns the block number of the given position
+func seekPosition(position *ab.SeekPosition, height uint64) (uint64, bool) {
+	if position == nil {
+		return 0, false
+	}
+
+	switch t := position.Type.(type) {
+	case *ab.SeekPosition_Oldest:
+		return 0, true
+	case *ab.SeekPosition_Newest:
+		return height - 1, true
+	case *ab.SeekPosition_Specified:
+		return t.Specified.Number, true
+	default:
+		return 0, false
+	}
+}
+
+// waitForBlock returns the block with the given number, waiting until it is committed
+func waitForBlock(ctx context.Context, store *blockStore, number uint64) (*common.Block, error) {
+	for {
+		updated := store.Updated()
+		if block := store.Block(number); block != nil {
+			return block, nil
+		}
+
+		select {
+		case <-updated:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
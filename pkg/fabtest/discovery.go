@@ -0,0 +1,231 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/gossip"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+// discoveryServer answers discovery queries from the network topology. All peers
+// are considered alive and every chaincode is installed on every peer.
+type discoveryServer struct {
+	peer *Peer
+}
+
+// Discover processes the queries of a signed discovery request
+func (s *discoveryServer) Discover(ctx context.Context, request *discovery.SignedRequest) (*discovery.Response, error) {
+	if request == nil {
+		return nil, errors.New("nil request")
+	}
+
+	req := &discovery.Request{}
+	if err := proto.Unmarshal(request.Payload, req); err != nil {
+		return nil, errors.Wrap(err, "failed parsing request")
+	}
+	if req.Authentication == nil || len(req.Authentication.ClientIdentity) == 0 {
+		return nil, errors.New("access denied, client identity wasn't supplied")
+	}
+	if err := verifySignature(s.peer.network.certificateAuthorities(), req.Authentication.ClientIdentity, request.Payload, request.Signature); err != nil {
+		return nil, errors.WithMessage(err, "access denied")
+	}
+
+	var results []*discovery.QueryResult
+	for _, q := range req.Queries {
+		results = append(results, s.processQuery(q))
+	}
+	return &discovery.Response{Results: results}, nil
+}
+
+func (s *discoveryServer) processQuery(q *discovery.Query) *discovery.QueryResult {
+	if q.Channel == "" {
+		if q.GetLocalPeers() != nil {
+			return s.membershipResult("")
+		}
+		return errorResult("unsupported query")
+	}
+
+	if s.peer.ledger(q.Channel) == nil {
+		return errorResult("access denied")
+	}
+
+	switch {
+	case q.GetPeerQuery() != nil:
+		return s.membershipResult(q.Channel)
+	case q.GetConfigQuery() != nil:
+		return s.configResult()
+	case q.GetCcQuery() != nil:
+		return s.chaincodeResult(q.Channel, q.GetCcQuery())
+	default:
+		return errorResult("unsupported query")
+	}
+}
+
+// membershipResult returns the peers by MSP ID. If a channel is given then the peers
+// include their state info for the channel.
+func (s *discoveryServer) membershipResult(channelID string) *discovery.QueryResult {
+	peersByOrg := make(map[string]*discovery.Peers)
+	for _, p := range s.peer.network.Peers() {
+		if channelID != "" && p.ledger(channelID) == nil {
+			continue
+		}
+		peers, ok := peersByOrg[p.MSPID()]
+		if !ok {
+			peers = &discovery.Peers{}
+			peersByOrg[p.MSPID()] = peers
+		}
+		peers.Peers = append(peers.Peers, s.asDiscoveryPeer(p, channelID))
+	}
+
+	return &discovery.QueryResult{
+		Result: &discovery.QueryResult_Members{
+			Members: &discovery.PeerMembershipResult{PeersByOrg: peersByOrg},
+		},
+	}
+}
+
+func (s *discoveryServer) configResult() *discovery.QueryResult {
+	network := s.peer.network
+
+	msps := make(map[string]*mb.FabricMSPConfig)
+	for _, org := range append(network.orgs, network.orderer.org) {
+		fabricMSPConfig := &mb.FabricMSPConfig{}
+		if err := proto.Unmarshal(org.mspConfig().Config, fabricMSPConfig); err != nil {
+			return errorResult(err.Error())
+		}
+		msps[org.mspID] = fabricMSPConfig
+	}
+
+	endpoint, err := toEndpoint(network.orderer.Address())
+	if err != nil {
+		return errorResult(err.Error())
+	}
+
+	return &discovery.QueryResult{
+		Result: &discovery.QueryResult_ConfigResult{
+			ConfigResult: &discovery.ConfigResult{
+				Msps: msps,
+				Orderers: map[string]*discovery.Endpoints{
+					network.orderer.MSPID(): {Endpoint: []*discovery.Endpoint{endpoint}},
+				},
+			},
+		},
+	}
+}
+
+// chaincodeResult returns one endorsement descriptor per chaincode interest. Each organization
+// is a group and a single endorsement from any group satisfies the (implicit) endorsement policy.
+func (s *discoveryServer) chaincodeResult(channelID string, query *discovery.ChaincodeQuery) *discovery.QueryResult {
+	var descriptors []*discovery.EndorsementDescriptor
+	for _, interest := range query.Interests {
+		if len(interest.Chaincodes) == 0 {
+			return errorResult("no chaincodes in interest")
+		}
+		ccName := interest.Chaincodes[0].Name
+		if _, ok := s.peer.network.chaincode(ccName); !ok {
+			return errorResult("chaincode " + ccName + " not found")
+		}
+
+		descriptor := &discovery.EndorsementDescriptor{
+			Chaincode:         ccName,
+			EndorsersByGroups: make(map[string]*discovery.Peers),
+		}
+		for _, org := range s.peer.network.orgs {
+			endorsers := &discovery.Peers{}
+			for _, p := range org.peers {
+				if p.ledger(channelID) != nil {
+					endorsers.Peers = append(endorsers.Peers, s.asDiscoveryPeer(p, channelID))
+				}
+			}
+			if len(endorsers.Peers) == 0 {
+				continue
+			}
+			descriptor.EndorsersByGroups[org.name] = endorsers
+			descriptor.Layouts = append(descriptor.Layouts, &discovery.Layout{
+				QuantitiesByGroup: map[string]uint32{org.name: 1},
+			})
+		}
+		descriptors = append(descriptors, descriptor)
+	}
+
+	return &discovery.QueryResult{
+		Result: &discovery.QueryResult_CcQueryRes{
+			CcQueryRes: &discovery.ChaincodeQueryResult{Content: descriptors},
+		},
+	}
+}
+
+func (s *discoveryServer) asDiscoveryPeer(p *Peer, channelID string) *discovery.Peer {
+	timestamp := &gossip.PeerTime{
+		SeqNum: uint64(1000),
+		IncNum: uint64(time.Now().UnixNano()),
+	}
+
+	aliveMsg := &gossip.GossipMessage{
+		Content: &gossip.GossipMessage_AliveMsg{
+			AliveMsg: &gossip.AliveMessage{
+				Membership: &gossip.Member{Endpoint: p.Address(), PkiId: []byte(p.Name())},
+				Timestamp:  timestamp,
+			},
+		},
+	}
+
+	peer := &discovery.Peer{
+		MembershipInfo: &gossip.Envelope{Payload: protoutil.MarshalOrPanic(aliveMsg)},
+		Identity:       p.identity.Serialize(),
+	}
+
+	if channelID != "" {
+		var chaincodes []*gossip.Chaincode
+		for _, name := range p.network.chaincodeNames() {
+			chaincodes = append(chaincodes, &gossip.Chaincode{Name: name, Version: "1.0"})
+		}
+		stateInfoMsg := &gossip.GossipMessage{
+			Content: &gossip.GossipMessage_StateInfo{
+				StateInfo: &gossip.StateInfo{
+					Properties: &gossip.Properties{
+						Chaincodes:   chaincodes,
+						LedgerHeight: p.BlockHeight(channelID),
+					},
+					Timestamp: timestamp,
+				},
+			},
+		}
+		peer.StateInfo = &gossip.Envelope{Payload: protoutil.MarshalOrPanic(stateInfoMsg)}
+	}
+
+	return peer
+}
+
+func errorResult(content string) *discovery.QueryResult {
+	return &discovery.QueryResult{
+		Result: &discovery.QueryResult_Error{
+			Error: &discovery.Error{Content: content},
+		},
+	}
+}
+
+func toEndpoint(address string) (*discovery.Endpoint, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid address [%s]", address)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 32)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid port in address [%s]", address)
+	}
+	return &discovery.Endpoint{Host: host, Port: uint32(port)}, nil
+}
@@ -0,0 +1,419 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	reqContext "context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	harnessDomain = "org1.example.com"
+	commitTimeout = 10 * time.Second
+)
+
+// ChaincodeHarness executes proposals directly against a chaincode, without a peer or a network.
+// It implements fab.Peer so that it can be passed to channel.WithTargets, and it returns real
+// proposal responses (signed, with read-write sets and chaincode events).
+//
+// Endorsed transactions are not committed automatically. Commit appends them to the harness's
+// in-memory ledger of the channel as a new block, which can then be passed to block decoders and
+// event consumers. Proposal signatures are not verified.
+//
+//	h, err := fabtest.NewChaincodeHarness("mycc", &MyChaincode{})
+//	block, err := h.Init("mychannel", []byte("init"))
+//	resp, err := client.Query(channel.Request{ChaincodeID: "mycc", Fcn: "get"}, channel.WithTargets(h))
+type ChaincodeHarness struct {
+	name     string
+	cc       ShimChaincode
+	identity *signingIdentity
+	peer     *Peer
+	mutex    sync.Mutex
+	ledgers  map[string]*ledger
+	endorsed map[string]*endorsedTx
+}
+
+// endorsedTx is a proposal which was endorsed by the harness but not yet committed
+type endorsedTx struct {
+	proposal *chaincodeProposal
+	response *pb.ProposalResponse
+}
+
+// HarnessOption configures the chaincode harness
+type HarnessOption func(h *ChaincodeHarness)
+
+// WithEndorser endorses proposals with the identity of the given peer of a network (by default
+// the harness has its own Org1MSP identity and ledgers). Proposals are simulated against the peer's
+// ledgers, so SDK clients of the network can query and execute the chaincode without it being
+// installed on the network.
+func WithEndorser(p *Peer) HarnessOption {
+	return func(h *ChaincodeHarness) {
+		h.peer = p
+		h.identity = p.identity
+	}
+}
+
+// NewChaincodeHarness returns a harness for the given chaincode
+func NewChaincodeHarness(name string, cc ShimChaincode, opts ...HarnessOption) (*ChaincodeHarness, error) {
+	h := &ChaincodeHarness{
+		name:     name,
+		cc:       cc,
+		ledgers:  make(map[string]*ledger),
+		endorsed: make(map[string]*endorsedTx),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if h.identity == nil {
+		ca, err := newCertificateAuthority(harnessDomain)
+		if err != nil {
+			return nil, err
+		}
+		h.identity, err = ca.issue(defaultOrg+"MSP", "harness."+harnessDomain, "peer")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// MSPID returns the MSP ID of the endorsing identity
+func (h *ChaincodeHarness) MSPID() string {
+	return h.identity.mspID
+}
+
+// URL returns a name which identifies the harness as a target
+func (h *ChaincodeHarness) URL() string {
+	return h.name + ".harness"
+}
+
+// ProcessTransactionProposal invokes the chaincode with the proposal and endorses the result. As with
+// a peer, invalid proposals and chaincode errors are returned as a response with status 500 (or the
+// status of the chaincode response) and no endorsement.
+func (h *ChaincodeHarness) ProcessTransactionProposal(ctx reqContext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	if request.SignedProposal == nil {
+		return nil, errors.New("signed proposal is required")
+	}
+
+	resp, err := h.process(request.SignedProposal.ProposalBytes)
+	if err != nil {
+		logger.Debugf("Harness [%s] rejected proposal: %s", h.name, err)
+		resp = &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}
+	}
+
+	return &fab.TransactionProposalResponse{
+		Endorser:         h.URL(),
+		Status:           resp.Response.Status,
+		ChaincodeStatus:  resp.Response.Status,
+		ProposalResponse: resp,
+	}, nil
+}
+
+func (h *ChaincodeHarness) process(proposalBytes []byte) (*pb.ProposalResponse, error) {
+	prop, err := parseProposal(proposalBytes)
+	if err != nil {
+		return nil, err
+	}
+	if prop.ccID.Name != h.name {
+		return nil, errors.Errorf("chaincode [%s] not found", prop.ccID.Name)
+	}
+	return h.endorse(prop, h.cc.Invoke)
+}
+
+// endorse simulates the proposal with the given chaincode function and keeps the endorsed
+// transaction until it is committed
+func (h *ChaincodeHarness) endorse(prop *chaincodeProposal, fn func(stub *Stub) pb.Response) (*pb.ProposalResponse, error) {
+	l := h.ledger(prop.channelHeader.ChannelId)
+	if l == nil {
+		return nil, errors.Errorf("channel [%s] not found", prop.channelHeader.ChannelId)
+	}
+
+	action, err := simulate(fn, prop.stub(l), prop.ccID)
+	if err != nil {
+		return nil, err
+	}
+	if action.Response.Status >= 400 {
+		return &pb.ProposalResponse{Version: 1, Response: action.Response}, nil
+	}
+
+	resp, err := endorse(h.identity, prop, action)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mutex.Lock()
+	h.endorsed[prop.channelHeader.TxId] = &endorsedTx{proposal: prop, response: resp}
+	h.mutex.Unlock()
+
+	return resp, nil
+}
+
+// Init invokes the Init function of the chaincode on the given channel and commits the result,
+// as on instantiation. The proposal is created by the harness's identity.
+func (h *ChaincodeHarness) Init(channelID string, args ...[]byte) (*common.Block, error) {
+	prop, err := h.newProposal(channelID, args)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.endorse(prop, h.cc.Init)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Response.Status >= 400 {
+		return nil, errors.Errorf("init of chaincode [%s] failed with status %d: %s", h.name, resp.Response.Status, resp.Response.Message)
+	}
+
+	return h.Commit(fab.TransactionID(prop.channelHeader.TxId))
+}
+
+func (h *ChaincodeHarness) newProposal(channelID string, args [][]byte) (*chaincodeProposal, error) {
+	signatureHeader, err := h.identity.NewSignatureHeader()
+	if err != nil {
+		return nil, err
+	}
+	txID, err := protoutil.ComputeTxID(signatureHeader.Nonce, signatureHeader.Creator)
+	if err != nil {
+		return nil, err
+	}
+
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_GOLANG,
+			ChaincodeId: &pb.ChaincodeID{Name: h.name},
+			Input:       &pb.ChaincodeInput{Args: args},
+		},
+	}
+	prop, _, err := protoutil.CreateChaincodeProposalWithTxIDNonceAndTransient(txID, common.HeaderType_ENDORSER_TRANSACTION,
+		channelID, cis, signatureHeader.Nonce, signatureHeader.Creator, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create proposal")
+	}
+	propBytes, err := proto.Marshal(prop)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal proposal failed")
+	}
+	return parseProposal(propBytes)
+}
+
+// Commit commits the given endorsed transactions, in order, and returns the block containing the
+// last one. The envelopes are signed by the harness's identity rather than by the client.
+//
+// By default the transactions are committed as a new block of the harness's ledger of their channel.
+// They are validated (MVCC and duplicate transaction IDs) as by a peer, and the validation codes are
+// stored in the transactions filter of the block. With WithEndorser the transactions are submitted to
+// the network's orderer instead, which only accepts envelopes signed by their creator; transactions
+// endorsed for SDK clients are submitted by the clients themselves.
+func (h *ChaincodeHarness) Commit(txIDs ...fab.TransactionID) (*common.Block, error) {
+	if len(txIDs) == 0 {
+		return nil, errors.New("at least one transaction ID is required")
+	}
+
+	channelID, envelopes, err := h.envelopes(txIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var block *common.Block
+	if h.peer != nil {
+		block, err = h.submit(channelID, envelopes, string(txIDs[len(txIDs)-1]))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		block = h.commitBlock(channelID, envelopes)
+	}
+
+	h.mutex.Lock()
+	for _, txID := range txIDs {
+		delete(h.endorsed, string(txID))
+	}
+	h.mutex.Unlock()
+
+	logger.Debugf("Harness [%s] committed block [%d] of channel [%s]", h.name, block.Header.Number, channelID)
+	return block, nil
+}
+
+// envelopes returns the envelopes of the given endorsed transactions, which must be on the same channel
+func (h *ChaincodeHarness) envelopes(txIDs []fab.TransactionID) (string, []*common.Envelope, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var channelID string
+	envelopes := make([]*common.Envelope, len(txIDs))
+	for i, txID := range txIDs {
+		tx, ok := h.endorsed[string(txID)]
+		if !ok {
+			return "", nil, errors.Errorf("transaction [%s] has not been endorsed", txID)
+		}
+		if i > 0 && tx.proposal.channelHeader.ChannelId != channelID {
+			return "", nil, errors.New("all transactions must be on the same channel")
+		}
+		channelID = tx.proposal.channelHeader.ChannelId
+
+		envelope, err := tx.envelope(h.identity)
+		if err != nil {
+			return "", nil, err
+		}
+		envelopes[i] = envelope
+	}
+	return channelID, envelopes, nil
+}
+
+// commitBlock cuts a block from the envelopes and commits it to the harness's ledger of the channel
+func (h *ChaincodeHarness) commitBlock(channelID string, envelopes []*common.Envelope) *common.Block {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	l := h.ledgerLocked(channelID)
+
+	var prevHash []byte
+	if height := l.Height(); height > 0 {
+		prevHash = protoutil.BlockHeaderHash(l.Block(height - 1).Header)
+	}
+	block := protoutil.NewBlock(l.Height(), prevHash)
+	for _, envelope := range envelopes {
+		block.Data.Data = append(block.Data.Data, protoutil.MarshalOrPanic(envelope))
+	}
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+
+	txs := make([]*committedTx, len(block.Data.Data))
+	for i, data := range block.Data.Data {
+		txs[i] = parseTransaction(data, nil)
+	}
+	l.commit(block, txs)
+	return block
+}
+
+// submit sends the envelopes to the network's orderer and waits until the endorsing peer has
+// committed the given (last) transaction
+func (h *ChaincodeHarness) submit(channelID string, envelopes []*common.Envelope, txID string) (*common.Block, error) {
+	for _, envelope := range envelopes {
+		if status, info := h.peer.network.orderer.enqueue(envelope); status != common.Status_SUCCESS {
+			return nil, errors.Errorf("orderer rejected transaction with status %s: %s", status, info)
+		}
+	}
+
+	l := h.peer.ledger(channelID)
+	timeout := time.After(commitTimeout)
+	for {
+		updated := l.Updated()
+		if info, ok := l.Transaction(txID); ok {
+			return l.Block(info.blockNum), nil
+		}
+		select {
+		case <-updated:
+		case <-timeout:
+			return nil, errors.Errorf("timed out waiting for transaction [%s] to be committed", txID)
+		}
+	}
+}
+
+// envelope assembles the transaction from the proposal and its endorsement
+func (tx *endorsedTx) envelope(identity *signingIdentity) (*common.Envelope, error) {
+	ccProposalPayload, err := proto.Marshal(&pb.ChaincodeProposalPayload{Input: tx.proposal.ccProposalPayload.Input})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal chaincode proposal payload failed")
+	}
+	actionPayload, err := proto.Marshal(&pb.ChaincodeActionPayload{
+		ChaincodeProposalPayload: ccProposalPayload,
+		Action: &pb.ChaincodeEndorsedAction{
+			ProposalResponsePayload: tx.response.Payload,
+			Endorsements:            []*pb.Endorsement{tx.response.Endorsement},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal chaincode action payload failed")
+	}
+	transaction, err := proto.Marshal(&pb.Transaction{
+		Actions: []*pb.TransactionAction{{Header: tx.proposal.header.SignatureHeader, Payload: actionPayload}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal transaction failed")
+	}
+	payload, err := proto.Marshal(&common.Payload{Header: tx.proposal.header, Data: transaction})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal payload failed")
+	}
+
+	signature, err := identity.Sign(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &common.Envelope{Payload: payload, Signature: signature}, nil
+}
+
+// BlockHeight returns the height of the ledger of the given channel
+func (h *ChaincodeHarness) BlockHeight(channelID string) uint64 {
+	l := h.ledger(channelID)
+	if l == nil {
+		return 0
+	}
+	return l.Height()
+}
+
+// Block returns the committed block with the given number (nil if it doesn't exist)
+func (h *ChaincodeHarness) Block(channelID string, number uint64) *common.Block {
+	l := h.ledger(channelID)
+	if l == nil {
+		return nil
+	}
+	return l.Block(number)
+}
+
+// FilteredBlock returns the committed block with the given number as delivered to filtered
+// block event consumers (nil if it doesn't exist)
+func (h *ChaincodeHarness) FilteredBlock(channelID string, number uint64) *pb.FilteredBlock {
+	block := h.Block(channelID, number)
+	if block == nil {
+		return nil
+	}
+	return filteredBlock(channelID, block)
+}
+
+// State returns the committed value of the given key on the given channel (nil if the key doesn't exist)
+func (h *ChaincodeHarness) State(channelID, key string) []byte {
+	l := h.ledger(channelID)
+	if l == nil {
+		return nil
+	}
+	value := l.State(h.name, key)
+	if value == nil {
+		return nil
+	}
+	return value.value
+}
+
+// ledger returns the ledger of the channel. The harness's own ledgers are created on first use.
+// With WithEndorser the ledger of the peer is returned (nil if the peer hasn't joined the channel).
+func (h *ChaincodeHarness) ledger(channelID string) *ledger {
+	if h.peer != nil {
+		return h.peer.ledger(channelID)
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.ledgerLocked(channelID)
+}
+
+func (h *ChaincodeHarness) ledgerLocked(channelID string) *ledger {
+	l, ok := h.ledgers[channelID]
+	if !ok {
+		l = newLedger()
+		h.ledgers[channelID] = l
+	}
+	return l
+}
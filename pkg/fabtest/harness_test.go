@@ -0,0 +1,198 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// counterChaincode is a shim chaincode which keeps a counter
+type counterChaincode struct{}
+
+func (counterChaincode) Init(stub *Stub) pb.Response {
+	if len(stub.Args()) != 1 {
+		return Error("expecting initial value")
+	}
+	if err := stub.PutState("counter", stub.Args()[0]); err != nil {
+		return Error(err.Error())
+	}
+	return Success(nil)
+}
+
+func (counterChaincode) Invoke(stub *Stub) pb.Response {
+	value, err := stub.GetState("counter")
+	if err != nil {
+		return Error(err.Error())
+	}
+
+	switch stub.Function() {
+	case "get":
+		return Success(value)
+	case "inc":
+		count, err := strconv.Atoi(string(value))
+		if err != nil {
+			return Error(err.Error())
+		}
+		value = []byte(strconv.Itoa(count + 1))
+		if err := stub.PutState("counter", value); err != nil {
+			return Error(err.Error())
+		}
+		if err := stub.SetEvent("incremented", value); err != nil {
+			return Error(err.Error())
+		}
+		return Success(value)
+	default:
+		return pb.Response{Status: 404, Message: "unknown function"}
+	}
+}
+
+func TestChaincodeHarness(t *testing.T) {
+	h, err := NewChaincodeHarness("counter", counterChaincode{})
+	require.NoError(t, err)
+	assert.Equal(t, "Org1MSP", h.MSPID())
+
+	_, err = h.Init(defaultChannel)
+	require.Error(t, err)
+	block, err := h.Init(defaultChannel, []byte("1"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), block.Header.Number)
+	assert.Equal(t, "1", string(h.State(defaultChannel, "counter")))
+
+	org, err := newOrganization("Org1", "Org1MSP", "org1.example.com")
+	require.NoError(t, err)
+
+	resp, txID := processProposal(t, h, org.user, "counter", "inc")
+	require.Equal(t, int32(200), resp.Status)
+	require.NotNil(t, resp.Endorsement)
+	assert.Equal(t, "2", string(resp.Response.Payload))
+
+	action := chaincodeAction(t, resp.ProposalResponse)
+	assert.Equal(t, "2", string(action.Response.Payload))
+	txRWSet := &rwsetutil.TxRwSet{}
+	require.NoError(t, txRWSet.FromProtoBytes(action.Results))
+	require.Len(t, txRWSet.NsRwSets, 1)
+	assert.Equal(t, "counter", txRWSet.NsRwSets[0].NameSpace)
+	assert.Len(t, txRWSet.NsRwSets[0].KvRwSet.Reads, 1)
+	assert.Len(t, txRWSet.NsRwSets[0].KvRwSet.Writes, 1)
+	event := &pb.ChaincodeEvent{}
+	require.NoError(t, proto.Unmarshal(action.Events, event))
+	assert.Equal(t, "incremented", event.EventName)
+
+	// A second increment endorsed against the same state conflicts with the first
+	conflicting, conflictingTxID := processProposal(t, h, org.user, "counter", "inc")
+	require.Equal(t, int32(200), conflicting.Status)
+
+	block, err = h.Commit(txID, conflictingTxID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), block.Header.Number)
+	assert.Equal(t, protoutil.BlockHeaderHash(h.Block(defaultChannel, 0).Header), block.Header.PreviousHash)
+	assert.Equal(t, []byte{byte(pb.TxValidationCode_VALID), byte(pb.TxValidationCode_MVCC_READ_CONFLICT)},
+		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assert.Equal(t, "2", string(h.State(defaultChannel, "counter")))
+	assert.Equal(t, uint64(2), h.BlockHeight(defaultChannel))
+
+	fb := h.FilteredBlock(defaultChannel, 1)
+	require.NotNil(t, fb)
+	require.Len(t, fb.FilteredTransactions, 2)
+	assert.Equal(t, string(txID), fb.FilteredTransactions[0].Txid)
+	actions := fb.FilteredTransactions[0].GetTransactionActions()
+	require.NotNil(t, actions)
+	assert.Equal(t, "incremented", actions.ChaincodeActions[0].ChaincodeEvent.EventName)
+
+	_, err = h.Commit(txID)
+	assert.Error(t, err)
+	_, err = h.Commit()
+	assert.Error(t, err)
+
+	resp, _ = processProposal(t, h, org.user, "counter", "unknown")
+	assert.Equal(t, int32(404), resp.Status)
+	assert.Nil(t, resp.Endorsement)
+
+	resp, _ = processProposal(t, h, org.user, "other", "get")
+	assert.Equal(t, int32(500), resp.Status)
+	assert.Contains(t, resp.Response.Message, "chaincode [other] not found")
+}
+
+func TestChaincodeHarnessChannelClient(t *testing.T) {
+	net, err := NewNetwork()
+	require.NoError(t, err)
+	defer net.Stop()
+
+	h, err := NewChaincodeHarness("counter", counterChaincode{}, WithEndorser(net.Peers()[0]))
+	require.NoError(t, err)
+	block, err := h.Init(defaultChannel, []byte("1"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), block.Header.Number)
+
+	sdk, err := fabsdk.New(net.ConfigProvider())
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	client, err := channel.New(sdk.ChannelContext(defaultChannel, fabsdk.WithUser(UserName), fabsdk.WithOrg(defaultOrg)))
+	require.NoError(t, err)
+
+	resp, err := client.Query(channel.Request{ChaincodeID: "counter", Fcn: "get"}, channel.WithTargets(h))
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(resp.Payload))
+
+	resp, err = client.Execute(channel.Request{ChaincodeID: "counter", Fcn: "inc"}, channel.WithTargets(h))
+	require.NoError(t, err)
+	assert.Equal(t, pb.TxValidationCode_VALID, resp.TxValidationCode)
+	assert.Equal(t, "2", string(resp.Payload))
+	assert.Equal(t, "2", string(net.Peers()[0].State(defaultChannel, "counter", "counter")))
+	assert.Equal(t, "2", string(h.State(defaultChannel, "counter")))
+}
+
+// processProposal sends a proposal signed by the given identity to the harness
+func processProposal(t *testing.T, h *ChaincodeHarness, creator *signingIdentity, ccName string, args ...string) (*fab.TransactionProposalResponse, fab.TransactionID) {
+	signatureHeader, err := creator.NewSignatureHeader()
+	require.NoError(t, err)
+	txID, err := protoutil.ComputeTxID(signatureHeader.Nonce, signatureHeader.Creator)
+	require.NoError(t, err)
+
+	var input [][]byte
+	for _, arg := range args {
+		input = append(input, []byte(arg))
+	}
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: ccName}, Input: &pb.ChaincodeInput{Args: input}},
+	}
+	prop, _, err := protoutil.CreateChaincodeProposalWithTxIDNonceAndTransient(txID, common.HeaderType_ENDORSER_TRANSACTION,
+		defaultChannel, cis, signatureHeader.Nonce, signatureHeader.Creator, nil)
+	require.NoError(t, err)
+	propBytes, err := proto.Marshal(prop)
+	require.NoError(t, err)
+	signature, err := creator.Sign(propBytes)
+	require.NoError(t, err)
+
+	resp, err := h.ProcessTransactionProposal(context.Background(), fab.ProcessProposalRequest{
+		SignedProposal: &pb.SignedProposal{ProposalBytes: propBytes, Signature: signature},
+	})
+	require.NoError(t, err)
+	return resp, fab.TransactionID(txID)
+}
+
+func chaincodeAction(t *testing.T, resp *pb.ProposalResponse) *pb.ChaincodeAction {
+	prp, err := protoutil.GetProposalResponsePayload(resp.Payload)
+	require.NoError(t, err)
+	action, err := protoutil.GetChaincodeAction(prp.Extension)
+	require.NoError(t, err)
+	return action
+}
@@ -0,0 +1,196 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// blockStore is an append-only, in-memory list of blocks
+type blockStore struct {
+	mutex   sync.RWMutex
+	blocks  []*common.Block
+	updated chan struct{}
+}
+
+func newBlockStore() *blockStore {
+	return &blockStore{updated: make(chan struct{})}
+}
+
+// Height returns the number of blocks in the store
+func (s *blockStore) Height() uint64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return uint64(len(s.blocks))
+}
+
+// Block returns the block with the given number or nil if it doesn't exist
+func (s *blockStore) Block(number uint64) *common.Block {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if number >= uint64(len(s.blocks)) {
+		return nil
+	}
+	return s.blocks[number]
+}
+
+// Blocks returns all blocks of the store
+func (s *blockStore) Blocks() []*common.Block {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]*common.Block(nil), s.blocks...)
+}
+
+// Updated returns a channel which is closed when the next block is appended
+func (s *blockStore) Updated() <-chan struct{} {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.updated
+}
+
+func (s *blockStore) append(block *common.Block) {
+	s.blocks = append(s.blocks, block)
+	close(s.updated)
+	s.updated = make(chan struct{})
+}
+
+// versionedValue is a value in the state database with the version at which it was written
+type versionedValue struct {
+	value   []byte
+	version *kvrwset.Version
+}
+
+// txInfo is the index entry of a committed transaction
+type txInfo struct {
+	blockNum       uint64
+	txNum          uint64
+	validationCode pb.TxValidationCode
+}
+
+// ledger is a peer's ledger of a channel. It holds the blocks, the world state
+// (keyed by chaincode name and key) and an index of the committed transactions.
+type ledger struct {
+	*blockStore
+	state map[string]map[string]*versionedValue
+	txs   map[string]*txInfo
+}
+
+func newLedger() *ledger {
+	return &ledger{
+		blockStore: newBlockStore(),
+		state:      make(map[string]map[string]*versionedValue),
+		txs:        make(map[string]*txInfo),
+	}
+}
+
+// State returns the committed value and version of the given key (nil if the key doesn't exist)
+func (l *ledger) State(namespace, key string) *versionedValue {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.state[namespace][key]
+}
+
+// StateRange returns the keys of the given namespace in the range [startKey, endKey) in sorted order.
+// An empty endKey means that the range is unbounded.
+func (l *ledger) StateRange(namespace, startKey, endKey string) []string {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	var keys []string
+	for key := range l.state[namespace] {
+		if key >= startKey && (endKey == "" || key < endKey) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Transaction returns the index entry of the given transaction
+func (l *ledger) Transaction(txID string) (*txInfo, bool) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	tx, ok := l.txs[txID]
+	return tx, ok
+}
+
+// commit validates the transactions of the given block, applies the writes of the valid ones
+// and appends the block. The validation codes are stored in the transactions filter of the block.
+func (l *ledger) commit(block *common.Block, txs []*committedTx) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	filter := make([]byte, len(txs))
+	for txNum, tx := range txs {
+		code := l.validate(tx)
+		filter[txNum] = byte(code)
+
+		if tx.txID != "" {
+			if _, ok := l.txs[tx.txID]; !ok {
+				l.txs[tx.txID] = &txInfo{blockNum: block.Header.Number, txNum: uint64(txNum), validationCode: code}
+			}
+		}
+		if code != pb.TxValidationCode_VALID {
+			continue
+		}
+
+		version := &kvrwset.Version{BlockNum: block.Header.Number, TxNum: uint64(txNum)}
+		for _, ns := range tx.rwSets {
+			for _, w := range ns.KvRwSet.Writes {
+				l.write(ns.NameSpace, w, version)
+			}
+		}
+	}
+
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = filter
+	l.append(block)
+}
+
+func (l *ledger) validate(tx *committedTx) pb.TxValidationCode {
+	if tx.validationCode != pb.TxValidationCode_VALID {
+		return tx.validationCode
+	}
+	if tx.txID != "" {
+		if _, ok := l.txs[tx.txID]; ok {
+			return pb.TxValidationCode_DUPLICATE_TXID
+		}
+	}
+
+	for _, ns := range tx.rwSets {
+		for _, r := range ns.KvRwSet.Reads {
+			if !sameVersion(l.state[ns.NameSpace][r.Key], r.Version) {
+				return pb.TxValidationCode_MVCC_READ_CONFLICT
+			}
+		}
+	}
+	return pb.TxValidationCode_VALID
+}
+
+func (l *ledger) write(namespace string, w *kvrwset.KVWrite, version *kvrwset.Version) {
+	values, ok := l.state[namespace]
+	if !ok {
+		values = make(map[string]*versionedValue)
+		l.state[namespace] = values
+	}
+	if w.IsDelete {
+		delete(values, w.Key)
+		return
+	}
+	values[w.Key] = &versionedValue{value: w.Value, version: version}
+}
+
+func sameVersion(value *versionedValue, version *kvrwset.Version) bool {
+	if value == nil || version == nil {
+		return value == nil && version == nil
+	}
+	return value.version.BlockNum == version.BlockNum && value.version.TxNum == version.TxNum
+}
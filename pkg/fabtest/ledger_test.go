@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgerCommit(t *testing.T) {
+	l := newLedger()
+
+	put := simulateTx(t, l, "tx1", func(stub *Stub) ([]byte, error) {
+		return nil, stub.PutState("key1", []byte("value1"))
+	})
+	l.commit(protoutil.NewBlock(0, nil), []*committedTx{put})
+
+	value := l.State("cc", "key1")
+	require.NotNil(t, value)
+	assert.Equal(t, "value1", string(value.value))
+
+	readWrite := func(stub *Stub) ([]byte, error) {
+		value, err := stub.GetState("key1")
+		if err != nil {
+			return nil, err
+		}
+		return nil, stub.PutState("key1", append(value, '!'))
+	}
+	tx2 := simulateTx(t, l, "tx2", readWrite)
+	tx3 := simulateTx(t, l, "tx3", readWrite)
+	duplicate := simulateTx(t, l, "tx1", readWrite)
+
+	block := protoutil.NewBlock(1, nil)
+	l.commit(block, []*committedTx{tx2, tx3, duplicate})
+
+	assert.Equal(t, []byte{
+		byte(pb.TxValidationCode_VALID),
+		byte(pb.TxValidationCode_MVCC_READ_CONFLICT),
+		byte(pb.TxValidationCode_DUPLICATE_TXID),
+	}, block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assert.Equal(t, "value1!", string(l.State("cc", "key1").value))
+	assert.Equal(t, uint64(2), l.Height())
+
+	info, ok := l.Transaction("tx3")
+	require.True(t, ok)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, info.validationCode)
+
+	del := simulateTx(t, l, "tx4", func(stub *Stub) ([]byte, error) {
+		return nil, stub.DelState("key1")
+	})
+	l.commit(protoutil.NewBlock(2, nil), []*committedTx{del})
+	assert.Nil(t, l.State("cc", "key1"))
+}
+
+func TestStubRange(t *testing.T) {
+	l := newLedger()
+	tx := simulateTx(t, l, "tx1", func(stub *Stub) ([]byte, error) {
+		for _, key := range []string{"b", "a", "c"} {
+			if err := stub.PutState(key, []byte(key)); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	l.commit(protoutil.NewBlock(0, nil), []*committedTx{tx})
+
+	stub := newStub("mychannel", "tx2", "cc", nil, nil, nil, l)
+	kvs, err := stub.GetStateByRange("a", "c")
+	require.NoError(t, err)
+	require.Len(t, kvs, 2)
+	assert.Equal(t, "a", kvs[0].Key)
+	assert.Equal(t, "b", kvs[1].Key)
+
+	assert.Error(t, stub.PutState("", []byte("value")))
+	assert.Error(t, stub.PutState("key", nil))
+	assert.Error(t, stub.SetEvent("", nil))
+}
+
+func simulateTx(t *testing.T, l *ledger, txID string, cc Chaincode) *committedTx {
+	stub := newStub("mychannel", txID, "cc", nil, nil, nil, l)
+	action, err := simulate(cc.Invoke, stub, &pb.ChaincodeID{Name: "cc"})
+	require.NoError(t, err)
+	require.Equal(t, int32(200), action.Response.Status)
+
+	return &committedTx{
+		txID:           txID,
+		rwSets:         stub.rwSet().NsRwSets,
+		validationCode: pb.TxValidationCode_VALID,
+	}
+}
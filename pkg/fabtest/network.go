@@ -0,0 +1,372 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package fabtest provides an in-process Fabric network for application tests.
+
+The network consists of a solo orderer and one or more peers per organization which
+run on random localhost ports. Chaincodes are plain Go functions, ledgers and world
+state are kept in memory and every peer has joined every channel. The network's
+connection profile can be passed to fabsdk.New so that application code is tested
+against the real SDK clients without Docker.
+
+Basic Flow:
+1) Create the network with the required chaincodes
+2) Create the SDK from the network's config provider
+3) Use the SDK clients as usual
+4) Stop the network
+
+	net, err := fabtest.NewNetwork(fabtest.WithChaincode("mycc", myChaincode))
+	defer net.Stop()
+	sdk, err := fabsdk.New(net.ConfigProvider())
+
+CAServer is an in-process stand-in for a Fabric CA server. Its CAProfile can be added to
+a connection profile in order to test msp.Client flows (register, enroll, revoke etc.).
+
+ChaincodeHarness executes proposals directly against a shim-style chaincode (Init/Invoke) without
+a network. It can be passed to channel.WithTargets and commits endorsed transactions on request.
+*/
+package fabtest
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/fabtest")
+
+const (
+	defaultChannel      = "mychannel"
+	defaultOrg          = "Org1"
+	defaultBatchSize    = 10
+	defaultBatchTimeout = 100 * time.Millisecond
+	ordererOrg          = "OrdererOrg"
+	ordererMSPID        = "OrdererMSP"
+	ordererName         = "orderer.example.com"
+
+	// UserName is the name of the (non-admin) user enrolled in every organization
+	UserName = "User1"
+	// AdminName is the name of the admin user enrolled in every organization
+	AdminName = "Admin"
+)
+
+// organization holds the certificate authority, users and peers of an organization
+type organization struct {
+	name   string
+	mspID  string
+	domain string
+	ca     *certificateAuthority
+	admin  *signingIdentity
+	user   *signingIdentity
+	peers  []*Peer
+}
+
+// Network is an in-process Fabric network
+type Network struct {
+	orgNames        []string
+	channelIDs      []string
+	peersPerOrg     int
+	batchSize       uint32
+	batchTimeout    time.Duration
+	chaincodes      map[string]Chaincode
+	etcdRaft        bool
+	systemChannelID string
+
+	orgs        []*organization
+	orderer     *Orderer
+	cas         map[string]*certificateAuthority
+	cryptoStore string
+	profile     *config.Profile
+
+	stopOnce sync.Once
+}
+
+// Option configures the network
+type Option func(n *Network)
+
+// WithOrgs sets the names of the peer organizations (default "Org1"). The MSP ID of an
+// organization is its name followed by "MSP".
+func WithOrgs(names ...string) Option {
+	return func(n *Network) {
+		n.orgNames = names
+	}
+}
+
+// WithChannels sets the channels which are created and joined by all peers (default "mychannel")
+func WithChannels(channelIDs ...string) Option {
+	return func(n *Network) {
+		n.channelIDs = channelIDs
+	}
+}
+
+// WithPeersPerOrg sets the number of peers of each organization (default 1)
+func WithPeersPerOrg(count int) Option {
+	return func(n *Network) {
+		n.peersPerOrg = count
+	}
+}
+
+// WithChaincode installs the chaincode under the given name on all peers and channels
+func WithChaincode(name string, cc Chaincode) Option {
+	return func(n *Network) {
+		n.chaincodes[name] = cc
+	}
+}
+
+// WithBatchSize sets the maximum number of transactions in a block (default 10)
+func WithBatchSize(size uint32) Option {
+	return func(n *Network) {
+		n.batchSize = size
+	}
+}
+
+// WithBatchTimeout sets the time after which a block is cut even if it isn't full (default 100ms)
+func WithBatchTimeout(timeout time.Duration) Option {
+	return func(n *Network) {
+		n.batchTimeout = timeout
+	}
+}
+
+// WithEtcdRaft sets the consensus type of the channels to etcdraft with the orderer as the only consenter
+// (default solo). Blocks are still cut by the in-process orderer; only the channel configs are affected.
+func WithEtcdRaft() Option {
+	return func(n *Network) {
+		n.etcdRaft = true
+	}
+}
+
+// WithSystemChannel creates an orderer system channel whose consortium holds the peer organizations.
+// The peers don't join the system channel.
+func WithSystemChannel(channelID string) Option {
+	return func(n *Network) {
+		n.systemChannelID = channelID
+	}
+}
+
+// NewNetwork creates and starts an in-process network
+func NewNetwork(opts ...Option) (*Network, error) {
+	n := &Network{
+		orgNames:     []string{defaultOrg},
+		channelIDs:   []string{defaultChannel},
+		peersPerOrg:  1,
+		batchSize:    defaultBatchSize,
+		batchTimeout: defaultBatchTimeout,
+		chaincodes:   make(map[string]Chaincode),
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	if len(n.orgNames) == 0 {
+		return nil, errors.New("at least one organization is required")
+	}
+	if n.peersPerOrg < 1 {
+		return nil, errors.New("at least one peer per organization is required")
+	}
+	if n.batchSize == 0 {
+		return nil, errors.New("batch size must be greater than zero")
+	}
+
+	if err := n.start(); err != nil {
+		n.Stop()
+		return nil, err
+	}
+	return n, nil
+}
+
+func (n *Network) start() error {
+	// The SDK stores the keys of enrolled users in the crypto store
+	cryptoStore, err := ioutil.TempDir("", "fabtest")
+	if err != nil {
+		return errors.Wrap(err, "failed to create crypto store directory")
+	}
+	n.cryptoStore = cryptoStore
+
+	ordOrg, err := newOrganization(ordererOrg, ordererMSPID, "example.com")
+	if err != nil {
+		return err
+	}
+	n.orderer, err = newOrderer(n, ordOrg, ordererName)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range n.orgNames {
+		org, err := newOrganization(name, name+"MSP", strings.ToLower(name)+".example.com")
+		if err != nil {
+			return err
+		}
+		// The org is added before its peers are created so that Stop stops the peers created so far if one fails
+		n.orgs = append(n.orgs, org)
+		for i := 0; i < n.peersPerOrg; i++ {
+			p, err := newPeer(n, org, "peer"+strconv.Itoa(i)+"."+org.domain)
+			if err != nil {
+				return err
+			}
+			org.peers = append(org.peers, p)
+		}
+	}
+
+	n.cas = map[string]*certificateAuthority{ordOrg.mspID: ordOrg.ca}
+	for _, org := range n.orgs {
+		n.cas[org.mspID] = org.ca
+	}
+
+	for _, channelID := range n.channelIDs {
+		if err := n.createChannel(channelID); err != nil {
+			return err
+		}
+	}
+	if n.systemChannelID != "" {
+		if err := n.createSystemChannel(n.systemChannelID); err != nil {
+			return err
+		}
+	}
+
+	n.orderer.server.Start()
+	for _, p := range n.Peers() {
+		p.start()
+	}
+
+	n.profile = n.newProfile()
+	return nil
+}
+
+func newOrganization(name, mspID, domain string) (*organization, error) {
+	org := &organization{name: name, mspID: mspID, domain: domain}
+
+	var err error
+	org.ca, err = newCertificateAuthority(domain)
+	if err != nil {
+		return nil, err
+	}
+	org.admin, err = org.ca.issue(org.mspID, AdminName+"@"+domain, "admin")
+	if err != nil {
+		return nil, err
+	}
+	org.user, err = org.ca.issue(org.mspID, UserName+"@"+domain, "client")
+	if err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+func (n *Network) createChannel(channelID string) error {
+	genesisBlock, err := n.newGenesisBlock(channelID)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to create genesis block for channel [%s]", channelID)
+	}
+	if err := n.orderer.signBlock(genesisBlock, 0); err != nil {
+		return err
+	}
+
+	n.orderer.createChannel(channelID, genesisBlock)
+	for _, p := range n.Peers() {
+		p.joinChannel(channelID, genesisBlock)
+	}
+	return nil
+}
+
+func (n *Network) createSystemChannel(channelID string) error {
+	genesisBlock, err := n.newGenesisBlock(channelID)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to create genesis block for system channel [%s]", channelID)
+	}
+	if err := n.orderer.signBlock(genesisBlock, 0); err != nil {
+		return err
+	}
+
+	n.orderer.createChannel(channelID, genesisBlock)
+	return nil
+}
+
+// Stop stops the orderer and peers and removes the crypto store
+func (n *Network) Stop() {
+	n.stopOnce.Do(func() {
+		for _, p := range n.Peers() {
+			p.stop()
+		}
+		if n.orderer != nil {
+			n.orderer.stop()
+		}
+		if n.cryptoStore != "" {
+			if err := os.RemoveAll(n.cryptoStore); err != nil {
+				logger.Debugf("Failed to remove crypto store [%s]: %s", n.cryptoStore, err)
+			}
+		}
+	})
+}
+
+// Peers returns all peers of the network
+func (n *Network) Peers() []*Peer {
+	var peers []*Peer
+	for _, org := range n.orgs {
+		peers = append(peers, org.peers...)
+	}
+	return peers
+}
+
+// Orderer returns the orderer of the network
+func (n *Network) Orderer() *Orderer {
+	return n.orderer
+}
+
+// Channels returns the IDs of the channels of the network
+func (n *Network) Channels() []string {
+	return n.channelIDs
+}
+
+// Profile returns the connection profile of the network. The profile may be modified
+// (e.g. to change timeouts) before it is passed to config.FromProfile.
+func (n *Network) Profile() *config.Profile {
+	return n.profile
+}
+
+// ConfigProvider returns a config provider for the network's connection profile
+func (n *Network) ConfigProvider() core.ConfigProvider {
+	return config.FromProfile(n.profile)
+}
+
+// certificateAuthorities returns the certificate authorities of all organizations by MSP ID
+func (n *Network) certificateAuthorities() map[string]*certificateAuthority {
+	return n.cas
+}
+
+func (n *Network) chaincode(name string) (Chaincode, bool) {
+	cc, ok := n.chaincodes[name]
+	return cc, ok
+}
+
+// chaincodeNames returns the names of the user chaincodes in sorted order
+func (n *Network) chaincodeNames() []string {
+	var names []string
+	for name := range n.chaincodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// commit commits the block cut by the orderer to all peers (except for the system channel, which peers don't join)
+func (n *Network) commit(channelID string, block *common.Block) {
+	if channelID == n.systemChannelID {
+		return
+	}
+	for _, p := range n.Peers() {
+		p.commit(channelID, block)
+	}
+}
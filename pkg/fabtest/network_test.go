@@ -0,0 +1,179 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	ledgerclient "github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChaincode = "kvcc"
+
+func kvChaincode(stub *Stub) ([]byte, error) {
+	params := stub.Parameters()
+	switch stub.Function() {
+	case "put":
+		if len(params) != 2 {
+			return nil, errors.New("expecting key and value")
+		}
+		if err := stub.PutState(params[0], []byte(params[1])); err != nil {
+			return nil, err
+		}
+		return nil, stub.SetEvent("put", []byte(params[0]))
+	case "get":
+		if len(params) != 1 {
+			return nil, errors.New("expecting key")
+		}
+		return stub.GetState(params[0])
+	default:
+		return nil, errors.Errorf("unknown function [%s]", stub.Function())
+	}
+}
+
+func TestNetwork(t *testing.T) {
+	net, err := NewNetwork(WithOrgs("Org1", "Org2"), WithChaincode(testChaincode, kvChaincode))
+	require.NoError(t, err)
+	defer net.Stop()
+
+	require.Len(t, net.Peers(), 2)
+	for _, p := range net.Peers() {
+		assert.Equal(t, uint64(1), p.BlockHeight(defaultChannel))
+	}
+
+	sdk, err := fabsdk.New(net.ConfigProvider())
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	channelContext := sdk.ChannelContext(defaultChannel, fabsdk.WithUser(UserName), fabsdk.WithOrg("Org1"))
+	client, err := channel.New(channelContext)
+	require.NoError(t, err)
+
+	reg, events, err := client.RegisterChaincodeEvent(testChaincode, "put")
+	require.NoError(t, err)
+	defer client.UnregisterChaincodeEvent(reg)
+
+	resp, err := client.Execute(channel.Request{ChaincodeID: testChaincode, Fcn: "put", Args: [][]byte{[]byte("key1"), []byte("value1")}})
+	require.NoError(t, err)
+	assert.Equal(t, pb.TxValidationCode_VALID, resp.TxValidationCode)
+	txID := resp.TransactionID
+
+	select {
+	case event := <-events:
+		assert.Equal(t, string(resp.TransactionID), event.TxID)
+		assert.Equal(t, testChaincode, event.ChaincodeID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for chaincode event")
+	}
+
+	resp, err = client.Query(channel.Request{ChaincodeID: testChaincode, Fcn: "get", Args: [][]byte{[]byte("key1")}})
+	require.NoError(t, err)
+	assert.Equal(t, "value1", string(resp.Payload))
+
+	for _, p := range net.Peers() {
+		assert.Equal(t, "value1", string(p.State(defaultChannel, testChaincode, "key1")))
+	}
+
+	_, err = client.Execute(channel.Request{ChaincodeID: testChaincode, Fcn: "put", Args: [][]byte{[]byte("key1")}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expecting key and value")
+
+	ledgerClient, err := ledgerclient.New(channelContext)
+	require.NoError(t, err)
+
+	info, err := ledgerClient.QueryInfo()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), info.BCI.Height)
+
+	tx, err := ledgerClient.QueryTransaction(txID)
+	require.NoError(t, err)
+	assert.Equal(t, int32(pb.TxValidationCode_VALID), tx.ValidationCode)
+}
+
+func TestNetworkOptions(t *testing.T) {
+	_, err := NewNetwork(WithOrgs())
+	assert.EqualError(t, err, "at least one organization is required")
+
+	_, err = NewNetwork(WithPeersPerOrg(0))
+	assert.EqualError(t, err, "at least one peer per organization is required")
+
+	_, err = NewNetwork(WithBatchSize(0))
+	assert.EqualError(t, err, "batch size must be greater than zero")
+
+	net, err := NewNetwork(WithChannels("ch1", "ch2"), WithPeersPerOrg(2), WithBatchSize(1), WithBatchTimeout(time.Second))
+	require.NoError(t, err)
+	defer net.Stop()
+
+	assert.Equal(t, []string{"ch1", "ch2"}, net.Channels())
+	require.Len(t, net.Peers(), 2)
+	assert.Equal(t, "peer1.org1.example.com", net.Peers()[1].Name())
+	assert.Equal(t, "Org1MSP", net.Peers()[1].MSPID())
+	assert.Equal(t, "OrdererMSP", net.Orderer().MSPID())
+
+	profile := net.Profile()
+	assert.Equal(t, "Org1", profile.Client.Organization)
+	assert.Len(t, profile.Channels, 2)
+	assert.Equal(t, net.Orderer().Address(), profile.Orderers[net.Orderer().Name()].URL)
+	assert.Equal(t, []string{"peer0.org1.example.com", "peer1.org1.example.com"}, profile.Organizations["Org1"].Peers)
+}
+
+func TestNetworkWithConfigBlock(t *testing.T) {
+	net, err := NewNetworkWithConfigBlock(WithOrgs("Org1", "Org2"))
+	require.NoError(t, err)
+	defer net.Stop()
+
+	expectedTypes := []common.HeaderType{
+		common.HeaderType_CONFIG,
+		common.HeaderType_ENDORSER_TRANSACTION,
+		common.HeaderType_CONFIG,
+		common.HeaderType_ENDORSER_TRANSACTION,
+	}
+
+	for _, p := range net.Peers() {
+		l := p.ledger(defaultChannel)
+		require.Equal(t, uint64(len(expectedTypes)), l.Height())
+
+		for i, expectedType := range expectedTypes {
+			envelope, err := protoutil.ExtractEnvelope(l.Block(uint64(i)), 0)
+			require.NoError(t, err)
+			channelHeader, err := protoutil.ChannelHeader(envelope)
+			require.NoError(t, err)
+			assert.Equal(t, int32(expectedType), channelHeader.Type, "unexpected type of block %d", i)
+
+			if expectedType == common.HeaderType_ENDORSER_TRANSACTION {
+				tx, ok := l.Transaction(channelHeader.TxId)
+				require.True(t, ok)
+				assert.Equal(t, pb.TxValidationCode_VALID, tx.validationCode)
+			}
+		}
+	}
+}
+
+func TestStopUnstartedPeer(t *testing.T) {
+	org, err := newOrganization("Org1", "Org1MSP", "org1.example.com")
+	require.NoError(t, err)
+	p, err := newPeer(&Network{}, org, "peer0.org1.example.com")
+	require.NoError(t, err)
+
+	// The listeners must be closed even though the peer was never started
+	p.stop()
+	for _, address := range []string{p.Address(), p.ops.listener.Addr().String()} {
+		listener, err := net.Listen("tcp", address)
+		require.NoError(t, err, "expected listener on %s to be closed", address)
+		require.NoError(t, listener.Close())
+	}
+}
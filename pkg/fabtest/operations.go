@@ -0,0 +1,126 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// PeerVersion is the version reported by the operations service of the peers
+const PeerVersion = "fabtest"
+
+// operationsServer serves the /healthz and /version endpoints of a peer's operations service
+type operationsServer struct {
+	httpServer *http.Server
+	listener   net.Listener
+	wg         sync.WaitGroup
+
+	mutex        sync.RWMutex
+	failedChecks []failedCheck
+}
+
+type failedCheck struct {
+	Component string `json:"component"`
+	Reason    string `json:"reason"`
+}
+
+func newOperationsServer() (*operationsServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to listen")
+	}
+
+	s := &operationsServer{listener: listener}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.healthz)
+	mux.HandleFunc("/version", s.version)
+	s.httpServer = &http.Server{Handler: mux}
+	return s, nil
+}
+
+// URL returns the URL of the operations service
+func (s *operationsServer) URL() string {
+	return "http://" + s.listener.Addr().String()
+}
+
+// Start starts serving requests in the background
+func (s *operationsServer) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.httpServer.Serve(s.listener); err != nil && err != http.ErrServerClosed {
+			logger.Debugf("Operations server [%s] stopped: %s", s.URL(), err)
+		}
+	}()
+}
+
+// Stop stops the server and waits for it to complete. The listener is closed even if the server was never started.
+func (s *operationsServer) Stop() {
+	if err := s.httpServer.Close(); err != nil {
+		logger.Debugf("Failed to close operations server [%s]: %s", s.URL(), err)
+	}
+	s.wg.Wait()
+	closeListener(s.listener)
+}
+
+func (s *operationsServer) setFailedChecks(checks []failedCheck) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failedChecks = checks
+}
+
+// healthz responds as the Fabric health check handler: 200 when all checks pass, otherwise 503
+// with the failed checks
+func (s *operationsServer) healthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mutex.RLock()
+	checks := s.failedChecks
+	s.mutex.RUnlock()
+
+	status := struct {
+		Status       string        `json:"status"`
+		Time         time.Time     `json:"time"`
+		FailedChecks []failedCheck `json:"failed_checks,omitempty"`
+	}{Status: "OK", Time: time.Now(), FailedChecks: checks}
+
+	code := http.StatusOK
+	if len(checks) > 0 {
+		status.Status = "Service Unavailable"
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, status)
+}
+
+func (s *operationsServer) version(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Version   string `json:"Version,omitempty"`
+		CommitSHA string `json:"CommitSHA,omitempty"`
+	}{Version: PeerVersion, CommitSHA: "development build"})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Debugf("Failed to write response: %s", err)
+	}
+}
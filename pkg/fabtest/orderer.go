@@ -0,0 +1,305 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/pkg/errors"
+)
+
+// Orderer is a solo orderer which cuts blocks from the transactions broadcast to a channel
+// and delivers them to the peers of the network
+type Orderer struct {
+	name     string
+	org      *organization
+	identity *signingIdentity
+	network  *Network
+	server   *server
+	mutex    sync.RWMutex
+	channels map[string]*ordererChannel
+}
+
+// ordererChannel holds the blocks of a channel and the transactions waiting to be cut into a block
+type ordererChannel struct {
+	*blockStore
+	id         string
+	pending    chan *common.Envelope
+	done       chan struct{}
+	stopped    chan struct{}
+	lastHash   []byte
+	lastConfig uint64
+	config     *common.Config
+}
+
+func newOrderer(network *Network, org *organization, name string) (*Orderer, error) {
+	identity, err := org.ca.issue(org.mspID, name, "orderer")
+	if err != nil {
+		return nil, err
+	}
+
+	o := &Orderer{
+		name:     name,
+		org:      org,
+		identity: identity,
+		network:  network,
+		channels: make(map[string]*ordererChannel),
+	}
+
+	o.server, err = newServer()
+	if err != nil {
+		return nil, err
+	}
+	ab.RegisterAtomicBroadcastServer(o.server.grpcServer, o)
+
+	return o, nil
+}
+
+// Name returns the name of the orderer
+func (o *Orderer) Name() string {
+	return o.name
+}
+
+// Address returns the host:port on which the orderer listens
+func (o *Orderer) Address() string {
+	return o.server.Address()
+}
+
+// MSPID returns the MSP ID of the orderer organization
+func (o *Orderer) MSPID() string {
+	return o.org.mspID
+}
+
+// createChannel creates the channel with the given genesis block and starts cutting blocks
+func (o *Orderer) createChannel(channelID string, genesisBlock *common.Block) {
+	ch := &ordererChannel{
+		blockStore: newBlockStore(),
+		id:         channelID,
+		pending:    make(chan *common.Envelope, 100),
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	ch.appendBlock(genesisBlock)
+
+	o.mutex.Lock()
+	o.channels[channelID] = ch
+	o.mutex.Unlock()
+
+	go o.cutBlocks(ch)
+}
+
+func (o *Orderer) channel(channelID string) *ordererChannel {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+	return o.channels[channelID]
+}
+
+func (o *Orderer) blockStore(channelID string) *blockStore {
+	ch := o.channel(channelID)
+	if ch == nil {
+		return nil
+	}
+	return ch.blockStore
+}
+
+func (o *Orderer) stop() {
+	o.server.Stop()
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	for _, ch := range o.channels {
+		close(ch.done)
+		<-ch.stopped
+	}
+	o.channels = make(map[string]*ordererChannel)
+}
+
+// Broadcast accepts transactions for ordering
+func (o *Orderer) Broadcast(stream ab.AtomicBroadcast_BroadcastServer) error {
+	for {
+		envelope, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		status, info := o.enqueue(envelope)
+		if err := stream.Send(&ab.BroadcastResponse{Status: status, Info: info}); err != nil {
+			return err
+		}
+	}
+}
+
+// Deliver delivers the blocks of a channel
+func (o *Orderer) Deliver(stream ab.AtomicBroadcast_DeliverServer) error {
+	handler := &deliverHandler{
+		cas:        o.network.certificateAuthorities,
+		blockStore: o.blockStore,
+		sendBlock: func(channelID string, block *common.Block) error {
+			return stream.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Block{Block: block}})
+		},
+		sendStatus: func(status common.Status) error {
+			return stream.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Status{Status: status}})
+		},
+	}
+	return handler.handle(stream)
+}
+
+func (o *Orderer) enqueue(envelope *common.Envelope) (common.Status, string) {
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	if err != nil || payload.Header == nil {
+		return common.Status_BAD_REQUEST, "invalid payload"
+	}
+	channelHeader, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return common.Status_BAD_REQUEST, "invalid channel header"
+	}
+	signatureHeader, err := protoutil.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return common.Status_BAD_REQUEST, "invalid signature header"
+	}
+
+	headerType := common.HeaderType(channelHeader.Type)
+	if headerType != common.HeaderType_ENDORSER_TRANSACTION && headerType != common.HeaderType_CONFIG_UPDATE {
+		return common.Status_BAD_REQUEST, "unsupported transaction type " + headerType.String()
+	}
+
+	if err := verifySignature(o.network.certificateAuthorities(), signatureHeader.Creator, envelope.Payload, envelope.Signature); err != nil {
+		return common.Status_FORBIDDEN, err.Error()
+	}
+
+	ch := o.channel(channelHeader.ChannelId)
+	if ch == nil {
+		return common.Status_NOT_FOUND, "channel " + channelHeader.ChannelId + " not found"
+	}
+
+	if headerType == common.HeaderType_CONFIG_UPDATE {
+		envelope, err = o.configTransaction(ch, envelope, payload.Data)
+		if err != nil {
+			return common.Status_BAD_REQUEST, err.Error()
+		}
+	}
+
+	select {
+	case ch.pending <- envelope:
+		return common.Status_SUCCESS, ""
+	case <-ch.done:
+		return common.Status_SERVICE_UNAVAILABLE, "orderer is stopping"
+	}
+}
+
+// cutBlocks cuts a block when the batch size is reached or when the batch timeout expires
+func (o *Orderer) cutBlocks(ch *ordererChannel) {
+	defer close(ch.stopped)
+
+	var batch []*common.Envelope
+	var timeout <-chan time.Time
+
+	for {
+		select {
+		case envelope := <-ch.pending:
+			if isConfigTransaction(envelope) {
+				// A config transaction is cut into a block of its own
+				if len(batch) > 0 {
+					o.cutBlock(ch, batch)
+				}
+				o.cutBlock(ch, []*common.Envelope{envelope})
+				batch = nil
+				timeout = nil
+				continue
+			}
+			batch = append(batch, envelope)
+			if uint32(len(batch)) >= o.network.batchSize {
+				o.cutBlock(ch, batch)
+				batch = nil
+				timeout = nil
+			} else if timeout == nil {
+				timeout = time.After(o.network.batchTimeout)
+			}
+		case <-timeout:
+			o.cutBlock(ch, batch)
+			batch = nil
+			timeout = nil
+		case <-ch.done:
+			return
+		}
+	}
+}
+
+func (o *Orderer) cutBlock(ch *ordererChannel, batch []*common.Envelope) {
+	block := protoutil.NewBlock(ch.Height(), ch.lastHash)
+	for _, envelope := range batch {
+		block.Data.Data = append(block.Data.Data, protoutil.MarshalOrPanic(envelope))
+	}
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+
+	lastConfig := ch.lastConfig
+	if len(batch) == 1 && isConfigTransaction(batch[0]) {
+		lastConfig = block.Header.Number
+	}
+	if err := o.signBlock(block, lastConfig); err != nil {
+		logger.Errorf("Failed to sign block [%d] of channel [%s]: %s", block.Header.Number, ch.id, err)
+		return
+	}
+
+	ch.appendBlock(block)
+	ch.lastConfig = lastConfig
+	o.network.commit(ch.id, block)
+}
+
+// signBlock adds the orderer signatures and the index of the last config block to the block metadata
+func (o *Orderer) signBlock(block *common.Block, lastConfig uint64) error {
+	headerBytes := protoutil.BlockHeaderBytes(block.Header)
+
+	signatures, err := o.signMetadata(nil, headerBytes)
+	if err != nil {
+		return err
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(signatures)
+
+	lastConfigValue := protoutil.MarshalOrPanic(&common.LastConfig{Index: lastConfig})
+	lastConfigMetadata, err := o.signMetadata(lastConfigValue, headerBytes)
+	if err != nil {
+		return err
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = protoutil.MarshalOrPanic(lastConfigMetadata)
+
+	return nil
+}
+
+func (o *Orderer) signMetadata(value []byte, headerBytes []byte) (*common.Metadata, error) {
+	signatureHeader, err := o.identity.NewSignatureHeader()
+	if err != nil {
+		return nil, err
+	}
+	signatureHeaderBytes := protoutil.MarshalOrPanic(signatureHeader)
+
+	signature, err := o.identity.Sign(util.ConcatenateBytes(value, signatureHeaderBytes, headerBytes))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to sign block metadata")
+	}
+
+	return &common.Metadata{
+		Value:      value,
+		Signatures: []*common.MetadataSignature{{SignatureHeader: signatureHeaderBytes, Signature: signature}},
+	}, nil
+}
+
+func (ch *ordererChannel) appendBlock(block *common.Block) {
+	ch.mutex.Lock()
+	defer ch.mutex.Unlock()
+	ch.append(block)
+	ch.lastHash = protoutil.BlockHeaderHash(block.Header)
+}
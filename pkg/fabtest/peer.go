@@ -0,0 +1,374 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"context"
+	"crypto/sha256"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/discovery"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// Peer is an endorsing and committing peer which keeps its ledgers in memory. It serves
+// the endorser, deliver and discovery services.
+type Peer struct {
+	name     string
+	org      *organization
+	identity *signingIdentity
+	network  *Network
+	server   *server
+	ops      *operationsServer
+	mutex    sync.RWMutex
+	ledgers  map[string]*ledger
+}
+
+func newPeer(network *Network, org *organization, name string) (*Peer, error) {
+	identity, err := org.ca.issue(org.mspID, name, "peer")
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Peer{
+		name:     name,
+		org:      org,
+		identity: identity,
+		network:  network,
+		ledgers:  make(map[string]*ledger),
+	}
+
+	p.server, err = newServer()
+	if err != nil {
+		return nil, err
+	}
+	pb.RegisterEndorserServer(p.server.grpcServer, &endorserServer{peer: p})
+	pb.RegisterDeliverServer(p.server.grpcServer, &deliverServer{peer: p})
+	discovery.RegisterDiscoveryServer(p.server.grpcServer, &discoveryServer{peer: p})
+
+	p.ops, err = newOperationsServer()
+	if err != nil {
+		p.server.Stop()
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Name returns the name of the peer
+func (p *Peer) Name() string {
+	return p.name
+}
+
+// Address returns the host:port on which the peer listens
+func (p *Peer) Address() string {
+	return p.server.Address()
+}
+
+// OperationsURL returns the URL of the peer's operations service (/healthz and /version)
+func (p *Peer) OperationsURL() string {
+	return p.ops.URL()
+}
+
+// SetUnhealthy makes the health check of the peer's operations service fail for the given component.
+// An empty reason makes the health check pass again.
+func (p *Peer) SetUnhealthy(component, reason string) {
+	if reason == "" {
+		p.ops.setFailedChecks(nil)
+		return
+	}
+	p.ops.setFailedChecks([]failedCheck{{Component: component, Reason: reason}})
+}
+
+// MSPID returns the MSP ID of the peer's organization
+func (p *Peer) MSPID() string {
+	return p.org.mspID
+}
+
+// BlockHeight returns the height of the peer's ledger for the given channel
+// (zero if the peer hasn't joined the channel)
+func (p *Peer) BlockHeight(channelID string) uint64 {
+	l := p.ledger(channelID)
+	if l == nil {
+		return 0
+	}
+	return l.Height()
+}
+
+// State returns the committed value of the given key of a chaincode on the given channel
+// (nil if the key doesn't exist)
+func (p *Peer) State(channelID, chaincode, key string) []byte {
+	l := p.ledger(channelID)
+	if l == nil {
+		return nil
+	}
+	value := l.State(chaincode, key)
+	if value == nil {
+		return nil
+	}
+	return value.value
+}
+
+// joinChannel creates the ledger of the channel from its genesis block
+func (p *Peer) joinChannel(channelID string, genesisBlock *common.Block) {
+	p.mutex.Lock()
+	p.ledgers[channelID] = newLedger()
+	p.mutex.Unlock()
+
+	p.commit(channelID, genesisBlock)
+}
+
+// channels returns the IDs of the channels which the peer has joined in sorted order
+func (p *Peer) channels() []string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	var channelIDs []string
+	for channelID := range p.ledgers {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+	return channelIDs
+}
+
+func (p *Peer) ledger(channelID string) *ledger {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.ledgers[channelID]
+}
+
+func (p *Peer) blockStore(channelID string) *blockStore {
+	l := p.ledger(channelID)
+	if l == nil {
+		return nil
+	}
+	return l.blockStore
+}
+
+// commit validates and commits a copy of the given block to the ledger of the channel
+func (p *Peer) commit(channelID string, block *common.Block) {
+	l := p.ledger(channelID)
+	if l == nil {
+		logger.Warnf("Peer [%s] has not joined channel [%s]", p.name, channelID)
+		return
+	}
+
+	block = proto.Clone(block).(*common.Block)
+
+	cas := p.network.certificateAuthorities()
+	txs := make([]*committedTx, len(block.Data.Data))
+	for i, data := range block.Data.Data {
+		txs[i] = parseTransaction(data, cas)
+	}
+
+	l.commit(block, txs)
+	logger.Debugf("Peer [%s] committed block [%d] of channel [%s]", p.name, block.Header.Number, channelID)
+}
+
+func (p *Peer) start() {
+	p.server.Start()
+	p.ops.Start()
+}
+
+func (p *Peer) stop() {
+	p.server.Stop()
+	p.ops.Stop()
+}
+
+// endorserServer simulates proposals and endorses the results
+type endorserServer struct {
+	peer *Peer
+}
+
+// ProcessProposal simulates the proposal against the committed state of the channel. As in Fabric,
+// invalid proposals and chaincode errors are returned as a response with status 500 and no endorsement.
+func (s *endorserServer) ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	resp, err := s.processProposal(signedProp)
+	if err != nil {
+		logger.Debugf("Peer [%s] rejected proposal: %s", s.peer.name, err)
+		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, nil
+	}
+	return resp, nil
+}
+
+func (s *endorserServer) processProposal(signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	prop, err := parseProposal(signedProp.ProposalBytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(s.peer.network.certificateAuthorities(), prop.signatureHeader.Creator, signedProp.ProposalBytes, signedProp.Signature); err != nil {
+		return nil, errors.WithMessage(err, "access denied")
+	}
+
+	cc, ok := s.peer.chaincode(prop.ccID.Name)
+	if !ok {
+		return nil, errors.Errorf("chaincode [%s] not found", prop.ccID.Name)
+	}
+
+	channelID := prop.channelHeader.ChannelId
+	var state stateReader = emptyState{}
+	if l := s.peer.ledger(channelID); l != nil {
+		state = l
+	} else if channelID != "" {
+		return nil, errors.Errorf("channel [%s] not found", channelID)
+	}
+
+	action, err := simulate(cc.Invoke, prop.stub(state), prop.ccID)
+	if err != nil {
+		return nil, err
+	}
+	if action.Response.Status >= 400 {
+		return &pb.ProposalResponse{Version: 1, Response: action.Response}, nil
+	}
+
+	return endorse(s.peer.identity, prop, action)
+}
+
+// chaincodeProposal is an unmarshalled chaincode proposal
+type chaincodeProposal struct {
+	proposal          *pb.Proposal
+	header            *common.Header
+	channelHeader     *common.ChannelHeader
+	signatureHeader   *common.SignatureHeader
+	ccProposalPayload *pb.ChaincodeProposalPayload
+	ccID              *pb.ChaincodeID
+	args              [][]byte
+}
+
+// parseProposal unmarshals an endorser transaction proposal which invokes a chaincode
+func parseProposal(proposalBytes []byte) (*chaincodeProposal, error) {
+	prop := &pb.Proposal{}
+	if err := proto.Unmarshal(proposalBytes, prop); err != nil {
+		return nil, errors.Wrap(err, "unmarshal proposal failed")
+	}
+	header, err := protoutil.GetHeader(prop.Header)
+	if err != nil {
+		return nil, err
+	}
+	channelHeader, err := protoutil.UnmarshalChannelHeader(header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	signatureHeader, err := protoutil.GetSignatureHeader(header.SignatureHeader)
+	if err != nil {
+		return nil, err
+	}
+	if common.HeaderType(channelHeader.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+		return nil, errors.Errorf("invalid header type %s", common.HeaderType(channelHeader.Type))
+	}
+
+	ccProposalPayload, err := protoutil.GetChaincodeProposalPayload(prop.Payload)
+	if err != nil {
+		return nil, err
+	}
+	invocationSpec := &pb.ChaincodeInvocationSpec{}
+	if err := proto.Unmarshal(ccProposalPayload.Input, invocationSpec); err != nil {
+		return nil, errors.Wrap(err, "unmarshal chaincode invocation spec failed")
+	}
+	if invocationSpec.ChaincodeSpec == nil || invocationSpec.ChaincodeSpec.ChaincodeId == nil || invocationSpec.ChaincodeSpec.Input == nil {
+		return nil, errors.New("invalid chaincode invocation spec")
+	}
+
+	return &chaincodeProposal{
+		proposal:          prop,
+		header:            header,
+		channelHeader:     channelHeader,
+		signatureHeader:   signatureHeader,
+		ccProposalPayload: ccProposalPayload,
+		ccID:              invocationSpec.ChaincodeSpec.ChaincodeId,
+		args:              invocationSpec.ChaincodeSpec.Input.Args,
+	}, nil
+}
+
+// stub returns a stub for simulating the proposal against the given state
+func (p *chaincodeProposal) stub(state stateReader) *Stub {
+	return newStub(p.channelHeader.ChannelId, p.channelHeader.TxId, p.ccID.Name, p.signatureHeader.Creator,
+		p.args, p.ccProposalPayload.TransientMap, state)
+}
+
+// endorse signs the proposal response payload. The proposal hash excludes the transient map, as in Fabric.
+func endorse(identity *signingIdentity, prop *chaincodeProposal, action *pb.ChaincodeAction) (*pb.ProposalResponse, error) {
+	payloadBytes, err := proto.Marshal(&pb.ChaincodeProposalPayload{Input: prop.ccProposalPayload.Input})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal chaincode proposal payload failed")
+	}
+	proposalHash := sha256.Sum256(util.ConcatenateBytes(prop.header.ChannelHeader, prop.header.SignatureHeader, payloadBytes))
+
+	actionBytes, err := proto.Marshal(action)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal chaincode action failed")
+	}
+	prpBytes, err := proto.Marshal(&pb.ProposalResponsePayload{ProposalHash: proposalHash[:], Extension: actionBytes})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal proposal response payload failed")
+	}
+
+	endorser := identity.Serialize()
+	signature, err := identity.Sign(util.ConcatenateBytes(prpBytes, endorser))
+	if err != nil {
+		return nil, errors.WithMessage(err, "endorsement failed")
+	}
+
+	return &pb.ProposalResponse{
+		Version:     1,
+		Response:    action.Response,
+		Payload:     prpBytes,
+		Endorsement: &pb.Endorsement{Endorser: endorser, Signature: signature},
+	}, nil
+}
+
+// emptyState is used for proposals which are not bound to a channel
+type emptyState struct{}
+
+func (emptyState) State(namespace, key string) *versionedValue {
+	return nil
+}
+
+func (emptyState) StateRange(namespace, startKey, endKey string) []string {
+	return nil
+}
+
+// deliverServer delivers the committed blocks of the peer's ledgers
+type deliverServer struct {
+	peer *Peer
+}
+
+// Deliver delivers full blocks
+func (s *deliverServer) Deliver(stream pb.Deliver_DeliverServer) error {
+	handler := s.handler(func(status common.Status) error {
+		return stream.Send(&pb.DeliverResponse{Type: &pb.DeliverResponse_Status{Status: status}})
+	})
+	handler.sendBlock = func(channelID string, block *common.Block) error {
+		return stream.Send(&pb.DeliverResponse{Type: &pb.DeliverResponse_Block{Block: block}})
+	}
+	return handler.handle(stream)
+}
+
+// DeliverFiltered delivers filtered blocks
+func (s *deliverServer) DeliverFiltered(stream pb.Deliver_DeliverFilteredServer) error {
+	handler := s.handler(func(status common.Status) error {
+		return stream.Send(&pb.DeliverResponse{Type: &pb.DeliverResponse_Status{Status: status}})
+	})
+	handler.sendBlock = func(channelID string, block *common.Block) error {
+		return stream.Send(&pb.DeliverResponse{Type: &pb.DeliverResponse_FilteredBlock{FilteredBlock: filteredBlock(channelID, block)}})
+	}
+	return handler.handle(stream)
+}
+
+func (s *deliverServer) handler(sendStatus func(status common.Status) error) *deliverHandler {
+	return &deliverHandler{
+		cas:        s.peer.network.certificateAuthorities,
+		blockStore: s.peer.blockStore,
+		sendStatus: sendStatus,
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+)
+
+// newProfile returns a connection profile for the network. Peers and orderers are configured
+// without TLS and users are embedded. Only the crypto store uses a (temporary) directory.
+func (n *Network) newProfile() *config.Profile {
+	enabled := true
+
+	profile := &config.Profile{
+		Version: "1.0.0",
+		Name:    "fabtest",
+		Client: config.ClientProfile{
+			Organization: n.orgs[0].name,
+			Logging:      config.LoggingProfile{Level: "info"},
+			CredentialStore: config.CredentialStoreProfile{
+				CryptoStore: config.PathProfile{Path: n.cryptoStore},
+			},
+			BCCSP: config.BCCSPProfile{
+				Security: config.SecurityProfile{
+					Enabled:       &enabled,
+					Default:       config.ProviderProfile{Provider: "SW"},
+					HashAlgorithm: "SHA2",
+					SoftVerify:    &enabled,
+					Level:         256,
+					Ephemeral:     &enabled,
+				},
+			},
+			Global: config.GlobalProfile{
+				Timeout: config.GlobalTimeoutProfile{
+					Query:   10 * time.Second,
+					Execute: 10 * time.Second,
+					Resmgmt: 10 * time.Second,
+				},
+			},
+		},
+		Channels:      make(map[string]config.ChannelProfile),
+		Organizations: make(map[string]config.OrganizationProfile),
+		Orderers: map[string]config.EndpointProfile{
+			n.orderer.Name(): endpointProfile(n.orderer.Address()),
+		},
+		Peers: make(map[string]config.EndpointProfile),
+	}
+
+	for _, channelID := range n.channelIDs {
+		channel := config.ChannelProfile{
+			Orderers: []string{n.orderer.Name()},
+			Peers:    make(map[string]config.PeerChannelProfile),
+			Policies: config.ChannelPoliciesProfile{
+				QueryChannelConfig: config.QueryPolicyProfile{
+					MinResponses: 1,
+					MaxTargets:   1,
+					RetryOpts:    config.RetryOptsProfile{Attempts: 3, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, BackoffFactor: 2},
+				},
+			},
+		}
+		for _, p := range n.Peers() {
+			channel.Peers[p.Name()] = config.PeerChannelProfile{}
+		}
+		profile.Channels[channelID] = channel
+	}
+
+	for _, org := range append(n.orgs, n.orderer.org) {
+		orgProfile := config.OrganizationProfile{
+			MSPID: org.mspID,
+			Users: map[string]config.TLSKeyPairProfile{
+				UserName:  keyPairProfile(org.user),
+				AdminName: keyPairProfile(org.admin),
+			},
+		}
+		for _, p := range org.peers {
+			orgProfile.Peers = append(orgProfile.Peers, p.Name())
+			peerProfile := endpointProfile(p.Address())
+			peerProfile.OperationsURL = p.OperationsURL()
+			profile.Peers[p.Name()] = peerProfile
+		}
+		profile.Organizations[org.name] = orgProfile
+	}
+
+	return profile
+}
+
+func endpointProfile(address string) config.EndpointProfile {
+	return config.EndpointProfile{
+		URL: address,
+		GRPCOptions: map[string]interface{}{
+			"allow-insecure": true,
+			"fail-fast":      false,
+		},
+	}
+}
+
+func keyPairProfile(id *signingIdentity) config.TLSKeyPairProfile {
+	return config.TLSKeyPairProfile{
+		Key:  config.TLSProfile{Pem: string(id.keyPEM)},
+		Cert: config.TLSProfile{Pem: string(id.certPEM)},
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// server is an insecure GRPC server listening on a random localhost port
+type server struct {
+	grpcServer *grpc.Server
+	listener   net.Listener
+	wg         sync.WaitGroup
+}
+
+func newServer() (*server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to listen")
+	}
+	return &server{grpcServer: grpc.NewServer(), listener: listener}, nil
+}
+
+// Address returns the host:port on which the server listens
+func (s *server) Address() string {
+	return s.listener.Addr().String()
+}
+
+// Start starts serving requests in the background
+func (s *server) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.grpcServer.Serve(s.listener); err != nil {
+			logger.Debugf("Server [%s] stopped: %s", s.Address(), err)
+		}
+	}()
+}
+
+// Stop stops the server and waits for it to complete. The listener is closed even if the server was never started.
+func (s *server) Stop() {
+	s.grpcServer.Stop()
+	s.wg.Wait()
+	closeListener(s.listener)
+}
+
+// closeListener closes the listener if it wasn't already closed by the server
+func closeListener(listener net.Listener) {
+	if err := listener.Close(); err != nil {
+		logger.Debugf("Listener [%s] already closed: %s", listener.Addr(), err)
+	}
+}
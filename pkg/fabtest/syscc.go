@@ -0,0 +1,162 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabtest
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	cscc = "cscc"
+	qscc = "qscc"
+)
+
+// chaincode returns the system or user chaincode with the given name
+func (p *Peer) chaincode(name string) (Chaincode, bool) {
+	switch name {
+	case cscc:
+		return p.cscc, true
+	case qscc:
+		return p.qscc, true
+	default:
+		return p.network.chaincode(name)
+	}
+}
+
+// cscc implements the configuration system chaincode functions used by the SDK
+func (p *Peer) cscc(stub *Stub) ([]byte, error) {
+	switch stub.Function() {
+	case "GetConfigBlock":
+		l, err := p.ledgerArg(stub)
+		if err != nil {
+			return nil, err
+		}
+		block, err := lastConfigBlock(l)
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(block)
+	case "GetChannels":
+		resp := &pb.ChannelQueryResponse{}
+		for _, channelID := range p.channels() {
+			resp.Channels = append(resp.Channels, &pb.ChannelInfo{ChannelId: channelID})
+		}
+		return proto.Marshal(resp)
+	default:
+		return nil, errors.Errorf("requested function [%s] not found", stub.Function())
+	}
+}
+
+// qscc implements the ledger query system chaincode
+func (p *Peer) qscc(stub *Stub) ([]byte, error) {
+	l, err := p.ledgerArg(stub)
+	if err != nil {
+		return nil, err
+	}
+	params := stub.Parameters()
+
+	switch stub.Function() {
+	case "GetChainInfo":
+		return proto.Marshal(chainInfo(l))
+	case "GetBlockByNumber":
+		if len(params) < 2 {
+			return nil, errors.New("block number must be provided")
+		}
+		number, err := strconv.ParseUint(params[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid block number [%s]", params[1])
+		}
+		return marshalBlock(l.Block(number))
+	case "GetBlockByHash":
+		if len(params) < 2 {
+			return nil, errors.New("block hash must be provided")
+		}
+		return marshalBlock(blockByHash(l, stub.Args()[2]))
+	case "GetTransactionByID":
+		if len(params) < 2 {
+			return nil, errors.New("transaction ID must be provided")
+		}
+		info, ok := l.Transaction(params[1])
+		if !ok {
+			return nil, errors.Errorf("transaction [%s] not found", params[1])
+		}
+		return proto.Marshal(&pb.ProcessedTransaction{
+			TransactionEnvelope: protoutil.UnmarshalEnvelopeOrPanic(l.Block(info.blockNum).Data.Data[info.txNum]),
+			ValidationCode:      int32(info.validationCode),
+		})
+	case "GetBlockByTxID":
+		if len(params) < 2 {
+			return nil, errors.New("transaction ID must be provided")
+		}
+		info, ok := l.Transaction(params[1])
+		if !ok {
+			return nil, errors.Errorf("transaction [%s] not found", params[1])
+		}
+		return marshalBlock(l.Block(info.blockNum))
+	default:
+		return nil, errors.Errorf("requested function [%s] not found", stub.Function())
+	}
+}
+
+// ledgerArg returns the ledger of the channel given as the first parameter
+func (p *Peer) ledgerArg(stub *Stub) (*ledger, error) {
+	params := stub.Parameters()
+	if len(params) == 0 || params[0] == "" {
+		return nil, errors.New("channel ID must be provided")
+	}
+	l := p.ledger(params[0])
+	if l == nil {
+		return nil, errors.Errorf("channel [%s] not found", params[0])
+	}
+	return l, nil
+}
+
+func lastConfigBlock(l *ledger) (*common.Block, error) {
+	block := l.Block(l.Height() - 1)
+	if block == nil {
+		return nil, errors.New("ledger is empty")
+	}
+	index, err := protoutil.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	return l.Block(index), nil
+}
+
+func chainInfo(l *ledger) *common.BlockchainInfo {
+	height := l.Height()
+	info := &common.BlockchainInfo{Height: height}
+	if height > 0 {
+		last := l.Block(height - 1)
+		info.CurrentBlockHash = protoutil.BlockHeaderHash(last.Header)
+		info.PreviousBlockHash = last.Header.PreviousHash
+	}
+	return info
+}
+
+func blockByHash(l *ledger, hash []byte) *common.Block {
+	for _, block := range l.Blocks() {
+		if bytes.Equal(protoutil.BlockHeaderHash(block.Header), hash) {
+			return block
+		}
+	}
+	return nil
+}
+
+func marshalBlock(block *common.Block) ([]byte, error) {
+	if block == nil {
+		return nil, errors.New("block not found")
+	}
+	return proto.Marshal(block)
+}
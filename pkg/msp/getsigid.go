@@ -32,6 +32,7 @@ func newUser(userData *msp.UserData, cryptoSuite core.CryptoSuite) (*User, error
 		mspID:                 userData.MSPID,
 		enrollmentCertificate: userData.EnrollmentCertificate,
 		privateKey:            pk,
+		cryptoSuite:           cryptoSuite,
 	}
 	return u, nil
 }
@@ -99,6 +100,7 @@ func (mgr *IdentityManager) CreateSigningIdentity(opts ...msp.SigningIdentityOpt
 		mspID:                 mgr.orgMSPID,
 		enrollmentCertificate: opt.Cert,
 		privateKey:            privateKey,
+		cryptoSuite:           mgr.cryptoSuite,
 	}, nil
 }
 
@@ -146,6 +148,7 @@ func (mgr *IdentityManager) GetUser(username string) (*User, error) { //nolint
 			mspID:                 mspID,
 			enrollmentCertificate: certBytes,
 			privateKey:            privateKey,
+			cryptoSuite:           mgr.cryptoSuite,
 		}
 	}
 	return u, nil
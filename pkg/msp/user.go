@@ -11,6 +11,8 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	pb_msp "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
 	"github.com/pkg/errors"
 )
@@ -21,6 +23,7 @@ type User struct {
 	mspID                 string
 	enrollmentCertificate []byte
 	privateKey            core.Key
+	cryptoSuite           core.CryptoSuite
 }
 
 // Identifier returns user identifier
@@ -30,7 +33,28 @@ func (u *User) Identifier() *msp.IdentityIdentifier {
 
 // Verify a signature over some message using this identity as reference
 func (u *User) Verify(msg []byte, sig []byte) error {
-	return errors.New("not implemented")
+	if u.cryptoSuite == nil {
+		return errors.New("crypto suite is not set")
+	}
+
+	pubKey, err := cryptoutil.GetPublicKeyFromCert(u.enrollmentCertificate, u.cryptoSuite)
+	if err != nil {
+		return errors.WithMessage(err, "fetching public key from cert failed")
+	}
+
+	digest, err := u.digest(msg)
+	if err != nil {
+		return err
+	}
+
+	valid, err := u.cryptoSuite.Verify(pubKey, sig, digest, nil)
+	if err != nil {
+		return errors.WithMessage(err, "could not determine the validity of the signature")
+	}
+	if !valid {
+		return errors.New("the signature is invalid")
+	}
+	return nil
 }
 
 // Serialize converts an identity to bytes
@@ -63,5 +87,31 @@ func (u *User) PublicVersion() msp.Identity {
 
 // Sign the message
 func (u *User) Sign(msg []byte) ([]byte, error) {
-	return nil, errors.New("Sign() function not implemented")
+	if u.cryptoSuite == nil {
+		return nil, errors.New("crypto suite is not set")
+	}
+	if u.privateKey == nil {
+		return nil, errors.New("private key is not set")
+	}
+
+	digest, err := u.digest(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := u.cryptoSuite.Sign(u.privateKey, digest, nil)
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing failed")
+	}
+	return signature, nil
+}
+
+// digest hashes the message with the same hash options as the signing manager, so that
+// the crypto suite determines the hash function (e.g. SM3 for the GM suite)
+func (u *User) digest(msg []byte) ([]byte, error) {
+	digest, err := u.cryptoSuite.Hash(msg, cryptosuite.GetSHAOpts())
+	if err != nil {
+		return nil, errors.WithMessage(err, "hash computation failed")
+	}
+	return digest, nil
 }
@@ -94,6 +94,19 @@ func verifyUserIdentity(cryptoSuite core.CryptoSuite, t *testing.T, userData *ms
 	verifyBytes(t, user.EnrollmentCertificate(), generatedCertBytes)
 	// Check PrivateKey
 	verifyBytes(t, user.PrivateKey().SKI(), generatedKey.SKI())
+
+	// Check Sign and Verify
+	msg := []byte("message to sign")
+	sig, err := user.Sign(msg)
+	if err != nil {
+		t.Fatalf("Sign failed: %s", err)
+	}
+	if err := user.Verify(msg, sig); err != nil {
+		t.Fatalf("Verify failed: %s", err)
+	}
+	if err := user.Verify([]byte("tampered message"), sig); err == nil {
+		t.Fatal("Expected Verify to fail for a different message")
+	}
 }
 
 func verifyBytes(t *testing.T, v interface{}, expected []byte) error {
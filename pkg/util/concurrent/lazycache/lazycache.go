@@ -156,6 +156,29 @@ func (c *Cache) MustGet(key Key) interface{} {
 	return value
 }
 
+// ForEach invokes the given function for each value in the
+// cache which has been initialized. Entries which are still being
+// initialized or which failed to initialize are skipped.
+func (c *Cache) ForEach(f func(value interface{})) {
+	c.m.Range(func(key interface{}, value interface{}) bool {
+		fv := value.(future)
+		if !fv.IsSet() {
+			return true
+		}
+		v, err := fv.Get()
+		if err != nil || v == nil {
+			return true
+		}
+		v, err = c.value(v, nil)
+		if err != nil {
+			logger.Debugf("%s - Unable to get value for key [%q]: %s", c.name, key, err)
+			return true
+		}
+		f(v)
+		return true
+	})
+}
+
 // Close does the following:
 // - calls Close on all values that implement a Close() function
 // - deletes all entries from the cache
@@ -185,6 +185,38 @@ func TestDelete(t *testing.T) {
 
 }
 
+func TestForEach(t *testing.T) {
+	cache := New("Example_Cache", func(key Key) (interface{}, error) {
+		if key.String() == "error" {
+			return nil, fmt.Errorf("some error")
+		}
+		return fmt.Sprintf("Value_for_key_%s", key), nil
+	})
+	defer cache.Close()
+
+	_, err := cache.Get(NewStringKey("Key1"))
+	if err != nil {
+		test.Failf(t, "Error returned: %s", err)
+	}
+	_, err = cache.Get(NewStringKey("Key2"))
+	if err != nil {
+		test.Failf(t, "Error returned: %s", err)
+	}
+	_, err = cache.Get(NewStringKey("error"))
+	if err == nil {
+		test.Failf(t, "Expecting error for key 'error'")
+	}
+
+	values := make(map[string]bool)
+	cache.ForEach(func(value interface{}) {
+		values[value.(string)] = true
+	})
+
+	if len(values) != 2 || !values["Value_for_key_Key1"] || !values["Value_for_key_Key2"] {
+		test.Failf(t, "unexpected values: %v", values)
+	}
+}
+
 func TestMustGetPanic(t *testing.T) {
 	cache := New("Example_Cache", func(key Key) (interface{}, error) {
 		if key.String() == "error" {
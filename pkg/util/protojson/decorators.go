@@ -0,0 +1,71 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protojson
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// decorate adds the expansion of opaque fields to messages which aren't decorated by protolator.
+// Note that these decorators only apply to the top-level message.
+func decorate(msg proto.Message) proto.Message {
+	switch m := msg.(type) {
+	case *pb.Proposal:
+		return &proposal{Proposal: m}
+	case *pb.ProposalResponse:
+		return &proposalResponse{ProposalResponse: m}
+	default:
+		return msg
+	}
+}
+
+type proposal struct {
+	*pb.Proposal
+}
+
+func (p *proposal) Underlying() proto.Message {
+	return p.Proposal
+}
+
+func (p *proposal) StaticallyOpaqueFields() []string {
+	return []string{"header", "payload", "extension"}
+}
+
+func (p *proposal) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	switch name {
+	case "header":
+		return &common.Header{}, nil
+	case "payload":
+		return &pb.ChaincodeProposalPayload{}, nil
+	case "extension":
+		return &pb.ChaincodeHeaderExtension{}, nil
+	default:
+		return nil, errors.Errorf("not a marshaled field: %s", name)
+	}
+}
+
+type proposalResponse struct {
+	*pb.ProposalResponse
+}
+
+func (p *proposalResponse) Underlying() proto.Message {
+	return p.ProposalResponse
+}
+
+func (p *proposalResponse) StaticallyOpaqueFields() []string {
+	return []string{"payload"}
+}
+
+func (p *proposalResponse) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != p.StaticallyOpaqueFields()[0] {
+		return nil, errors.Errorf("not a marshaled field: %s", name)
+	}
+	return &pb.ProposalResponsePayload{}, nil
+}
@@ -0,0 +1,44 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package protojson marshals Fabric protobuf messages to and from JSON. The JSON format is the same as the
+// format used by configtxlator: bytes fields which contain nested marshaled messages (such as config updates,
+// transactions and read-write sets) are expanded into the JSON representation of those messages rather than
+// being encoded as base64. Unmarshal re-marshals the expanded messages back into their binary form.
+package protojson
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/tools/protolator"
+	"github.com/pkg/errors"
+)
+
+// Marshal returns the JSON encoding of the given Fabric message
+func Marshal(msg proto.Message) ([]byte, error) {
+	if msg == nil {
+		return nil, errors.New("message is nil")
+	}
+
+	var buf bytes.Buffer
+	if err := protolator.DeepMarshalJSON(&buf, decorate(msg)); err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal %T to JSON", msg)
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes the given JSON (as produced by Marshal or configtxlator) into the given Fabric message
+func Unmarshal(data []byte, msg proto.Message) error {
+	if msg == nil {
+		return errors.New("message is nil")
+	}
+
+	if err := protolator.DeepUnmarshalJSON(bytes.NewReader(data), decorate(msg)); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal JSON to %T", msg)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protojson
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protoutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	channelID = "mychannel"
+	txID      = "txid1"
+	ccID      = "mycc"
+)
+
+var (
+	creator = protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("cert")})
+	cis     = &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{
+		ChaincodeId: &pb.ChaincodeID{Name: ccID},
+		Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("invoke")}},
+	}}
+)
+
+func TestEnvelope(t *testing.T) {
+	env := newEndorserTxEnvelope()
+
+	data, err := Marshal(env)
+	require.NoError(t, err)
+
+	// The nested opaque fields should be expanded
+	assert.Contains(t, string(data), `"channel_id": "mychannel"`)
+	assert.Contains(t, string(data), `"tx_id": "txid1"`)
+	assert.Contains(t, string(data), `"key": "key1"`)
+
+	env2 := &common.Envelope{}
+	require.NoError(t, Unmarshal(data, env2))
+	assert.True(t, proto.Equal(env, env2))
+}
+
+func TestBlock(t *testing.T) {
+	block := protoutil.NewBlock(5, []byte("previous hash"))
+	block.Data.Data = [][]byte{protoutil.MarshalOrPanic(newEndorserTxEnvelope())}
+	block.Header.DataHash = protoutil.BlockDataHash(block.Data)
+
+	data, err := Marshal(block)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"tx_id": "txid1"`)
+
+	block2 := &common.Block{}
+	require.NoError(t, Unmarshal(data, block2))
+	assert.True(t, proto.Equal(block, block2))
+}
+
+func TestConfigUpdateEnvelope(t *testing.T) {
+	configUpdate := &common.ConfigUpdate{
+		ChannelId: channelID,
+		ReadSet:   &common.ConfigGroup{Groups: map[string]*common.ConfigGroup{"Application": {Version: 1}}},
+		WriteSet:  &common.ConfigGroup{Groups: map[string]*common.ConfigGroup{"Application": {Version: 2, ModPolicy: "Admins"}}},
+	}
+	configUpdateEnv := &common.ConfigUpdateEnvelope{
+		ConfigUpdate: protoutil.MarshalOrPanic(configUpdate),
+		Signatures: []*common.ConfigSignature{
+			{
+				SignatureHeader: protoutil.MarshalOrPanic(&common.SignatureHeader{Creator: creator, Nonce: []byte("nonce")}),
+				Signature:       []byte("signature"),
+			},
+		},
+	}
+
+	data, err := Marshal(configUpdateEnv)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"channel_id": "mychannel"`)
+	assert.Contains(t, string(data), `"Application"`)
+
+	configUpdateEnv2 := &common.ConfigUpdateEnvelope{}
+	require.NoError(t, Unmarshal(data, configUpdateEnv2))
+	assert.True(t, proto.Equal(configUpdateEnv, configUpdateEnv2))
+}
+
+func TestConfig(t *testing.T) {
+	config := &common.Config{
+		Sequence: 3,
+		ChannelGroup: &common.ConfigGroup{
+			Policies: map[string]*common.ConfigPolicy{
+				"Admins": {
+					ModPolicy: "Admins",
+					Policy: &common.Policy{
+						Type:  int32(common.Policy_IMPLICIT_META),
+						Value: protoutil.MarshalOrPanic(&common.ImplicitMetaPolicy{SubPolicy: "Admins", Rule: common.ImplicitMetaPolicy_MAJORITY}),
+					},
+				},
+			},
+		},
+	}
+
+	data, err := Marshal(config)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"rule": "MAJORITY"`)
+
+	config2 := &common.Config{}
+	require.NoError(t, Unmarshal(data, config2))
+	assert.True(t, proto.Equal(config, config2))
+}
+
+func TestProposal(t *testing.T) {
+	prop, _, err := protoutil.CreateChaincodeProposalWithTxIDNonceAndTransient(txID, common.HeaderType_ENDORSER_TRANSACTION, channelID, cis, []byte("nonce"), creator, nil)
+	require.NoError(t, err)
+
+	data, err := Marshal(prop)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"tx_id": "txid1"`)
+	assert.Contains(t, string(data), `"chaincode_id"`)
+
+	prop2 := &pb.Proposal{}
+	require.NoError(t, Unmarshal(data, prop2))
+	assert.True(t, proto.Equal(prop, prop2))
+}
+
+func TestProposalResponse(t *testing.T) {
+	resp := &pb.ProposalResponse{
+		Version:     1,
+		Response:    &pb.Response{Status: 200, Payload: []byte("payload")},
+		Payload:     protoutil.MarshalOrPanic(newProposalResponsePayload()),
+		Endorsement: &pb.Endorsement{Endorser: []byte("endorser"), Signature: []byte("signature")},
+	}
+
+	data, err := Marshal(resp)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"key": "key1"`)
+
+	resp2 := &pb.ProposalResponse{}
+	require.NoError(t, Unmarshal(data, resp2))
+	assert.True(t, proto.Equal(resp, resp2))
+}
+
+func TestEnums(t *testing.T) {
+	cds := &pb.ChaincodeDeploymentSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_JAVA, ChaincodeId: &pb.ChaincodeID{Name: ccID}},
+		ExecEnv:       pb.ChaincodeDeploymentSpec_SYSTEM,
+	}
+
+	data, err := Marshal(cds)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"type": "JAVA"`)
+	assert.Contains(t, string(data), `"exec_env": "SYSTEM"`)
+
+	cds2 := &pb.ChaincodeDeploymentSpec{}
+	require.NoError(t, Unmarshal(data, cds2))
+	assert.True(t, proto.Equal(cds, cds2))
+
+	// The enums must only be registered under the SDK's prefixed names
+	assert.Nil(t, proto.EnumValueMap("protos.ChaincodeSpec_Type"))
+}
+
+func TestErrors(t *testing.T) {
+	_, err := Marshal(nil)
+	assert.EqualError(t, err, "message is nil")
+
+	assert.EqualError(t, Unmarshal([]byte("{}"), nil), "message is nil")
+
+	err = Unmarshal([]byte("invalid"), &common.Envelope{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to unmarshal JSON to *common.Envelope")
+}
+
+func newEndorserTxEnvelope() *common.Envelope {
+	chdr := &common.ChannelHeader{
+		Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+		ChannelId: channelID,
+		TxId:      txID,
+	}
+	shdr := &common.SignatureHeader{Creator: creator, Nonce: []byte("nonce")}
+
+	cap := &pb.ChaincodeActionPayload{
+		ChaincodeProposalPayload: protoutil.MarshalOrPanic(&pb.ChaincodeProposalPayload{Input: protoutil.MarshalOrPanic(cis)}),
+		Action: &pb.ChaincodeEndorsedAction{
+			ProposalResponsePayload: protoutil.MarshalOrPanic(newProposalResponsePayload()),
+			Endorsements:            []*pb.Endorsement{{Endorser: []byte("endorser"), Signature: []byte("signature")}},
+		},
+	}
+
+	tx := &pb.Transaction{
+		Actions: []*pb.TransactionAction{
+			{
+				Header:  protoutil.MarshalOrPanic(shdr),
+				Payload: protoutil.MarshalOrPanic(cap),
+			},
+		},
+	}
+
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader:   protoutil.MarshalOrPanic(chdr),
+			SignatureHeader: protoutil.MarshalOrPanic(shdr),
+		},
+		Data: protoutil.MarshalOrPanic(tx),
+	}
+
+	return &common.Envelope{
+		Payload:   protoutil.MarshalOrPanic(payload),
+		Signature: []byte("signature"),
+	}
+}
+
+func newProposalResponsePayload() *pb.ProposalResponsePayload {
+	kvrwSet := &kvrwset.KVRWSet{
+		Writes: []*kvrwset.KVWrite{{Key: "key1", Value: []byte("value1")}},
+	}
+
+	txRWSet := &rwset.TxReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsRwset: []*rwset.NsReadWriteSet{
+			{Namespace: ccID, Rwset: protoutil.MarshalOrPanic(kvrwSet)},
+		},
+	}
+
+	action := &pb.ChaincodeAction{
+		Results:     protoutil.MarshalOrPanic(txRWSet),
+		Response:    &pb.Response{Status: 200},
+		ChaincodeId: &pb.ChaincodeID{Name: ccID, Version: "v1"},
+	}
+
+	return &pb.ProposalResponsePayload{
+		ProposalHash: []byte("proposal hash"),
+		Extension:    protoutil.MarshalOrPanic(action),
+	}
+}
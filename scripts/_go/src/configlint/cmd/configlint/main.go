@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/validator"
+)
+
+func main() {
+
+	// get flags
+	expiryWarning := flag.Duration("expiryWarning", 30*24*time.Hour, "Report a warning for certificates which expire within this period")
+	strict := flag.Bool("strict", false, "Exit with a non-zero status if warnings are found")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] <connection profile>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, file := range flag.Args() {
+		findings, err := validator.Validate(config.FromFile(file), validator.WithExpiryWarning(*expiryWarning))
+		exitWhenError(err)
+
+		for _, finding := range findings {
+			fmt.Printf("%s: %s\n", file, finding)
+		}
+
+		if findings.HasErrors() || (*strict && len(findings) > 0) {
+			failed = true
+		} else if len(findings) == 0 {
+			fmt.Printf("%s: OK\n", file)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// exit properly
+func exitWhenError(err error) {
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+}
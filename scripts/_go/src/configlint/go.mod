@@ -0,0 +1,12 @@
+// Copyright SecureKey Technologies Inc. All Rights Reserved.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+module configlint
+
+require (
+	github.com/hyperledger/fabric-sdk-go v0.0.0
+	github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric v0.0.0-20190524192706-bfae339c63bf // indirect
+)
+
+replace github.com/hyperledger/fabric-sdk-go => ../../../../
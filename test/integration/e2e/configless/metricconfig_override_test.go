@@ -9,8 +9,8 @@ package configless
 import "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics/cfg"
 
 // metricconfig_override_test.go is an example of programmatically configuring the sdk by injecting instances that implement Metricsonfig's functions (representing the sdk's configs)
-// for the sake of overriding MetricsConfig integration tests, the structure variables below set the metrics to disabled as the standarad build does not use metrics.
-// Using the pprof build tag, application developers can create sub interfaces of MetricsConfig with values similar to what is found in /test/fixtures/config/config_test.yaml
+// for the sake of overriding MetricsConfig integration tests, the structure variables below set the metrics to disabled.
+// Application developers can enable metrics by creating sub interfaces of MetricsConfig with values similar to what is found in /test/fixtures/config/config_test.yaml
 // the example implementation functions in this file can be overridden to load configs in any way that suits the client application needs
 
 var (